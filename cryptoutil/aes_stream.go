@@ -0,0 +1,346 @@
+package cryptoutil
+
+import (
+	"bufio"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/up-zero/gotool"
+)
+
+// 流式加密格式 (版本 1):
+//
+//	header: magic "GTAE" (4) | version (1) | chunkSize uint32 BE (4) | salt (16) | noncePrefix (7)
+//	chunks: AES-GCM(chunk_i) || tag (16), 除最后一块外均为 chunkSize 字节, 明文为空时只有一个空块
+//
+// 每个流使用 HMAC-SHA256(key, info || salt) 派生独立的子密钥,
+// 第 i 块的 nonce 为 noncePrefix (7) | i uint32 BE (4) | last (1), last 仅最后一块为 1,
+// 头部作为每一块的附加认证数据, 因此块的重排、删除、截断以及头部篡改均能被检测
+const (
+	aesStreamMagic      = "GTAE"
+	aesStreamVersion    = 1
+	aesStreamSaltSize   = 16
+	aesStreamPrefixSize = 7
+	aesStreamHeaderSize = 4 + 1 + 4 + aesStreamSaltSize + aesStreamPrefixSize
+	aesStreamInfo       = "gotool aes stream v1"
+
+	// AesStreamChunkSize 流式加密默认的分块大小
+	AesStreamChunkSize = 64 * 1024
+	// aesStreamMaxChunkSize 分块大小上限
+	aesStreamMaxChunkSize = 16 * 1024 * 1024
+)
+
+// aesStream 流式加解密的公共状态
+type aesStream struct {
+	aead      cipher.AEAD
+	header    []byte
+	chunkSize int
+	counter   uint32
+	nonce     []byte
+}
+
+// newAesStream 根据头部派生子密钥
+func newAesStream(key, header []byte) (*aesStream, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, fmt.Errorf("%w, aes key size %d", gotool.ErrInvalidParam, len(key))
+	}
+	salt := header[9 : 9+aesStreamSaltSize]
+	subKey := hmacGenerate(append([]byte(aesStreamInfo), salt...), key, crypto.SHA256)[:len(key)]
+	block, err := aes.NewCipher(subKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	copy(nonce, header[9+aesStreamSaltSize:])
+	return &aesStream{
+		aead:      aead,
+		header:    header,
+		chunkSize: int(binary.BigEndian.Uint32(header[5:9])),
+		nonce:     nonce,
+	}, nil
+}
+
+// nextNonce 生成当前块的 nonce 并递增计数器
+func (s *aesStream) nextNonce(last bool) ([]byte, error) {
+	if s.counter == ^uint32(0) {
+		return nil, fmt.Errorf("%w, aes stream too long", gotool.ErrInvalidParam)
+	}
+	binary.BigEndian.PutUint32(s.nonce[aesStreamPrefixSize:], s.counter)
+	s.nonce[len(s.nonce)-1] = 0
+	if last {
+		s.nonce[len(s.nonce)-1] = 1
+	}
+	s.counter++
+	return s.nonce, nil
+}
+
+// AesGcmWriter 流式 AES-GCM 加密写入器
+type AesGcmWriter struct {
+	w      io.Writer
+	stream *aesStream
+	buf    []byte
+	closed bool
+}
+
+// NewAesGcmWriter 创建流式 AES-GCM 加密写入器, 写入完成后必须调用 Close 写入最后一块
+//
+// # Params:
+//
+//	w: 密文输出
+//	key: 密钥, 长度必须是 16, 24 或 32 字节
+//	chunkSize: 分块大小, 默认 AesStreamChunkSize
+func NewAesGcmWriter(w io.Writer, key []byte, chunkSize ...int) (*AesGcmWriter, error) {
+	size := AesStreamChunkSize
+	if len(chunkSize) > 0 {
+		size = chunkSize[0]
+	}
+	if size <= 0 || size > aesStreamMaxChunkSize {
+		return nil, fmt.Errorf("%w, chunk size %d", gotool.ErrInvalidParam, size)
+	}
+
+	header := make([]byte, aesStreamHeaderSize)
+	copy(header, aesStreamMagic)
+	header[4] = aesStreamVersion
+	binary.BigEndian.PutUint32(header[5:9], uint32(size))
+	if _, err := io.ReadFull(rand.Reader, header[9:]); err != nil {
+		return nil, err
+	}
+	stream, err := newAesStream(key, header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &AesGcmWriter{w: w, stream: stream, buf: make([]byte, 0, size)}, nil
+}
+
+// Write 写入明文, 缓冲满一块且有后续数据时加密输出
+func (a *AesGcmWriter) Write(p []byte) (int, error) {
+	if a.closed {
+		return 0, os.ErrClosed
+	}
+	n := 0
+	for len(p) > 0 {
+		// 缓冲区已满且还有数据, 说明当前块不是最后一块
+		if len(a.buf) == a.stream.chunkSize {
+			if err := a.flush(false); err != nil {
+				return n, err
+			}
+		}
+		m := copy(a.buf[len(a.buf):a.stream.chunkSize], p)
+		a.buf = a.buf[:len(a.buf)+m]
+		p = p[m:]
+		n += m
+	}
+	return n, nil
+}
+
+// flush 加密并输出缓冲区
+func (a *AesGcmWriter) flush(last bool) error {
+	nonce, err := a.stream.nextNonce(last)
+	if err != nil {
+		return err
+	}
+	sealed := a.stream.aead.Seal(a.buf[:0:0], nonce, a.buf, a.stream.header)
+	a.buf = a.buf[:0]
+	_, err = a.w.Write(sealed)
+	return err
+}
+
+// Close 加密并输出最后一块, 不会关闭底层的 io.Writer
+func (a *AesGcmWriter) Close() error {
+	if a.closed {
+		return nil
+	}
+	a.closed = true
+	return a.flush(true)
+}
+
+// AesGcmReader 流式 AES-GCM 解密读取器
+type AesGcmReader struct {
+	r      *bufio.Reader
+	stream *aesStream
+	chunk  []byte // 密文缓冲
+	plain  []byte // 未读取的明文
+	done   bool
+}
+
+// NewAesGcmReader 创建流式 AES-GCM 解密读取器, 读取 AesGcmWriter 写入的密文
+//
+// 每一块在返回前均已通过认证, 读取到 io.EOF 时才能确认数据未被截断
+//
+// # Params:
+//
+//	r: 密文输入
+//	key: 密钥
+func NewAesGcmReader(r io.Reader, key []byte) (*AesGcmReader, error) {
+	header := make([]byte, aesStreamHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("%w, aes stream header: %v", gotool.ErrNotSupportFormat, err)
+	}
+	if string(header[:4]) != aesStreamMagic {
+		return nil, fmt.Errorf("%w, not an aes stream", gotool.ErrNotSupportFormat)
+	}
+	if header[4] != aesStreamVersion {
+		return nil, fmt.Errorf("%w, unsupported aes stream version %d", gotool.ErrNotSupportFormat, header[4])
+	}
+	size := binary.BigEndian.Uint32(header[5:9])
+	if size == 0 || size > aesStreamMaxChunkSize {
+		return nil, fmt.Errorf("%w, aes stream chunk size %d", gotool.ErrNotSupportFormat, size)
+	}
+	stream, err := newAesStream(key, header)
+	if err != nil {
+		return nil, err
+	}
+	return &AesGcmReader{
+		r:      bufio.NewReader(r),
+		stream: stream,
+		chunk:  make([]byte, int(size)+stream.aead.Overhead()),
+	}, nil
+}
+
+// Read 读取解密后的明文
+func (a *AesGcmReader) Read(p []byte) (int, error) {
+	for len(a.plain) == 0 {
+		if a.done {
+			return 0, io.EOF
+		}
+		if err := a.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, a.plain)
+	a.plain = a.plain[n:]
+	return n, nil
+}
+
+// next 读取并解密下一块
+func (a *AesGcmReader) next() error {
+	n, err := io.ReadFull(a.r, a.chunk)
+	last := false
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF):
+		// 不足一整块, 只能是最后一块
+		last = true
+	case err != nil:
+		return err
+	default:
+		// 整块之后没有数据时为最后一块
+		if _, err := a.r.Peek(1); errors.Is(err, io.EOF) {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+	if n < a.stream.aead.Overhead() {
+		return fmt.Errorf("%w, aes stream truncated", gotool.ErrDecryptFailed)
+	}
+
+	nonce, err := a.stream.nextNonce(last)
+	if err != nil {
+		return err
+	}
+	plain, err := a.stream.aead.Open(a.chunk[:0], nonce, a.chunk[:n], a.stream.header)
+	if err != nil {
+		return fmt.Errorf("%w, aes stream chunk %d: %v", gotool.ErrDecryptFailed, a.stream.counter-1, err)
+	}
+	a.plain = plain
+	a.done = last
+	return nil
+}
+
+// AesEncryptFile 使用 AES-GCM 分块流式加密文件
+//
+// # Params:
+//
+//	srcPath: 源文件路径
+//	dstPath: 加密后的文件输出路径
+//	key: 密钥, 长度必须是 16, 24 或 32 字节
+func AesEncryptFile(srcPath, dstPath string, key []byte) error {
+	return encryptFile(srcPath, dstPath, func(w io.Writer) (io.WriteCloser, error) {
+		return NewAesGcmWriter(w, key)
+	})
+}
+
+// AesDecryptFile 解密 AesEncryptFile 加密的文件
+//
+// 先写入临时文件, 全部数据认证通过后再重命名为 dstPath, 失败时不会留下不完整的明文
+//
+// # Params:
+//
+//	srcPath: 密文文件路径
+//	dstPath: 解密后的文件输出路径
+//	key: 密钥
+func AesDecryptFile(srcPath, dstPath string, key []byte) error {
+	return decryptFile(srcPath, dstPath, func(r io.Reader) (io.Reader, error) {
+		return NewAesGcmReader(r, key)
+	})
+}
+
+// encryptFile 使用流式加密写入器加密文件
+func encryptFile(srcPath, dstPath string, newWriter func(w io.Writer) (io.WriteCloser, error)) error {
+	srcFile, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+	dstFile, err := os.Create(dstPath)
+	if err != nil {
+		return err
+	}
+	defer dstFile.Close()
+
+	w, err := newWriter(dstFile)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, srcFile); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return dstFile.Close()
+}
+
+// decryptFile 使用流式解密读取器解密文件, 成功后才将临时文件重命名为 dstPath
+func decryptFile(srcPath, dstPath string, newReader func(r io.Reader) (io.Reader, error)) error {
+	srcFile, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	r, err := newReader(srcFile)
+	if err != nil {
+		return err
+	}
+	tmpPath := dstPath + ".tmp"
+	dstFile, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dstFile, r)
+	if closeErr := dstFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, dstPath)
+}
@@ -0,0 +1,177 @@
+package cryptoutil
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/up-zero/gotool"
+	"github.com/up-zero/gotool/testutil"
+)
+
+// aesStreamEncrypt 测试用流式加密
+func aesStreamEncrypt(t *testing.T, plain, key []byte, chunkSize int) []byte {
+	var buf bytes.Buffer
+	w, err := NewAesGcmWriter(&buf, key, chunkSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 分多次写入
+	for i := 0; i < len(plain); i += 7 {
+		if _, err := w.Write(plain[i:min(i+7, len(plain))]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// aesStreamDecrypt 测试用流式解密
+func aesStreamDecrypt(data, key []byte) ([]byte, error) {
+	r, err := NewAesGcmReader(bytes.NewReader(data), key)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestAesGcmStream(t *testing.T) {
+	key := []byte("1234567890123456")
+	const chunkSize = 64
+	for _, size := range []int{0, 1, chunkSize - 1, chunkSize, chunkSize + 1, 3 * chunkSize, 1000} {
+		plain := make([]byte, size)
+		rand.Read(plain)
+		sealed := aesStreamEncrypt(t, plain, key, chunkSize)
+
+		// 头部 + 每块 16 字节标签, 空明文对应一个空块
+		chunks := max(1, (size+chunkSize-1)/chunkSize)
+		testutil.Equal(t, len(sealed), aesStreamHeaderSize+size+16*chunks)
+
+		got, err := aesStreamDecrypt(sealed, key)
+		if err != nil {
+			t.Fatal(size, err)
+		}
+		testutil.Equal(t, bytes.Equal(got, plain), true)
+	}
+
+	// 相同明文每次加密结果不同
+	plain := bytes.Repeat([]byte("gotool"), 100)
+	testutil.Equal(t, bytes.Equal(aesStreamEncrypt(t, plain, key, chunkSize), aesStreamEncrypt(t, plain, key, chunkSize)), false)
+
+	// AES-256 与默认分块大小
+	key256 := make([]byte, 32)
+	rand.Read(key256)
+	var buf bytes.Buffer
+	w, err := NewAesGcmWriter(&buf, key256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	big := make([]byte, 3*AesStreamChunkSize+5)
+	w.Write(big)
+	w.Close()
+	got, err := aesStreamDecrypt(buf.Bytes(), key256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, len(got), len(big))
+
+	_, err = NewAesGcmWriter(&buf, []byte("short"))
+	testutil.Equal(t, errors.Is(err, gotool.ErrInvalidParam), true)
+	_, err = w.Write([]byte("x"))
+	testutil.Equal(t, err, os.ErrClosed)
+}
+
+func TestAesGcmStreamTamper(t *testing.T) {
+	key := []byte("1234567890123456")
+	const chunkSize = 32
+	plain := bytes.Repeat([]byte("0123456789abcdef"), 8) // 4 块
+	sealed := aesStreamEncrypt(t, plain, key, chunkSize)
+	encChunk := chunkSize + 16
+
+	decryptErr := func(data []byte) error {
+		_, err := aesStreamDecrypt(data, key)
+		return err
+	}
+	clone := func() []byte {
+		return append([]byte{}, sealed...)
+	}
+
+	// 修改密文
+	data := clone()
+	data[aesStreamHeaderSize+40] ^= 1
+	testutil.Equal(t, errors.Is(decryptErr(data), gotool.ErrDecryptFailed), true)
+
+	// 修改头部中的分块大小
+	data = clone()
+	data[8] ^= 1
+	testutil.NotEqual(t, decryptErr(data), nil)
+
+	// 在块边界截断
+	data = clone()[:aesStreamHeaderSize+2*encChunk]
+	testutil.Equal(t, errors.Is(decryptErr(data), gotool.ErrDecryptFailed), true)
+
+	// 在块中间截断
+	data = clone()[:len(sealed)-5]
+	testutil.Equal(t, errors.Is(decryptErr(data), gotool.ErrDecryptFailed), true)
+
+	// 交换两块
+	data = clone()
+	first := aesStreamHeaderSize
+	copy(data[first:], sealed[first+encChunk:first+2*encChunk])
+	copy(data[first+encChunk:], sealed[first:first+encChunk])
+	testutil.Equal(t, errors.Is(decryptErr(data), gotool.ErrDecryptFailed), true)
+
+	// 追加数据
+	data = append(clone(), 0)
+	testutil.Equal(t, errors.Is(decryptErr(data), gotool.ErrDecryptFailed), true)
+
+	// 错误的密钥
+	_, err := aesStreamDecrypt(sealed, []byte("6543210987654321"))
+	testutil.Equal(t, errors.Is(err, gotool.ErrDecryptFailed), true)
+
+	// 错误的魔数和版本
+	data = clone()
+	data[0] = 'X'
+	testutil.Equal(t, errors.Is(decryptErr(data), gotool.ErrNotSupportFormat), true)
+	data = clone()
+	data[4] = 2
+	testutil.Equal(t, errors.Is(decryptErr(data), gotool.ErrNotSupportFormat), true)
+	testutil.Equal(t, errors.Is(decryptErr(sealed[:10]), gotool.ErrNotSupportFormat), true)
+}
+
+func TestAesEncryptFile(t *testing.T) {
+	key := []byte("1234567890123456")
+	dir := t.TempDir()
+	src := filepath.Join(dir, "plain.bin")
+	enc := filepath.Join(dir, "plain.bin.enc")
+	dec := filepath.Join(dir, "plain.dec.bin")
+
+	plain := make([]byte, 200*1024+3)
+	rand.Read(plain)
+	if err := os.WriteFile(src, plain, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := AesEncryptFile(src, enc, key); err != nil {
+		t.Fatal(err)
+	}
+	if err := AesDecryptFile(enc, dec, key); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(dec)
+	testutil.Equal(t, bytes.Equal(got, plain), true)
+
+	// 解密失败时不产生输出文件
+	os.Remove(dec)
+	err := AesDecryptFile(enc, dec, []byte("6543210987654321"))
+	testutil.Equal(t, errors.Is(err, gotool.ErrDecryptFailed), true)
+	_, err = os.Stat(dec)
+	testutil.Equal(t, os.IsNotExist(err), true)
+	_, err = os.Stat(dec + ".tmp")
+	testutil.Equal(t, os.IsNotExist(err), true)
+}
@@ -0,0 +1,282 @@
+package cryptoutil
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"sync"
+)
+
+const (
+	argon2Version    = 0x13
+	argon2SyncPoints = 4
+	argon2BlockWords = 128 // 1KiB 块中 uint64 的个数
+
+	argon2d  = 0
+	argon2i  = 1
+	argon2id = 2
+)
+
+// argon2Block Argon2 内存块
+type argon2Block [argon2BlockWords]uint64
+
+// Argon2idKey Argon2id 密钥派生 (RFC 9106, 版本 0x13)
+//
+// # Params:
+//
+//	password: 密码
+//	salt: 盐, 推荐 16 字节
+//	time: 迭代次数
+//	memory: 内存大小 (KiB)
+//	threads: 并行度
+//	keyLen: 派生密钥的长度
+func Argon2idKey(password, salt []byte, time, memory uint32, threads uint8, keyLen uint32) []byte {
+	return argon2Key(argon2id, password, salt, nil, nil, time, memory, threads, keyLen)
+}
+
+// argon2Key Argon2 密钥派生, 支持可选的密钥 secret 和附加数据 data
+func argon2Key(mode int, password, salt, secret, data []byte, time, memory uint32, threads uint8, keyLen uint32) []byte {
+	if time < 1 {
+		time = 1
+	}
+	if threads < 1 {
+		threads = 1
+	}
+	h0 := argon2InitHash(mode, password, salt, secret, data, time, memory, uint32(threads), keyLen)
+
+	// 内存块数向下取整为 4*threads 的倍数, 且不少于 8*threads
+	blocks := memory / (argon2SyncPoints * uint32(threads)) * (argon2SyncPoints * uint32(threads))
+	if blocks < 2*argon2SyncPoints*uint32(threads) {
+		blocks = 2 * argon2SyncPoints * uint32(threads)
+	}
+	B := argon2InitBlocks(h0, blocks, uint32(threads))
+	argon2ProcessBlocks(mode, B, time, blocks, uint32(threads))
+	return argon2Extract(B, blocks, uint32(threads), keyLen)
+}
+
+// argon2InitHash 计算初始哈希 H0, 预留 8 字节用于块序号和通道号
+func argon2InitHash(mode int, password, salt, secret, data []byte, time, memory, threads, keyLen uint32) []byte {
+	var params [24]byte
+	binary.LittleEndian.PutUint32(params[0:], threads)
+	binary.LittleEndian.PutUint32(params[4:], keyLen)
+	binary.LittleEndian.PutUint32(params[8:], memory)
+	binary.LittleEndian.PutUint32(params[12:], time)
+	binary.LittleEndian.PutUint32(params[16:], argon2Version)
+	binary.LittleEndian.PutUint32(params[20:], uint32(mode))
+
+	h, _ := newBlake2b(blake2bSize, nil)
+	h.Write(params[:])
+	for _, v := range [][]byte{password, salt, secret, data} {
+		var size [4]byte
+		binary.LittleEndian.PutUint32(size[:], uint32(len(v)))
+		h.Write(size[:])
+		h.Write(v)
+	}
+	return append(h.Sum(nil), make([]byte, 8)...)
+}
+
+// argon2InitBlocks 生成每个通道的前两个块
+func argon2InitBlocks(h0 []byte, blocks, threads uint32) []argon2Block {
+	B := make([]argon2Block, blocks)
+	var buf [1024]byte
+	for lane := uint32(0); lane < threads; lane++ {
+		j := lane * (blocks / threads)
+		binary.LittleEndian.PutUint32(h0[blake2bSize+4:], lane)
+		for i := uint32(0); i < 2; i++ {
+			binary.LittleEndian.PutUint32(h0[blake2bSize:], i)
+			argon2Hash(buf[:], h0)
+			for k := range B[j+i] {
+				B[j+i][k] = binary.LittleEndian.Uint64(buf[k*8:])
+			}
+		}
+	}
+	return B
+}
+
+// argon2ProcessBlocks 按 pass、slice 填充内存, 同一 slice 内各通道并行处理
+func argon2ProcessBlocks(mode int, B []argon2Block, time, blocks, threads uint32) {
+	laneLen := blocks / threads
+	segLen := laneLen / argon2SyncPoints
+
+	processSegment := func(n, slice, lane uint32) {
+		var addresses, in, zero argon2Block
+		dataIndependent := mode == argon2i || (mode == argon2id && n == 0 && slice < argon2SyncPoints/2)
+		if dataIndependent {
+			in[0] = uint64(n)
+			in[1] = uint64(lane)
+			in[2] = uint64(slice)
+			in[3] = uint64(blocks)
+			in[4] = uint64(time)
+			in[5] = uint64(mode)
+		}
+
+		index := uint32(0)
+		if n == 0 && slice == 0 {
+			// 前两个块已经生成
+			index = 2
+			if dataIndependent {
+				in[6]++
+				argon2Compress(&addresses, &in, &zero, false)
+				argon2Compress(&addresses, &addresses, &zero, false)
+			}
+		}
+
+		offset := lane*laneLen + slice*segLen + index
+		for ; index < segLen; index, offset = index+1, offset+1 {
+			prev := offset - 1
+			if index == 0 && slice == 0 {
+				// 通道的最后一个块
+				prev += laneLen
+			}
+
+			var random uint64
+			if dataIndependent {
+				if index%argon2BlockWords == 0 {
+					in[6]++
+					argon2Compress(&addresses, &in, &zero, false)
+					argon2Compress(&addresses, &addresses, &zero, false)
+				}
+				random = addresses[index%argon2BlockWords]
+			} else {
+				random = B[prev][0]
+			}
+			ref := argon2IndexAlpha(random, laneLen, segLen, threads, n, slice, lane, index)
+			argon2Compress(&B[offset], &B[prev], &B[ref], true)
+		}
+	}
+
+	for n := uint32(0); n < time; n++ {
+		for slice := uint32(0); slice < argon2SyncPoints; slice++ {
+			var wg sync.WaitGroup
+			for lane := uint32(0); lane < threads; lane++ {
+				wg.Add(1)
+				go func(lane uint32) {
+					defer wg.Done()
+					processSegment(n, slice, lane)
+				}(lane)
+			}
+			wg.Wait()
+		}
+	}
+}
+
+// argon2IndexAlpha 计算参考块的位置
+func argon2IndexAlpha(random uint64, laneLen, segLen, threads, n, slice, lane, index uint32) uint32 {
+	refLane := uint32(random>>32) % threads
+	if n == 0 && slice == 0 {
+		refLane = lane
+	}
+
+	// 参考区域的大小 m 和起始位置 s
+	m, s := 3*segLen, ((slice+1)%argon2SyncPoints)*segLen
+	if lane == refLane {
+		m += index
+	}
+	if n == 0 {
+		m, s = slice*segLen, 0
+		if slice == 0 || lane == refLane {
+			m += index
+		}
+	}
+	if index == 0 || lane == refLane {
+		m--
+	}
+
+	// 非均匀映射, 偏向较近的块
+	x := random & 0xFFFFFFFF
+	x = (x * x) >> 32
+	x = (x * uint64(m)) >> 32
+	return refLane*laneLen + uint32((uint64(s)+uint64(m)-(x+1))%uint64(laneLen))
+}
+
+// argon2Extract 合并各通道的最后一个块并输出标签
+func argon2Extract(B []argon2Block, blocks, threads, keyLen uint32) []byte {
+	laneLen := blocks / threads
+	last := B[laneLen-1]
+	for lane := uint32(1); lane < threads; lane++ {
+		for i, v := range B[lane*laneLen+laneLen-1] {
+			last[i] ^= v
+		}
+	}
+	var buf [1024]byte
+	for i, v := range last {
+		binary.LittleEndian.PutUint64(buf[i*8:], v)
+	}
+	out := make([]byte, keyLen)
+	argon2Hash(out, buf[:])
+	return out
+}
+
+// argon2Hash 变长哈希 H'
+func argon2Hash(out, in []byte) {
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(out)))
+	if len(out) <= blake2bSize {
+		copy(out, blake2bSum(len(out), size[:], in))
+		return
+	}
+
+	// V1 = H(size || in), Vi = H(Vi-1), 每次输出前 32 字节, 最后一次输出剩余部分
+	v := blake2bSum(blake2bSize, size[:], in)
+	copy(out, v[:32])
+	out = out[32:]
+	for len(out) > blake2bSize {
+		v = blake2bSum(blake2bSize, v)
+		copy(out, v[:32])
+		out = out[32:]
+	}
+	copy(out, blake2bSum(len(out), v))
+}
+
+// argon2Compress 压缩函数 G, xor 为真时结果与 out 原值异或 (版本 0x13)
+func argon2Compress(out, in1, in2 *argon2Block, xor bool) {
+	var t argon2Block
+	for i := range t {
+		t[i] = in1[i] ^ in2[i]
+	}
+	// 按行: 每行 16 个字
+	for i := 0; i < argon2BlockWords; i += 16 {
+		argon2Blamka(&t[i], &t[i+1], &t[i+2], &t[i+3], &t[i+4], &t[i+5], &t[i+6], &t[i+7],
+			&t[i+8], &t[i+9], &t[i+10], &t[i+11], &t[i+12], &t[i+13], &t[i+14], &t[i+15])
+	}
+	// 按列: 每列由每行的两个字组成
+	for i := 0; i < argon2BlockWords/8; i += 2 {
+		argon2Blamka(&t[i], &t[i+1], &t[16+i], &t[16+i+1], &t[32+i], &t[32+i+1], &t[48+i], &t[48+i+1],
+			&t[64+i], &t[64+i+1], &t[80+i], &t[80+i+1], &t[96+i], &t[96+i+1], &t[112+i], &t[112+i+1])
+	}
+	if xor {
+		for i := range t {
+			out[i] ^= in1[i] ^ in2[i] ^ t[i]
+		}
+	} else {
+		for i := range t {
+			out[i] = in1[i] ^ in2[i] ^ t[i]
+		}
+	}
+}
+
+// argon2Blamka 置换 P, 基于 BLAKE2b 轮函数并加入乘法
+func argon2Blamka(t00, t01, t02, t03, t04, t05, t06, t07, t08, t09, t10, t11, t12, t13, t14, t15 *uint64) {
+	argon2GB(t00, t04, t08, t12)
+	argon2GB(t01, t05, t09, t13)
+	argon2GB(t02, t06, t10, t14)
+	argon2GB(t03, t07, t11, t15)
+	argon2GB(t00, t05, t10, t15)
+	argon2GB(t01, t06, t11, t12)
+	argon2GB(t02, t07, t08, t13)
+	argon2GB(t03, t04, t09, t14)
+}
+
+// argon2GB 轮函数 GB
+func argon2GB(a, b, c, d *uint64) {
+	fBlaMka := func(x, y uint64) uint64 {
+		return x + y + 2*uint64(uint32(x))*uint64(uint32(y))
+	}
+	*a = fBlaMka(*a, *b)
+	*d = bits.RotateLeft64(*d^*a, -32)
+	*c = fBlaMka(*c, *d)
+	*b = bits.RotateLeft64(*b^*c, -24)
+	*a = fBlaMka(*a, *b)
+	*d = bits.RotateLeft64(*d^*a, -16)
+	*c = fBlaMka(*c, *d)
+	*b = bits.RotateLeft64(*b^*c, -63)
+}
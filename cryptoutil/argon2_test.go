@@ -0,0 +1,22 @@
+package cryptoutil
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestArgon2idKey(t *testing.T) {
+	// RFC 9106 5.3
+	got := argon2Key(argon2id, bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 16), bytes.Repeat([]byte{3}, 8), bytes.Repeat([]byte{4}, 12), 3, 32, 4, 32)
+	testutil.Equal(t, hex.EncodeToString(got), "0d640df58d78766c08c037a34a8b53c9d01ef0452d75b65eb52520e96b01e659")
+
+	k1 := Argon2idKey([]byte("password"), []byte("somesalt"), 1, 64, 1, 64)
+	k2 := Argon2idKey([]byte("password"), []byte("somesalt"), 1, 64, 1, 64)
+	testutil.Equal(t, k1, k2)
+	testutil.Equal(t, len(k1), 64)
+	k3 := Argon2idKey([]byte("password"), []byte("somesalt"), 2, 64, 1, 64)
+	testutil.NotEqual(t, hex.EncodeToString(k1), hex.EncodeToString(k3))
+}
@@ -0,0 +1,110 @@
+package cryptoutil
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	"github.com/up-zero/gotool"
+)
+
+const (
+	// BcryptMinCost bcrypt 最小代价因子
+	BcryptMinCost = 4
+	// BcryptMaxCost bcrypt 最大代价因子
+	BcryptMaxCost = 31
+	// BcryptDefaultCost bcrypt 默认代价因子
+	BcryptDefaultCost = 10
+
+	bcryptMaxPasswordSize = 72
+	bcryptSaltSize        = 16
+	bcryptHashSize        = 23
+	bcryptMagic           = "OrpheanBeholderScryDoubt"
+)
+
+// bcryptEncoding bcrypt 使用的 base64 编码, 字母表与标准 base64 不同且不填充
+var bcryptEncoding = base64.NewEncoding("./ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789").WithPadding(base64.NoPadding)
+
+// BcryptHash 生成 bcrypt 哈希, 格式为 $2b$cost$salt(22)hash(31)
+//
+// # Params:
+//
+//	password: 密码, 不超过 72 字节
+//	cost: 代价因子, 默认 BcryptDefaultCost
+func BcryptHash(password []byte, cost ...int) (string, error) {
+	c := BcryptDefaultCost
+	if len(cost) > 0 {
+		c = cost[0]
+	}
+	salt, err := randomBytes(bcryptSaltSize)
+	if err != nil {
+		return "", err
+	}
+	return bcryptHash(password, salt, c)
+}
+
+// BcryptVerify 校验密码与 bcrypt 哈希是否匹配, 支持 $2a$, $2b$, $2y$ 前缀
+//
+// # Params:
+//
+//	password: 密码
+//	hash: BcryptHash 生成的哈希
+func BcryptVerify(password []byte, hash string) (bool, error) {
+	prefix, cost, salt, err := bcryptParse(hash)
+	if err != nil {
+		return false, err
+	}
+	computed, err := bcryptHash(password, salt, cost)
+	if err != nil {
+		return false, err
+	}
+	// 统一前缀后比较, 三种前缀对于不含高位字节问题的实现是等价的
+	computed = prefix + computed[len(prefix):]
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(hash)) == 1, nil
+}
+
+// bcryptHash 使用指定的盐计算 bcrypt 哈希
+func bcryptHash(password, salt []byte, cost int) (string, error) {
+	if cost < BcryptMinCost || cost > BcryptMaxCost {
+		return "", fmt.Errorf("%w, bcrypt cost %d", gotool.ErrInvalidParam, cost)
+	}
+	if len(password) > bcryptMaxPasswordSize {
+		return "", fmt.Errorf("%w, bcrypt password longer than %d bytes", gotool.ErrInvalidParam, bcryptMaxPasswordSize)
+	}
+
+	// 密钥包含结尾的 NUL
+	key := make([]byte, len(password)+1)
+	copy(key, password)
+	c := eksBlowfishSetup(cost, salt, key)
+
+	text := []byte(bcryptMagic)
+	for i := 0; i < 64; i++ {
+		for j := 0; j < len(text); j += 8 {
+			c.encryptBlock(text[j:], text[j:])
+		}
+	}
+	return fmt.Sprintf("$2b$%02d$%s%s", cost, bcryptEncoding.EncodeToString(salt), bcryptEncoding.EncodeToString(text[:bcryptHashSize])), nil
+}
+
+// bcryptParse 解析 bcrypt 哈希, 返回前缀、代价因子和盐
+func bcryptParse(hash string) (string, int, []byte, error) {
+	// $2b$10$ + 22 + 31
+	if len(hash) != 60 || hash[0] != '$' || hash[1] != '2' || hash[3] != '$' || hash[6] != '$' {
+		return "", 0, nil, fmt.Errorf("%w, invalid bcrypt hash", gotool.ErrNotSupportFormat)
+	}
+	switch hash[2] {
+	case 'a', 'b', 'y':
+	default:
+		return "", 0, nil, fmt.Errorf("%w, unsupported bcrypt version 2%c", gotool.ErrNotSupportFormat, hash[2])
+	}
+	cost, err := strconv.Atoi(hash[4:6])
+	if err != nil {
+		return "", 0, nil, fmt.Errorf("%w, invalid bcrypt cost", gotool.ErrNotSupportFormat)
+	}
+	salt, err := bcryptEncoding.DecodeString(hash[7:29])
+	if err != nil {
+		return "", 0, nil, fmt.Errorf("%w, invalid bcrypt salt", gotool.ErrNotSupportFormat)
+	}
+	return hash[:4], cost, salt, nil
+}
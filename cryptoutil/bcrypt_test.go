@@ -0,0 +1,51 @@
+package cryptoutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestBcryptHash(t *testing.T) {
+	salt, _ := bcryptEncoding.DecodeString("CCCCCCCCCCCCCCCCCCCCC.")
+	got, err := bcryptHash([]byte("U*U"), salt, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, got, "$2b$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW")
+
+	hash, err := BcryptHash([]byte("get"), BcryptMinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, strings.HasPrefix(hash, "$2b$04$"), true)
+	testutil.Equal(t, len(hash), 60)
+
+	// 密码超过 72 字节
+	_, err = BcryptHash(make([]byte, 73), BcryptMinCost)
+	testutil.NotEqual(t, err, nil)
+	_, err = BcryptHash([]byte("get"), 3)
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestBcryptVerify(t *testing.T) {
+	for _, hash := range []string{
+		"$2b$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW",
+		"$2a$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW",
+		"$2y$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW",
+	} {
+		ok, err := BcryptVerify([]byte("U*U"), hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, ok, true)
+		ok, _ = BcryptVerify([]byte("U*V"), hash)
+		testutil.Equal(t, ok, false)
+	}
+
+	_, err := BcryptVerify([]byte("U*U"), "$2x$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW")
+	testutil.NotEqual(t, err, nil)
+	_, err = BcryptVerify([]byte("U*U"), "$2b$05$CCCC")
+	testutil.NotEqual(t, err, nil)
+}
@@ -0,0 +1,36 @@
+package cryptoutil
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestBlake2b(t *testing.T) {
+	testutil.Equal(t, hex.EncodeToString(blake2bSum(64)), "786a02f742015903c6c6fd852552d272912f4740e15847618a86e217f71f5419d25e1031afee585313896444934eb04b903a685b1448b755d56f701afe9be2ce")
+	testutil.Equal(t, hex.EncodeToString(blake2bSum(64, []byte("abc"))), "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923")
+	testutil.Equal(t, hex.EncodeToString(blake2bSum(32, []byte("a"), []byte("bc"))), "bddd813c634239723171ef3fee98579b94964e3bb1cb3e427262c8c068d52319")
+
+	// 带密钥, 输入跨越多个块
+	key := make([]byte, 64)
+	data := make([]byte, 256)
+	for i := range data {
+		data[i] = byte(i)
+		if i < len(key) {
+			key[i] = byte(i)
+		}
+	}
+	h, err := newBlake2b(64, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Write(data[:100])
+	h.Write(data[100:])
+	testutil.Equal(t, hex.EncodeToString(h.Sum(nil)), "b72071e096277edebb8ee5134dd3714996307ba3a55aa4733d412abbe28e909e10e57e6fbfb4ef53b3b960518294ff889a90829254412e2a60b85add07a3674f")
+
+	_, err = newBlake2b(65, nil)
+	testutil.NotEqual(t, err, nil)
+	_, err = newBlake2b(32, make([]byte, 65))
+	testutil.NotEqual(t, err, nil)
+}
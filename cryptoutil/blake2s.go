@@ -0,0 +1,142 @@
+package cryptoutil
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"math/bits"
+
+	"github.com/up-zero/gotool"
+)
+
+const (
+	blake2sBlockSize = 64
+	blake2sSize      = 32
+)
+
+// blake2sIV BLAKE2s 初始向量, 与 SHA-256 相同
+var blake2sIV = [8]uint32{
+	0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a,
+	0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+}
+
+// blake2sDigest BLAKE2s 哈希 (RFC 7693)
+type blake2sDigest struct {
+	h    [8]uint32
+	t    [2]uint32
+	buf  [blake2sBlockSize]byte
+	n    int
+	size int
+	key  [blake2sBlockSize]byte
+	klen int
+}
+
+// newBlake2s 创建 BLAKE2s 哈希
+//
+// # Params:
+//
+//	size: 输出长度, 1~32 字节
+//	key: 密钥, 可选, 最长 32 字节
+func newBlake2s(size int, key []byte) (hash.Hash, error) {
+	if size < 1 || size > blake2sSize || len(key) > blake2sSize {
+		return nil, fmt.Errorf("%w, blake2s size=%d, key size=%d", gotool.ErrInvalidParam, size, len(key))
+	}
+	d := &blake2sDigest{size: size, klen: len(key)}
+	copy(d.key[:], key)
+	d.Reset()
+	return d, nil
+}
+
+// Reset 重置状态
+func (d *blake2sDigest) Reset() {
+	d.h = blake2sIV
+	d.h[0] ^= 0x01010000 ^ uint32(d.klen)<<8 ^ uint32(d.size)
+	d.t = [2]uint32{}
+	d.n = 0
+	if d.klen > 0 {
+		// 密钥填充为一个完整的块
+		d.buf = d.key
+		d.n = blake2sBlockSize
+	}
+}
+
+// Size 输出长度
+func (d *blake2sDigest) Size() int { return d.size }
+
+// BlockSize 块大小
+func (d *blake2sDigest) BlockSize() int { return blake2sBlockSize }
+
+// Write 写入数据, 最后一块保留到 Sum 时处理
+func (d *blake2sDigest) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		if d.n == blake2sBlockSize {
+			d.compress(false)
+			d.n = 0
+		}
+		m := copy(d.buf[d.n:], p)
+		d.n += m
+		p = p[m:]
+	}
+	return n, nil
+}
+
+// Sum 追加哈希值, 不改变当前状态
+func (d *blake2sDigest) Sum(in []byte) []byte {
+	c := *d
+	for i := c.n; i < blake2sBlockSize; i++ {
+		c.buf[i] = 0
+	}
+	c.compress(true)
+	var out [blake2sSize]byte
+	for i, v := range c.h {
+		binary.LittleEndian.PutUint32(out[i*4:], v)
+	}
+	return append(in, out[:d.size]...)
+}
+
+// compress 压缩缓冲区中的块
+func (d *blake2sDigest) compress(last bool) {
+	d.t[0] += uint32(d.n)
+	if d.t[0] < uint32(d.n) {
+		d.t[1]++
+	}
+
+	var m [16]uint32
+	for i := range m {
+		m[i] = binary.LittleEndian.Uint32(d.buf[i*4:])
+	}
+	var v [16]uint32
+	copy(v[:8], d.h[:])
+	copy(v[8:], blake2sIV[:])
+	v[12] ^= d.t[0]
+	v[13] ^= d.t[1]
+	if last {
+		v[14] = ^v[14]
+	}
+
+	g := func(a, b, c, d int, x, y uint32) {
+		v[a] += v[b] + x
+		v[d] = bits.RotateLeft32(v[d]^v[a], -16)
+		v[c] += v[d]
+		v[b] = bits.RotateLeft32(v[b]^v[c], -12)
+		v[a] += v[b] + y
+		v[d] = bits.RotateLeft32(v[d]^v[a], -8)
+		v[c] += v[d]
+		v[b] = bits.RotateLeft32(v[b]^v[c], -7)
+	}
+	// BLAKE2s 为 10 轮, 使用 blake2bSigma 的前 10 组置换
+	for _, s := range blake2bSigma[:10] {
+		g(0, 4, 8, 12, m[s[0]], m[s[1]])
+		g(1, 5, 9, 13, m[s[2]], m[s[3]])
+		g(2, 6, 10, 14, m[s[4]], m[s[5]])
+		g(3, 7, 11, 15, m[s[6]], m[s[7]])
+		g(0, 5, 10, 15, m[s[8]], m[s[9]])
+		g(1, 6, 11, 12, m[s[10]], m[s[11]])
+		g(2, 7, 8, 13, m[s[12]], m[s[13]])
+		g(3, 4, 9, 14, m[s[14]], m[s[15]])
+	}
+	for i := range d.h {
+		d.h[i] ^= v[i] ^ v[i+8]
+	}
+}
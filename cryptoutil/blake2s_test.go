@@ -0,0 +1,39 @@
+package cryptoutil
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestBlake2s(t *testing.T) {
+	h, err := newBlake2s(32, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Write([]byte("abc"))
+	testutil.Equal(t, hex.EncodeToString(h.Sum(nil)), "508c5e8c327c14e2e1a72ba34eeb452f37458b209ed63a294d999b4c86675982")
+
+	// 带密钥, 输入跨越多个块
+	key := make([]byte, 32)
+	data := make([]byte, 256)
+	for i := range data {
+		data[i] = byte(i)
+		if i < len(key) {
+			key[i] = byte(i)
+		}
+	}
+	h, err = newBlake2s(32, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Write(data[:100])
+	h.Write(data[100:])
+	testutil.Equal(t, hex.EncodeToString(h.Sum(nil)), "5211d1aefc0025be7f85c06b3e14e0fc645ae12bd41746485ea6d8a364a2eaee")
+
+	_, err = newBlake2s(33, nil)
+	testutil.NotEqual(t, err, nil)
+	_, err = newBlake2s(32, make([]byte, 33))
+	testutil.NotEqual(t, err, nil)
+}
@@ -0,0 +1,27 @@
+package cryptoutil
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestBlowfish(t *testing.T) {
+	// Eric Young 的测试向量
+	tests := []struct{ key, plain, want string }{
+		{"0000000000000000", "0000000000000000", "4ef997456198dd78"},
+		{"ffffffffffffffff", "ffffffffffffffff", "51866fd5b85ecb8a"},
+		{"0123456789abcdef", "1111111111111111", "61f9c3802281b096"},
+		{"fedcba9876543210", "0123456789abcdef", "0aceab0fc6a0a28d"},
+	}
+	for _, tt := range tests {
+		key, _ := hex.DecodeString(tt.key)
+		plain, _ := hex.DecodeString(tt.plain)
+		c := newBlowfishCipher()
+		c.expandKey(key, nil)
+		dst := make([]byte, 8)
+		c.encryptBlock(dst, plain)
+		testutil.Equal(t, hex.EncodeToString(dst), tt.want)
+	}
+}
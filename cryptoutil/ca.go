@@ -0,0 +1,260 @@
+package cryptoutil
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/up-zero/gotool"
+)
+
+// certIssuer 签发者，CA 证书及其私钥
+type certIssuer struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+// parseCertIssuer 解析 CA 证书和私钥，并检查两者是否匹配以及证书是否可用于签发
+func parseCertIssuer(caCertPEM, caKeyPEM string) (*certIssuer, error) {
+	certs, err := parseCertPEM(caCertPEM)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parseCertKey(caKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	cert := certs[0]
+	if !cert.IsCA || cert.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return nil, fmt.Errorf("%w, certificate %q is not a CA", gotool.ErrInvalidParam, cert.Subject.CommonName)
+	}
+	if pub, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !pub.Equal(cert.PublicKey) {
+		return nil, fmt.Errorf("%w, CA private key does not match the certificate", gotool.ErrInvalidParam)
+	}
+	return &certIssuer{cert: cert, key: key}, nil
+}
+
+// sign 使用 CA 签发证书，有效期不会超过 CA 证书
+func (i *certIssuer) sign(template *x509.Certificate, pub crypto.PublicKey) (string, error) {
+	if template.NotAfter.After(i.cert.NotAfter) {
+		template.NotAfter = i.cert.NotAfter
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, i.cert, pub, i.key)
+	if err != nil {
+		return "", err
+	}
+	return encodeCertPEM(der), nil
+}
+
+// setCAUsage 设置 CA 证书的密钥用途
+func setCAUsage(template *x509.Certificate) {
+	template.IsCA = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+}
+
+// GenRootCA 生成自签名的根 CA 证书和私钥，默认有效期 10 年
+//
+// # Params:
+//
+//	conf: 证书配置信息，Hosts 和 Usage 对 CA 证书无效
+//
+// # Returns:
+//
+//	certPEM: CA 证书字符串
+//	keyPEM: CA 私钥字符串
+//	err: 错误信息
+func GenRootCA(conf CertConfig) (certPEM, keyPEM string, err error) {
+	if conf.Years <= 0 {
+		conf.Years = 10
+	}
+	conf.Hosts = nil
+	conf.setDefaults()
+
+	privateKey, err := genCertKey(conf)
+	if err != nil {
+		return "", "", err
+	}
+	template, err := newCertTemplate(conf)
+	if err != nil {
+		return "", "", err
+	}
+	setCAUsage(template)
+
+	derBytes, err := x509.CreateCertificate(rand.Reader, template, template, privateKey.Public(), privateKey)
+	if err != nil {
+		return "", "", err
+	}
+	keyPEM, err = marshalCertKey(privateKey)
+	if err != nil {
+		return "", "", err
+	}
+	return encodeCertPEM(derBytes), keyPEM, nil
+}
+
+// GenIntermediateCA 使用上级 CA 签发中间 CA 证书，默认有效期 5 年，中间 CA 只能签发叶子证书
+//
+// # Params:
+//
+//	conf: 证书配置信息，Hosts 和 Usage 对 CA 证书无效
+//	caCertPEM: 上级 CA 证书
+//	caKeyPEM: 上级 CA 私钥
+func GenIntermediateCA(conf CertConfig, caCertPEM, caKeyPEM string) (certPEM, keyPEM string, err error) {
+	if conf.Years <= 0 {
+		conf.Years = 5
+	}
+	conf.Hosts = nil
+	conf.setDefaults()
+
+	issuer, err := parseCertIssuer(caCertPEM, caKeyPEM)
+	if err != nil {
+		return "", "", err
+	}
+	privateKey, err := genCertKey(conf)
+	if err != nil {
+		return "", "", err
+	}
+	template, err := newCertTemplate(conf)
+	if err != nil {
+		return "", "", err
+	}
+	setCAUsage(template)
+	template.MaxPathLenZero = true
+
+	certPEM, err = issuer.sign(template, privateKey.Public())
+	if err != nil {
+		return "", "", err
+	}
+	keyPEM, err = marshalCertKey(privateKey)
+	if err != nil {
+		return "", "", err
+	}
+	return certPEM, keyPEM, nil
+}
+
+// IssueCert 使用 CA 签发叶子证书，生成新的私钥
+//
+// # Params:
+//
+//	conf: 证书配置信息，Usage 指定服务端或客户端证书
+//	caCertPEM: CA 证书
+//	caKeyPEM: CA 私钥
+func IssueCert(conf CertConfig, caCertPEM, caKeyPEM string) (certPEM, keyPEM string, err error) {
+	conf.setDefaults()
+
+	issuer, err := parseCertIssuer(caCertPEM, caKeyPEM)
+	if err != nil {
+		return "", "", err
+	}
+	privateKey, err := genCertKey(conf)
+	if err != nil {
+		return "", "", err
+	}
+	template, err := newCertTemplate(conf)
+	if err != nil {
+		return "", "", err
+	}
+	if err := setLeafUsage(template, conf, privateKey.Public()); err != nil {
+		return "", "", err
+	}
+
+	certPEM, err = issuer.sign(template, privateKey.Public())
+	if err != nil {
+		return "", "", err
+	}
+	keyPEM, err = marshalCertKey(privateKey)
+	if err != nil {
+		return "", "", err
+	}
+	return certPEM, keyPEM, nil
+}
+
+// GenCSR 生成证书签名请求 (CSR) 和私钥
+//
+// # Params:
+//
+//	conf: 证书配置信息，使用 CommonName、Organization、Hosts 和密钥相关的配置
+//
+// # Returns:
+//
+//	csrPEM: CSR 字符串
+//	keyPEM: 私钥字符串
+//	err: 错误信息
+func GenCSR(conf CertConfig) (csrPEM, keyPEM string, err error) {
+	conf.setDefaults()
+
+	privateKey, err := genCertKey(conf)
+	if err != nil {
+		return "", "", err
+	}
+	template := &x509.CertificateRequest{
+		Subject: pkix.Name{
+			Organization: conf.Organization,
+			CommonName:   conf.CommonName,
+		},
+	}
+	hosts := &x509.Certificate{}
+	if err := setCertHosts(hosts, conf.Hosts); err != nil {
+		return "", "", err
+	}
+	template.DNSNames, template.IPAddresses, template.EmailAddresses, template.URIs = hosts.DNSNames, hosts.IPAddresses, hosts.EmailAddresses, hosts.URIs
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, privateKey)
+	if err != nil {
+		return "", "", err
+	}
+	keyPEM, err = marshalCertKey(privateKey)
+	if err != nil {
+		return "", "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})), keyPEM, nil
+}
+
+// SignCSR 使用 CA 签发 CSR，主题和 SAN 默认取自 CSR
+//
+// # Params:
+//
+//	csrPEM: CSR 字符串
+//	conf: 证书配置信息，CommonName、Organization、Hosts 非空时覆盖 CSR 中的值，密钥相关的配置无效
+//	caCertPEM: CA 证书
+//	caKeyPEM: CA 私钥
+func SignCSR(csrPEM string, conf CertConfig, caCertPEM, caKeyPEM string) (certPEM string, err error) {
+	conf.setDefaults()
+
+	block, _ := pem.Decode([]byte(csrPEM))
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return "", fmt.Errorf("pem decode error: %w", gotool.ErrInvalidParam)
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return "", fmt.Errorf("%w, parse certificate request: %v", gotool.ErrInvalidParam, err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return "", fmt.Errorf("%w, certificate request signature: %v", gotool.ErrInvalidParam, err)
+	}
+	issuer, err := parseCertIssuer(caCertPEM, caKeyPEM)
+	if err != nil {
+		return "", err
+	}
+
+	template, err := newCertTemplate(conf)
+	if err != nil {
+		return "", err
+	}
+	template.Subject = csr.Subject
+	if conf.CommonName != "" {
+		template.Subject.CommonName = conf.CommonName
+	}
+	if len(conf.Organization) > 0 {
+		template.Subject.Organization = conf.Organization
+	}
+	if len(conf.Hosts) == 0 {
+		template.DNSNames, template.IPAddresses, template.EmailAddresses, template.URIs = csr.DNSNames, csr.IPAddresses, csr.EmailAddresses, csr.URIs
+	}
+	if err := setLeafUsage(template, conf, csr.PublicKey); err != nil {
+		return "", err
+	}
+	return issuer.sign(template, csr.PublicKey)
+}
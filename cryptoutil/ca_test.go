@@ -0,0 +1,108 @@
+package cryptoutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestIssueCert(t *testing.T) {
+	rootCert, rootKey, err := GenRootCA(CertConfig{CommonName: "UpZero Root CA", Organization: []string{"UpZero"}, KeyType: CertKeyECDSA, Curve: EcdsaP384})
+	if err != nil {
+		t.Fatal(err)
+	}
+	interCert, interKey, err := GenIntermediateCA(CertConfig{CommonName: "UpZero Intermediate CA", KeyType: CertKeyEd25519}, rootCert, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, keyType := range []string{CertKeyRSA, CertKeyECDSA, CertKeyEd25519} {
+		certPEM, keyPEM, err := IssueCert(CertConfig{
+			CommonName: "api.getcharzp.cn",
+			Days:       90,
+			Hosts:      []string{"api.getcharzp.cn", "192.168.1.8"},
+			KeyType:    keyType,
+			RsaBits:    2048,
+		}, interCert, interKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = parseCertKey(keyPEM)
+		testutil.Equal(t, err, nil)
+
+		chain, err := VerifyCert(certPEM, rootCert, CertVerifyConfig{Intermediates: interCert, Host: "192.168.1.8", Usage: CertUsageServer})
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, len(chain), 3)
+		testutil.Equal(t, chain[0].KeyType, keyType)
+		testutil.Equal(t, chain[1].CommonName, "UpZero Intermediate CA")
+		testutil.Equal(t, chain[2].IsCA, true)
+		testutil.Equal(t, chain[0].NotAfter.Sub(chain[0].NotBefore), 90*24*time.Hour)
+
+		// 服务端证书不能用于客户端认证
+		_, err = VerifyCert(certPEM+interCert, rootCert, CertVerifyConfig{Usage: CertUsageClient})
+		testutil.NotEqual(t, err, nil)
+	}
+
+	// 中间 CA 不能再签发 CA
+	subCert, subKey, err := GenIntermediateCA(CertConfig{CommonName: "Sub CA", KeyType: CertKeyECDSA}, interCert, interKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafCert, _, err := IssueCert(CertConfig{CommonName: "leaf", KeyType: CertKeyECDSA}, subCert, subKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = VerifyCert(leafCert+subCert+interCert, rootCert)
+	testutil.NotEqual(t, err, nil)
+
+	// 叶子证书不能作为 CA, CA 私钥必须匹配
+	certPEM, keyPEM, _ := IssueCert(CertConfig{CommonName: "leaf", KeyType: CertKeyECDSA}, rootCert, rootKey)
+	_, _, err = IssueCert(CertConfig{CommonName: "leaf"}, certPEM, keyPEM)
+	testutil.NotEqual(t, err, nil)
+	_, _, err = IssueCert(CertConfig{CommonName: "leaf"}, rootCert, interKey)
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestSignCSR(t *testing.T) {
+	caCert, caKey, err := GenRootCA(CertConfig{CommonName: "UpZero Root CA", KeyType: CertKeyECDSA})
+	if err != nil {
+		t.Fatal(err)
+	}
+	csrPEM, keyPEM, err := GenCSR(CertConfig{
+		CommonName: "client-1",
+		Hosts:      []string{"spiffe://getcharzp.cn/client-1", "client@getcharzp.cn"},
+		KeyType:    CertKeyECDSA,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = parseCertKey(keyPEM)
+	testutil.Equal(t, err, nil)
+
+	certPEM, err := SignCSR(csrPEM, CertConfig{Usage: CertUsageClient, Days: 30}, caCert, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, err := VerifyCert(certPEM, caCert, CertVerifyConfig{Usage: CertUsageClient})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, chain[0].CommonName, "client-1")
+	testutil.Equal(t, chain[0].URIs, []string{"spiffe://getcharzp.cn/client-1"})
+	testutil.Equal(t, chain[0].EmailAddresses, []string{"client@getcharzp.cn"})
+
+	// 覆盖 CSR 中的 SAN
+	certPEM, err = SignCSR(csrPEM, CertConfig{Hosts: []string{"client.getcharzp.cn"}}, caCert, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	infos, _ := ParseCert(certPEM)
+	testutil.Equal(t, infos[0].DNSNames, []string{"client.getcharzp.cn"})
+	testutil.Equal(t, len(infos[0].URIs), 0)
+
+	_, err = SignCSR("invalid", CertConfig{}, caCert, caKey)
+	testutil.NotEqual(t, err, nil)
+}
@@ -0,0 +1,163 @@
+package cryptoutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/up-zero/gotool"
+)
+
+// CertInfo 证书信息
+type CertInfo struct {
+	Subject            string    // 主题
+	Issuer             string    // 签发者
+	CommonName         string    // 通用名称
+	SerialNumber       string    // 序列号 (十六进制)
+	NotBefore          time.Time // 生效时间
+	NotAfter           time.Time // 过期时间
+	DNSNames           []string  // SAN 域名
+	IPAddresses        []string  // SAN IP
+	EmailAddresses     []string  // SAN 邮箱
+	URIs               []string  // SAN URI
+	IsCA               bool      // 是否为 CA 证书
+	KeyType            string    // 公钥类型，CertKeyRSA、CertKeyECDSA 或 CertKeyEd25519
+	SignatureAlgorithm string    // 签名算法
+	Fingerprint        string    // SHA-256 指纹 (十六进制)
+}
+
+// ExpiresIn 距离过期的时长，已过期时为负数
+func (c *CertInfo) ExpiresIn() time.Duration {
+	return time.Until(c.NotAfter)
+}
+
+// Expired 是否已过期
+func (c *CertInfo) Expired() bool {
+	return time.Now().After(c.NotAfter)
+}
+
+// newCertInfo 提取证书信息
+func newCertInfo(cert *x509.Certificate) *CertInfo {
+	fingerprint := sha256.Sum256(cert.Raw)
+	info := &CertInfo{
+		Subject:            cert.Subject.String(),
+		Issuer:             cert.Issuer.String(),
+		CommonName:         cert.Subject.CommonName,
+		SerialNumber:       formatCertSerial(cert.SerialNumber),
+		NotBefore:          cert.NotBefore,
+		NotAfter:           cert.NotAfter,
+		DNSNames:           cert.DNSNames,
+		EmailAddresses:     cert.EmailAddresses,
+		IsCA:               cert.IsCA,
+		SignatureAlgorithm: cert.SignatureAlgorithm.String(),
+		Fingerprint:        hex.EncodeToString(fingerprint[:]),
+	}
+	for _, ip := range cert.IPAddresses {
+		info.IPAddresses = append(info.IPAddresses, ip.String())
+	}
+	for _, u := range cert.URIs {
+		info.URIs = append(info.URIs, u.String())
+	}
+	switch cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		info.KeyType = CertKeyRSA
+	case *ecdsa.PublicKey:
+		info.KeyType = CertKeyECDSA
+	case ed25519.PublicKey:
+		info.KeyType = CertKeyEd25519
+	}
+	return info
+}
+
+// ParseCert 解析 PEM 格式的证书信息，支持包含多个证书的证书链
+//
+// # Params:
+//
+//	certPEM: 证书字符串
+func ParseCert(certPEM string) ([]*CertInfo, error) {
+	certs, err := parseCertPEM(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]*CertInfo, 0, len(certs))
+	for _, cert := range certs {
+		infos = append(infos, newCertInfo(cert))
+	}
+	return infos, nil
+}
+
+// CertVerifyConfig 证书链校验配置
+type CertVerifyConfig struct {
+	Intermediates string    // 中间证书，可包含多个
+	Host          string    // 需要匹配的域名或 IP，为空时不校验
+	Usage         string    // 证书用途，可选值为 CertUsageServer、CertUsageClient，为空时不校验
+	Time          time.Time // 校验时间，默认当前时间
+}
+
+// VerifyCert 校验证书链，返回从叶子证书到根证书的证书信息
+//
+// # Params:
+//
+//	certPEM: 待校验的证书，其后可以附带中间证书 (例如 fullchain.pem)
+//	rootPEM: 受信任的根证书，可包含多个
+//	conf: 校验配置
+func VerifyCert(certPEM, rootPEM string, conf ...CertVerifyConfig) ([]*CertInfo, error) {
+	var c CertVerifyConfig
+	if len(conf) > 0 {
+		c = conf[0]
+	}
+	certs, err := parseCertPEM(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	roots, err := parseCertPEM(rootPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         x509.NewCertPool(),
+		Intermediates: x509.NewCertPool(),
+		DNSName:       c.Host,
+		CurrentTime:   c.Time,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	for _, root := range roots {
+		opts.Roots.AddCert(root)
+	}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	if c.Intermediates != "" {
+		intermediates, err := parseCertPEM(c.Intermediates)
+		if err != nil {
+			return nil, err
+		}
+		for _, cert := range intermediates {
+			opts.Intermediates.AddCert(cert)
+		}
+	}
+	switch c.Usage {
+	case "":
+	case CertUsageServer:
+		opts.KeyUsages = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	case CertUsageClient:
+		opts.KeyUsages = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	default:
+		return nil, fmt.Errorf("%w, unsupported cert usage: %s", gotool.ErrInvalidParam, c.Usage)
+	}
+	chains, err := certs[0].Verify(opts)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]*CertInfo, 0, len(chains[0]))
+	for _, cert := range chains[0] {
+		infos = append(infos, newCertInfo(cert))
+	}
+	return infos, nil
+}
@@ -0,0 +1,55 @@
+package cryptoutil
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestParseCert(t *testing.T) {
+	caCert, caKey, err := GenRootCA(CertConfig{CommonName: "UpZero Root CA", KeyType: CertKeyECDSA})
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM, _, err := IssueCert(CertConfig{
+		CommonName:   "getcharzp.cn",
+		Organization: []string{"UpZero"},
+		Hosts:        []string{"getcharzp.cn", "192.168.1.8"},
+		KeyType:      CertKeyECDSA,
+	}, caCert, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	infos, err := ParseCert(certPEM + caCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, len(infos), 2)
+	info := infos[0]
+	testutil.Equal(t, info.CommonName, "getcharzp.cn")
+	testutil.Equal(t, info.Subject, "CN=getcharzp.cn,O=UpZero")
+	testutil.Equal(t, info.Issuer, "CN=UpZero Root CA")
+	testutil.Equal(t, info.DNSNames, []string{"getcharzp.cn"})
+	testutil.Equal(t, info.IPAddresses, []string{"192.168.1.8"})
+	testutil.Equal(t, info.IsCA, false)
+	testutil.Equal(t, info.KeyType, CertKeyECDSA)
+	testutil.Equal(t, info.SignatureAlgorithm, "ECDSA-SHA256")
+	testutil.Equal(t, len(info.Fingerprint), 64)
+	testutil.Equal(t, info.Expired(), false)
+	testutil.Equal(t, info.ExpiresIn() > 364*24*time.Hour, true)
+	testutil.Equal(t, infos[1].IsCA, true)
+
+	// 过期校验
+	_, err = VerifyCert(certPEM, caCert, CertVerifyConfig{Time: time.Now().AddDate(2, 0, 0)})
+	testutil.NotEqual(t, err, nil)
+	_, err = VerifyCert(certPEM, caCert, CertVerifyConfig{Host: "other.cn"})
+	testutil.NotEqual(t, err, nil)
+
+	_, err = ParseCert("invalid")
+	testutil.NotEqual(t, err, nil)
+	_, err = ParseCert(strings.Replace(certPEM, "CERTIFICATE", "PUBLIC KEY", -1))
+	testutil.NotEqual(t, err, nil)
+}
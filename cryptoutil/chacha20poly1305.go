@@ -0,0 +1,274 @@
+package cryptoutil
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+
+	"github.com/up-zero/gotool"
+)
+
+const (
+	chachaKeySize     = 32
+	chachaNonceSize   = 12
+	xchachaNonceSize  = 24
+	poly1305TagSize   = 16
+	chachaBlockSize   = 64
+	chachaConstSigma0 = 0x61707865
+	chachaConstSigma1 = 0x3320646e
+	chachaConstSigma2 = 0x79622d32
+	chachaConstSigma3 = 0x6b206574
+)
+
+// chachaRounds 执行 ChaCha 的 20 轮 (10 次双轮) 运算
+func chachaRounds(x *[16]uint32) {
+	qr := func(a, b, c, d int) {
+		x[a] += x[b]
+		x[d] = bits.RotateLeft32(x[d]^x[a], 16)
+		x[c] += x[d]
+		x[b] = bits.RotateLeft32(x[b]^x[c], 12)
+		x[a] += x[b]
+		x[d] = bits.RotateLeft32(x[d]^x[a], 8)
+		x[c] += x[d]
+		x[b] = bits.RotateLeft32(x[b]^x[c], 7)
+	}
+	for i := 0; i < 10; i++ {
+		// 列
+		qr(0, 4, 8, 12)
+		qr(1, 5, 9, 13)
+		qr(2, 6, 10, 14)
+		qr(3, 7, 11, 15)
+		// 对角线
+		qr(0, 5, 10, 15)
+		qr(1, 6, 11, 12)
+		qr(2, 7, 8, 13)
+		qr(3, 4, 9, 14)
+	}
+}
+
+// chachaInit 初始化状态的常量和密钥部分
+func chachaInit(key []byte) [16]uint32 {
+	s := [16]uint32{chachaConstSigma0, chachaConstSigma1, chachaConstSigma2, chachaConstSigma3}
+	for i := 0; i < 8; i++ {
+		s[4+i] = binary.LittleEndian.Uint32(key[i*4:])
+	}
+	return s
+}
+
+// chacha20XOR 使用 ChaCha20 (RFC 8439) 密钥流异或 src 写入 dst
+//
+// # Params:
+//
+//	dst: 输出, 长度不小于 src
+//	src: 输入
+//	key: 32 字节密钥
+//	nonce: 12 字节随机数
+//	counter: 初始块计数
+func chacha20XOR(dst, src, key, nonce []byte, counter uint32) {
+	s := chachaInit(key)
+	s[13] = binary.LittleEndian.Uint32(nonce[0:])
+	s[14] = binary.LittleEndian.Uint32(nonce[4:])
+	s[15] = binary.LittleEndian.Uint32(nonce[8:])
+
+	var block [chachaBlockSize]byte
+	for len(src) > 0 {
+		s[12] = counter
+		x := s
+		chachaRounds(&x)
+		for i := range x {
+			binary.LittleEndian.PutUint32(block[i*4:], x[i]+s[i])
+		}
+		n := subtle.XORBytes(dst, src, block[:])
+		dst, src = dst[n:], src[n:]
+		counter++
+	}
+}
+
+// hChaCha20 根据密钥和 16 字节随机数派生子密钥 (draft-irtf-cfrg-xchacha)
+func hChaCha20(key, nonce []byte) []byte {
+	x := chachaInit(key)
+	for i := 0; i < 4; i++ {
+		x[12+i] = binary.LittleEndian.Uint32(nonce[i*4:])
+	}
+	chachaRounds(&x)
+
+	out := make([]byte, chachaKeySize)
+	for i := 0; i < 4; i++ {
+		binary.LittleEndian.PutUint32(out[i*4:], x[i])
+		binary.LittleEndian.PutUint32(out[16+i*4:], x[12+i])
+	}
+	return out
+}
+
+// poly1305Sum 计算 Poly1305 消息认证码 (RFC 8439)
+//
+// # Params:
+//
+//	key: 32 字节一次性密钥
+//	msg: 消息
+func poly1305Sum(key, msg []byte) [poly1305TagSize]byte {
+	// r 需要按规范清除部分比特位
+	r0 := binary.LittleEndian.Uint64(key[0:]) & 0x0ffffffc0fffffff
+	r1 := binary.LittleEndian.Uint64(key[8:]) & 0x0ffffffc0ffffffc
+	s0 := binary.LittleEndian.Uint64(key[16:])
+	s1 := binary.LittleEndian.Uint64(key[24:])
+
+	// 累加器 h = h2:h1:h0, 模 2^130-5
+	var h0, h1, h2, c uint64
+	for len(msg) > 0 {
+		var block [poly1305TagSize]byte
+		n := copy(block[:], msg)
+		msg = msg[n:]
+		if n < poly1305TagSize {
+			// 不足一块时在末尾补 1
+			block[n] = 1
+		}
+		h0, c = bits.Add64(h0, binary.LittleEndian.Uint64(block[0:]), 0)
+		h1, c = bits.Add64(h1, binary.LittleEndian.Uint64(block[8:]), c)
+		h2 += c
+		if n == poly1305TagSize {
+			h2++
+		}
+
+		// h *= r, h2 很小且 r 的高位已被清除, h2*r 不会溢出
+		h0r0hi, h0r0lo := bits.Mul64(h0, r0)
+		h1r0hi, h1r0lo := bits.Mul64(h1, r0)
+		h0r1hi, h0r1lo := bits.Mul64(h0, r1)
+		h1r1hi, h1r1lo := bits.Mul64(h1, r1)
+		h2r0 := h2 * r0
+		h2r1 := h2 * r1
+
+		m1lo, c := bits.Add64(h1r0lo, h0r1lo, 0)
+		m1hi, _ := bits.Add64(h1r0hi, h0r1hi, c)
+		m2lo, c := bits.Add64(h2r0, h1r1lo, 0)
+		m2hi, _ := bits.Add64(h1r1hi, 0, c)
+
+		t0 := h0r0lo
+		t1, c := bits.Add64(m1lo, h0r0hi, 0)
+		t2, c := bits.Add64(m2lo, m1hi, c)
+		t3, _ := bits.Add64(h2r1, m2hi, c)
+
+		// 约减: 2^130 ≡ 5, 高位部分 cc 乘 5 = cc*4 + cc 加回低 130 位
+		h0, h1, h2 = t0, t1, t2&3
+		cclo, cchi := t2&^3, t3
+		h0, c = bits.Add64(h0, cclo, 0)
+		h1, c = bits.Add64(h1, cchi, c)
+		h2 += c
+		cclo, cchi = cclo>>2|cchi<<62, cchi>>2
+		h0, c = bits.Add64(h0, cclo, 0)
+		h1, c = bits.Add64(h1, cchi, c)
+		h2 += c
+	}
+
+	// 如果 h >= 2^130-5 则减去模数
+	t0, b := bits.Sub64(h0, 0xfffffffffffffffb, 0)
+	t1, b := bits.Sub64(h1, 0xffffffffffffffff, b)
+	_, b = bits.Sub64(h2, 3, b)
+	mask := b - 1
+	h0 = h0&^mask | t0&mask
+	h1 = h1&^mask | t1&mask
+
+	// tag = (h + s) mod 2^128
+	h0, c = bits.Add64(h0, s0, 0)
+	h1, _ = bits.Add64(h1, s1, c)
+
+	var tag [poly1305TagSize]byte
+	binary.LittleEndian.PutUint64(tag[0:], h0)
+	binary.LittleEndian.PutUint64(tag[8:], h1)
+	return tag
+}
+
+// chacha20Poly1305Tag 计算 AEAD 认证标签
+func chacha20Poly1305Tag(key, nonce, ciphertext, additionalData []byte) [poly1305TagSize]byte {
+	var polyKey [chachaBlockSize]byte
+	chacha20XOR(polyKey[:], polyKey[:], key, nonce, 0)
+
+	pad := func(b []byte) []byte {
+		if r := len(b) % 16; r != 0 {
+			b = append(b, make([]byte, 16-r)...)
+		}
+		return b
+	}
+	mac := make([]byte, 0, len(additionalData)+len(ciphertext)+48)
+	mac = pad(append(mac, additionalData...))
+	mac = pad(append(mac, ciphertext...))
+	mac = binary.LittleEndian.AppendUint64(mac, uint64(len(additionalData)))
+	mac = binary.LittleEndian.AppendUint64(mac, uint64(len(ciphertext)))
+	return poly1305Sum(polyKey[:32], mac)
+}
+
+// xchacha20Poly1305 XChaCha20-Poly1305 AEAD, 使用 24 字节随机数, 可以安全地随机生成
+type xchacha20Poly1305 struct {
+	key [chachaKeySize]byte
+}
+
+// newXChaCha20Poly1305 创建 XChaCha20-Poly1305 AEAD
+//
+// # Params:
+//
+//	key: 32 字节密钥
+func newXChaCha20Poly1305(key []byte) (cipher.AEAD, error) {
+	if len(key) != chachaKeySize {
+		return nil, fmt.Errorf("%w, xchacha20-poly1305 key size=%d", gotool.ErrInvalidParam, len(key))
+	}
+	a := new(xchacha20Poly1305)
+	copy(a.key[:], key)
+	return a, nil
+}
+
+// NonceSize 随机数长度
+func (a *xchacha20Poly1305) NonceSize() int { return xchachaNonceSize }
+
+// Overhead 密文相对明文增加的长度
+func (a *xchacha20Poly1305) Overhead() int { return poly1305TagSize }
+
+// subKey 派生 ChaCha20-Poly1305 的子密钥和 12 字节随机数
+func (a *xchacha20Poly1305) subKey(nonce []byte) (key, chachaNonce []byte) {
+	if len(nonce) != xchachaNonceSize {
+		panic("cryptoutil: incorrect nonce length given to XChaCha20-Poly1305")
+	}
+	chachaNonce = make([]byte, chachaNonceSize)
+	copy(chachaNonce[4:], nonce[16:])
+	return hChaCha20(a.key[:], nonce[:16]), chachaNonce
+}
+
+// Seal 加密并认证
+func (a *xchacha20Poly1305) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	key, chachaNonce := a.subKey(nonce)
+	ret, out := sliceForAppend(dst, len(plaintext)+poly1305TagSize)
+	ct := out[:len(plaintext)]
+	chacha20XOR(ct, plaintext, key, chachaNonce, 1)
+	tag := chacha20Poly1305Tag(key, chachaNonce, ct, additionalData)
+	copy(out[len(plaintext):], tag[:])
+	return ret
+}
+
+// Open 认证并解密
+func (a *xchacha20Poly1305) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < poly1305TagSize {
+		return nil, gotool.ErrDecryptFailed
+	}
+	key, chachaNonce := a.subKey(nonce)
+	ct, tag := ciphertext[:len(ciphertext)-poly1305TagSize], ciphertext[len(ciphertext)-poly1305TagSize:]
+	want := chacha20Poly1305Tag(key, chachaNonce, ct, additionalData)
+	if subtle.ConstantTimeCompare(want[:], tag) != 1 {
+		return nil, gotool.ErrDecryptFailed
+	}
+	ret, out := sliceForAppend(dst, len(ct))
+	chacha20XOR(out, ct, key, chachaNonce, 1)
+	return ret, nil
+}
+
+// sliceForAppend 扩展 in 的长度 n, 返回扩展后的切片和新增部分
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	tail = head[len(in):]
+	return
+}
@@ -0,0 +1,60 @@
+package cryptoutil
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/up-zero/gotool"
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestHChaCha20(t *testing.T) {
+	// draft-irtf-cfrg-xchacha 2.2.1
+	key, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")
+	nonce, _ := hex.DecodeString("000000090000004a0000000031415927")
+	testutil.Equal(t, hex.EncodeToString(hChaCha20(key, nonce)), "82413b4227b27bfed30e42508a877d73a0f9e4d58a74a853c12ec41326d3ecdc")
+}
+
+func TestPoly1305(t *testing.T) {
+	// RFC 8439 2.5.2
+	key, _ := hex.DecodeString("85d6be7857556d337f4452fe42d506a80103808afb0db2fd4abff6af4149f51b")
+	tag := poly1305Sum(key, []byte("Cryptographic Forum Research Group"))
+	testutil.Equal(t, hex.EncodeToString(tag[:]), "a8061dc1305136c6c22b8baf0c0127a9")
+
+	// 累加器接近模数的情况
+	ff := make([]byte, 32)
+	for i := range ff {
+		ff[i] = 0xff
+	}
+	tag = poly1305Sum(ff, ff[:17])
+	testutil.Equal(t, hex.EncodeToString(tag[:]), "7cfe7ff768f81f2763f8bf565df85f86")
+}
+
+func TestXChaCha20Poly1305(t *testing.T) {
+	// draft-irtf-cfrg-xchacha A.3.1
+	key, _ := hex.DecodeString("808182838485868788898a8b8c8d8e8f909192939495969798999a9b9c9d9e9f")
+	nonce, _ := hex.DecodeString("404142434445464748494a4b4c4d4e4f5051525354555657")
+	ad, _ := hex.DecodeString("50515253c0c1c2c3c4c5c6c7")
+	plaintext := "Ladies and Gentlemen of the class of '99: If I could offer you only one tip for the future, sunscreen would be it."
+	want := "bd6d179d3e83d43b9576579493c0e939572a1700252bfaccbed2902c21396cbb731c7f1b0b4aa6440bf3a82f4eda7e39ae64c6708c54c216cb96b72e1213b4522f8c9ba40db5d945b11b69b982c1bb9e3f3fac2bc369488f76b2383565d3fff921f9664c97637da9768812f615c68b13b52e" +
+		"c0875924c1c7987947deafd8780acf49"
+
+	aead, err := newXChaCha20Poly1305(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct := aead.Seal(nil, nonce, []byte(plaintext), ad)
+	testutil.Equal(t, hex.EncodeToString(ct), want)
+
+	pt, err := aead.Open(nil, nonce, ct, ad)
+	testutil.Equal(t, err, nil)
+	testutil.Equal(t, string(pt), plaintext)
+
+	ct[0] ^= 1
+	_, err = aead.Open(nil, nonce, ct, ad)
+	testutil.Equal(t, errors.Is(err, gotool.ErrDecryptFailed), true)
+
+	_, err = newXChaCha20Poly1305(key[:16])
+	testutil.Equal(t, errors.Is(err, gotool.ErrInvalidParam), true)
+}
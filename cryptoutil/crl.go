@@ -0,0 +1,121 @@
+package cryptoutil
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/up-zero/gotool"
+)
+
+// CrlConfig 证书吊销列表 (CRL) 配置，零值字段使用默认值
+type CrlConfig struct {
+	Number  int64    // CRL 序号，必须单调递增，默认使用当前时间戳
+	Days    int      // 有效天数，默认 7
+	Revoked []string // 吊销证书的序列号 (十六进制，与 CertInfo.SerialNumber 相同)
+}
+
+// setDefaults 设置默认值
+func (c *CrlConfig) setDefaults() {
+	if c.Number <= 0 {
+		c.Number = time.Now().Unix()
+	}
+	if c.Days <= 0 {
+		c.Days = 7
+	}
+}
+
+// GenCRL 使用 CA 生成证书吊销列表
+//
+// # Params:
+//
+//	conf: CRL 配置
+//	caCertPEM: CA 证书
+//	caKeyPEM: CA 私钥
+func GenCRL(conf CrlConfig, caCertPEM, caKeyPEM string) (string, error) {
+	conf.setDefaults()
+
+	issuer, err := parseCertIssuer(caCertPEM, caKeyPEM)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	template := &x509.RevocationList{
+		Number:     big.NewInt(conf.Number),
+		ThisUpdate: now,
+		NextUpdate: now.AddDate(0, 0, conf.Days),
+	}
+	for _, s := range conf.Revoked {
+		serial, ok := parseCertSerial(s)
+		if !ok {
+			return "", fmt.Errorf("%w, invalid serial number %q", gotool.ErrInvalidParam, s)
+		}
+		template.RevokedCertificateEntries = append(template.RevokedCertificateEntries, x509.RevocationListEntry{
+			SerialNumber:   serial,
+			RevocationTime: now,
+		})
+	}
+
+	der, err := x509.CreateRevocationList(rand.Reader, template, issuer.cert, issuer.key)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der})), nil
+}
+
+// IsCertRevoked 检查证书是否已被吊销，会校验 CRL 的签名和有效期
+//
+// # Params:
+//
+//	certPEM: 待检查的证书
+//	crlPEM: CRL 字符串
+//	caCertPEM: 签发证书和 CRL 的 CA 证书
+func IsCertRevoked(certPEM, crlPEM, caCertPEM string) (bool, error) {
+	certs, err := parseCertPEM(certPEM)
+	if err != nil {
+		return false, err
+	}
+	cas, err := parseCertPEM(caCertPEM)
+	if err != nil {
+		return false, err
+	}
+	block, _ := pem.Decode([]byte(crlPEM))
+	if block == nil || block.Type != "X509 CRL" {
+		return false, fmt.Errorf("pem decode error: %w", gotool.ErrInvalidParam)
+	}
+	crl, err := x509.ParseRevocationList(block.Bytes)
+	if err != nil {
+		return false, fmt.Errorf("%w, parse CRL: %v", gotool.ErrInvalidParam, err)
+	}
+	if err := crl.CheckSignatureFrom(cas[0]); err != nil {
+		return false, fmt.Errorf("%w, CRL signature: %v", gotool.ErrInvalidParam, err)
+	}
+	if !crl.NextUpdate.IsZero() && time.Now().After(crl.NextUpdate) {
+		return false, fmt.Errorf("%w, CRL expired at %s", gotool.ErrInvalidParam, crl.NextUpdate.Format(time.RFC3339))
+	}
+
+	cert := certs[0]
+	if err := cert.CheckSignatureFrom(cas[0]); err != nil {
+		return false, fmt.Errorf("%w, certificate is not issued by the CA: %v", gotool.ErrInvalidParam, err)
+	}
+	for _, entry := range crl.RevokedCertificateEntries {
+		if entry.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// formatCertSerial 将序列号格式化为十六进制
+func formatCertSerial(serial *big.Int) string {
+	return serial.Text(16)
+}
+
+// parseCertSerial 解析十六进制序列号，允许使用冒号分隔
+func parseCertSerial(s string) (*big.Int, bool) {
+	return new(big.Int).SetString(strings.ReplaceAll(s, ":", ""), 16)
+}
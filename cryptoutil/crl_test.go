@@ -0,0 +1,41 @@
+package cryptoutil
+
+import (
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestGenCRL(t *testing.T) {
+	caCert, caKey, err := GenRootCA(CertConfig{CommonName: "UpZero Root CA", KeyType: CertKeyECDSA})
+	if err != nil {
+		t.Fatal(err)
+	}
+	revokedCert, _, _ := IssueCert(CertConfig{CommonName: "revoked", KeyType: CertKeyECDSA}, caCert, caKey)
+	validCert, _, _ := IssueCert(CertConfig{CommonName: "valid", KeyType: CertKeyECDSA}, caCert, caKey)
+	infos, _ := ParseCert(revokedCert)
+
+	crlPEM, err := GenCRL(CrlConfig{Number: 1, Revoked: []string{infos[0].SerialNumber}}, caCert, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	revoked, err := IsCertRevoked(revokedCert, crlPEM, caCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, revoked, true)
+	revoked, err = IsCertRevoked(validCert, crlPEM, caCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, revoked, false)
+
+	// 其他 CA 签发的 CRL
+	otherCert, otherKey, _ := GenRootCA(CertConfig{CommonName: "Other CA", KeyType: CertKeyECDSA})
+	otherCRL, _ := GenCRL(CrlConfig{}, otherCert, otherKey)
+	_, err = IsCertRevoked(revokedCert, otherCRL, caCert)
+	testutil.NotEqual(t, err, nil)
+
+	_, err = GenCRL(CrlConfig{Revoked: []string{"xyz"}}, caCert, caKey)
+	testutil.NotEqual(t, err, nil)
+}
@@ -0,0 +1,126 @@
+package cryptoutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/up-zero/gotool"
+)
+
+const (
+	// EcdsaP256 NIST P-256 曲线
+	EcdsaP256 = "P-256"
+	// EcdsaP384 NIST P-384 曲线
+	EcdsaP384 = "P-384"
+	// EcdsaP521 NIST P-521 曲线
+	EcdsaP521 = "P-521"
+)
+
+// ecdsaCurve 根据名称获取椭圆曲线
+func ecdsaCurve(name string) (elliptic.Curve, error) {
+	switch name {
+	case EcdsaP256:
+		return elliptic.P256(), nil
+	case EcdsaP384:
+		return elliptic.P384(), nil
+	case EcdsaP521:
+		return elliptic.P521(), nil
+	}
+	return nil, fmt.Errorf("%w, unsupported curve: %s", gotool.ErrInvalidParam, name)
+}
+
+// EcdsaGenerateKey 生成 ECDSA 密钥对，返回 PEM 格式的私钥 (SEC 1) 和公钥 (PKIX) 字符串
+//
+// # Params:
+//
+//	curve: 曲线名称，可选值为 EcdsaP256、EcdsaP384、EcdsaP521
+func EcdsaGenerateKey(curve string) (prvKey, pubKey string, err error) {
+	c, err := ecdsaCurve(curve)
+	if err != nil {
+		return "", "", err
+	}
+	privateKey, err := ecdsa.GenerateKey(c, rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+
+	derPrivateStream, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		return "", "", err
+	}
+	prvKey = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: derPrivateStream}))
+
+	derPublicStream, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return "", "", err
+	}
+	pubKey = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: derPublicStream}))
+
+	return prvKey, pubKey, nil
+}
+
+// EcdsaMarshalPrivateKey 按格式编码 ECDSA 私钥
+//
+// # Params:
+//
+//	key: 私钥
+//	format: 格式，可选值为 KeyFormatPEM (SEC 1)、KeyFormatPKCS8、KeyFormatDER、KeyFormatJWK
+func EcdsaMarshalPrivateKey(key *ecdsa.PrivateKey, format string) ([]byte, error) {
+	return marshalAnyPrivateKey(key, format)
+}
+
+// EcdsaMarshalPublicKey 按格式编码 ECDSA 公钥
+//
+// # Params:
+//
+//	key: 公钥
+//	format: 格式，可选值为 KeyFormatPEM、KeyFormatDER、KeyFormatSSH、KeyFormatJWK
+func EcdsaMarshalPublicKey(key *ecdsa.PublicKey, format string) ([]byte, error) {
+	return marshalAnyPublicKey(key, format)
+}
+
+// EcdsaParsePrivateKey 解析 ECDSA 私钥，自动识别 PEM (SEC 1 或 PKCS8)、DER 和 JWK 格式
+//
+// # Params:
+//
+//	data: 私钥数据
+func EcdsaParsePrivateKey(data []byte) (*ecdsa.PrivateKey, error) {
+	key, err := parseAnyPrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+	if prv, ok := key.(*ecdsa.PrivateKey); ok {
+		return prv, nil
+	}
+	return nil, fmt.Errorf("parse ECDSA private key error: %w", gotool.ErrInvalidParam)
+}
+
+// EcdsaParsePublicKey 解析 ECDSA 公钥，自动识别 PEM (包括证书)、DER、SSH 和 JWK 格式
+//
+// # Params:
+//
+//	data: 公钥数据
+func EcdsaParsePublicKey(data []byte) (*ecdsa.PublicKey, error) {
+	key, err := parseAnyPublicKey(data)
+	if err != nil {
+		return nil, err
+	}
+	if pub, ok := key.(*ecdsa.PublicKey); ok {
+		return pub, nil
+	}
+	return nil, fmt.Errorf("PKIX pub key assert error: %w", gotool.ErrInvalidParam)
+}
+
+// parseEcdsaPublicKey 解析 ECDSA 公钥
+func parseEcdsaPublicKey(pubKeyStr string) (*ecdsa.PublicKey, error) {
+	return EcdsaParsePublicKey([]byte(pubKeyStr))
+}
+
+// parseEcdsaPrivateKey 解析 ECDSA 私钥
+func parseEcdsaPrivateKey(prvKeyStr string) (*ecdsa.PrivateKey, error) {
+	return EcdsaParsePrivateKey([]byte(prvKeyStr))
+}
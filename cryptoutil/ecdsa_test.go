@@ -0,0 +1,31 @@
+package cryptoutil
+
+import (
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestEcdsaGenerateKey(t *testing.T) {
+	for _, curve := range []string{EcdsaP256, EcdsaP384, EcdsaP521} {
+		prvKey, pubKey, err := EcdsaGenerateKey(curve)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prv, err := parseEcdsaPrivateKey(prvKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := parseEcdsaPublicKey(pubKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, prv.Curve.Params().Name, curve)
+		testutil.Equal(t, prv.PublicKey.Equal(pub), true)
+	}
+
+	_, _, err := EcdsaGenerateKey("P-224")
+	testutil.NotEqual(t, err, nil)
+	_, err = parseEcdsaPublicKey(testPrvKey)
+	testutil.NotEqual(t, err, nil)
+}
@@ -0,0 +1,95 @@
+package cryptoutil
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/up-zero/gotool"
+)
+
+// Ed25519GenerateKey 生成 Ed25519 密钥对，返回 PEM 格式的私钥 (PKCS8) 和公钥 (PKIX) 字符串
+func Ed25519GenerateKey() (prvKey, pubKey string, err error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+
+	derPrivateStream, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return "", "", err
+	}
+	prvKey = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: derPrivateStream}))
+
+	derPublicStream, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", "", err
+	}
+	pubKey = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: derPublicStream}))
+
+	return prvKey, pubKey, nil
+}
+
+// Ed25519MarshalPrivateKey 按格式编码 Ed25519 私钥
+//
+// # Params:
+//
+//	key: 私钥
+//	format: 格式，可选值为 KeyFormatPEM (PKCS8)、KeyFormatPKCS8、KeyFormatDER、KeyFormatJWK
+func Ed25519MarshalPrivateKey(key ed25519.PrivateKey, format string) ([]byte, error) {
+	return marshalAnyPrivateKey(key, format)
+}
+
+// Ed25519MarshalPublicKey 按格式编码 Ed25519 公钥
+//
+// # Params:
+//
+//	key: 公钥
+//	format: 格式，可选值为 KeyFormatPEM、KeyFormatDER、KeyFormatSSH、KeyFormatJWK
+func Ed25519MarshalPublicKey(key ed25519.PublicKey, format string) ([]byte, error) {
+	return marshalAnyPublicKey(key, format)
+}
+
+// Ed25519ParsePrivateKey 解析 Ed25519 私钥，自动识别 PEM、DER 和 JWK 格式
+//
+// # Params:
+//
+//	data: 私钥数据
+func Ed25519ParsePrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	key, err := parseAnyPrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+	if prv, ok := key.(ed25519.PrivateKey); ok {
+		return prv, nil
+	}
+	return nil, fmt.Errorf("parse Ed25519 private key error: %w", gotool.ErrInvalidParam)
+}
+
+// Ed25519ParsePublicKey 解析 Ed25519 公钥，自动识别 PEM (包括证书)、DER、SSH 和 JWK 格式
+//
+// # Params:
+//
+//	data: 公钥数据
+func Ed25519ParsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	key, err := parseAnyPublicKey(data)
+	if err != nil {
+		return nil, err
+	}
+	if pub, ok := key.(ed25519.PublicKey); ok {
+		return pub, nil
+	}
+	return nil, fmt.Errorf("PKIX pub key assert error: %w", gotool.ErrInvalidParam)
+}
+
+// parseEd25519PublicKey 解析 Ed25519 公钥
+func parseEd25519PublicKey(pubKeyStr string) (ed25519.PublicKey, error) {
+	return Ed25519ParsePublicKey([]byte(pubKeyStr))
+}
+
+// parseEd25519PrivateKey 解析 Ed25519 私钥
+func parseEd25519PrivateKey(prvKeyStr string) (ed25519.PrivateKey, error) {
+	return Ed25519ParsePrivateKey([]byte(prvKeyStr))
+}
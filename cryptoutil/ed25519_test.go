@@ -0,0 +1,27 @@
+package cryptoutil
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestEd25519GenerateKey(t *testing.T) {
+	prvKey, pubKey, err := Ed25519GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prv, err := parseEd25519PrivateKey(prvKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := parseEd25519PublicKey(pubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, prv.Public().(ed25519.PublicKey).Equal(pub), true)
+
+	_, err = parseEd25519PrivateKey(testPubKey)
+	testutil.NotEqual(t, err, nil)
+}
@@ -0,0 +1,284 @@
+package cryptoutil
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/up-zero/gotool"
+)
+
+// 信封加密格式 (版本 1):
+//
+//	header: magic "GTEV" (4) | version (1) | count uint16 BE (2) | recipients
+//	recipient: type (1) | keyID (8) | size uint16 BE (2) | wrapped key (size)
+//	payload: AES-GCM 分块流 (见 NewAesGcmWriter), 密钥为 HMAC-SHA256(dataKey, header)
+//
+// dataKey 为随机生成的 32 字节密钥, 按接收者分别封装:
+// RSA 使用 RSA-OAEP (SHA-256) 加密; X25519 使用临时密钥协商出共享密钥,
+// 经 HMAC-SHA256 派生出 KEK 后用 AES-256-GCM 加密, 封装结果为 临时公钥 (32) | 密文 (48)
+//
+// keyID 为接收者公钥 PKIX DER 的 SHA-256 前 8 字节, 用于定位自己的封装条目,
+// 负载密钥由 header 派生, 因此对接收者列表的任何修改都会导致解密失败
+const (
+	envelopeMagic         = "GTEV"
+	envelopeVersion       = 1
+	envelopeKeyIDSize     = 8
+	envelopeDataKeySize   = 32
+	envelopeMaxRecipients = 1024
+	envelopeOAEPLabel     = "gotool envelope v1"
+	envelopeX25519Info    = "gotool envelope x25519 v1"
+
+	envelopeRecipientRSA    = 1
+	envelopeRecipientX25519 = 2
+)
+
+// envelopeRecipient 封装条目
+type envelopeRecipient struct {
+	typ     byte
+	keyID   []byte
+	wrapped []byte
+}
+
+// envelopeKeyID 计算公钥的 keyID
+func envelopeKeyID(pub any) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(der)
+	return sum[:envelopeKeyIDSize], nil
+}
+
+// envelopeKek 根据 X25519 共享密钥派生 KEK
+func envelopeKek(shared, ephemeral, recipient []byte) (cipher.AEAD, error) {
+	info := append([]byte(envelopeX25519Info), ephemeral...)
+	info = append(info, recipient...)
+	block, err := aes.NewCipher(hmacGenerate(info, shared, crypto.SHA256))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// wrapDataKey 为接收者封装数据密钥
+//
+// # Params:
+//
+//	dataKey: 数据密钥
+//	recipient: PEM 格式的 RSA 或 X25519 公钥
+func wrapDataKey(dataKey []byte, recipient string) (*envelopeRecipient, error) {
+	if pub, err := parsePublicKey(recipient); err == nil {
+		keyID, err := envelopeKeyID(pub)
+		if err != nil {
+			return nil, err
+		}
+		wrapped, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, dataKey, []byte(envelopeOAEPLabel))
+		if err != nil {
+			return nil, err
+		}
+		return &envelopeRecipient{typ: envelopeRecipientRSA, keyID: keyID, wrapped: wrapped}, nil
+	}
+
+	pub, err := parseX25519PublicKey(recipient)
+	if err != nil {
+		return nil, fmt.Errorf("%w, recipient must be a RSA or X25519 public key", gotool.ErrInvalidParam)
+	}
+	keyID, err := envelopeKeyID(pub)
+	if err != nil {
+		return nil, err
+	}
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := ephemeral.ECDH(pub)
+	if err != nil {
+		return nil, err
+	}
+	kek, err := envelopeKek(shared, ephemeral.PublicKey().Bytes(), pub.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	// KEK 每次均不同, 可以使用全零 nonce
+	wrapped := kek.Seal(ephemeral.PublicKey().Bytes(), make([]byte, kek.NonceSize()), dataKey, nil)
+	return &envelopeRecipient{typ: envelopeRecipientX25519, keyID: keyID, wrapped: wrapped}, nil
+}
+
+// envelopePrivateKey 解密方的私钥
+type envelopePrivateKey struct {
+	typ    byte
+	keyID  []byte
+	rsa    *rsa.PrivateKey
+	x25519 *ecdh.PrivateKey
+}
+
+// parseEnvelopePrivateKey 解析 PEM 格式的 RSA 或 X25519 私钥
+func parseEnvelopePrivateKey(privateKey string) (*envelopePrivateKey, error) {
+	if prv, err := parsePrivateKey(privateKey); err == nil {
+		keyID, err := envelopeKeyID(&prv.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		return &envelopePrivateKey{typ: envelopeRecipientRSA, keyID: keyID, rsa: prv}, nil
+	}
+	prv, err := parseX25519PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w, private key must be a RSA or X25519 private key", gotool.ErrInvalidParam)
+	}
+	keyID, err := envelopeKeyID(prv.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+	return &envelopePrivateKey{typ: envelopeRecipientX25519, keyID: keyID, x25519: prv}, nil
+}
+
+// unwrap 解封数据密钥
+func (k *envelopePrivateKey) unwrap(r *envelopeRecipient) ([]byte, error) {
+	if k.typ == envelopeRecipientRSA {
+		return rsa.DecryptOAEP(sha256.New(), rand.Reader, k.rsa, r.wrapped, []byte(envelopeOAEPLabel))
+	}
+	if len(r.wrapped) != 32+envelopeDataKeySize+16 {
+		return nil, gotool.ErrDecryptFailed
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(r.wrapped[:32])
+	if err != nil {
+		return nil, err
+	}
+	shared, err := k.x25519.ECDH(ephemeral)
+	if err != nil {
+		return nil, err
+	}
+	kek, err := envelopeKek(shared, r.wrapped[:32], k.x25519.PublicKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return kek.Open(nil, make([]byte, kek.NonceSize()), r.wrapped[32:], nil)
+}
+
+// NewEnvelopeWriter 创建信封加密写入器, 随机数据密钥按接收者分别封装, 负载使用 AES-GCM 分块加密
+//
+// 写入完成后必须调用 Close, 任意一个接收者的私钥均可解密
+//
+// # Params:
+//
+//	w: 密文输出
+//	recipients: 接收者 PEM 格式的公钥, 支持 RSA (例如 RsaGenerateKey 生成的公钥) 和 X25519 (例如 X25519GenerateKey 生成的公钥)
+func NewEnvelopeWriter(w io.Writer, recipients ...string) (*AesGcmWriter, error) {
+	if len(recipients) == 0 || len(recipients) > envelopeMaxRecipients {
+		return nil, fmt.Errorf("%w, recipients count %d", gotool.ErrInvalidParam, len(recipients))
+	}
+	dataKey := make([]byte, envelopeDataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, err
+	}
+
+	header := []byte(envelopeMagic)
+	header = append(header, envelopeVersion)
+	header = binary.BigEndian.AppendUint16(header, uint16(len(recipients)))
+	for _, recipient := range recipients {
+		entry, err := wrapDataKey(dataKey, recipient)
+		if err != nil {
+			return nil, err
+		}
+		header = append(header, entry.typ)
+		header = append(header, entry.keyID...)
+		header = binary.BigEndian.AppendUint16(header, uint16(len(entry.wrapped)))
+		header = append(header, entry.wrapped...)
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return NewAesGcmWriter(w, hmacGenerate(header, dataKey, crypto.SHA256))
+}
+
+// NewEnvelopeReader 创建信封解密读取器, 读取 NewEnvelopeWriter 写入的密文
+//
+// # Params:
+//
+//	r: 密文输入
+//	privateKey: 接收者 PEM 格式的 RSA 或 X25519 私钥
+func NewEnvelopeReader(r io.Reader, privateKey string) (*AesGcmReader, error) {
+	prv, err := parseEnvelopePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var header bytes.Buffer
+	tee := io.TeeReader(r, &header)
+	fixed := make([]byte, 7)
+	if _, err := io.ReadFull(tee, fixed); err != nil {
+		return nil, fmt.Errorf("%w, envelope header: %v", gotool.ErrNotSupportFormat, err)
+	}
+	if string(fixed[:4]) != envelopeMagic {
+		return nil, fmt.Errorf("%w, not an envelope", gotool.ErrNotSupportFormat)
+	}
+	if fixed[4] != envelopeVersion {
+		return nil, fmt.Errorf("%w, unsupported envelope version %d", gotool.ErrNotSupportFormat, fixed[4])
+	}
+	count := int(binary.BigEndian.Uint16(fixed[5:]))
+	if count == 0 || count > envelopeMaxRecipients {
+		return nil, fmt.Errorf("%w, envelope recipients count %d", gotool.ErrNotSupportFormat, count)
+	}
+
+	var dataKey []byte
+	for i := 0; i < count; i++ {
+		meta := make([]byte, 1+envelopeKeyIDSize+2)
+		if _, err := io.ReadFull(tee, meta); err != nil {
+			return nil, fmt.Errorf("%w, envelope recipient: %v", gotool.ErrNotSupportFormat, err)
+		}
+		entry := &envelopeRecipient{
+			typ:     meta[0],
+			keyID:   meta[1 : 1+envelopeKeyIDSize],
+			wrapped: make([]byte, binary.BigEndian.Uint16(meta[1+envelopeKeyIDSize:])),
+		}
+		if _, err := io.ReadFull(tee, entry.wrapped); err != nil {
+			return nil, fmt.Errorf("%w, envelope recipient: %v", gotool.ErrNotSupportFormat, err)
+		}
+		if dataKey != nil || entry.typ != prv.typ || !bytes.Equal(entry.keyID, prv.keyID) {
+			continue
+		}
+		if key, err := prv.unwrap(entry); err == nil && len(key) == envelopeDataKeySize {
+			dataKey = key
+		}
+	}
+	if dataKey == nil {
+		return nil, fmt.Errorf("%w, no envelope recipient matches the private key", gotool.ErrDecryptFailed)
+	}
+	return NewAesGcmReader(r, hmacGenerate(header.Bytes(), dataKey, crypto.SHA256))
+}
+
+// EnvelopeEncryptFile 使用信封加密文件, 支持多个接收者
+//
+// # Params:
+//
+//	srcPath: 源文件路径
+//	dstPath: 加密后的文件输出路径
+//	recipients: 接收者 PEM 格式的 RSA 或 X25519 公钥
+func EnvelopeEncryptFile(srcPath, dstPath string, recipients ...string) error {
+	return encryptFile(srcPath, dstPath, func(w io.Writer) (io.WriteCloser, error) {
+		return NewEnvelopeWriter(w, recipients...)
+	})
+}
+
+// EnvelopeDecryptFile 解密 EnvelopeEncryptFile 加密的文件, 认证失败时不会留下不完整的明文
+//
+// # Params:
+//
+//	srcPath: 密文文件路径
+//	dstPath: 解密后的文件输出路径
+//	privateKey: 接收者 PEM 格式的 RSA 或 X25519 私钥
+func EnvelopeDecryptFile(srcPath, dstPath, privateKey string) error {
+	return decryptFile(srcPath, dstPath, func(r io.Reader) (io.Reader, error) {
+		return NewEnvelopeReader(r, privateKey)
+	})
+}
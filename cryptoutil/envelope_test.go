@@ -0,0 +1,128 @@
+package cryptoutil
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/up-zero/gotool"
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestEnvelope(t *testing.T) {
+	rsaPrv, rsaPub, err := RsaGenerateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xPrv, xPub, err := X25519GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPrv, _, _ := X25519GenerateKey()
+
+	plain := make([]byte, 100*1024+7)
+	rand.Read(plain)
+	var buf bytes.Buffer
+	w, err := NewEnvelopeWriter(&buf, rsaPub, xPub, testPrvKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(plain)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	sealed := buf.Bytes()
+
+	decrypt := func(data []byte, prvKey string) ([]byte, error) {
+		r, err := NewEnvelopeReader(bytes.NewReader(data), prvKey)
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(r)
+	}
+
+	// 每个接收者均可解密
+	for _, prvKey := range []string{rsaPrv, xPrv, testPubKey} {
+		got, err := decrypt(sealed, prvKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, bytes.Equal(got, plain), true)
+	}
+
+	// 非接收者无法解密
+	_, err = decrypt(sealed, otherPrv)
+	testutil.Equal(t, errors.Is(err, gotool.ErrDecryptFailed), true)
+
+	// 修改其他接收者的条目同样导致解密失败
+	tampered := append([]byte{}, sealed...)
+	tampered[8] ^= 1 // 第一个接收者的 keyID
+	_, err = decrypt(tampered, xPrv)
+	testutil.Equal(t, errors.Is(err, gotool.ErrDecryptFailed), true)
+
+	// 修改负载
+	tampered = append([]byte{}, sealed...)
+	tampered[len(tampered)-1] ^= 1
+	_, err = decrypt(tampered, xPrv)
+	testutil.Equal(t, errors.Is(err, gotool.ErrDecryptFailed), true)
+
+	_, err = NewEnvelopeWriter(&buf)
+	testutil.Equal(t, errors.Is(err, gotool.ErrInvalidParam), true)
+	_, err = NewEnvelopeWriter(&buf, "not a key")
+	testutil.Equal(t, errors.Is(err, gotool.ErrInvalidParam), true)
+	_, err = decrypt([]byte("GTAE\x01"), xPrv)
+	testutil.Equal(t, errors.Is(err, gotool.ErrNotSupportFormat), true)
+}
+
+func TestEnvelopeFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "plain.txt")
+	enc := filepath.Join(dir, "plain.enc")
+	dec := filepath.Join(dir, "plain.dec.txt")
+	plain := bytes.Repeat([]byte("gotool envelope\n"), 10000)
+	os.WriteFile(src, plain, 0644)
+
+	xPrv, xPub, _ := X25519GenerateKey()
+	if err := EnvelopeEncryptFile(src, enc, xPub, testPrvKey); err != nil {
+		t.Fatal(err)
+	}
+	for _, prvKey := range []string{xPrv, testPubKey} {
+		if err := EnvelopeDecryptFile(enc, dec, prvKey); err != nil {
+			t.Fatal(err)
+		}
+		got, _ := os.ReadFile(dec)
+		testutil.Equal(t, bytes.Equal(got, plain), true)
+	}
+
+	// RsaEncryptFile 使用信封格式, RsaDecryptFile 兼容旧的分块格式
+	if err := RsaEncryptFile(src, enc, testPrvKey); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(enc)
+	testutil.Equal(t, string(data[:4]), envelopeMagic)
+	if err := RsaDecryptFile(enc, dec, testPubKey); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(dec)
+	testutil.Equal(t, bytes.Equal(got, plain), true)
+
+	pub, _ := parsePublicKey(testPrvKey)
+	var legacy bytes.Buffer
+	for chunk := plain[:1000]; len(chunk) > 0; {
+		n := min(len(chunk), pub.Size()-11)
+		sealed, _ := rsa.EncryptPKCS1v15(rand.Reader, pub, chunk[:n])
+		legacy.Write(sealed)
+		chunk = chunk[n:]
+	}
+	os.WriteFile(enc, legacy.Bytes(), 0644)
+	if err := RsaDecryptFile(enc, dec, testPubKey); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = os.ReadFile(dec)
+	testutil.Equal(t, bytes.Equal(got, plain[:1000]), true)
+}
@@ -0,0 +1,223 @@
+package cryptoutil
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"hash/crc64"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/up-zero/gotool"
+)
+
+// 哈希算法
+const (
+	HashMD5        = "md5"
+	HashSHA1       = "sha1"
+	HashSHA224     = "sha224"
+	HashSHA256     = "sha256"
+	HashSHA384     = "sha384"
+	HashSHA512     = "sha512"
+	HashSHA3_224   = "sha3-224"
+	HashSHA3_256   = "sha3-256"
+	HashSHA3_384   = "sha3-384"
+	HashSHA3_512   = "sha3-512"
+	HashBLAKE2b256 = "blake2b-256"
+	HashBLAKE2b512 = "blake2b-512"
+	HashBLAKE2s256 = "blake2s-256"
+	HashSM3        = "sm3"
+	HashCRC32      = "crc32"      // IEEE 多项式
+	HashCRC32C     = "crc32c"     // Castagnoli 多项式
+	HashCRC64ISO   = "crc64-iso"  // ISO 多项式
+	HashCRC64ECMA  = "crc64-ecma" // ECMA 多项式
+	HashXXH64      = "xxh64"      // xxHash64，种子为 0
+)
+
+// hashChunkSize MultiHash 每次读取的数据块大小
+const hashChunkSize = 64 << 10
+
+// hashAlgorithm 哈希算法信息
+type hashAlgorithm struct {
+	new    func() hash.Hash
+	crypto bool // 是否为密码学哈希，只有密码学哈希可用于 HMAC
+}
+
+var hashAlgorithms = map[string]hashAlgorithm{
+	HashMD5:        {md5.New, true},
+	HashSHA1:       {sha1.New, true},
+	HashSHA224:     {sha256.New224, true},
+	HashSHA256:     {sha256.New, true},
+	HashSHA384:     {sha512.New384, true},
+	HashSHA512:     {sha512.New, true},
+	HashSHA3_224:   {func() hash.Hash { return newSha3(28) }, true},
+	HashSHA3_256:   {func() hash.Hash { return newSha3(32) }, true},
+	HashSHA3_384:   {func() hash.Hash { return newSha3(48) }, true},
+	HashSHA3_512:   {func() hash.Hash { return newSha3(64) }, true},
+	HashBLAKE2b256: {func() hash.Hash { h, _ := newBlake2b(32, nil); return h }, true},
+	HashBLAKE2b512: {func() hash.Hash { h, _ := newBlake2b(64, nil); return h }, true},
+	HashBLAKE2s256: {func() hash.Hash { h, _ := newBlake2s(32, nil); return h }, true},
+	HashSM3:        {newSm3, true},
+	HashCRC32:      {func() hash.Hash { return crc32.NewIEEE() }, false},
+	HashCRC32C:     {func() hash.Hash { return crc32.New(crc32.MakeTable(crc32.Castagnoli)) }, false},
+	HashCRC64ISO:   {func() hash.Hash { return crc64.New(crc64.MakeTable(crc64.ISO)) }, false},
+	HashCRC64ECMA:  {func() hash.Hash { return crc64.New(crc64.MakeTable(crc64.ECMA)) }, false},
+	HashXXH64:      {func() hash.Hash { return newXxh64(0) }, false},
+}
+
+// newHash 根据算法名称创建哈希
+func newHash(alg string) (hash.Hash, error) {
+	a, ok := hashAlgorithms[alg]
+	if !ok {
+		return nil, fmt.Errorf("%w, unsupported hash algorithm: %s", gotool.ErrInvalidParam, alg)
+	}
+	return a.new(), nil
+}
+
+// HashSum 哈希值
+type HashSum []byte
+
+// Hex 十六进制编码
+func (s HashSum) Hex() string {
+	return hex.EncodeToString(s)
+}
+
+// Base64 标准 Base64 编码
+func (s HashSum) Base64() string {
+	return base64.StdEncoding.EncodeToString(s)
+}
+
+// Bytes 原始字节
+func (s HashSum) Bytes() []byte {
+	return s
+}
+
+// Hash 流式计算哈希值
+//
+// # Params:
+//
+//	alg: 哈希算法，如 HashSHA256、HashSHA3_256、HashBLAKE2b256、HashCRC32、HashXXH64
+//	r: 数据源
+//
+// # Example:
+//
+//	sum, _ := Hash(HashSHA256, strings.NewReader("hello world"))
+//	sum.Hex() // b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9
+func Hash(alg string, r io.Reader) (HashSum, error) {
+	h, err := newHash(alg)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// HashFile 计算文件的哈希值
+//
+// # Params:
+//
+//	alg: 哈希算法
+//	path: 文件路径
+func HashFile(alg, path string) (HashSum, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Hash(alg, f)
+}
+
+// MultiHash 只读取一遍数据，并行计算多个哈希值
+//
+// # Params:
+//
+//	r: 数据源
+//	algs: 哈希算法列表
+//
+// # Example:
+//
+//	sums, _ := MultiHash(f, HashMD5, HashSHA256)
+//	sums[HashSHA256].Hex()
+func MultiHash(r io.Reader, algs ...string) (map[string]HashSum, error) {
+	if len(algs) == 0 {
+		return nil, fmt.Errorf("%w, no hash algorithm", gotool.ErrInvalidParam)
+	}
+	hashes := make(map[string]hash.Hash, len(algs))
+	for _, alg := range algs {
+		h, err := newHash(alg)
+		if err != nil {
+			return nil, err
+		}
+		hashes[alg] = h
+	}
+
+	// 每个哈希在独立的 goroutine 中计算，数据块只读共享
+	var wg sync.WaitGroup
+	chans := make([]chan []byte, 0, len(hashes))
+	for _, h := range hashes {
+		ch := make(chan []byte, 4)
+		chans = append(chans, ch)
+		wg.Add(1)
+		go func(h hash.Hash) {
+			defer wg.Done()
+			for b := range ch {
+				h.Write(b)
+			}
+		}(h)
+	}
+
+	var readErr error
+	for {
+		buf := make([]byte, hashChunkSize)
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			for _, ch := range chans {
+				ch <- buf[:n]
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			readErr = err
+			break
+		}
+	}
+	for _, ch := range chans {
+		close(ch)
+	}
+	wg.Wait()
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	sums := make(map[string]HashSum, len(hashes))
+	for alg, h := range hashes {
+		sums[alg] = h.Sum(nil)
+	}
+	return sums, nil
+}
+
+// MultiHashFile 只读取一遍文件，并行计算多个哈希值
+//
+// # Params:
+//
+//	path: 文件路径
+//	algs: 哈希算法列表
+func MultiHashFile(path string, algs ...string) (map[string]HashSum, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return MultiHash(f, algs...)
+}
@@ -0,0 +1,88 @@
+package cryptoutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestHash(t *testing.T) {
+	for _, tt := range []struct {
+		alg  string
+		want string
+	}{
+		{HashMD5, "5eb63bbbe01eeed093cb22bb8f5acdc3"},
+		{HashSHA1, "2aae6c35c94fcfb415dbe95f408b9ce91ee846ed"},
+		{HashSHA256, "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"},
+		{HashSHA3_256, "644bcc7e564373040999aac89e7622f3ca71fba1d972fd94a31c3bfbf24e3938"},
+		{HashBLAKE2b256, "256c83b297114d201b30179f3f0ef0cace9783622da5974326b436178aeef610"},
+		{HashBLAKE2s256, "9aec6806794561107e594b1f6a8a6b0c92a0cba9acf5e5e93cca06f781813b0b"},
+		{HashSM3, "44f0061e69fa6fdfc290c494654a05dc0c053da7e5c52b84ef93a9d67d3fff88"},
+		{HashCRC32, "0d4a1185"},
+		{HashXXH64, "45ab6734b21e6968"},
+	} {
+		sum, err := Hash(tt.alg, strings.NewReader("hello world"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, sum.Hex(), tt.want)
+	}
+
+	// 标准校验值 "123456789"
+	for alg, want := range map[string]string{
+		HashCRC32:     "cbf43926",
+		HashCRC32C:    "e3069283",
+		HashCRC64ISO:  "b90956c775a41001",
+		HashCRC64ECMA: "995dc9bbdf1939fa",
+	} {
+		sum, _ := Hash(alg, strings.NewReader("123456789"))
+		testutil.Equal(t, sum.Hex(), want)
+	}
+
+	sum, _ := Hash(HashSHA256, strings.NewReader(""))
+	testutil.Equal(t, sum.Base64(), "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=")
+	testutil.Equal(t, len(sum.Bytes()), 32)
+
+	_, err := Hash("md4", strings.NewReader(""))
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestHashFile(t *testing.T) {
+	sum, err := HashFile(HashSHA256, "../LICENSE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := Sha256File("../LICENSE")
+	testutil.Equal(t, sum.Hex(), want)
+
+	_, err = HashFile(HashSHA256, "not-exist")
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestMultiHash(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 20000)
+	algs := []string{HashMD5, HashSHA256, HashSHA3_512, HashBLAKE2b512, HashCRC64ECMA, HashXXH64}
+	sums, err := MultiHash(bytes.NewReader(data), algs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, len(sums), len(algs))
+	for _, alg := range algs {
+		sum, _ := Hash(alg, bytes.NewReader(data))
+		testutil.Equal(t, sums[alg].Hex(), sum.Hex())
+	}
+
+	sums, err = MultiHashFile("../LICENSE", HashMD5, HashSHA1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := Sha1File("../LICENSE")
+	testutil.Equal(t, sums[HashSHA1].Hex(), want)
+
+	_, err = MultiHash(bytes.NewReader(data))
+	testutil.NotEqual(t, err, nil)
+	_, err = MultiHash(bytes.NewReader(data), HashMD5, "md4")
+	testutil.NotEqual(t, err, nil)
+}
@@ -0,0 +1,71 @@
+package cryptoutil
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"fmt"
+
+	"github.com/up-zero/gotool"
+)
+
+// HkdfExtract HKDF 提取步骤 (RFC 5869)，由输入密钥材料生成伪随机密钥 PRK
+//
+// # Params:
+//
+//	secret: 输入密钥材料
+//	salt: 盐，可为空，为空时使用全零
+//	hash: HMAC 使用的哈希算法, 例如: crypto.SHA256
+func HkdfExtract(secret, salt []byte, hash crypto.Hash) []byte {
+	if len(salt) == 0 {
+		salt = make([]byte, hash.Size())
+	}
+	return hmacGenerate(secret, salt, hash)
+}
+
+// HkdfExpand HKDF 扩展步骤 (RFC 5869)，由 PRK 派生指定长度的密钥
+//
+// # Params:
+//
+//	prk: 伪随机密钥，通常为 HkdfExtract 的输出
+//	info: 上下文信息，不同用途使用不同的 info 可派生出互相独立的密钥
+//	keyLen: 派生密钥的长度，最大为哈希长度的 255 倍
+//	hash: HMAC 使用的哈希算法
+func HkdfExpand(prk, info []byte, keyLen int, hash crypto.Hash) ([]byte, error) {
+	hashLen := hash.Size()
+	if keyLen <= 0 || keyLen > 255*hashLen {
+		return nil, fmt.Errorf("%w, hkdf key length %d out of range", gotool.ErrInvalidParam, keyLen)
+	}
+
+	// T(i) = HMAC(PRK, T(i-1) | info | i)
+	prf := hmac.New(hash.New, prk)
+	okm := make([]byte, 0, keyLen+hashLen)
+	var t []byte
+	for i := byte(1); len(okm) < keyLen; i++ {
+		prf.Reset()
+		prf.Write(t)
+		prf.Write(info)
+		prf.Write([]byte{i})
+		okm = prf.Sum(okm)
+		t = okm[len(okm)-hashLen:]
+	}
+	return okm[:keyLen], nil
+}
+
+// Hkdf HKDF 密钥派生，先提取再扩展
+//
+// # Params:
+//
+//	secret: 输入密钥材料，如主密钥
+//	salt: 盐，可为空
+//	info: 上下文信息，如租户 ID
+//	keyLen: 派生密钥的长度
+//	hash: HMAC 使用的哈希算法
+//
+// # Examples:
+//
+//	// 为每个租户派生独立的 AES-256 密钥
+//	key, _ := Hkdf(masterKey, nil, []byte("tenant:"+tenantID), 32, crypto.SHA256)
+//	cipherText, _ := AesGcmEncrypt(plainText, key)
+func Hkdf(secret, salt, info []byte, keyLen int, hash crypto.Hash) ([]byte, error) {
+	return HkdfExpand(HkdfExtract(secret, salt, hash), info, keyLen, hash)
+}
@@ -0,0 +1,56 @@
+package cryptoutil
+
+import (
+	"crypto"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/up-zero/gotool"
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestHkdf(t *testing.T) {
+	// RFC 5869 A.1
+	ikm, _ := hex.DecodeString("0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b")
+	salt, _ := hex.DecodeString("000102030405060708090a0b0c")
+	info, _ := hex.DecodeString("f0f1f2f3f4f5f6f7f8f9")
+	prk := HkdfExtract(ikm, salt, crypto.SHA256)
+	testutil.Equal(t, hex.EncodeToString(prk), "077709362c2e32df0ddc3f0dc47bba6390b6c73bb50f9c3122ec844ad7c2b3e5")
+	okm, err := HkdfExpand(prk, info, 42, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, hex.EncodeToString(okm), "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865")
+
+	// RFC 5869 A.7, 盐为空
+	ikm, _ = hex.DecodeString("0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c")
+	okm, _ = Hkdf(ikm, nil, nil, 42, crypto.SHA1)
+	testutil.Equal(t, hex.EncodeToString(okm), "2c91117204d745f3500d636a62f64f0ab3bae548aa53d423b0d1f27ebba6f5e5673a081d70cce7acfc48")
+
+	_, err = HkdfExpand(prk, nil, 255*32+1, crypto.SHA256)
+	testutil.Equal(t, errors.Is(err, gotool.ErrInvalidParam), true)
+	_, err = HkdfExpand(prk, nil, 0, crypto.SHA256)
+	testutil.Equal(t, errors.Is(err, gotool.ErrInvalidParam), true)
+}
+
+func TestHkdfTenantKey(t *testing.T) {
+	master := []byte("master")
+	key, err := Hkdf(master, nil, []byte("tenant:42"), 32, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, hex.EncodeToString(key), "061b6604237d71ec05af7c2f5035a8466502491423c3c5defeaf985b5afd654e")
+	other, _ := Hkdf(master, nil, []byte("tenant:43"), 32, crypto.SHA256)
+	testutil.NotEqual(t, hex.EncodeToString(other), hex.EncodeToString(key))
+
+	cipherText, err := AesGcmEncrypt("hello", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plainText, err := AesGcmDecrypt(cipherText, key)
+	testutil.Equal(t, err, nil)
+	testutil.Equal(t, plainText, "hello")
+	_, err = AesGcmDecrypt(cipherText, other)
+	testutil.NotEqual(t, err, nil)
+}
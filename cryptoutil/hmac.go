@@ -3,6 +3,9 @@ package cryptoutil
 import (
 	"crypto"
 	"crypto/hmac"
+	"fmt"
+
+	"github.com/up-zero/gotool"
 )
 
 // hmacGenerate 计算 HMAC
@@ -47,3 +50,69 @@ func HmacSHA384(data []byte, key []byte) []byte {
 func HmacSHA512(data []byte, key []byte) []byte {
 	return hmacGenerate(data, key, crypto.SHA512)
 }
+
+// Hmac 使用指定的哈希算法计算 HMAC
+//
+// # Params:
+//
+//	alg: 哈希算法，如 HashSHA256、HashSHA3_256、HashSM3，不支持 CRC 和 xxHash 等非密码学哈希
+//	data: 数据
+//	key: 密钥
+func Hmac(alg string, data, key []byte) ([]byte, error) {
+	a, ok := hashAlgorithms[alg]
+	if !ok || !a.crypto {
+		return nil, fmt.Errorf("%w, unsupported hmac algorithm: %s", gotool.ErrInvalidParam, alg)
+	}
+	h := hmac.New(a.new, key)
+	h.Write(data)
+	return h.Sum(nil), nil
+}
+
+// HmacVerify 使用常量时间比较校验 HMAC
+//
+// # Params:
+//
+//	alg: 哈希算法
+//	data: 数据
+//	key: 密钥
+//	mac: 待校验的 HMAC
+func HmacVerify(alg string, data, key, mac []byte) (bool, error) {
+	expected, err := Hmac(alg, data, key)
+	if err != nil {
+		return false, err
+	}
+	return hmac.Equal(expected, mac), nil
+}
+
+// HmacVerifySHA256 使用常量时间比较校验 HmacSHA256
+//
+// # Params:
+//
+//	data: 数据
+//	key: 密钥
+//	mac: 待校验的 HMAC
+func HmacVerifySHA256(data, key, mac []byte) bool {
+	return hmac.Equal(HmacSHA256(data, key), mac)
+}
+
+// HmacVerifySHA384 使用常量时间比较校验 HmacSHA384
+//
+// # Params:
+//
+//	data: 数据
+//	key: 密钥
+//	mac: 待校验的 HMAC
+func HmacVerifySHA384(data, key, mac []byte) bool {
+	return hmac.Equal(HmacSHA384(data, key), mac)
+}
+
+// HmacVerifySHA512 使用常量时间比较校验 HmacSHA512
+//
+// # Params:
+//
+//	data: 数据
+//	key: 密钥
+//	mac: 待校验的 HMAC
+func HmacVerifySHA512(data, key, mac []byte) bool {
+	return hmac.Equal(HmacSHA512(data, key), mac)
+}
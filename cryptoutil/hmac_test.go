@@ -0,0 +1,41 @@
+package cryptoutil
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestHmac(t *testing.T) {
+	data := []byte("The quick brown fox jumps over the lazy dog")
+	key := []byte("key")
+	mac, err := Hmac(HashSHA256, data, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, hex.EncodeToString(mac), "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8")
+
+	mac, _ = Hmac(HashSHA3_256, data, key)
+	testutil.Equal(t, hex.EncodeToString(mac), "8c6e0683409427f8931711b10ca92a506eb1fafa48fadd66d76126f47ac2c333")
+
+	_, err = Hmac(HashCRC32, data, key)
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestHmacVerify(t *testing.T) {
+	data := []byte("hello world")
+	key := []byte("secret")
+	ok, err := HmacVerify(HashSHA512, data, key, HmacSHA512(data, key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, ok, true)
+	ok, _ = HmacVerify(HashSHA512, data, []byte("other"), HmacSHA512(data, key))
+	testutil.Equal(t, ok, false)
+
+	testutil.Equal(t, HmacVerifySHA256(data, key, HmacSHA256(data, key)), true)
+	testutil.Equal(t, HmacVerifySHA384(data, key, HmacSHA384(data, key)), true)
+	testutil.Equal(t, HmacVerifySHA512(data, key, HmacSHA512(data, key)), true)
+	testutil.Equal(t, HmacVerifySHA256(data, key, HmacSHA256(data, key)[:16]), false)
+}
@@ -0,0 +1,659 @@
+package cryptoutil
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/up-zero/gotool"
+)
+
+// Jwk JSON Web Key (RFC 7517), 支持 RSA、EC、oct、OKP (Ed25519、X25519)
+type Jwk struct {
+	Kty string `json:"kty"`           // 密钥类型: RSA, EC, oct, OKP
+	Kid string `json:"kid,omitempty"` // 密钥 ID
+	Use string `json:"use,omitempty"` // 用途: sig, enc
+	Alg string `json:"alg,omitempty"` // 算法
+
+	// RSA
+	N  string `json:"n,omitempty"`
+	E  string `json:"e,omitempty"`
+	P  string `json:"p,omitempty"`
+	Q  string `json:"q,omitempty"`
+	Dp string `json:"dp,omitempty"`
+	Dq string `json:"dq,omitempty"`
+	Qi string `json:"qi,omitempty"`
+
+	// EC, OKP
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+
+	// 私钥 (RSA, EC, OKP)
+	D string `json:"d,omitempty"`
+
+	// oct
+	K string `json:"k,omitempty"`
+}
+
+// jwkEncode 大整数编码为 base64url, size 大于 0 时左补零到固定长度
+func jwkEncode(n *big.Int, size int) string {
+	if size > 0 {
+		return base64.RawURLEncoding.EncodeToString(n.FillBytes(make([]byte, size)))
+	}
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
+
+// jwkDecode 解码 base64url 字段
+func jwkDecode(name, value string) ([]byte, error) {
+	if value == "" {
+		return nil, fmt.Errorf("%w, jwk missing %s", gotool.ErrInvalidParam, name)
+	}
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w, jwk invalid %s", gotool.ErrInvalidParam, name)
+	}
+	return data, nil
+}
+
+// jwkDecodeInt 解码大整数字段
+func jwkDecodeInt(name, value string) (*big.Int, error) {
+	data, err := jwkDecode(name, value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+// JwkFromKey 将密钥转换为 JWK
+//
+// # Params:
+//
+//	key: 密钥, 支持 *rsa.PrivateKey, *rsa.PublicKey, *ecdsa.PrivateKey, *ecdsa.PublicKey,
+//	     ed25519.PrivateKey, ed25519.PublicKey, *ecdh.PrivateKey, *ecdh.PublicKey (X25519), []byte (oct)
+//	kid: 密钥 ID, 为空时使用 RFC 7638 指纹
+func JwkFromKey(key any, kid string) (*Jwk, error) {
+	jwk := new(Jwk)
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if len(k.Primes) != 2 {
+			return nil, fmt.Errorf("%w, multi-prime rsa key", gotool.ErrInvalidParam)
+		}
+		k.Precompute()
+		jwk = rsaPublicJwk(&k.PublicKey)
+		jwk.D = jwkEncode(k.D, 0)
+		jwk.P = jwkEncode(k.Primes[0], 0)
+		jwk.Q = jwkEncode(k.Primes[1], 0)
+		jwk.Dp = jwkEncode(k.Precomputed.Dp, 0)
+		jwk.Dq = jwkEncode(k.Precomputed.Dq, 0)
+		jwk.Qi = jwkEncode(k.Precomputed.Qinv, 0)
+	case *rsa.PublicKey:
+		jwk = rsaPublicJwk(k)
+	case *ecdsa.PrivateKey:
+		pub, err := ecdsaPublicJwk(&k.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		jwk = pub
+		jwk.D = jwkEncode(k.D, (k.Curve.Params().BitSize+7)/8)
+	case *ecdsa.PublicKey:
+		pub, err := ecdsaPublicJwk(k)
+		if err != nil {
+			return nil, err
+		}
+		jwk = pub
+	case ed25519.PrivateKey:
+		if len(k) != ed25519.PrivateKeySize {
+			return nil, fmt.Errorf("%w, ed25519 private key size", gotool.ErrInvalidParam)
+		}
+		jwk.Kty, jwk.Crv = "OKP", "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(k.Public().(ed25519.PublicKey))
+		jwk.D = base64.RawURLEncoding.EncodeToString(k.Seed())
+	case ed25519.PublicKey:
+		if len(k) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w, ed25519 public key size", gotool.ErrInvalidParam)
+		}
+		jwk.Kty, jwk.Crv = "OKP", "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(k)
+	case *ecdh.PrivateKey:
+		if k.Curve() != ecdh.X25519() {
+			return nil, fmt.Errorf("%w, only X25519 ecdh keys are supported", gotool.ErrInvalidParam)
+		}
+		jwk.Kty, jwk.Crv = "OKP", "X25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(k.PublicKey().Bytes())
+		jwk.D = base64.RawURLEncoding.EncodeToString(k.Bytes())
+	case *ecdh.PublicKey:
+		if k.Curve() != ecdh.X25519() {
+			return nil, fmt.Errorf("%w, only X25519 ecdh keys are supported", gotool.ErrInvalidParam)
+		}
+		jwk.Kty, jwk.Crv = "OKP", "X25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(k.Bytes())
+	case []byte:
+		if len(k) == 0 {
+			return nil, fmt.Errorf("%w, oct key", gotool.ErrCannotBeEmpty)
+		}
+		jwk.Kty = "oct"
+		jwk.K = base64.RawURLEncoding.EncodeToString(k)
+	default:
+		return nil, fmt.Errorf("%w, unsupported key type %T", gotool.ErrNotSupportType, key)
+	}
+
+	jwk.Kid = kid
+	if jwk.Kid == "" {
+		thumbprint, err := jwk.Thumbprint()
+		if err != nil {
+			return nil, err
+		}
+		jwk.Kid = thumbprint
+	}
+	return jwk, nil
+}
+
+// rsaPublicJwk RSA 公钥转换为 JWK
+func rsaPublicJwk(pub *rsa.PublicKey) *Jwk {
+	return &Jwk{Kty: "RSA", N: jwkEncode(pub.N, 0), E: jwkEncode(big.NewInt(int64(pub.E)), 0)}
+}
+
+// ecdsaPublicJwk ECDSA 公钥转换为 JWK
+func ecdsaPublicJwk(pub *ecdsa.PublicKey) (*Jwk, error) {
+	crv := pub.Curve.Params().Name
+	if _, err := ecdsaCurve(crv); err != nil {
+		return nil, err
+	}
+	size := (pub.Curve.Params().BitSize + 7) / 8
+	return &Jwk{Kty: "EC", Crv: crv, X: jwkEncode(pub.X, size), Y: jwkEncode(pub.Y, size)}, nil
+}
+
+// ParseJwk 解析 JWK JSON
+//
+// # Params:
+//
+//	data: JWK JSON 数据
+func ParseJwk(data []byte) (*Jwk, error) {
+	jwk := new(Jwk)
+	if err := json.Unmarshal(data, jwk); err != nil {
+		return nil, err
+	}
+	// 校验密钥参数
+	if _, err := jwk.Key(); err != nil {
+		return nil, err
+	}
+	return jwk, nil
+}
+
+// IsPrivate 是否包含私钥 (oct 视为私钥)
+func (j *Jwk) IsPrivate() bool {
+	return j.D != "" || j.Kty == "oct"
+}
+
+// Public 返回只包含公钥参数的副本, oct 密钥返回错误
+func (j *Jwk) Public() (*Jwk, error) {
+	if j.Kty == "oct" {
+		return nil, fmt.Errorf("%w, oct key has no public part", gotool.ErrInvalidParam)
+	}
+	pub := *j
+	pub.D, pub.P, pub.Q, pub.Dp, pub.Dq, pub.Qi = "", "", "", "", "", ""
+	return &pub, nil
+}
+
+// Key 转换为密钥, 包含私钥参数时返回私钥
+//
+// RSA: *rsa.PrivateKey 或 *rsa.PublicKey
+// EC: *ecdsa.PrivateKey 或 *ecdsa.PublicKey
+// OKP Ed25519: ed25519.PrivateKey 或 ed25519.PublicKey
+// OKP X25519: *ecdh.PrivateKey 或 *ecdh.PublicKey
+// oct: []byte
+func (j *Jwk) Key() (any, error) {
+	switch j.Kty {
+	case "RSA":
+		n, err := jwkDecodeInt("n", j.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := jwkDecodeInt("e", j.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("%w, jwk invalid e", gotool.ErrInvalidParam)
+		}
+		pub := rsa.PublicKey{N: n, E: int(e.Int64())}
+		if j.D == "" {
+			return &pub, nil
+		}
+		d, err := jwkDecodeInt("d", j.D)
+		if err != nil {
+			return nil, err
+		}
+		p, err := jwkDecodeInt("p", j.P)
+		if err != nil {
+			return nil, err
+		}
+		q, err := jwkDecodeInt("q", j.Q)
+		if err != nil {
+			return nil, err
+		}
+		prv := &rsa.PrivateKey{PublicKey: pub, D: d, Primes: []*big.Int{p, q}}
+		if err := prv.Validate(); err != nil {
+			return nil, fmt.Errorf("%w, jwk invalid rsa key: %v", gotool.ErrInvalidParam, err)
+		}
+		prv.Precompute()
+		return prv, nil
+	case "EC":
+		curve, err := ecdsaCurve(j.Crv)
+		if err != nil {
+			return nil, err
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		x, err := jwkDecode("x", j.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := jwkDecode("y", j.Y)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != size || len(y) != size {
+			return nil, fmt.Errorf("%w, jwk invalid ec coordinate size", gotool.ErrInvalidParam)
+		}
+		pub := ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		ecdhPub, err := pub.ECDH()
+		if err != nil {
+			return nil, fmt.Errorf("%w, jwk point is not on curve", gotool.ErrInvalidParam)
+		}
+		if j.D == "" {
+			return &pub, nil
+		}
+		d, err := jwkDecode("d", j.D)
+		if err != nil {
+			return nil, err
+		}
+		if len(d) != size {
+			return nil, fmt.Errorf("%w, jwk invalid ec private key size", gotool.ErrInvalidParam)
+		}
+		prv := &ecdsa.PrivateKey{PublicKey: pub, D: new(big.Int).SetBytes(d)}
+		ecdhPrv, err := prv.ECDH()
+		if err != nil || !ecdhPrv.PublicKey().Equal(ecdhPub) {
+			return nil, fmt.Errorf("%w, jwk ec private key does not match public key", gotool.ErrInvalidParam)
+		}
+		return prv, nil
+	case "OKP":
+		x, err := jwkDecode("x", j.X)
+		if err != nil {
+			return nil, err
+		}
+		switch j.Crv {
+		case "Ed25519":
+			if len(x) != ed25519.PublicKeySize {
+				return nil, fmt.Errorf("%w, jwk invalid ed25519 public key size", gotool.ErrInvalidParam)
+			}
+			if j.D == "" {
+				return ed25519.PublicKey(x), nil
+			}
+			d, err := jwkDecode("d", j.D)
+			if err != nil {
+				return nil, err
+			}
+			if len(d) != ed25519.SeedSize {
+				return nil, fmt.Errorf("%w, jwk invalid ed25519 private key size", gotool.ErrInvalidParam)
+			}
+			prv := ed25519.NewKeyFromSeed(d)
+			if !prv.Public().(ed25519.PublicKey).Equal(ed25519.PublicKey(x)) {
+				return nil, fmt.Errorf("%w, jwk ed25519 private key does not match public key", gotool.ErrInvalidParam)
+			}
+			return prv, nil
+		case "X25519":
+			pub, err := ecdh.X25519().NewPublicKey(x)
+			if err != nil {
+				return nil, fmt.Errorf("%w, jwk invalid x25519 public key", gotool.ErrInvalidParam)
+			}
+			if j.D == "" {
+				return pub, nil
+			}
+			d, err := jwkDecode("d", j.D)
+			if err != nil {
+				return nil, err
+			}
+			prv, err := ecdh.X25519().NewPrivateKey(d)
+			if err != nil || !prv.PublicKey().Equal(pub) {
+				return nil, fmt.Errorf("%w, jwk x25519 private key does not match public key", gotool.ErrInvalidParam)
+			}
+			return prv, nil
+		}
+		return nil, fmt.Errorf("%w, unsupported OKP curve: %s", gotool.ErrInvalidParam, j.Crv)
+	case "oct":
+		return jwkDecode("k", j.K)
+	}
+	return nil, fmt.Errorf("%w, unsupported kty: %s", gotool.ErrNotSupportType, j.Kty)
+}
+
+// Thumbprint 计算 RFC 7638 JWK 指纹 (SHA-256, base64url)
+func (j *Jwk) Thumbprint() (string, error) {
+	// 必需成员按字典序排列, encoding/json 对 map 的键排序
+	var members map[string]string
+	switch j.Kty {
+	case "RSA":
+		members = map[string]string{"e": j.E, "kty": j.Kty, "n": j.N}
+	case "EC":
+		members = map[string]string{"crv": j.Crv, "kty": j.Kty, "x": j.X, "y": j.Y}
+	case "OKP":
+		members = map[string]string{"crv": j.Crv, "kty": j.Kty, "x": j.X}
+	case "oct":
+		members = map[string]string{"k": j.K, "kty": j.Kty}
+	default:
+		return "", fmt.Errorf("%w, unsupported kty: %s", gotool.ErrNotSupportType, j.Kty)
+	}
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// defaultAlg 密钥默认的签名算法
+func (j *Jwk) defaultAlg() string {
+	if j.Alg != "" {
+		return j.Alg
+	}
+	switch j.Kty {
+	case "RSA":
+		return JwtRS256
+	case "EC":
+		switch j.Crv {
+		case EcdsaP384:
+			return JwtES384
+		case EcdsaP521:
+			return JwtES512
+		}
+		return JwtES256
+	case "OKP":
+		if j.Crv == "Ed25519" {
+			return JwtEdDSA
+		}
+	case "oct":
+		return JwtHS256
+	}
+	return ""
+}
+
+// jwkSet JWKS JSON 结构
+type jwkSet struct {
+	Keys []*Jwk `json:"keys"`
+}
+
+// KeySet JWK 密钥集, 按 kid 索引, 并发安全
+//
+// 可作为 JwtVerifyConfig.KeyFunc 根据令牌头部的 kid 选择验证密钥, 配合 SetRefresh 实现密钥轮换
+type KeySet struct {
+	mu          sync.RWMutex
+	keys        []*Jwk
+	refresh     func() ([]byte, error)
+	interval    time.Duration
+	lastRefresh time.Time
+	refreshMu   sync.Mutex
+}
+
+// NewKeySet 创建空的密钥集
+func NewKeySet() *KeySet {
+	return &KeySet{}
+}
+
+// ParseKeySet 解析 JWKS JSON, 格式为 {"keys": [...]}
+//
+// # Params:
+//
+//	data: JWKS JSON 数据
+func ParseKeySet(data []byte) (*KeySet, error) {
+	keys, err := parseJwkSet(data)
+	if err != nil {
+		return nil, err
+	}
+	return &KeySet{keys: keys}, nil
+}
+
+// parseJwkSet 解析并校验 JWKS
+func parseJwkSet(data []byte) ([]*Jwk, error) {
+	var set jwkSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk == nil {
+			return nil, fmt.Errorf("%w, null jwk", gotool.ErrInvalidParam)
+		}
+		if _, err := jwk.Key(); err != nil {
+			return nil, err
+		}
+		if seen[jwk.Kid] {
+			return nil, fmt.Errorf("%w, duplicate kid %q", gotool.ErrInvalidParam, jwk.Kid)
+		}
+		seen[jwk.Kid] = true
+	}
+	return set.Keys, nil
+}
+
+// Add 添加密钥, 已存在相同 kid 时替换
+//
+// # Params:
+//
+//	key: 密钥, 支持类型同 JwkFromKey
+//	kid: 密钥 ID, 为空时使用 RFC 7638 指纹
+//	alg: 算法, 可选, 设置后只接受该算法签名的令牌
+func (s *KeySet) Add(key any, kid string, alg ...string) (*Jwk, error) {
+	jwk, err := JwkFromKey(key, kid)
+	if err != nil {
+		return nil, err
+	}
+	if len(alg) > 0 {
+		jwk.Alg = alg[0]
+	}
+	return jwk, s.AddJwk(jwk)
+}
+
+// AddJwk 添加 JWK, 已存在相同 kid 时替换
+//
+// # Params:
+//
+//	jwk: JWK
+func (s *KeySet) AddJwk(jwk *Jwk) error {
+	if _, err := jwk.Key(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, k := range s.keys {
+		if k.Kid == jwk.Kid {
+			s.keys[i] = jwk
+			return nil
+		}
+	}
+	s.keys = append(s.keys, jwk)
+	return nil
+}
+
+// Remove 删除密钥
+//
+// # Params:
+//
+//	kid: 密钥 ID
+func (s *KeySet) Remove(kid string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, k := range s.keys {
+		if k.Kid == kid {
+			s.keys = append(s.keys[:i], s.keys[i+1:]...)
+			return
+		}
+	}
+}
+
+// Get 根据 kid 获取 JWK
+//
+// # Params:
+//
+//	kid: 密钥 ID
+func (s *KeySet) Get(kid string) (*Jwk, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, k := range s.keys {
+		if k.Kid == kid {
+			return k, true
+		}
+	}
+	return nil, false
+}
+
+// Kids 返回全部 kid, 按添加顺序排列
+func (s *KeySet) Kids() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	kids := make([]string, len(s.keys))
+	for i, k := range s.keys {
+		kids[i] = k.Kid
+	}
+	return kids
+}
+
+// Len 密钥数量
+func (s *KeySet) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.keys)
+}
+
+// MarshalJSON 导出完整的 JWKS JSON (包含私钥)
+func (s *KeySet) MarshalJSON() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return json.Marshal(jwkSet{Keys: append([]*Jwk{}, s.keys...)})
+}
+
+// PublicJSON 导出只包含公钥的 JWKS JSON, 可直接对外发布, oct 密钥被忽略
+func (s *KeySet) PublicJSON() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	set := jwkSet{Keys: make([]*Jwk, 0, len(s.keys))}
+	for _, k := range s.keys {
+		if k.Kty == "oct" {
+			continue
+		}
+		pub, err := k.Public()
+		if err != nil {
+			return nil, err
+		}
+		set.Keys = append(set.Keys, pub)
+	}
+	return json.Marshal(set)
+}
+
+// SetRefresh 设置刷新回调, 遇到未知 kid 时自动调用以重新加载密钥
+//
+// # Params:
+//
+//	fn: 刷新回调, 返回 JWKS JSON, 例如读取文件或请求 jwks_uri
+//	interval: 两次自动刷新的最小间隔, 防止未知 kid 导致频繁刷新
+func (s *KeySet) SetRefresh(fn func() ([]byte, error), interval time.Duration) {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+	s.refresh = fn
+	s.interval = interval
+}
+
+// Refresh 立即调用刷新回调, 以返回的 JWKS 整体替换当前密钥
+func (s *KeySet) Refresh() error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+	return s.refreshLocked()
+}
+
+// refreshLocked 执行刷新, 调用方持有 refreshMu
+func (s *KeySet) refreshLocked() error {
+	if s.refresh == nil {
+		return fmt.Errorf("%w, refresh callback", gotool.ErrCannotBeEmpty)
+	}
+	s.lastRefresh = time.Now()
+	data, err := s.refresh()
+	if err != nil {
+		return err
+	}
+	keys, err := parseJwkSet(data)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+	return nil
+}
+
+// lookup 根据 kid 查找 JWK, kid 为空且只有一个密钥时返回该密钥
+func (s *KeySet) lookup(kid string) (*Jwk, bool) {
+	if kid == "" {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		if len(s.keys) == 1 {
+			return s.keys[0], true
+		}
+		return nil, false
+	}
+	return s.Get(kid)
+}
+
+// KeyFunc 根据令牌头部选择验证密钥, 用于 JwtVerifyConfig.KeyFunc
+//
+// 找不到 kid 时, 若设置了刷新回调且距上次刷新超过最小间隔, 则刷新后重试;
+// 密钥设置了 alg 时必须与令牌头部一致, use 为 enc 的密钥不能用于验证
+func (s *KeySet) KeyFunc(header *JwtHeader) (any, error) {
+	jwk, ok := s.lookup(header.Kid)
+	if !ok {
+		s.refreshMu.Lock()
+		if s.refresh != nil && time.Since(s.lastRefresh) >= s.interval {
+			if err := s.refreshLocked(); err != nil {
+				s.refreshMu.Unlock()
+				return nil, err
+			}
+		}
+		s.refreshMu.Unlock()
+		if jwk, ok = s.lookup(header.Kid); !ok {
+			return nil, fmt.Errorf("%w, kid %q", gotool.ErrJwkNotFound, header.Kid)
+		}
+	}
+
+	if jwk.Use == "enc" {
+		return nil, fmt.Errorf("%w, key %q is not for signature", gotool.ErrInvalidJwtAlgorithm, jwk.Kid)
+	}
+	if jwk.Alg != "" && jwk.Alg != header.Alg {
+		return nil, fmt.Errorf("%w, key %q requires %s", gotool.ErrInvalidJwtAlgorithm, jwk.Kid, jwk.Alg)
+	}
+	return jwk.Key()
+}
+
+// SignConfig 生成使用指定密钥签名的配置, 算法优先使用 JWK 的 alg, 否则按密钥类型推断
+//
+// # Params:
+//
+//	kid: 密钥 ID
+func (s *KeySet) SignConfig(kid string) (JwtSignConfig, error) {
+	jwk, ok := s.Get(kid)
+	if !ok {
+		return JwtSignConfig{}, fmt.Errorf("%w, kid %q", gotool.ErrJwkNotFound, kid)
+	}
+	if !jwk.IsPrivate() {
+		return JwtSignConfig{}, fmt.Errorf("%w, key %q has no private part", gotool.ErrInvalidParam, kid)
+	}
+	key, err := jwk.Key()
+	if err != nil {
+		return JwtSignConfig{}, err
+	}
+	return JwtSignConfig{Algorithm: jwk.defaultAlg(), Key: key, KeyID: jwk.Kid}, nil
+}
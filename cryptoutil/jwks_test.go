@@ -0,0 +1,225 @@
+package cryptoutil
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/up-zero/gotool"
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestJwkThumbprint(t *testing.T) {
+	// RFC 7638 3.1
+	jwk, err := ParseJwk([]byte(`{"kty":"RSA","e":"AQAB","alg":"RS256","kid":"2011-04-29",
+		"n":"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	thumbprint, err := jwk.Thumbprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, thumbprint, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs")
+
+	// RFC 8037 A.1, A.3
+	jwk, err = ParseJwk([]byte(`{"kty":"OKP","crv":"Ed25519",
+		"d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A",
+		"x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	thumbprint, _ = jwk.Thumbprint()
+	testutil.Equal(t, thumbprint, "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k")
+	key, _ := jwk.Key()
+	testutil.Equal(t, len(key.(ed25519.PrivateKey)), ed25519.PrivateKeySize)
+
+	// RFC 7517 A.1
+	jwk, err = ParseJwk([]byte(`{"kty":"EC","crv":"P-256","use":"enc","kid":"1",
+		"x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4",
+		"y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, jwk.IsPrivate(), false)
+
+	// 点不在曲线上
+	_, err = ParseJwk([]byte(`{"kty":"EC","crv":"P-256",
+		"x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4",
+		"y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyA"}`))
+	testutil.Equal(t, errors.Is(err, gotool.ErrInvalidParam), true)
+}
+
+func TestJwkFromKey(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	xKey, _ := ecdh.X25519().GenerateKey(rand.Reader)
+
+	keys := []any{
+		rsaKey, &rsaKey.PublicKey,
+		ecKey, &ecKey.PublicKey,
+		edKey, edKey.Public(),
+		xKey, xKey.PublicKey(),
+		[]byte("secret"),
+	}
+	for _, key := range keys {
+		jwk, err := JwkFromKey(key, "")
+		if err != nil {
+			t.Fatalf("%T: %v", key, err)
+		}
+		thumbprint, _ := jwk.Thumbprint()
+		testutil.Equal(t, jwk.Kid, thumbprint)
+
+		// JSON 往返后得到相同的密钥
+		data, _ := json.Marshal(jwk)
+		parsed, err := ParseJwk(data)
+		if err != nil {
+			t.Fatalf("%T: %v", key, err)
+		}
+		got, err := parsed.Key()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if k, ok := got.(interface{ Equal(x crypto.PrivateKey) bool }); ok {
+			testutil.Equal(t, k.Equal(key), true)
+		} else if k, ok := got.(interface{ Equal(x crypto.PublicKey) bool }); ok {
+			testutil.Equal(t, k.Equal(key), true)
+		} else {
+			testutil.Equal(t, got, key)
+		}
+	}
+
+	_, err := JwkFromKey("secret", "k")
+	testutil.Equal(t, errors.Is(err, gotool.ErrNotSupportType), true)
+}
+
+func TestKeySet(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	set := NewKeySet()
+	if _, err := set.Add(rsaKey, "rsa-1", JwtPS256); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := set.Add(edKey, "ed-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := set.Add([]byte("hmac-secret"), "hs-1"); err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, set.Kids(), []string{"rsa-1", "ed-1", "hs-1"})
+
+	// 按 kid 签名、验证
+	for _, kid := range set.Kids() {
+		conf, err := set.SignConfig(kid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		token, err := JWTSign(JwtRegisteredClaims{Subject: kid}, conf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var claims JwtRegisteredClaims
+		header, err := JWTVerify(token, &claims, JwtVerifyConfig{KeyFunc: set.KeyFunc})
+		if err != nil {
+			t.Fatal(kid, err)
+		}
+		testutil.Equal(t, header.Kid, kid)
+		testutil.Equal(t, claims.Subject, kid)
+	}
+
+	// 密钥指定了 alg 时拒绝其他算法
+	token, _ := JWTSign(JwtRegisteredClaims{}, JwtSignConfig{Algorithm: JwtRS256, Key: rsaKey, KeyID: "rsa-1"})
+	_, err := JWTVerify(token, nil, JwtVerifyConfig{KeyFunc: set.KeyFunc})
+	testutil.Equal(t, errors.Is(err, gotool.ErrInvalidJwtAlgorithm), true)
+
+	// 未知 kid
+	token, _ = JWTSign(JwtRegisteredClaims{}, JwtSignConfig{Algorithm: JwtEdDSA, Key: edKey, KeyID: "ed-2"})
+	_, err = JWTVerify(token, nil, JwtVerifyConfig{KeyFunc: set.KeyFunc})
+	testutil.Equal(t, errors.Is(err, gotool.ErrJwkNotFound), true)
+
+	// 公开的 JWKS 不包含私钥和 oct 密钥
+	public, err := set.PublicJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, strings.Contains(string(public), `"d"`), false)
+	publicSet, err := ParseKeySet(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, publicSet.Kids(), []string{"rsa-1", "ed-1"})
+	token, _ = JWTSign(JwtRegisteredClaims{}, JwtSignConfig{Algorithm: JwtEdDSA, Key: edKey, KeyID: "ed-1"})
+	_, err = JWTVerify(token, nil, JwtVerifyConfig{KeyFunc: publicSet.KeyFunc})
+	testutil.Equal(t, err, nil)
+	_, err = publicSet.SignConfig("ed-1")
+	testutil.Equal(t, errors.Is(err, gotool.ErrInvalidParam), true)
+
+	// 完整导出后重新导入
+	data, _ := json.Marshal(set)
+	imported, err := ParseKeySet(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, imported.Len(), 3)
+
+	set.Remove("hs-1")
+	testutil.Equal(t, set.Kids(), []string{"rsa-1", "ed-1"})
+
+	_, err = ParseKeySet([]byte(`{"keys":[{"kty":"oct","kid":"a","k":"AQ"},{"kty":"oct","kid":"a","k":"Ag"}]}`))
+	testutil.Equal(t, errors.Is(err, gotool.ErrInvalidParam), true)
+}
+
+func TestKeySetRefresh(t *testing.T) {
+	_, oldKey, _ := ed25519.GenerateKey(rand.Reader)
+	_, newKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	// 模拟密钥服务, 轮换后发布新密钥
+	source := NewKeySet()
+	source.Add(oldKey.Public(), "old")
+	calls := 0
+	set := NewKeySet()
+	set.SetRefresh(func() ([]byte, error) {
+		calls++
+		return source.PublicJSON()
+	}, time.Hour)
+	if err := set.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, set.Kids(), []string{"old"})
+
+	source.Add(newKey.Public(), "new")
+	token, _ := JWTSign(JwtRegisteredClaims{}, JwtSignConfig{Algorithm: JwtEdDSA, Key: newKey, KeyID: "new"})
+
+	// 距上次刷新未超过最小间隔, 不自动刷新
+	_, err := JWTVerify(token, nil, JwtVerifyConfig{KeyFunc: set.KeyFunc})
+	testutil.Equal(t, errors.Is(err, gotool.ErrJwkNotFound), true)
+	testutil.Equal(t, calls, 1)
+
+	// 未知 kid 触发刷新
+	set.SetRefresh(func() ([]byte, error) {
+		calls++
+		return source.PublicJSON()
+	}, 0)
+	_, err = JWTVerify(token, nil, JwtVerifyConfig{KeyFunc: set.KeyFunc})
+	testutil.Equal(t, err, nil)
+	testutil.Equal(t, calls, 2)
+	testutil.Equal(t, set.Kids(), []string{"old", "new"})
+
+	// 刷新失败时保留原密钥
+	set.SetRefresh(func() ([]byte, error) {
+		return nil, errors.New("unavailable")
+	}, 0)
+	testutil.NotEqual(t, set.Refresh(), nil)
+	testutil.Equal(t, set.Len(), 2)
+}
@@ -52,15 +52,9 @@ func JWTGenerate(claims JwtClaims, secretKey string, algorithm ...string) (strin
 
 	// 拼接header和claims，并计算签名
 	unsignedToken := base64Header + "." + base64Claims
-	var signature []byte
-	if alg == "HS256" {
-		signature = HmacSHA256([]byte(unsignedToken), []byte(secretKey))
-	} else if alg == "HS384" {
-		signature = HmacSHA384([]byte(unsignedToken), []byte(secretKey))
-	} else if alg == "HS512" {
-		signature = HmacSHA512([]byte(unsignedToken), []byte(secretKey))
-	} else {
-		return "", gotool.ErrInvalidJwtAlgorithm
+	signature, err := jwtHmac(alg, []byte(unsignedToken), []byte(secretKey))
+	if err != nil {
+		return "", err
 	}
 
 	// 拼接整个JWT
@@ -101,17 +95,19 @@ func JWTParse(jwt string, secretKey string, algorithm ...string) (*JwtClaims, er
 	if len(algorithm) > 0 {
 		alg = algorithm[0]
 	}
-	unsignedToken := base64Header + "." + base64Claims
-	var expectedSignature []byte
-	if alg == "HS256" {
-		expectedSignature = HmacSHA256([]byte(unsignedToken), []byte(secretKey))
-	} else if alg == "HS384" {
-		expectedSignature = HmacSHA384([]byte(unsignedToken), []byte(secretKey))
-	} else if alg == "HS512" {
-		expectedSignature = HmacSHA512([]byte(unsignedToken), []byte(secretKey))
-	} else {
+	// 头部声明的算法必须与期望的算法一致, 防止算法混淆
+	header, err := decodeJwtHeader(base64Header)
+	if err != nil {
+		return nil, err
+	}
+	if header.Alg != alg {
 		return nil, gotool.ErrInvalidJwtAlgorithm
 	}
+	unsignedToken := base64Header + "." + base64Claims
+	expectedSignature, err := jwtHmac(alg, []byte(unsignedToken), []byte(secretKey))
+	if err != nil {
+		return nil, err
+	}
 	if !hmac.Equal(signature, expectedSignature) {
 		return nil, gotool.ErrInvalidJwtSignature
 	}
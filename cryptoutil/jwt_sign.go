@@ -0,0 +1,520 @@
+package cryptoutil
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/up-zero/gotool"
+)
+
+const (
+	// JwtHS256 HMAC SHA-256
+	JwtHS256 = "HS256"
+	// JwtHS384 HMAC SHA-384
+	JwtHS384 = "HS384"
+	// JwtHS512 HMAC SHA-512
+	JwtHS512 = "HS512"
+	// JwtRS256 RSASSA-PKCS1-v1_5 SHA-256
+	JwtRS256 = "RS256"
+	// JwtRS384 RSASSA-PKCS1-v1_5 SHA-384
+	JwtRS384 = "RS384"
+	// JwtRS512 RSASSA-PKCS1-v1_5 SHA-512
+	JwtRS512 = "RS512"
+	// JwtPS256 RSASSA-PSS SHA-256
+	JwtPS256 = "PS256"
+	// JwtPS384 RSASSA-PSS SHA-384
+	JwtPS384 = "PS384"
+	// JwtPS512 RSASSA-PSS SHA-512
+	JwtPS512 = "PS512"
+	// JwtES256 ECDSA P-256 SHA-256
+	JwtES256 = "ES256"
+	// JwtES384 ECDSA P-384 SHA-384
+	JwtES384 = "ES384"
+	// JwtES512 ECDSA P-521 SHA-512
+	JwtES512 = "ES512"
+	// JwtEdDSA Ed25519
+	JwtEdDSA = "EdDSA"
+)
+
+// jwtAlgorithm 签名算法参数
+type jwtAlgorithm struct {
+	family string      // HS, RS, PS, ES, EdDSA
+	hash   crypto.Hash // 摘要算法, EdDSA 为 0
+	curve  string      // ES 使用的曲线
+}
+
+// jwtAlgorithms 支持的签名算法
+var jwtAlgorithms = map[string]jwtAlgorithm{
+	JwtHS256: {family: "HS", hash: crypto.SHA256},
+	JwtHS384: {family: "HS", hash: crypto.SHA384},
+	JwtHS512: {family: "HS", hash: crypto.SHA512},
+	JwtRS256: {family: "RS", hash: crypto.SHA256},
+	JwtRS384: {family: "RS", hash: crypto.SHA384},
+	JwtRS512: {family: "RS", hash: crypto.SHA512},
+	JwtPS256: {family: "PS", hash: crypto.SHA256},
+	JwtPS384: {family: "PS", hash: crypto.SHA384},
+	JwtPS512: {family: "PS", hash: crypto.SHA512},
+	JwtES256: {family: "ES", hash: crypto.SHA256, curve: EcdsaP256},
+	JwtES384: {family: "ES", hash: crypto.SHA384, curve: EcdsaP384},
+	JwtES512: {family: "ES", hash: crypto.SHA512, curve: EcdsaP521},
+	JwtEdDSA: {family: "EdDSA"},
+}
+
+// jwtMinRsaBits RSA 密钥的最小长度 (RFC 7518)
+const jwtMinRsaBits = 2048
+
+// JwtHeader JWT 头部
+type JwtHeader struct {
+	Alg  string   `json:"alg"`            // 签名算法
+	Typ  string   `json:"typ,omitempty"`  // 类型
+	Kid  string   `json:"kid,omitempty"`  // 密钥 ID
+	Crit []string `json:"crit,omitempty"` // 必须理解的扩展头部
+}
+
+// JwtAudience JWT 受众, 单个受众序列化为字符串, 多个受众序列化为数组
+type JwtAudience []string
+
+// MarshalJSON 序列化受众
+func (a JwtAudience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// UnmarshalJSON 反序列化受众, 支持字符串和字符串数组
+func (a *JwtAudience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = JwtAudience{single}
+		return nil
+	}
+	var multi []string
+	if err := json.Unmarshal(data, &multi); err != nil {
+		return fmt.Errorf("%w, invalid aud", gotool.ErrInvalidJwtClaims)
+	}
+	*a = multi
+	return nil
+}
+
+// Contains 是否包含指定受众
+func (a JwtAudience) Contains(audience string) bool {
+	for _, v := range a {
+		if v == audience {
+			return true
+		}
+	}
+	return false
+}
+
+// JwtRegisteredClaims RFC 7519 注册声明, 时间均为 Unix 秒, 0 表示未设置
+//
+// 自定义声明可通过嵌入该结构体扩展, 例如:
+//
+//	type UserClaims struct {
+//		cryptoutil.JwtRegisteredClaims
+//		Name string `json:"name"`
+//	}
+type JwtRegisteredClaims struct {
+	Issuer    string      `json:"iss,omitempty"` // 签发者
+	Subject   string      `json:"sub,omitempty"` // 主题
+	Audience  JwtAudience `json:"aud,omitempty"` // 受众
+	ExpiresAt int64       `json:"exp,omitempty"` // 过期时间
+	NotBefore int64       `json:"nbf,omitempty"` // 生效时间
+	IssuedAt  int64       `json:"iat,omitempty"` // 签发时间
+	ID        string      `json:"jti,omitempty"` // 唯一标识
+}
+
+// JwtSignConfig JWT 签名配置
+type JwtSignConfig struct {
+	Algorithm string // 签名算法, 例如: JwtHS256, JwtRS256, JwtPS256, JwtES256, JwtEdDSA
+	// Key 签名密钥
+	//
+	// HS*: []byte
+	// RS*, PS*: *rsa.PrivateKey 或 PEM 格式私钥字符串 (例如 RsaGenerateKey 生成的私钥)
+	// ES*: *ecdsa.PrivateKey 或 PEM 格式私钥字符串 (例如 EcdsaGenerateKey 生成的私钥)
+	// EdDSA: ed25519.PrivateKey 或 PEM 格式私钥字符串 (例如 Ed25519GenerateKey 生成的私钥)
+	Key   any
+	KeyID string // 密钥 ID, 写入头部 kid
+}
+
+// JwtVerifyConfig JWT 验证配置
+type JwtVerifyConfig struct {
+	// Algorithms 允许的签名算法, 为空时允许与密钥类型匹配的全部算法, 始终拒绝 none
+	Algorithms []string
+	// Key 验证密钥
+	//
+	// HS*: []byte
+	// RS*, PS*: *rsa.PublicKey 或 PEM 格式公钥字符串
+	// ES*: *ecdsa.PublicKey 或 PEM 格式公钥字符串
+	// EdDSA: ed25519.PublicKey 或 PEM 格式公钥字符串
+	Key any
+	// KeyFunc 根据头部 (例如 kid) 选择验证密钥, 设置后忽略 Key
+	KeyFunc func(header *JwtHeader) (any, error)
+
+	Issuer            string        // 期望的签发者, 为空时不校验
+	Subject           string        // 期望的主题, 为空时不校验
+	Audience          string        // 期望包含的受众, 为空时不校验
+	Leeway            time.Duration // 校验 exp、nbf、iat 时允许的时钟偏差
+	RequireExpiration bool          // 是否要求必须包含 exp
+	Time              time.Time     // 校验时间, 默认当前时间
+}
+
+// JWTSign 生成签名的 JWT (JWS 紧凑序列化)
+//
+// # Params:
+//
+//	claims: JWT 声明, 可以是 JwtRegisteredClaims、嵌入 JwtRegisteredClaims 的结构体或 map
+//	conf: 签名配置
+func JWTSign(claims any, conf JwtSignConfig) (string, error) {
+	if _, ok := jwtAlgorithms[conf.Algorithm]; !ok {
+		return "", fmt.Errorf("%w, %q", gotool.ErrInvalidJwtAlgorithm, conf.Algorithm)
+	}
+
+	encodedHeader, err := json.Marshal(JwtHeader{Alg: conf.Algorithm, Typ: "JWT", Kid: conf.KeyID})
+	if err != nil {
+		return "", err
+	}
+	encodedClaims, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	unsignedToken := base64.RawURLEncoding.EncodeToString(encodedHeader) + "." + base64.RawURLEncoding.EncodeToString(encodedClaims)
+	signature, err := jwtSign(conf.Algorithm, []byte(unsignedToken), conf.Key)
+	if err != nil {
+		return "", err
+	}
+	return unsignedToken + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// JWTVerify 验证 JWT 的签名和注册声明, 并将声明解析到 claims
+//
+// # Params:
+//
+//	token: JWT 字符串
+//	claims: 声明的解析目标 (指针), 为 nil 时只做验证
+//	conf: 验证配置
+func JWTVerify(token string, claims any, conf JwtVerifyConfig) (*JwtHeader, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, gotool.ErrInvalidJwtFormat
+	}
+	header, err := decodeJwtHeader(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(header.Crit) > 0 {
+		return nil, fmt.Errorf("%w, unsupported crit header: %v", gotool.ErrInvalidJwtFormat, header.Crit)
+	}
+
+	// 算法必须受支持且在允许列表中, none 不在 jwtAlgorithms 中
+	if _, ok := jwtAlgorithms[header.Alg]; !ok {
+		return nil, fmt.Errorf("%w, %q", gotool.ErrInvalidJwtAlgorithm, header.Alg)
+	}
+	if len(conf.Algorithms) > 0 {
+		allowed := false
+		for _, alg := range conf.Algorithms {
+			if alg == header.Alg {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return nil, fmt.Errorf("%w, %q is not allowed", gotool.ErrInvalidJwtAlgorithm, header.Alg)
+		}
+	}
+
+	key := conf.Key
+	if conf.KeyFunc != nil {
+		if key, err = conf.KeyFunc(header); err != nil {
+			return nil, err
+		}
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, gotool.ErrInvalidJwtFormat
+	}
+	if err := jwtVerify(header.Alg, []byte(parts[0]+"."+parts[1]), signature, key); err != nil {
+		return nil, err
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, gotool.ErrInvalidJwtFormat
+	}
+	registered := new(JwtRegisteredClaims)
+	if err := json.Unmarshal(payload, registered); err != nil {
+		return nil, fmt.Errorf("%w, %v", gotool.ErrInvalidJwtClaims, err)
+	}
+	if err := registered.validate(conf); err != nil {
+		return nil, err
+	}
+	if claims != nil {
+		if err := json.Unmarshal(payload, claims); err != nil {
+			return nil, err
+		}
+	}
+	return header, nil
+}
+
+// validate 校验注册声明
+func (c *JwtRegisteredClaims) validate(conf JwtVerifyConfig) error {
+	now := conf.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	if c.ExpiresAt == 0 && conf.RequireExpiration {
+		return fmt.Errorf("%w, missing exp", gotool.ErrInvalidJwtClaims)
+	}
+	if c.ExpiresAt != 0 && !now.Before(time.Unix(c.ExpiresAt, 0).Add(conf.Leeway)) {
+		return gotool.ErrExpiredJwt
+	}
+	if c.NotBefore != 0 && now.Before(time.Unix(c.NotBefore, 0).Add(-conf.Leeway)) {
+		return gotool.ErrJwtNotValidYet
+	}
+	if c.IssuedAt != 0 && now.Before(time.Unix(c.IssuedAt, 0).Add(-conf.Leeway)) {
+		return fmt.Errorf("%w, issued in the future", gotool.ErrJwtNotValidYet)
+	}
+
+	if conf.Issuer != "" && c.Issuer != conf.Issuer {
+		return fmt.Errorf("%w, unexpected iss %q", gotool.ErrInvalidJwtClaims, c.Issuer)
+	}
+	if conf.Subject != "" && c.Subject != conf.Subject {
+		return fmt.Errorf("%w, unexpected sub %q", gotool.ErrInvalidJwtClaims, c.Subject)
+	}
+	if conf.Audience != "" && !c.Audience.Contains(conf.Audience) {
+		return fmt.Errorf("%w, aud does not contain %q", gotool.ErrInvalidJwtClaims, conf.Audience)
+	}
+	return nil
+}
+
+// decodeJwtHeader 解码 JWT 头部
+func decodeJwtHeader(encoded string) (*JwtHeader, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, gotool.ErrInvalidJwtFormat
+	}
+	header := new(JwtHeader)
+	if err := json.Unmarshal(data, header); err != nil {
+		return nil, gotool.ErrInvalidJwtFormat
+	}
+	return header, nil
+}
+
+// jwtHmac 计算 HS* 签名
+func jwtHmac(alg string, data, key []byte) ([]byte, error) {
+	a, ok := jwtAlgorithms[alg]
+	if !ok || a.family != "HS" {
+		return nil, gotool.ErrInvalidJwtAlgorithm
+	}
+	return hmacGenerate(data, key, a.hash), nil
+}
+
+// jwtKeyMismatch 密钥类型与算法不匹配
+func jwtKeyMismatch(alg string, key any) error {
+	return fmt.Errorf("%w, key type %T does not match %s", gotool.ErrInvalidJwtAlgorithm, key, alg)
+}
+
+// jwtDigest 计算摘要
+func jwtDigest(hash crypto.Hash, data []byte) []byte {
+	h := hash.New()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// jwtSign 按算法对 data 签名, 密钥类型必须与算法匹配
+func jwtSign(alg string, data []byte, key any) ([]byte, error) {
+	a := jwtAlgorithms[alg]
+	switch a.family {
+	case "HS":
+		secret, ok := key.([]byte)
+		if !ok {
+			return nil, jwtKeyMismatch(alg, key)
+		}
+		if len(secret) == 0 {
+			return nil, fmt.Errorf("%w, hmac key", gotool.ErrCannotBeEmpty)
+		}
+		return hmacGenerate(data, secret, a.hash), nil
+	case "RS", "PS":
+		var prv *rsa.PrivateKey
+		switch k := key.(type) {
+		case *rsa.PrivateKey:
+			prv = k
+		case string:
+			p, err := parsePrivateKey(k)
+			if err != nil {
+				return nil, err
+			}
+			prv = p
+		default:
+			return nil, jwtKeyMismatch(alg, key)
+		}
+		if prv.N.BitLen() < jwtMinRsaBits {
+			return nil, fmt.Errorf("%w, rsa key must be at least %d bits", gotool.ErrInvalidParam, jwtMinRsaBits)
+		}
+		if a.family == "PS" {
+			return rsa.SignPSS(rand.Reader, prv, a.hash, jwtDigest(a.hash, data), &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		}
+		return rsa.SignPKCS1v15(rand.Reader, prv, a.hash, jwtDigest(a.hash, data))
+	case "ES":
+		var prv *ecdsa.PrivateKey
+		switch k := key.(type) {
+		case *ecdsa.PrivateKey:
+			prv = k
+		case string:
+			p, err := parseEcdsaPrivateKey(k)
+			if err != nil {
+				return nil, err
+			}
+			prv = p
+		default:
+			return nil, jwtKeyMismatch(alg, key)
+		}
+		if prv.Curve.Params().Name != a.curve {
+			return nil, jwtKeyMismatch(alg, key)
+		}
+		r, s, err := ecdsa.Sign(rand.Reader, prv, jwtDigest(a.hash, data))
+		if err != nil {
+			return nil, err
+		}
+		// R || S, 各自按曲线长度左补零
+		size := (prv.Curve.Params().BitSize + 7) / 8
+		signature := make([]byte, 2*size)
+		r.FillBytes(signature[:size])
+		s.FillBytes(signature[size:])
+		return signature, nil
+	case "EdDSA":
+		var prv ed25519.PrivateKey
+		switch k := key.(type) {
+		case ed25519.PrivateKey:
+			prv = k
+		case string:
+			p, err := parseEd25519PrivateKey(k)
+			if err != nil {
+				return nil, err
+			}
+			prv = p
+		default:
+			return nil, jwtKeyMismatch(alg, key)
+		}
+		if len(prv) != ed25519.PrivateKeySize {
+			return nil, fmt.Errorf("%w, ed25519 private key size", gotool.ErrInvalidParam)
+		}
+		return ed25519.Sign(prv, data), nil
+	}
+	return nil, gotool.ErrInvalidJwtAlgorithm
+}
+
+// jwtVerify 按算法验证 data 的签名, 密钥类型必须与算法匹配
+func jwtVerify(alg string, data, signature []byte, key any) error {
+	a := jwtAlgorithms[alg]
+	switch a.family {
+	case "HS":
+		// 只接受 []byte, 避免公钥字符串被当作 HMAC 密钥 (算法混淆)
+		secret, ok := key.([]byte)
+		if !ok {
+			return jwtKeyMismatch(alg, key)
+		}
+		if len(secret) == 0 {
+			return fmt.Errorf("%w, hmac key", gotool.ErrCannotBeEmpty)
+		}
+		if !hmac.Equal(signature, hmacGenerate(data, secret, a.hash)) {
+			return gotool.ErrInvalidJwtSignature
+		}
+		return nil
+	case "RS", "PS":
+		var pub *rsa.PublicKey
+		switch k := key.(type) {
+		case *rsa.PublicKey:
+			pub = k
+		case *rsa.PrivateKey:
+			pub = &k.PublicKey
+		case string:
+			p, err := parsePublicKey(k)
+			if err != nil {
+				return err
+			}
+			pub = p
+		default:
+			return jwtKeyMismatch(alg, key)
+		}
+		if pub.N.BitLen() < jwtMinRsaBits {
+			return fmt.Errorf("%w, rsa key must be at least %d bits", gotool.ErrInvalidParam, jwtMinRsaBits)
+		}
+		var err error
+		if a.family == "PS" {
+			err = rsa.VerifyPSS(pub, a.hash, jwtDigest(a.hash, data), signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		} else {
+			err = rsa.VerifyPKCS1v15(pub, a.hash, jwtDigest(a.hash, data), signature)
+		}
+		if err != nil {
+			return gotool.ErrInvalidJwtSignature
+		}
+		return nil
+	case "ES":
+		var pub *ecdsa.PublicKey
+		switch k := key.(type) {
+		case *ecdsa.PublicKey:
+			pub = k
+		case *ecdsa.PrivateKey:
+			pub = &k.PublicKey
+		case string:
+			p, err := parseEcdsaPublicKey(k)
+			if err != nil {
+				return err
+			}
+			pub = p
+		default:
+			return jwtKeyMismatch(alg, key)
+		}
+		if pub.Curve.Params().Name != a.curve {
+			return jwtKeyMismatch(alg, key)
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return gotool.ErrInvalidJwtSignature
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(pub, jwtDigest(a.hash, data), r, s) {
+			return gotool.ErrInvalidJwtSignature
+		}
+		return nil
+	case "EdDSA":
+		var pub ed25519.PublicKey
+		switch k := key.(type) {
+		case ed25519.PublicKey:
+			pub = k
+		case ed25519.PrivateKey:
+			if len(k) != ed25519.PrivateKeySize {
+				return fmt.Errorf("%w, ed25519 private key size", gotool.ErrInvalidParam)
+			}
+			pub = k.Public().(ed25519.PublicKey)
+		case string:
+			p, err := parseEd25519PublicKey(k)
+			if err != nil {
+				return err
+			}
+			pub = p
+		default:
+			return jwtKeyMismatch(alg, key)
+		}
+		if len(pub) != ed25519.PublicKeySize || !ed25519.Verify(pub, data, signature) {
+			return gotool.ErrInvalidJwtSignature
+		}
+		return nil
+	}
+	return gotool.ErrInvalidJwtAlgorithm
+}
@@ -0,0 +1,421 @@
+package cryptoutil
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/up-zero/gotool"
+)
+
+const (
+	// KeyFormatPEM PEM 格式，与 XxxGenerateKey 的输出一致: RSA 私钥为 PKCS#1，ECDSA 私钥为 SEC 1，其余私钥为 PKCS#8，公钥为 PKIX
+	KeyFormatPEM = "pem"
+	// KeyFormatPKCS8 PEM 格式，私钥统一为 PKCS#8，公钥为 PKIX
+	KeyFormatPKCS8 = "pkcs8"
+	// KeyFormatDER DER 格式，私钥为 PKCS#8，公钥为 PKIX
+	KeyFormatDER = "der"
+	// KeyFormatSSH OpenSSH authorized_keys 格式，仅支持 RSA、ECDSA 和 Ed25519 公钥
+	KeyFormatSSH = "ssh"
+	// KeyFormatJWK JWK JSON 格式，kid 为 RFC 7638 指纹
+	KeyFormatJWK = "jwk"
+)
+
+// sshCurveNames ECDSA 曲线对应的 SSH 名称
+var sshCurveNames = map[string]string{
+	EcdsaP256: "nistp256",
+	EcdsaP384: "nistp384",
+	EcdsaP521: "nistp521",
+}
+
+// Sign 使用私钥签名，根据密钥类型选择算法
+//
+// RSA 使用 PKCS#1 v1.5 和 SHA-256；ECDSA 输出 ASN.1 DER 签名，P-256、P-384、P-521 分别使用 SHA-256、SHA-384、SHA-512；Ed25519 对原始数据签名
+//
+// # Params:
+//
+//	data: 待签名的数据
+//	privateKey: 私钥，支持 PEM、DER 和 JWK 格式
+func Sign(data []byte, privateKey string) ([]byte, error) {
+	key, err := parseAnyPrivateKey([]byte(privateKey))
+	if err != nil {
+		return nil, err
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256(data)
+		return rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+	case *ecdsa.PrivateKey:
+		return ecdsa.SignASN1(rand.Reader, k, ecdsaDigest(k.Curve.Params().BitSize, data))
+	case ed25519.PrivateKey:
+		return ed25519.Sign(k, data), nil
+	}
+	return nil, fmt.Errorf("%w, %T cannot be used for signing", gotool.ErrNotSupportType, key)
+}
+
+// Verify 使用公钥验签，算法与 Sign 相同，签名有效时返回 nil
+//
+// # Params:
+//
+//	data: 原始数据
+//	signature: 签名
+//	publicKey: 公钥，支持 PEM (包括证书)、DER、SSH 和 JWK 格式
+func Verify(data, signature []byte, publicKey string) error {
+	key, err := parseAnyPublicKey([]byte(publicKey))
+	if err != nil {
+		return err
+	}
+	valid := false
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(data)
+		valid = rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature) == nil
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(k, ecdsaDigest(k.Curve.Params().BitSize, data), signature)
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(k, data, signature)
+	default:
+		return fmt.Errorf("%w, %T cannot be used for verification", gotool.ErrNotSupportType, key)
+	}
+	if !valid {
+		return gotool.ErrInvalidSignature
+	}
+	return nil
+}
+
+// ecdsaDigest 根据曲线位数选择摘要算法
+func ecdsaDigest(bitSize int, data []byte) []byte {
+	hash := crypto.SHA256
+	switch {
+	case bitSize > 384:
+		hash = crypto.SHA512
+	case bitSize > 256:
+		hash = crypto.SHA384
+	}
+	h := hash.New()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// marshalAnyPrivateKey 按格式编码私钥
+func marshalAnyPrivateKey(key any, format string) ([]byte, error) {
+	switch format {
+	case KeyFormatPEM:
+		switch k := key.(type) {
+		case *rsa.PrivateKey:
+			return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)}), nil
+		case *ecdsa.PrivateKey:
+			der, err := x509.MarshalECPrivateKey(k)
+			if err != nil {
+				return nil, err
+			}
+			return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+		}
+		return marshalAnyPrivateKey(key, KeyFormatPKCS8)
+	case KeyFormatPKCS8, KeyFormatDER:
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("%w, %v", gotool.ErrNotSupportType, err)
+		}
+		if format == KeyFormatDER {
+			return der, nil
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+	case KeyFormatJWK:
+		jwk, err := JwkFromKey(key, "")
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(jwk)
+	case KeyFormatSSH:
+		return nil, fmt.Errorf("%w, ssh format only supports public keys", gotool.ErrNotSupportType)
+	}
+	return nil, fmt.Errorf("%w, unsupported key format: %s", gotool.ErrInvalidParam, format)
+}
+
+// marshalAnyPublicKey 按格式编码公钥
+func marshalAnyPublicKey(key any, format string) ([]byte, error) {
+	switch format {
+	case KeyFormatPEM, KeyFormatPKCS8, KeyFormatDER:
+		der, err := x509.MarshalPKIXPublicKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("%w, %v", gotool.ErrNotSupportType, err)
+		}
+		if format == KeyFormatDER {
+			return der, nil
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+	case KeyFormatSSH:
+		return marshalSSHPublicKey(key)
+	case KeyFormatJWK:
+		jwk, err := JwkFromKey(key, "")
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(jwk)
+	}
+	return nil, fmt.Errorf("%w, unsupported key format: %s", gotool.ErrInvalidParam, format)
+}
+
+// parseAnyPrivateKey 自动识别 PEM、DER 和 JWK 格式的私钥
+func parseAnyPrivateKey(data []byte) (any, error) {
+	// DER 是二进制数据, 首尾字节可能恰好是空白字符, 必须在去除空白之前解析
+	if key, ok := parseDERPrivateKey(data); ok {
+		return key, nil
+	}
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("{")) {
+		jwk, err := ParseJwk(data)
+		if err != nil {
+			return nil, err
+		}
+		if !jwk.IsPrivate() || jwk.Kty == "oct" {
+			return nil, fmt.Errorf("%w, jwk is not a private key", gotool.ErrInvalidParam)
+		}
+		return jwk.Key()
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		if bytes.HasPrefix(data, []byte("-----")) {
+			return nil, fmt.Errorf("pem decode error: %w", gotool.ErrInvalidParam)
+		}
+		// 兼容末尾带有换行的 DER 文件
+		if key, ok := parseDERPrivateKey(data); ok {
+			return key, nil
+		}
+		return nil, fmt.Errorf("parse private key error: %w", gotool.ErrInvalidParam)
+	}
+	if key, ok := parseDERPrivateKey(block.Bytes); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("parse private key error: %w", gotool.ErrInvalidParam)
+}
+
+// parseDERPrivateKey 依次尝试 PKCS#8、PKCS#1 和 SEC 1 格式的 DER 私钥
+func parseDERPrivateKey(der []byte) (any, bool) {
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		return key, true
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, true
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, true
+	}
+	return nil, false
+}
+
+// parseAnyPublicKey 自动识别 PEM (包括证书)、DER、SSH 和 JWK 格式的公钥，JWK 私钥返回其公钥
+func parseAnyPublicKey(data []byte) (any, error) {
+	// DER 是二进制数据, 首尾字节可能恰好是空白字符, 必须在去除空白之前解析
+	if key, ok := parseDERPublicKey(data); ok {
+		return key, nil
+	}
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("{")) {
+		jwk, err := ParseJwk(data)
+		if err != nil {
+			return nil, err
+		}
+		pub, err := jwk.Public()
+		if err != nil {
+			return nil, err
+		}
+		return pub.Key()
+	}
+	if key, err := parseSSHPublicKey(data); err == nil {
+		return key, nil
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		if bytes.HasPrefix(data, []byte("-----")) {
+			return nil, fmt.Errorf("pem decode error: %w", gotool.ErrInvalidParam)
+		}
+		// 兼容末尾带有换行的 DER 文件
+		if key, ok := parseDERPublicKey(data); ok {
+			return key, nil
+		}
+		return nil, fmt.Errorf("parse public key error: %w", gotool.ErrInvalidParam)
+	}
+	if block.Type == "CERTIFICATE" {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%w, parse certificate: %v", gotool.ErrInvalidParam, err)
+		}
+		return cert.PublicKey, nil
+	}
+	if key, ok := parseDERPublicKey(block.Bytes); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("parse public key error: %w", gotool.ErrInvalidParam)
+}
+
+// parseDERPublicKey 依次尝试 PKIX 和 PKCS#1 格式的 DER 公钥
+func parseDERPublicKey(der []byte) (any, bool) {
+	if key, err := x509.ParsePKIXPublicKey(der); err == nil {
+		return key, true
+	}
+	if key, err := x509.ParsePKCS1PublicKey(der); err == nil {
+		return key, true
+	}
+	return nil, false
+}
+
+// marshalSSHPublicKey 编码为 authorized_keys 格式 (RFC 4253, RFC 5656, RFC 8709)
+func marshalSSHPublicKey(key any) ([]byte, error) {
+	var name string
+	var blob []byte
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		name = "ssh-rsa"
+		blob = sshAppendString(nil, []byte(name))
+		blob = sshAppendMpint(blob, big.NewInt(int64(k.E)))
+		blob = sshAppendMpint(blob, k.N)
+	case *ecdsa.PublicKey:
+		curve, ok := sshCurveNames[k.Curve.Params().Name]
+		if !ok {
+			return nil, fmt.Errorf("%w, unsupported curve: %s", gotool.ErrNotSupportType, k.Curve.Params().Name)
+		}
+		point, err := k.ECDH()
+		if err != nil {
+			return nil, fmt.Errorf("%w, %v", gotool.ErrInvalidParam, err)
+		}
+		name = "ecdsa-sha2-" + curve
+		blob = sshAppendString(nil, []byte(name))
+		blob = sshAppendString(blob, []byte(curve))
+		blob = sshAppendString(blob, point.Bytes())
+	case ed25519.PublicKey:
+		name = "ssh-ed25519"
+		blob = sshAppendString(nil, []byte(name))
+		blob = sshAppendString(blob, k)
+	default:
+		return nil, fmt.Errorf("%w, ssh format does not support %T", gotool.ErrNotSupportType, key)
+	}
+	return []byte(name + " " + base64.StdEncoding.EncodeToString(blob) + "\n"), nil
+}
+
+// parseSSHPublicKey 解析 authorized_keys 格式的公钥, 忽略选项和注释
+func parseSSHPublicKey(data []byte) (any, error) {
+	line, _, _ := strings.Cut(string(data), "\n")
+	fields := strings.Fields(line)
+	for i := 0; i+1 < len(fields); i++ {
+		name := fields[i]
+		if name != "ssh-rsa" && name != "ssh-ed25519" && !strings.HasPrefix(name, "ecdsa-sha2-") {
+			continue
+		}
+		blob, err := base64.StdEncoding.DecodeString(fields[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("%w, invalid ssh public key", gotool.ErrInvalidParam)
+		}
+		return parseSSHPublicKeyBlob(name, blob)
+	}
+	return nil, fmt.Errorf("%w, not a ssh public key", gotool.ErrInvalidParam)
+}
+
+// parseSSHPublicKeyBlob 解析 SSH 公钥的二进制编码
+func parseSSHPublicKeyBlob(name string, blob []byte) (any, error) {
+	invalid := fmt.Errorf("%w, invalid %s public key", gotool.ErrInvalidParam, name)
+	typ, blob, ok := sshReadString(blob)
+	if !ok || string(typ) != name {
+		return nil, invalid
+	}
+	switch {
+	case name == "ssh-rsa":
+		e, blob, ok1 := sshReadString(blob)
+		n, blob, ok2 := sshReadString(blob)
+		if !ok1 || !ok2 || len(blob) != 0 || len(e) == 0 || len(e) > 4 || len(n) == 0 || e[0]&0x80 != 0 || n[0]&0x80 != 0 {
+			return nil, invalid
+		}
+		E := new(big.Int).SetBytes(e).Int64()
+		if E < 3 || E&1 == 0 {
+			return nil, invalid
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(E)}, nil
+	case name == "ssh-ed25519":
+		key, blob, ok := sshReadString(blob)
+		if !ok || len(blob) != 0 || len(key) != ed25519.PublicKeySize {
+			return nil, invalid
+		}
+		return ed25519.PublicKey(key), nil
+	default:
+		curveName, blob, ok1 := sshReadString(blob)
+		point, blob, ok2 := sshReadString(blob)
+		if !ok1 || !ok2 || len(blob) != 0 || "ecdsa-sha2-"+string(curveName) != name {
+			return nil, invalid
+		}
+		for crv, sshName := range sshCurveNames {
+			if sshName == string(curveName) {
+				return ecdsaPublicKeyFromPoint(crv, point)
+			}
+		}
+		return nil, fmt.Errorf("%w, unsupported ssh curve: %s", gotool.ErrNotSupportType, curveName)
+	}
+}
+
+// ecdsaPublicKeyFromPoint 由未压缩的点构造 ECDSA 公钥, 并检查点是否在曲线上
+func ecdsaPublicKeyFromPoint(crv string, point []byte) (*ecdsa.PublicKey, error) {
+	curve, err := ecdsaCurve(crv)
+	if err != nil {
+		return nil, err
+	}
+	var ecdhCurve ecdh.Curve
+	switch crv {
+	case EcdsaP256:
+		ecdhCurve = ecdh.P256()
+	case EcdsaP384:
+		ecdhCurve = ecdh.P384()
+	default:
+		ecdhCurve = ecdh.P521()
+	}
+	if _, err := ecdhCurve.NewPublicKey(point); err != nil {
+		return nil, fmt.Errorf("%w, invalid ecdsa point", gotool.ErrInvalidParam)
+	}
+	size := (curve.Params().BitSize + 7) / 8
+	return &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(point[1 : 1+size]),
+		Y:     new(big.Int).SetBytes(point[1+size:]),
+	}, nil
+}
+
+// sshAppendString 追加 SSH string (uint32 长度 + 数据)
+func sshAppendString(b, s []byte) []byte {
+	b = binary.BigEndian.AppendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+// sshAppendMpint 追加 SSH mpint (非负数), 最高位为 1 时补 0
+func sshAppendMpint(b []byte, n *big.Int) []byte {
+	data := n.Bytes()
+	if len(data) > 0 && data[0]&0x80 != 0 {
+		data = append([]byte{0}, data...)
+	}
+	return sshAppendString(b, data)
+}
+
+// sshReadString 读取 SSH string
+func sshReadString(b []byte) ([]byte, []byte, bool) {
+	if len(b) < 4 {
+		return nil, nil, false
+	}
+	n := binary.BigEndian.Uint32(b)
+	if uint64(n) > uint64(len(b)-4) {
+		return nil, nil, false
+	}
+	return b[4 : 4+n], b[4+n:], true
+}
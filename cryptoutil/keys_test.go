@@ -0,0 +1,250 @@
+package cryptoutil
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/up-zero/gotool"
+	"github.com/up-zero/gotool/testutil"
+)
+
+// ssh-keygen 生成的公钥及 ssh-keygen -e -m PKCS8 导出的 PEM
+const (
+	testSSHEd25519 = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIL93uk3XpaJyH/00uVJrIKGfIS1xdLVwi03r2TCQuhhg test@host"
+	testSSHEcdsa   = "ecdsa-sha2-nistp384 AAAAE2VjZHNhLXNoYTItbmlzdHAzODQAAAAIbmlzdHAzODQAAABhBEEm3jJMoAeI+ZZsgW6ca3+vl2XxiWVoRPGRD6crSfYFPB1K1B581QXlgc41gBHvfKarc4z3WoOXhnl8EimHLaq/JIdDKj3a9QmGPk96Fc2yeFaW7cySIfJERXUQVtxKXw== test@host"
+	testPEMEcdsa   = `-----BEGIN PUBLIC KEY-----
+MHYwEAYHKoZIzj0CAQYFK4EEACIDYgAEQSbeMkygB4j5lmyBbpxrf6+XZfGJZWhE
+8ZEPpytJ9gU8HUrUHnzVBeWBzjWAEe98pqtzjPdag5eGeXwSKYctqr8kh0MqPdr1
+CYY+T3oVzbJ4VpbtzJIh8kRFdRBW3Epf
+-----END PUBLIC KEY-----`
+	testSSHRsa = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAAAgQCize+S4XhtMlhwjRzNfUgfJTLQs9A2YQDyAkgu1LXxgiJMw3MXE0iEAxAdGHhDuwauDIRJdlcQOERM8GcWbHDNYYZ1jMxyV6BX4oxelYQX0ifDuonMP3Ut1B/UZvQ5MzvWNHYghHZ5JC2TZqvlm6gsNkQ5TPioyDRHXw/sjW933w== test@host"
+	testPEMRsa = `-----BEGIN PUBLIC KEY-----
+MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQCize+S4XhtMlhwjRzNfUgfJTLQ
+s9A2YQDyAkgu1LXxgiJMw3MXE0iEAxAdGHhDuwauDIRJdlcQOERM8GcWbHDNYYZ1
+jMxyV6BX4oxelYQX0ifDuonMP3Ut1B/UZvQ5MzvWNHYghHZ5JC2TZqvlm6gsNkQ5
+TPioyDRHXw/sjW933wIDAQAB
+-----END PUBLIC KEY-----`
+)
+
+func TestSSHPublicKey(t *testing.T) {
+	tests := []struct{ ssh, pem string }{
+		{testSSHEcdsa, testPEMEcdsa},
+		{testSSHRsa, testPEMRsa},
+		{testSSHEd25519, ""},
+	}
+	for _, tt := range tests {
+		key, err := parseAnyPublicKey([]byte(tt.ssh))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.pem != "" {
+			want, err := parseAnyPublicKey([]byte(tt.pem))
+			if err != nil {
+				t.Fatal(err)
+			}
+			testutil.Equal(t, key.(interface{ Equal(x crypto.PublicKey) bool }).Equal(want), true)
+		}
+		out, err := marshalAnyPublicKey(key, KeyFormatSSH)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, string(out), strings.TrimSuffix(tt.ssh, " test@host")+"\n")
+	}
+
+	// 带选项的 authorized_keys
+	_, err := Ed25519ParsePublicKey([]byte(`command="ls" ` + testSSHEd25519))
+	testutil.Equal(t, err, nil)
+	// 类型与内容不一致
+	_, err = parseAnyPublicKey([]byte(strings.Replace(testSSHEd25519, "ssh-ed25519", "ssh-rsa", 1)))
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestEcdsaKeyFormats(t *testing.T) {
+	for _, curve := range []string{EcdsaP256, EcdsaP384, EcdsaP521} {
+		prvPEM, _, err := EcdsaGenerateKey(curve)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prv, err := EcdsaParsePrivateKey([]byte(prvPEM))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, format := range []string{KeyFormatPEM, KeyFormatPKCS8, KeyFormatDER, KeyFormatJWK} {
+			data, err := EcdsaMarshalPrivateKey(prv, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := EcdsaParsePrivateKey(data)
+			if err != nil {
+				t.Fatal(format, err)
+			}
+			testutil.Equal(t, got.Equal(prv), true)
+		}
+		for _, format := range []string{KeyFormatPEM, KeyFormatDER, KeyFormatSSH, KeyFormatJWK} {
+			data, err := EcdsaMarshalPublicKey(&prv.PublicKey, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := EcdsaParsePublicKey(data)
+			if err != nil {
+				t.Fatal(format, err)
+			}
+			testutil.Equal(t, got.Equal(&prv.PublicKey), true)
+		}
+	}
+	_, err := EcdsaMarshalPrivateKey(nil, "xml")
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestEd25519KeyFormats(t *testing.T) {
+	prvPEM, pubPEM, err := Ed25519GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prv, err := Ed25519ParsePrivateKey([]byte(prvPEM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, format := range []string{KeyFormatPEM, KeyFormatPKCS8, KeyFormatDER, KeyFormatJWK} {
+		data, err := Ed25519MarshalPrivateKey(prv, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Ed25519ParsePrivateKey(data)
+		if err != nil {
+			t.Fatal(format, err)
+		}
+		testutil.Equal(t, got.Equal(prv), true)
+	}
+	pub := prv.Public().(ed25519.PublicKey)
+	for _, format := range []string{KeyFormatPEM, KeyFormatDER, KeyFormatSSH, KeyFormatJWK} {
+		data, err := Ed25519MarshalPublicKey(pub, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Ed25519ParsePublicKey(data)
+		if err != nil {
+			t.Fatal(format, err)
+		}
+		testutil.Equal(t, got.Equal(pub), true)
+	}
+	data, _ := Ed25519MarshalPublicKey(pub, KeyFormatPEM)
+	testutil.Equal(t, string(data), pubPEM)
+
+	_, err = Ed25519MarshalPrivateKey(prv, KeyFormatSSH)
+	testutil.Equal(t, errors.Is(err, gotool.ErrNotSupportType), true)
+	_, err = Ed25519ParsePrivateKey([]byte(testPrvKey))
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestX25519KeyFormats(t *testing.T) {
+	prvPEM, _, err := X25519GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prv, err := X25519ParsePrivateKey([]byte(prvPEM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, format := range []string{KeyFormatPEM, KeyFormatPKCS8, KeyFormatDER, KeyFormatJWK} {
+		data, err := X25519MarshalPrivateKey(prv, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := X25519ParsePrivateKey(data)
+		if err != nil {
+			t.Fatal(format, err)
+		}
+		testutil.Equal(t, got.Equal(prv), true)
+	}
+	for _, format := range []string{KeyFormatPEM, KeyFormatDER, KeyFormatJWK} {
+		data, err := X25519MarshalPublicKey(prv.PublicKey(), format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := X25519ParsePublicKey(data)
+		if err != nil {
+			t.Fatal(format, err)
+		}
+		testutil.Equal(t, got.Equal(prv.PublicKey()), true)
+	}
+	_, err = X25519MarshalPublicKey(prv.PublicKey(), KeyFormatSSH)
+	testutil.Equal(t, errors.Is(err, gotool.ErrNotSupportType), true)
+
+	p256, _ := ecdh.P256().GenerateKey(rand.Reader)
+	_, err = X25519MarshalPrivateKey(p256, KeyFormatPEM)
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestSignVerify(t *testing.T) {
+	data := []byte("gotool")
+	ecPrv, ecPub, _ := EcdsaGenerateKey(EcdsaP384)
+	edPrv, edPub, _ := Ed25519GenerateKey()
+	xPrv, _, _ := X25519GenerateKey()
+	tests := []struct{ prv, pub string }{
+		{testPubKey, testPrvKey},
+		{ecPrv, ecPub},
+		{edPrv, edPub},
+	}
+	for _, tt := range tests {
+		signature, err := Sign(data, tt.prv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, Verify(data, signature, tt.pub), nil)
+		testutil.Equal(t, errors.Is(Verify([]byte("other"), signature, tt.pub), gotool.ErrInvalidSignature), true)
+	}
+
+	// 与 RsaSign 兼容, 公钥可以使用 SSH 和 JWK 格式
+	signature, _ := RsaSign(data, testPubKey)
+	testutil.Equal(t, Verify(data, signature, testPrvKey), nil)
+	key, _ := parseAnyPublicKey([]byte(edPub))
+	ssh, _ := marshalAnyPublicKey(key, KeyFormatSSH)
+	jwk, _ := marshalAnyPublicKey(key, KeyFormatJWK)
+	signature, _ = Sign(data, edPrv)
+	testutil.Equal(t, Verify(data, signature, string(ssh)), nil)
+	testutil.Equal(t, Verify(data, signature, string(jwk)), nil)
+
+	_, err := Sign(data, xPrv)
+	testutil.Equal(t, errors.Is(err, gotool.ErrNotSupportType), true)
+
+}
+
+func TestParseDERKeyWhitespaceBytes(t *testing.T) {
+	// DER 末尾是密钥字节, 可能恰好是空白字符, 多次生成以覆盖这种情况
+	for i := 0; i < 500; i++ {
+		pub, prv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prvDER, _ := Ed25519MarshalPrivateKey(prv, KeyFormatDER)
+		gotPrv, err := Ed25519ParsePrivateKey(prvDER)
+		if err != nil {
+			t.Fatalf("%x: %v", prvDER, err)
+		}
+		testutil.Equal(t, gotPrv.Equal(prv), true)
+
+		pubDER, _ := Ed25519MarshalPublicKey(pub, KeyFormatDER)
+		gotPub, err := Ed25519ParsePublicKey(pubDER)
+		if err != nil {
+			t.Fatalf("%x: %v", pubDER, err)
+		}
+		testutil.Equal(t, gotPub.Equal(pub), true)
+	}
+
+	// 末尾带换行的 DER 仍可解析
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+	pubDER, _ := Ed25519MarshalPublicKey(pub, KeyFormatDER)
+	for pubDER[len(pubDER)-1] <= ' ' {
+		pub, _, _ = ed25519.GenerateKey(rand.Reader)
+		pubDER, _ = Ed25519MarshalPublicKey(pub, KeyFormatDER)
+	}
+	gotPub, err := Ed25519ParsePublicKey(append(pubDER, '\n'))
+	testutil.Equal(t, err, nil)
+	testutil.Equal(t, gotPub.Equal(pub), true)
+}
@@ -0,0 +1,307 @@
+package cryptoutil
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/up-zero/gotool"
+	"github.com/up-zero/gotool/imageutil"
+)
+
+// OTP 的 HMAC 算法
+const (
+	OtpSHA1   = "SHA1"
+	OtpSHA256 = "SHA256"
+	OtpSHA512 = "SHA512"
+)
+
+// otpEncoding 密钥使用无填充的 Base32 编码
+var otpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// OtpConfig 一次性密码配置，零值字段使用默认值
+type OtpConfig struct {
+	Algorithm string        // HMAC 算法，默认 OtpSHA1
+	Digits    int           // 密码位数，范围 6-8，默认 6
+	Period    time.Duration // TOTP 时间步长，默认 30s
+	Skew      int           // 校验时允许偏差的步数，TOTP 为前后各 Skew 个时间步，HOTP 为向后 Skew 个计数器，默认 1，小于 0 时不允许偏差
+}
+
+// setDefaults 设置默认值
+func (c *OtpConfig) setDefaults() {
+	if c.Algorithm == "" {
+		c.Algorithm = OtpSHA1
+	}
+	if c.Digits == 0 {
+		c.Digits = 6
+	}
+	if c.Period <= 0 {
+		c.Period = 30 * time.Second
+	}
+	if c.Skew == 0 {
+		c.Skew = 1
+	}
+	if c.Skew < 0 {
+		c.Skew = 0
+	}
+}
+
+// newOtpConfig 合并可选配置并校验参数
+func newOtpConfig(conf []OtpConfig) (OtpConfig, error) {
+	var c OtpConfig
+	if len(conf) > 0 {
+		c = conf[0]
+	}
+	c.setDefaults()
+	if _, err := otpHash(c.Algorithm); err != nil {
+		return c, err
+	}
+	if c.Digits < 6 || c.Digits > 8 {
+		return c, fmt.Errorf("%w, OTP digits must be between 6 and 8: %d", gotool.ErrInvalidParam, c.Digits)
+	}
+	if c.Period%time.Second != 0 {
+		return c, fmt.Errorf("%w, OTP period must be a whole number of seconds: %s", gotool.ErrInvalidParam, c.Period)
+	}
+	return c, nil
+}
+
+// otpHash 获取 OTP 算法对应的哈希
+func otpHash(algorithm string) (crypto.Hash, error) {
+	switch algorithm {
+	case OtpSHA1:
+		return crypto.SHA1, nil
+	case OtpSHA256:
+		return crypto.SHA256, nil
+	case OtpSHA512:
+		return crypto.SHA512, nil
+	}
+	return 0, fmt.Errorf("%w, unsupported OTP algorithm: %s", gotool.ErrInvalidParam, algorithm)
+}
+
+// GenerateOtpSecret 生成随机的 OTP 密钥，返回无填充的 Base32 字符串
+//
+// # Params:
+//
+//	size: 密钥字节数，默认 20 (与 SHA1 输出长度相同)
+func GenerateOtpSecret(size ...int) (string, error) {
+	n := 20
+	if len(size) > 0 && size[0] > 0 {
+		n = size[0]
+	}
+	key, err := randomBytes(n)
+	if err != nil {
+		return "", err
+	}
+	return otpEncoding.EncodeToString(key), nil
+}
+
+// otpDecodeSecret 解码 Base32 密钥，忽略大小写、空格和填充
+func otpDecodeSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	key, err := otpEncoding.DecodeString(strings.TrimRight(secret, "="))
+	if err != nil || len(key) == 0 {
+		return nil, fmt.Errorf("%w, invalid OTP secret", gotool.ErrInvalidParam)
+	}
+	return key, nil
+}
+
+// otpGenerate 按 RFC 4226 计算指定计数器的一次性密码
+func otpGenerate(key []byte, counter uint64, c OtpConfig) string {
+	hash, _ := otpHash(c.Algorithm)
+	sum := hmacGenerate(binary.BigEndian.AppendUint64(nil, counter), key, hash)
+
+	// 动态截断
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < c.Digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", c.Digits, code%mod)
+}
+
+// HOTP 生成基于计数器的一次性密码 (RFC 4226)
+//
+// # Params:
+//
+//	secret: Base32 编码的密钥
+//	counter: 计数器
+//	conf: 配置，Period 无效
+func HOTP(secret string, counter uint64, conf ...OtpConfig) (string, error) {
+	c, err := newOtpConfig(conf)
+	if err != nil {
+		return "", err
+	}
+	key, err := otpDecodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return otpGenerate(key, counter, c), nil
+}
+
+// VerifyHOTP 校验基于计数器的一次性密码，在 [counter, counter+Skew] 范围内查找匹配项
+//
+// 校验成功时返回下一个计数器，调用方应保存该值，防止密码被重复使用
+//
+// # Params:
+//
+//	secret: Base32 编码的密钥
+//	code: 待校验的密码
+//	counter: 服务端保存的计数器
+//	conf: 配置
+func VerifyHOTP(secret, code string, counter uint64, conf ...OtpConfig) (next uint64, ok bool, err error) {
+	c, err := newOtpConfig(conf)
+	if err != nil {
+		return counter, false, err
+	}
+	key, err := otpDecodeSecret(secret)
+	if err != nil {
+		return counter, false, err
+	}
+	if len(code) != c.Digits {
+		return counter, false, nil
+	}
+	for i := 0; i <= c.Skew; i++ {
+		if subtle.ConstantTimeCompare([]byte(otpGenerate(key, counter+uint64(i), c)), []byte(code)) == 1 {
+			return counter + uint64(i) + 1, true, nil
+		}
+	}
+	return counter, false, nil
+}
+
+// totpCounter 计算时间对应的 TOTP 时间步
+func totpCounter(t time.Time, c OtpConfig) uint64 {
+	return uint64(t.Unix()) / uint64(c.Period/time.Second)
+}
+
+// TOTP 生成基于时间的一次性密码 (RFC 6238)
+//
+// # Params:
+//
+//	secret: Base32 编码的密钥
+//	t: 时间，通常为 time.Now()
+//	conf: 配置
+func TOTP(secret string, t time.Time, conf ...OtpConfig) (string, error) {
+	c, err := newOtpConfig(conf)
+	if err != nil {
+		return "", err
+	}
+	key, err := otpDecodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return otpGenerate(key, totpCounter(t, c), c), nil
+}
+
+// VerifyTOTP 校验基于时间的一次性密码，允许前后 Skew 个时间步的时钟偏差
+//
+// # Params:
+//
+//	secret: Base32 编码的密钥
+//	code: 待校验的密码
+//	t: 校验时间，通常为 time.Now()
+//	conf: 配置
+func VerifyTOTP(secret, code string, t time.Time, conf ...OtpConfig) (bool, error) {
+	c, err := newOtpConfig(conf)
+	if err != nil {
+		return false, err
+	}
+	key, err := otpDecodeSecret(secret)
+	if err != nil {
+		return false, err
+	}
+	if len(code) != c.Digits {
+		return false, nil
+	}
+	counter := totpCounter(t, c)
+	ok := 0
+	for i := -c.Skew; i <= c.Skew; i++ {
+		if i < 0 && counter < uint64(-i) {
+			continue
+		}
+		ok |= subtle.ConstantTimeCompare([]byte(otpGenerate(key, counter+uint64(i), c)), []byte(code))
+	}
+	return ok == 1, nil
+}
+
+// otpURI 生成 otpauth:// URI
+func otpURI(otpType, secret, issuer, account string, c OtpConfig, extra url.Values) (string, error) {
+	key, err := otpDecodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	label := url.PathEscape(account)
+	if issuer != "" {
+		label = url.PathEscape(issuer) + ":" + label
+	}
+	query := url.Values{}
+	query.Set("secret", otpEncoding.EncodeToString(key))
+	if issuer != "" {
+		query.Set("issuer", issuer)
+	}
+	query.Set("algorithm", c.Algorithm)
+	query.Set("digits", strconv.Itoa(c.Digits))
+	for k, v := range extra {
+		query[k] = v
+	}
+	return "otpauth://" + otpType + "/" + label + "?" + query.Encode(), nil
+}
+
+// TotpURI 生成 TOTP 的 otpauth:// URI，可用于生成二维码供身份验证器 App 扫描
+//
+// # Params:
+//
+//	secret: Base32 编码的密钥
+//	issuer: 签发者，例如应用名称
+//	account: 账户名，例如用户名或邮箱
+//	conf: 配置
+//
+// # Example:
+//
+//	TotpURI("JBSWY3DPEHPK3PXP", "gotool", "admin@example.com")
+//	// otpauth://totp/gotool:admin@example.com?algorithm=SHA1&digits=6&issuer=gotool&period=30&secret=JBSWY3DPEHPK3PXP
+func TotpURI(secret, issuer, account string, conf ...OtpConfig) (string, error) {
+	c, err := newOtpConfig(conf)
+	if err != nil {
+		return "", err
+	}
+	return otpURI("totp", secret, issuer, account, c, url.Values{
+		"period": {strconv.Itoa(int(c.Period / time.Second))},
+	})
+}
+
+// HotpURI 生成 HOTP 的 otpauth:// URI
+//
+// # Params:
+//
+//	secret: Base32 编码的密钥
+//	issuer: 签发者，例如应用名称
+//	account: 账户名，例如用户名或邮箱
+//	counter: 初始计数器
+//	conf: 配置
+func HotpURI(secret, issuer, account string, counter uint64, conf ...OtpConfig) (string, error) {
+	c, err := newOtpConfig(conf)
+	if err != nil {
+		return "", err
+	}
+	return otpURI("hotp", secret, issuer, account, c, url.Values{
+		"counter": {strconv.FormatUint(counter, 10)},
+	})
+}
+
+// OtpQRCode 将 otpauth:// URI 渲染为二维码图片
+//
+// # Params:
+//
+//	uri: TotpURI 或 HotpURI 生成的 URI
+//	size: 图片边长 (像素)
+func OtpQRCode(uri string, size int) (image.Image, error) {
+	return imageutil.QRCode(uri, size, imageutil.QRLevelM)
+}
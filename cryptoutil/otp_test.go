@@ -0,0 +1,122 @@
+package cryptoutil
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestHOTP(t *testing.T) {
+	// RFC 4226 Appendix D
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for i, w := range want {
+		code, err := HOTP(secret, uint64(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, code, w)
+	}
+
+	next, ok, err := VerifyHOTP(secret, "359152", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, ok, true)
+	testutil.Equal(t, next, uint64(3))
+
+	next, ok, _ = VerifyHOTP(secret, "969429", 1)
+	testutil.Equal(t, ok, false)
+	testutil.Equal(t, next, uint64(1))
+
+	_, ok, _ = VerifyHOTP(secret, "359152", 1, OtpConfig{Skew: -1})
+	testutil.Equal(t, ok, false)
+
+	_, err = HOTP("not base32!", 0)
+	testutil.NotEqual(t, err, nil)
+	_, err = HOTP(secret, 0, OtpConfig{Digits: 4})
+	testutil.NotEqual(t, err, nil)
+	_, err = HOTP(secret, 0, OtpConfig{Algorithm: "MD5"})
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestTOTP(t *testing.T) {
+	// RFC 6238 Appendix B
+	seeds := map[string]string{
+		OtpSHA1:   "12345678901234567890",
+		OtpSHA256: "12345678901234567890123456789012",
+		OtpSHA512: "1234567890123456789012345678901234567890123456789012345678901234",
+	}
+	tests := []struct {
+		time int64
+		want map[string]string
+	}{
+		{59, map[string]string{OtpSHA1: "94287082", OtpSHA256: "46119246", OtpSHA512: "90693936"}},
+		{1111111109, map[string]string{OtpSHA1: "07081804", OtpSHA256: "68084774", OtpSHA512: "25091201"}},
+		{1111111111, map[string]string{OtpSHA1: "14050471", OtpSHA256: "67062674", OtpSHA512: "99943326"}},
+		{1234567890, map[string]string{OtpSHA1: "89005924", OtpSHA256: "91819424", OtpSHA512: "93441116"}},
+		{2000000000, map[string]string{OtpSHA1: "69279037", OtpSHA256: "90698825", OtpSHA512: "38618901"}},
+		{20000000000, map[string]string{OtpSHA1: "65353130", OtpSHA256: "77737706", OtpSHA512: "47863826"}},
+	}
+	for _, tt := range tests {
+		for alg, want := range tt.want {
+			secret := base32.StdEncoding.EncodeToString([]byte(seeds[alg]))
+			code, err := TOTP(secret, time.Unix(tt.time, 0), OtpConfig{Algorithm: alg, Digits: 8})
+			if err != nil {
+				t.Fatal(err)
+			}
+			testutil.Equal(t, code, want)
+		}
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	secret, err := GenerateOtpSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, len(secret), 32)
+
+	now := time.Unix(1700000000, 0)
+	code, err := TOTP(secret, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := VerifyTOTP(strings.ToLower(secret), code, now.Add(30*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, ok, true)
+
+	ok, _ = VerifyTOTP(secret, code, now.Add(90*time.Second))
+	testutil.Equal(t, ok, false)
+	ok, _ = VerifyTOTP(secret, code, now.Add(90*time.Second), OtpConfig{Skew: 3})
+	testutil.Equal(t, ok, true)
+	ok, _ = VerifyTOTP(secret, code, now.Add(30*time.Second), OtpConfig{Skew: -1})
+	testutil.Equal(t, ok, false)
+	ok, _ = VerifyTOTP(secret, code[:5], now)
+	testutil.Equal(t, ok, false)
+}
+
+func TestTotpURI(t *testing.T) {
+	uri, err := TotpURI("JBSWY3DPEHPK3PXP", "gotool", "admin@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, uri, "otpauth://totp/gotool:admin@example.com?algorithm=SHA1&digits=6&issuer=gotool&period=30&secret=JBSWY3DPEHPK3PXP")
+
+	uri, err = HotpURI("jbsw y3dp ehpk 3pxp", "My App", "alice", 5, OtpConfig{Algorithm: OtpSHA256, Digits: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, uri, "otpauth://hotp/My%20App:alice?algorithm=SHA256&counter=5&digits=8&issuer=My+App&secret=JBSWY3DPEHPK3PXP")
+
+	img, err := OtpQRCode(uri, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, img.Bounds().Dx(), 200)
+}
@@ -0,0 +1,346 @@
+package cryptoutil
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/up-zero/gotool"
+)
+
+// 密码哈希算法
+const (
+	PasswordArgon2id     = "argon2id"      // Argon2id (RFC 9106)，默认算法
+	PasswordScrypt       = "scrypt"        // scrypt (RFC 7914)
+	PasswordBcrypt       = "bcrypt"        // bcrypt，密码最长 72 字节
+	PasswordPbkdf2Sha256 = "pbkdf2-sha256" // PBKDF2-HMAC-SHA256 (RFC 8018)
+	PasswordPbkdf2Sha512 = "pbkdf2-sha512" // PBKDF2-HMAC-SHA512 (RFC 8018)
+)
+
+// 校验时允许的参数上限, 防止恶意构造的哈希消耗过多资源
+const (
+	passwordMaxArgon2Memory = 1 << 21 // 2GiB
+	passwordMaxArgon2Time   = 1024
+	passwordMaxScryptLogN   = 22
+	passwordMaxScryptR      = 32
+	passwordMaxScryptP      = 16
+	passwordMaxIterations   = 10000000
+	passwordMaxSaltLength   = 1024
+	passwordMaxKeyLength    = 1024
+	passwordMinKeyLength    = 16
+)
+
+// PasswordConfig 密码哈希配置，零值字段使用默认值
+type PasswordConfig struct {
+	Algorithm   string // 算法, 默认 PasswordArgon2id
+	Time        uint32 // Argon2id 迭代次数, 默认 2
+	Memory      uint32 // Argon2id 内存大小 (KiB), 默认 19456 (19MiB)
+	Parallelism uint8  // Argon2id 和 scrypt 的并行度, 默认 1
+	ScryptLogN  uint8  // scrypt 的 log2(N), 默认 15
+	ScryptR     int    // scrypt 的块大小, 默认 8
+	Cost        int    // bcrypt 代价因子, 默认 BcryptDefaultCost
+	Iterations  int    // PBKDF2 迭代次数, SHA-256 默认 600000, SHA-512 默认 210000
+	SaltLength  int    // 盐长度, 默认 16 (bcrypt 固定为 16)
+	KeyLength   int    // 哈希长度, 默认 32 (bcrypt 固定为 23)
+}
+
+// setDefaults 设置默认值
+func (c *PasswordConfig) setDefaults() {
+	if c.Algorithm == "" {
+		c.Algorithm = PasswordArgon2id
+	}
+	if c.Time == 0 {
+		c.Time = 2
+	}
+	if c.Memory == 0 {
+		c.Memory = 19456
+	}
+	if c.Parallelism == 0 {
+		c.Parallelism = 1
+	}
+	if c.ScryptLogN == 0 {
+		c.ScryptLogN = 15
+	}
+	if c.ScryptR <= 0 {
+		c.ScryptR = 8
+	}
+	if c.Cost <= 0 {
+		c.Cost = BcryptDefaultCost
+	}
+	if c.Iterations <= 0 {
+		c.Iterations = 600000
+		if c.Algorithm == PasswordPbkdf2Sha512 {
+			c.Iterations = 210000
+		}
+	}
+	if c.SaltLength <= 0 {
+		c.SaltLength = 16
+	}
+	if c.KeyLength <= 0 {
+		c.KeyLength = 32
+	}
+}
+
+// validate 检查参数是否在校验时允许的范围内, 避免生成无法校验的哈希
+func (c *PasswordConfig) validate() error {
+	valid := true
+	switch c.Algorithm {
+	case PasswordBcrypt:
+		if c.Cost < BcryptMinCost || c.Cost > BcryptMaxCost {
+			return fmt.Errorf("%w, bcrypt cost %d", gotool.ErrInvalidParam, c.Cost)
+		}
+		return nil
+	case PasswordArgon2id:
+		valid = c.Time >= 1 && c.Time <= passwordMaxArgon2Time && c.Parallelism >= 1 &&
+			c.Memory >= 8*uint32(c.Parallelism) && c.Memory <= passwordMaxArgon2Memory
+	case PasswordScrypt:
+		valid = c.ScryptLogN >= 1 && c.ScryptLogN <= passwordMaxScryptLogN && c.ScryptR >= 1 && c.ScryptR <= passwordMaxScryptR &&
+			c.Parallelism >= 1 && c.Parallelism <= passwordMaxScryptP
+	case PasswordPbkdf2Sha256, PasswordPbkdf2Sha512:
+		valid = c.Iterations >= 1 && c.Iterations <= passwordMaxIterations
+	default:
+		return fmt.Errorf("%w, password algorithm %s", gotool.ErrInvalidParam, c.Algorithm)
+	}
+	if !valid {
+		return fmt.Errorf("%w, %s parameters out of range", gotool.ErrInvalidParam, c.Algorithm)
+	}
+	if c.KeyLength < passwordMinKeyLength || c.KeyLength > passwordMaxKeyLength || c.SaltLength > passwordMaxSaltLength {
+		return fmt.Errorf("%w, password key length %d, salt length %d", gotool.ErrInvalidParam, c.KeyLength, c.SaltLength)
+	}
+	return nil
+}
+
+// passwordHash 解析后的密码哈希
+type passwordHash struct {
+	conf PasswordConfig
+	salt []byte
+	hash []byte
+}
+
+// PasswordHash 生成 PHC 格式的密码哈希, 每次使用随机盐
+//
+// 格式示例:
+//
+//	$argon2id$v=19$m=19456,t=2,p=1$<salt>$<hash>
+//	$scrypt$ln=15,r=8,p=1$<salt>$<hash>
+//	$pbkdf2-sha256$i=600000,l=32$<salt>$<hash>
+//	$2b$10$<salt><hash> (bcrypt)
+//
+// # Params:
+//
+//	password: 密码
+//	conf: 配置, 默认使用 Argon2id
+func PasswordHash(password string, conf ...PasswordConfig) (string, error) {
+	var c PasswordConfig
+	if len(conf) > 0 {
+		c = conf[0]
+	}
+	c.setDefaults()
+	if err := c.validate(); err != nil {
+		return "", err
+	}
+
+	if c.Algorithm == PasswordBcrypt {
+		return BcryptHash([]byte(password), c.Cost)
+	}
+	salt, err := randomBytes(c.SaltLength)
+	if err != nil {
+		return "", err
+	}
+	p := &passwordHash{conf: c, salt: salt}
+	if p.hash, err = p.derive(password); err != nil {
+		return "", err
+	}
+	return p.String(), nil
+}
+
+// PasswordVerify 以常量时间校验密码与 PasswordHash 生成的哈希是否匹配
+//
+// # Params:
+//
+//	password: 密码
+//	encoded: PasswordHash 生成的哈希, 也支持 $2a$, $2b$, $2y$ 前缀的 bcrypt 哈希
+func PasswordVerify(password, encoded string) (bool, error) {
+	p, err := parsePasswordHash(encoded)
+	if err != nil {
+		return false, err
+	}
+	if p.conf.Algorithm == PasswordBcrypt {
+		return BcryptVerify([]byte(password), encoded)
+	}
+	hash, err := p.derive(password)
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(hash, p.hash) == 1, nil
+}
+
+// NeedsRehash 判断哈希的算法或参数是否与配置不一致, 通常在校验成功后调用, 需要时重新生成哈希,
+// 配置超出允许范围时返回 gotool.ErrInvalidParam
+//
+// # Params:
+//
+//	encoded: PasswordHash 生成的哈希
+//	conf: 当前的配置, 默认使用 Argon2id
+func NeedsRehash(encoded string, conf ...PasswordConfig) (bool, error) {
+	var c PasswordConfig
+	if len(conf) > 0 {
+		c = conf[0]
+	}
+	c.setDefaults()
+	if err := c.validate(); err != nil {
+		return false, err
+	}
+
+	p, err := parsePasswordHash(encoded)
+	if err != nil || p.conf.Algorithm != c.Algorithm {
+		return true, nil
+	}
+	switch c.Algorithm {
+	case PasswordBcrypt:
+		return p.conf.Cost != c.Cost, nil
+	case PasswordArgon2id:
+		if p.conf.Time != c.Time || p.conf.Memory != c.Memory || p.conf.Parallelism != c.Parallelism {
+			return true, nil
+		}
+	case PasswordScrypt:
+		if p.conf.ScryptLogN != c.ScryptLogN || p.conf.ScryptR != c.ScryptR || p.conf.Parallelism != c.Parallelism {
+			return true, nil
+		}
+	default:
+		if p.conf.Iterations != c.Iterations {
+			return true, nil
+		}
+	}
+	return len(p.salt) < c.SaltLength || len(p.hash) != c.KeyLength, nil
+}
+
+// derive 根据配置计算哈希
+func (p *passwordHash) derive(password string) ([]byte, error) {
+	c := p.conf
+	switch c.Algorithm {
+	case PasswordArgon2id:
+		return Argon2idKey([]byte(password), p.salt, c.Time, c.Memory, c.Parallelism, uint32(c.KeyLength)), nil
+	case PasswordScrypt:
+		return Scrypt([]byte(password), p.salt, 1<<c.ScryptLogN, c.ScryptR, int(c.Parallelism), c.KeyLength)
+	case PasswordPbkdf2Sha256:
+		return Pbkdf2([]byte(password), p.salt, c.Iterations, c.KeyLength, crypto.SHA256), nil
+	case PasswordPbkdf2Sha512:
+		return Pbkdf2([]byte(password), p.salt, c.Iterations, c.KeyLength, crypto.SHA512), nil
+	}
+	return nil, fmt.Errorf("%w, password algorithm %s", gotool.ErrInvalidParam, c.Algorithm)
+}
+
+// String 输出 PHC 格式的哈希
+func (p *passwordHash) String() string {
+	c := p.conf
+	var params string
+	switch c.Algorithm {
+	case PasswordArgon2id:
+		params = fmt.Sprintf("v=%d$m=%d,t=%d,p=%d", argon2Version, c.Memory, c.Time, c.Parallelism)
+	case PasswordScrypt:
+		params = fmt.Sprintf("ln=%d,r=%d,p=%d", c.ScryptLogN, c.ScryptR, c.Parallelism)
+	default:
+		params = fmt.Sprintf("i=%d,l=%d", c.Iterations, c.KeyLength)
+	}
+	return "$" + c.Algorithm + "$" + params + "$" +
+		base64.RawStdEncoding.EncodeToString(p.salt) + "$" + base64.RawStdEncoding.EncodeToString(p.hash)
+}
+
+// parsePasswordHash 解析 PHC 格式或 bcrypt 格式的哈希, 并检查参数是否在允许范围内
+func parsePasswordHash(encoded string) (*passwordHash, error) {
+	if strings.HasPrefix(encoded, "$2") {
+		_, cost, salt, err := bcryptParse(encoded)
+		if err != nil {
+			return nil, err
+		}
+		return &passwordHash{conf: PasswordConfig{Algorithm: PasswordBcrypt, Cost: cost}, salt: salt}, nil
+	}
+
+	parts := strings.Split(encoded, "$")
+	if len(parts) < 5 || parts[0] != "" {
+		return nil, fmt.Errorf("%w, invalid password hash", gotool.ErrNotSupportFormat)
+	}
+	p := &passwordHash{conf: PasswordConfig{Algorithm: parts[1]}}
+	switch parts[1] {
+	case PasswordArgon2id:
+		if len(parts) != 6 || parts[2] != "v="+strconv.Itoa(argon2Version) {
+			return nil, fmt.Errorf("%w, unsupported argon2id version", gotool.ErrNotSupportFormat)
+		}
+		parts = append(parts[:2], parts[3:]...)
+	case PasswordScrypt, PasswordPbkdf2Sha256, PasswordPbkdf2Sha512:
+		if len(parts) != 5 {
+			return nil, fmt.Errorf("%w, invalid password hash", gotool.ErrNotSupportFormat)
+		}
+	default:
+		return nil, fmt.Errorf("%w, password algorithm %s", gotool.ErrNotSupportFormat, parts[1])
+	}
+
+	params, err := parsePasswordParams(parts[2])
+	if err != nil {
+		return nil, err
+	}
+	if p.salt, err = base64.RawStdEncoding.DecodeString(parts[3]); err != nil {
+		return nil, fmt.Errorf("%w, invalid password salt", gotool.ErrNotSupportFormat)
+	}
+	if p.hash, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, fmt.Errorf("%w, invalid password hash", gotool.ErrNotSupportFormat)
+	}
+	if len(p.salt) == 0 || len(p.salt) > passwordMaxSaltLength || len(p.hash) < passwordMinKeyLength || len(p.hash) > passwordMaxKeyLength {
+		return nil, fmt.Errorf("%w, invalid password salt or hash length", gotool.ErrNotSupportFormat)
+	}
+	p.conf.SaltLength = len(p.salt)
+	p.conf.KeyLength = len(p.hash)
+
+	// 先检查原始参数, 避免转换为较小的整数类型时溢出
+	c := &p.conf
+	valid := false
+	switch c.Algorithm {
+	case PasswordArgon2id:
+		c.Memory, c.Time = uint32(params["m"]), uint32(params["t"])
+		c.Parallelism = uint8(params["p"])
+		valid = params["p"] <= 255 && params["m"] <= passwordMaxArgon2Memory && params["t"] <= passwordMaxArgon2Time
+	case PasswordScrypt:
+		c.ScryptLogN, c.ScryptR = uint8(params["ln"]), params["r"]
+		c.Parallelism = uint8(params["p"])
+		valid = params["ln"] <= passwordMaxScryptLogN && params["p"] <= passwordMaxScryptP
+	default:
+		c.Iterations = params["i"]
+		valid = params["l"] == len(p.hash)
+	}
+	if !valid || c.validate() != nil {
+		return nil, fmt.Errorf("%w, password hash parameters out of range", gotool.ErrNotSupportFormat)
+	}
+	return p, nil
+}
+
+// parsePasswordParams 解析 k=v,k=v 形式的参数
+func parsePasswordParams(s string) (map[string]int, error) {
+	params := make(map[string]int)
+	for _, kv := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w, invalid password hash parameter %q", gotool.ErrNotSupportFormat, kv)
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%w, invalid password hash parameter %q", gotool.ErrNotSupportFormat, kv)
+		}
+		params[k] = n
+	}
+	return params, nil
+}
+
+// randomBytes 生成指定长度的随机字节
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
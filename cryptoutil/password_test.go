@@ -0,0 +1,145 @@
+package cryptoutil
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/up-zero/gotool"
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestPasswordHash(t *testing.T) {
+	confs := []PasswordConfig{
+		{Algorithm: PasswordArgon2id, Time: 1, Memory: 64},
+		{Algorithm: PasswordScrypt, ScryptLogN: 10},
+		{Algorithm: PasswordBcrypt, Cost: BcryptMinCost},
+		{Algorithm: PasswordPbkdf2Sha256, Iterations: 1000},
+		{Algorithm: PasswordPbkdf2Sha512, Iterations: 1000},
+	}
+	for _, conf := range confs {
+		encoded, err := PasswordHash("get", conf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if conf.Algorithm != PasswordBcrypt {
+			testutil.Equal(t, strings.HasPrefix(encoded, "$"+conf.Algorithm+"$"), true)
+		}
+
+		ok, err := PasswordVerify("get", encoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, ok, true)
+		ok, err = PasswordVerify("got", encoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, ok, false)
+
+		// 盐随机, 两次结果不同
+		encoded2, _ := PasswordHash("get", conf)
+		testutil.NotEqual(t, encoded, encoded2)
+	}
+
+	_, err := PasswordHash("get", PasswordConfig{Algorithm: "md5"})
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestPasswordVerify(t *testing.T) {
+	tests := []struct {
+		encoded string
+		want    bool
+	}{
+		// Argon2 参考实现的测试向量
+		{"$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc", true},
+		{"$scrypt$ln=4,r=1,p=1$c2FsdA$RRM8PfukjIIjXfUaU0mSQRDu6JN1Lw1BaNLiruVyLYI", true},
+		{"$pbkdf2-sha256$i=1000,l=32$c2FsdA$YywoEuRtRgQQK6dhjp1tfS+BKPYma0oDJk0qBGC33LM", true},
+		{"$pbkdf2-sha256$i=999,l=32$c2FsdA$YywoEuRtRgQQK6dhjp1tfS+BKPYma0oDJk0qBGC33LM", false},
+		{"$2b$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW", false},
+	}
+	for _, tt := range tests {
+		ok, err := PasswordVerify("password", tt.encoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, ok, tt.want)
+	}
+
+	for _, encoded := range []string{
+		"",
+		"plain",
+		"$argon2i$v=19$m=64,t=1,p=1$c29tZXNhbHQ$RdescudvJCsgt3ub+b+dWRWJTmaaJObG",
+		"$argon2id$v=16$m=64,t=1,p=1$c29tZXNhbHQ$RdescudvJCsgt3ub+b+dWRWJTmaaJObG",
+		"$argon2id$v=19$m=4194304,t=1,p=1$c29tZXNhbHQ$RdescudvJCsgt3ub+b+dWRWJTmaaJObG",
+		"$argon2id$v=19$m=64,t=1$c29tZXNhbHQ$RdescudvJCsgt3ub+b+dWRWJTmaaJObG",
+		"$scrypt$ln=30,r=8,p=1$c2FsdA$d1iSL+OtpdUYMRcOjPCdyEjL0tKW44E7LHW+h7fEDTw",
+		"$pbkdf2-sha256$i=1000,l=16$c2FsdA$d1iSL+OtpdUYMRcOjPCdyEjL0tKW44E7LHW+h7fEDTw",
+		"$pbkdf2-sha256$i=abc,l=32$c2FsdA$d1iSL+OtpdUYMRcOjPCdyEjL0tKW44E7LHW+h7fEDTw",
+	} {
+		_, err := PasswordVerify("password", encoded)
+		testutil.NotEqual(t, err, nil)
+	}
+}
+
+// needsRehash 调用 NeedsRehash, 配置错误时测试失败
+func needsRehash(t *testing.T, encoded string, conf ...PasswordConfig) bool {
+	t.Helper()
+	ok, err := NeedsRehash(encoded, conf...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ok
+}
+
+func TestPasswordConfigOutOfRange(t *testing.T) {
+	// 超出校验上限的配置无法生成可校验的哈希, 直接拒绝
+	for _, conf := range []PasswordConfig{
+		{Time: passwordMaxArgon2Time + 1, Memory: 64},
+		{Time: 1, Memory: 8, Parallelism: 2},
+		{Time: 1, Memory: passwordMaxArgon2Memory + 1},
+		{Algorithm: PasswordScrypt, ScryptLogN: passwordMaxScryptLogN + 1},
+		{Algorithm: PasswordScrypt, ScryptLogN: 200},
+		{Algorithm: PasswordScrypt, ScryptR: passwordMaxScryptR + 1},
+		{Algorithm: PasswordScrypt, Parallelism: passwordMaxScryptP + 1},
+		{Algorithm: PasswordPbkdf2Sha256, Iterations: passwordMaxIterations + 1},
+		{Algorithm: PasswordBcrypt, Cost: BcryptMaxCost + 1},
+		{KeyLength: passwordMaxKeyLength + 1},
+		{Algorithm: "md5"},
+	} {
+		_, err := PasswordHash("pw", conf)
+		testutil.Equal(t, errors.Is(err, gotool.ErrInvalidParam), true)
+		_, err = NeedsRehash("$2b$04$", conf)
+		testutil.Equal(t, errors.Is(err, gotool.ErrInvalidParam), true)
+	}
+
+	// 边界值生成的哈希可以校验
+	encoded, err := PasswordHash("pw", PasswordConfig{Time: 1, Memory: 16, Parallelism: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := PasswordVerify("pw", encoded)
+	testutil.Equal(t, err, nil)
+	testutil.Equal(t, ok, true)
+}
+
+func TestNeedsRehash(t *testing.T) {
+	conf := PasswordConfig{Algorithm: PasswordArgon2id, Time: 1, Memory: 64}
+	encoded, err := PasswordHash("get", conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, needsRehash(t, encoded, conf), false)
+	testutil.Equal(t, needsRehash(t, encoded, PasswordConfig{Algorithm: PasswordArgon2id, Time: 2, Memory: 64}), true)
+	testutil.Equal(t, needsRehash(t, encoded, PasswordConfig{Algorithm: PasswordArgon2id, Time: 1, Memory: 64, KeyLength: 64}), true)
+	testutil.Equal(t, needsRehash(t, encoded, PasswordConfig{Algorithm: PasswordScrypt}), true)
+	testutil.Equal(t, needsRehash(t, encoded), true)
+
+	encoded, err = PasswordHash("get", PasswordConfig{Algorithm: PasswordBcrypt, Cost: BcryptMinCost})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, needsRehash(t, encoded, PasswordConfig{Algorithm: PasswordBcrypt, Cost: BcryptMinCost}), false)
+	testutil.Equal(t, needsRehash(t, encoded, PasswordConfig{Algorithm: PasswordBcrypt}), true)
+	testutil.Equal(t, needsRehash(t, "invalid"), true)
+}
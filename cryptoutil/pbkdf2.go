@@ -0,0 +1,47 @@
+package cryptoutil
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"encoding/binary"
+)
+
+// Pbkdf2 PBKDF2 密钥派生 (RFC 8018)
+//
+// # Params:
+//
+//	password: 密码
+//	salt: 盐
+//	iterations: 迭代次数
+//	keyLen: 派生密钥的长度
+//	hash: HMAC 使用的哈希算法, 例如: crypto.SHA256
+func Pbkdf2(password, salt []byte, iterations, keyLen int, hash crypto.Hash) []byte {
+	prf := hmac.New(hash.New, password)
+	hashLen := prf.Size()
+	numBlocks := (keyLen + hashLen - 1) / hashLen
+
+	var counter [4]byte
+	dk := make([]byte, 0, numBlocks*hashLen)
+	u := make([]byte, hashLen)
+	for block := 1; block <= numBlocks; block++ {
+		// U1 = PRF(P, S || INT(i))
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(counter[:], uint32(block))
+		prf.Write(counter[:])
+		dk = prf.Sum(dk)
+		t := dk[len(dk)-hashLen:]
+		copy(u, t)
+
+		// Un = PRF(P, Un-1), T = U1 ^ U2 ^ ... ^ Uc
+		for n := 2; n <= iterations; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for i := range u {
+				t[i] ^= u[i]
+			}
+		}
+	}
+	return dk[:keyLen]
+}
@@ -0,0 +1,31 @@
+package cryptoutil
+
+import (
+	"crypto"
+	"encoding/hex"
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestPbkdf2(t *testing.T) {
+	// RFC 6070
+	tests := []struct {
+		password, salt string
+		iterations     int
+		keyLen         int
+		hash           crypto.Hash
+		want           string
+	}{
+		{"password", "salt", 1, 20, crypto.SHA1, "0c60c80f961f0e71f3a9b524af6012062fe037a6"},
+		{"password", "salt", 2, 20, crypto.SHA1, "ea6c014dc72d6f8ccd1ed92ace1d41f0d8de8957"},
+		{"password", "salt", 4096, 20, crypto.SHA1, "4b007901b765489abead49d926f721d065a429c1"},
+		{"passwordPASSWORDpassword", "saltSALTsaltSALTsaltSALTsaltSALTsalt", 4096, 25, crypto.SHA1, "3d2eec4fe41c849b80c8d83662c0e44a8b291a964cf2f07038"},
+		{"pass\x00word", "sa\x00lt", 4096, 16, crypto.SHA1, "56fa6aa75548099dcc37d7f03425e0c3"},
+		{"password", "salt", 1000, 64, crypto.SHA512, "afe6c5530785b6cc6b1c6453384731bd5ee432ee549fd42fb6695779ad8a1c5bf59de69c48f774efc4007d5298f9033c0241d5ab69305e7b64eceeb8d834cfec"},
+	}
+	for _, tt := range tests {
+		got := Pbkdf2([]byte(tt.password), []byte(tt.salt), tt.iterations, tt.keyLen, tt.hash)
+		testutil.Equal(t, hex.EncodeToString(got), tt.want)
+	}
+}
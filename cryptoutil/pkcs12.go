@@ -0,0 +1,626 @@
+package cryptoutil
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"unicode/utf16"
+
+	"github.com/up-zero/gotool"
+)
+
+// PKCS#12 (RFC 7292) 编码参数，与 OpenSSL 3 的默认值兼容:
+// 证书使用 PBES2 (PBKDF2-HMAC-SHA256, AES-256-CBC) 加密, 私钥使用同样算法的 pkcs8ShroudedKeyBag,
+// 完整性校验使用 HMAC-SHA256, MAC 密钥由 PKCS#12 KDF 派生
+const (
+	pkcs12Iterations = 10000
+	pkcs12SaltSize   = 16
+	pkcs12MaxIter    = 10000000
+)
+
+var (
+	oidPkcs7Data          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidPkcs7EncryptedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 6}
+	oidKeyBag             = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 1}
+	oidShroudedKeyBag     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 2}
+	oidCertBag            = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 3}
+	oidCertTypeX509       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 22, 1}
+	oidLocalKeyID         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 21}
+	oidPBES2              = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2             = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidPbeSHA13DES        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 3}
+	oidHmacSHA1           = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHmacSHA256         = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHmacSHA512         = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+	oidAES128CBC          = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC          = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC          = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidSHA1               = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidSHA256             = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidSHA512             = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+)
+
+type pkcs12Pfx struct {
+	Version  int
+	AuthSafe pkcs12ContentInfo
+	MacData  pkcs12MacData `asn1:"optional"`
+}
+
+type pkcs12ContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"tag:0,explicit,optional"`
+}
+
+type pkcs12EncryptedData struct {
+	Version              int
+	EncryptedContentInfo pkcs12EncryptedContentInfo
+}
+
+type pkcs12EncryptedContentInfo struct {
+	ContentType                asn1.ObjectIdentifier
+	ContentEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedContent           []byte `asn1:"tag:0,optional"`
+}
+
+type pkcs12SafeBag struct {
+	ID         asn1.ObjectIdentifier
+	Value      asn1.RawValue     `asn1:"tag:0,explicit"`
+	Attributes []pkcs12Attribute `asn1:"set,optional"`
+}
+
+type pkcs12Attribute struct {
+	ID    asn1.ObjectIdentifier
+	Value asn1.RawValue
+}
+
+type pkcs12CertBag struct {
+	ID   asn1.ObjectIdentifier
+	Data []byte `asn1:"tag:0,explicit"`
+}
+
+type pkcs12EncryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+type pkcs12MacData struct {
+	Mac        pkcs12DigestInfo
+	MacSalt    []byte
+	Iterations int `asn1:"optional,default:1"`
+}
+
+type pkcs12DigestInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	Digest    []byte
+}
+
+type pkcs12PBES2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+type pkcs12PBKDF2Params struct {
+	Salt       []byte
+	Iterations int
+	KeyLength  int                      `asn1:"optional"`
+	Prf        pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+type pkcs12PbeParams struct {
+	Salt       []byte
+	Iterations int
+}
+
+// Pkcs12Encode 将证书、私钥和 CA 证书打包为 PKCS#12 (.p12/.pfx)
+//
+// # Params:
+//
+//	certPEM: 证书
+//	keyPEM: 与证书匹配的私钥
+//	password: 密码
+//	caCertPEM: CA 证书，每个参数可包含多个证书
+func Pkcs12Encode(certPEM, keyPEM, password string, caCertPEM ...string) ([]byte, error) {
+	certs, err := parseCertPEM(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parseCertKey(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	leaf := certs[0]
+	if !pkcs12KeyMatches(key, leaf) {
+		return nil, fmt.Errorf("%w, private key does not match the certificate", gotool.ErrInvalidParam)
+	}
+	for _, s := range caCertPEM {
+		cas, err := parseCertPEM(s)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cas...)
+	}
+
+	// 证书和私钥通过 localKeyID 关联
+	localKeyID := sha1.Sum(leaf.Raw)
+	localKeyAttr, err := pkcs12NewAttribute(oidLocalKeyID, localKeyID[:])
+	if err != nil {
+		return nil, err
+	}
+
+	var certBags []pkcs12SafeBag
+	for i, cert := range certs {
+		bag, err := pkcs12NewBag(oidCertBag, pkcs12CertBag{ID: oidCertTypeX509, Data: cert.Raw})
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			bag.Attributes = []pkcs12Attribute{localKeyAttr}
+		}
+		certBags = append(certBags, *bag)
+	}
+	certContents, err := asn1.Marshal(certBags)
+	if err != nil {
+		return nil, err
+	}
+	certAlg, certEncrypted, err := pkcs12Encrypt(certContents, password)
+	if err != nil {
+		return nil, err
+	}
+	certInfo, err := pkcs12NewContentInfo(oidPkcs7EncryptedData, pkcs12EncryptedData{
+		EncryptedContentInfo: pkcs12EncryptedContentInfo{
+			ContentType:                oidPkcs7Data,
+			ContentEncryptionAlgorithm: certAlg,
+			EncryptedContent:           certEncrypted,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	keyAlg, keyEncrypted, err := pkcs12Encrypt(pkcs8, password)
+	if err != nil {
+		return nil, err
+	}
+	keyBag, err := pkcs12NewBag(oidShroudedKeyBag, pkcs12EncryptedPrivateKeyInfo{Algorithm: keyAlg, EncryptedData: keyEncrypted})
+	if err != nil {
+		return nil, err
+	}
+	keyBag.Attributes = []pkcs12Attribute{localKeyAttr}
+	keyContents, err := asn1.Marshal([]pkcs12SafeBag{*keyBag})
+	if err != nil {
+		return nil, err
+	}
+	keyInfo, err := pkcs12NewContentInfo(oidPkcs7Data, keyContents)
+	if err != nil {
+		return nil, err
+	}
+
+	authSafe, err := asn1.Marshal([]pkcs12ContentInfo{*certInfo, *keyInfo})
+	if err != nil {
+		return nil, err
+	}
+	authSafeInfo, err := pkcs12NewContentInfo(oidPkcs7Data, authSafe)
+	if err != nil {
+		return nil, err
+	}
+
+	macSalt, err := randomBytes(pkcs12SaltSize)
+	if err != nil {
+		return nil, err
+	}
+	macKey := pkcs12KDF(crypto.SHA256, pkcs12BMPPassword(password), macSalt, 3, pkcs12Iterations, crypto.SHA256.Size())
+	return asn1.Marshal(pkcs12Pfx{
+		Version:  3,
+		AuthSafe: *authSafeInfo,
+		MacData: pkcs12MacData{
+			Mac: pkcs12DigestInfo{
+				Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidSHA256, Parameters: asn1.NullRawValue},
+				Digest:    hmacGenerate(authSafe, macKey, crypto.SHA256),
+			},
+			MacSalt:    macSalt,
+			Iterations: pkcs12Iterations,
+		},
+	})
+}
+
+// Pkcs12Decode 解析 PKCS#12 (.p12/.pfx)，返回证书、私钥和其余的 CA 证书
+//
+// 支持 PBES2 (AES-CBC) 和 pbeWithSHAAnd3-KeyTripleDES-CBC 加密，不支持 RC2 等已废弃的算法
+//
+// # Params:
+//
+//	data: PKCS#12 数据
+//	password: 密码
+func Pkcs12Decode(data []byte, password string) (certPEM, keyPEM string, caCertPEM []string, err error) {
+	var pfx pkcs12Pfx
+	if rest, err := asn1.Unmarshal(data, &pfx); err != nil || len(rest) > 0 {
+		return "", "", nil, fmt.Errorf("%w, invalid PKCS#12 data", gotool.ErrNotSupportFormat)
+	}
+	if pfx.Version != 3 || !pfx.AuthSafe.ContentType.Equal(oidPkcs7Data) {
+		return "", "", nil, fmt.Errorf("%w, only password integrity PKCS#12 is supported", gotool.ErrNotSupportFormat)
+	}
+	var authSafe []byte
+	if _, err := asn1.Unmarshal(pfx.AuthSafe.Content.Bytes, &authSafe); err != nil {
+		return "", "", nil, fmt.Errorf("%w, invalid PKCS#12 auth safe", gotool.ErrNotSupportFormat)
+	}
+	if err := pkcs12VerifyMac(&pfx.MacData, authSafe, password); err != nil {
+		return "", "", nil, err
+	}
+
+	var contents []pkcs12ContentInfo
+	if _, err := asn1.Unmarshal(authSafe, &contents); err != nil {
+		return "", "", nil, fmt.Errorf("%w, invalid PKCS#12 auth safe", gotool.ErrNotSupportFormat)
+	}
+	var (
+		certs []*x509.Certificate
+		key   crypto.Signer
+	)
+	for _, ci := range contents {
+		bags, err := pkcs12DecodeSafeContents(&ci, password)
+		if err != nil {
+			return "", "", nil, err
+		}
+		for _, bag := range bags {
+			switch {
+			case bag.ID.Equal(oidCertBag):
+				var cb pkcs12CertBag
+				if _, err := asn1.Unmarshal(bag.Value.Bytes, &cb); err != nil || !cb.ID.Equal(oidCertTypeX509) {
+					return "", "", nil, fmt.Errorf("%w, invalid PKCS#12 cert bag", gotool.ErrNotSupportFormat)
+				}
+				cert, err := x509.ParseCertificate(cb.Data)
+				if err != nil {
+					return "", "", nil, fmt.Errorf("%w, parse certificate: %v", gotool.ErrNotSupportFormat, err)
+				}
+				certs = append(certs, cert)
+			case bag.ID.Equal(oidKeyBag), bag.ID.Equal(oidShroudedKeyBag):
+				if key != nil {
+					return "", "", nil, fmt.Errorf("%w, PKCS#12 contains more than one private key", gotool.ErrNotSupportFormat)
+				}
+				if key, err = pkcs12DecodeKeyBag(&bag, password); err != nil {
+					return "", "", nil, err
+				}
+			}
+		}
+	}
+	if key == nil || len(certs) == 0 {
+		return "", "", nil, fmt.Errorf("%w, PKCS#12 must contain a certificate and a private key", gotool.ErrNotSupportFormat)
+	}
+
+	for _, cert := range certs {
+		if certPEM == "" && pkcs12KeyMatches(key, cert) {
+			certPEM = encodeCertPEM(cert.Raw)
+		} else {
+			caCertPEM = append(caCertPEM, encodeCertPEM(cert.Raw))
+		}
+	}
+	if certPEM == "" {
+		return "", "", nil, fmt.Errorf("%w, no certificate matches the private key", gotool.ErrNotSupportFormat)
+	}
+	keyPEM, err = marshalCertKey(key)
+	if err != nil {
+		return "", "", nil, err
+	}
+	return certPEM, keyPEM, caCertPEM, nil
+}
+
+// pkcs12KeyMatches 判断私钥与证书的公钥是否匹配
+func pkcs12KeyMatches(key crypto.Signer, cert *x509.Certificate) bool {
+	pub, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool })
+	return ok && pub.Equal(cert.PublicKey)
+}
+
+// pkcs12NewContentInfo 将 content 编码后放入 ContentInfo, data 类型使用 OCTET STRING 包装
+func pkcs12NewContentInfo(contentType asn1.ObjectIdentifier, content any) (*pkcs12ContentInfo, error) {
+	der, err := asn1.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	return &pkcs12ContentInfo{
+		ContentType: contentType,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: der},
+	}, nil
+}
+
+// pkcs12NewBag 创建 SafeBag
+func pkcs12NewBag(id asn1.ObjectIdentifier, value any) (*pkcs12SafeBag, error) {
+	der, err := asn1.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return &pkcs12SafeBag{ID: id, Value: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: der}}, nil
+}
+
+// pkcs12NewAttribute 创建只有一个值的属性
+func pkcs12NewAttribute(id asn1.ObjectIdentifier, value any) (pkcs12Attribute, error) {
+	der, err := asn1.Marshal(value)
+	if err != nil {
+		return pkcs12Attribute{}, err
+	}
+	return pkcs12Attribute{ID: id, Value: asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: der}}, nil
+}
+
+// pkcs12DecodeSafeContents 解析 ContentInfo 中的 SafeBag, 必要时先解密
+func pkcs12DecodeSafeContents(ci *pkcs12ContentInfo, password string) ([]pkcs12SafeBag, error) {
+	var contents []byte
+	switch {
+	case ci.ContentType.Equal(oidPkcs7Data):
+		if _, err := asn1.Unmarshal(ci.Content.Bytes, &contents); err != nil {
+			return nil, fmt.Errorf("%w, invalid PKCS#12 data content", gotool.ErrNotSupportFormat)
+		}
+	case ci.ContentType.Equal(oidPkcs7EncryptedData):
+		var ed pkcs12EncryptedData
+		if _, err := asn1.Unmarshal(ci.Content.Bytes, &ed); err != nil {
+			return nil, fmt.Errorf("%w, invalid PKCS#12 encrypted data", gotool.ErrNotSupportFormat)
+		}
+		var err error
+		contents, err = pkcs12Decrypt(ed.EncryptedContentInfo.ContentEncryptionAlgorithm, ed.EncryptedContentInfo.EncryptedContent, password)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w, unsupported PKCS#12 content type %v", gotool.ErrNotSupportFormat, ci.ContentType)
+	}
+
+	var bags []pkcs12SafeBag
+	if _, err := asn1.Unmarshal(contents, &bags); err != nil {
+		return nil, fmt.Errorf("%w, invalid PKCS#12 safe contents", gotool.ErrNotSupportFormat)
+	}
+	return bags, nil
+}
+
+// pkcs12DecodeKeyBag 解析私钥, 必要时先解密
+func pkcs12DecodeKeyBag(bag *pkcs12SafeBag, password string) (crypto.Signer, error) {
+	pkcs8 := bag.Value.Bytes
+	if bag.ID.Equal(oidShroudedKeyBag) {
+		var info pkcs12EncryptedPrivateKeyInfo
+		if _, err := asn1.Unmarshal(bag.Value.Bytes, &info); err != nil {
+			return nil, fmt.Errorf("%w, invalid PKCS#12 key bag", gotool.ErrNotSupportFormat)
+		}
+		var err error
+		if pkcs8, err = pkcs12Decrypt(info.Algorithm, info.EncryptedData, password); err != nil {
+			return nil, err
+		}
+	}
+	prvAny, err := x509.ParsePKCS8PrivateKey(pkcs8)
+	if err != nil {
+		return nil, fmt.Errorf("%w, parse PKCS#8 private key: %v", gotool.ErrNotSupportFormat, err)
+	}
+	key, ok := prvAny.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%w, unsupported private key type %T", gotool.ErrNotSupportFormat, prvAny)
+	}
+	return key, nil
+}
+
+// pkcs12VerifyMac 校验 MAC, 密码错误时返回 ErrDecryptFailed
+func pkcs12VerifyMac(mac *pkcs12MacData, authSafe []byte, password string) error {
+	if mac.Mac.Algorithm.Algorithm == nil {
+		return fmt.Errorf("%w, PKCS#12 without MAC is not supported", gotool.ErrNotSupportFormat)
+	}
+	hash, ok := pkcs12DigestHash(mac.Mac.Algorithm.Algorithm)
+	if !ok {
+		return fmt.Errorf("%w, unsupported PKCS#12 MAC algorithm %v", gotool.ErrNotSupportFormat, mac.Mac.Algorithm.Algorithm)
+	}
+	if mac.Iterations < 1 || mac.Iterations > pkcs12MaxIter {
+		return fmt.Errorf("%w, PKCS#12 MAC iterations %d", gotool.ErrNotSupportFormat, mac.Iterations)
+	}
+	macKey := pkcs12KDF(hash, pkcs12BMPPassword(password), mac.MacSalt, 3, mac.Iterations, hash.Size())
+	if !hmac.Equal(hmacGenerate(authSafe, macKey, hash), mac.Mac.Digest) {
+		return fmt.Errorf("%w, PKCS#12 MAC mismatch, wrong password or corrupted data", gotool.ErrDecryptFailed)
+	}
+	return nil
+}
+
+// pkcs12DigestHash 根据摘要算法 OID 获取哈希算法
+func pkcs12DigestHash(oid asn1.ObjectIdentifier) (crypto.Hash, bool) {
+	switch {
+	case oid.Equal(oidSHA1):
+		return crypto.SHA1, true
+	case oid.Equal(oidSHA256):
+		return crypto.SHA256, true
+	case oid.Equal(oidSHA512):
+		return crypto.SHA512, true
+	}
+	return 0, false
+}
+
+// pkcs12Encrypt 使用 PBES2 (PBKDF2-HMAC-SHA256, AES-256-CBC) 加密
+func pkcs12Encrypt(data []byte, password string) (pkix.AlgorithmIdentifier, []byte, error) {
+	salt, err := randomBytes(pkcs12SaltSize)
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+	iv, err := randomBytes(aes.BlockSize)
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+	kdfParams, err := asn1.Marshal(pkcs12PBKDF2Params{
+		Salt:       salt,
+		Iterations: pkcs12Iterations,
+		Prf:        pkix.AlgorithmIdentifier{Algorithm: oidHmacSHA256, Parameters: asn1.NullRawValue},
+	})
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+	ivParam, err := asn1.Marshal(iv)
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+	params, err := asn1.Marshal(pkcs12PBES2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: kdfParams}},
+		EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: ivParam}},
+	})
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+
+	block, err := aes.NewCipher(Pbkdf2([]byte(password), salt, pkcs12Iterations, 32, crypto.SHA256))
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+	encrypted := pkcs7Padding(append([]byte(nil), data...), aes.BlockSize)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, encrypted)
+	return pkix.AlgorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: params}}, encrypted, nil
+}
+
+// pkcs12Decrypt 根据算法标识解密
+func pkcs12Decrypt(alg pkix.AlgorithmIdentifier, data []byte, password string) ([]byte, error) {
+	var (
+		block cipher.Block
+		iv    []byte
+	)
+	switch {
+	case alg.Algorithm.Equal(oidPBES2):
+		var params pkcs12PBES2Params
+		if _, err := asn1.Unmarshal(alg.Parameters.FullBytes, &params); err != nil || !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+			return nil, fmt.Errorf("%w, unsupported PBES2 parameters", gotool.ErrNotSupportFormat)
+		}
+		var kdf pkcs12PBKDF2Params
+		if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil {
+			return nil, fmt.Errorf("%w, invalid PBKDF2 parameters", gotool.ErrNotSupportFormat)
+		}
+		if kdf.Iterations < 1 || kdf.Iterations > pkcs12MaxIter {
+			return nil, fmt.Errorf("%w, PBKDF2 iterations %d", gotool.ErrNotSupportFormat, kdf.Iterations)
+		}
+		prf := crypto.SHA1
+		switch {
+		case kdf.Prf.Algorithm == nil || kdf.Prf.Algorithm.Equal(oidHmacSHA1):
+		case kdf.Prf.Algorithm.Equal(oidHmacSHA256):
+			prf = crypto.SHA256
+		case kdf.Prf.Algorithm.Equal(oidHmacSHA512):
+			prf = crypto.SHA512
+		default:
+			return nil, fmt.Errorf("%w, unsupported PBKDF2 PRF %v", gotool.ErrNotSupportFormat, kdf.Prf.Algorithm)
+		}
+		var keyLen int
+		switch scheme := params.EncryptionScheme.Algorithm; {
+		case scheme.Equal(oidAES128CBC):
+			keyLen = 16
+		case scheme.Equal(oidAES192CBC):
+			keyLen = 24
+		case scheme.Equal(oidAES256CBC):
+			keyLen = 32
+		default:
+			return nil, fmt.Errorf("%w, unsupported PBES2 encryption scheme %v", gotool.ErrNotSupportFormat, scheme)
+		}
+		if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil || len(iv) != aes.BlockSize {
+			return nil, fmt.Errorf("%w, invalid PBES2 iv", gotool.ErrNotSupportFormat)
+		}
+		var err error
+		if block, err = aes.NewCipher(Pbkdf2([]byte(password), kdf.Salt, kdf.Iterations, keyLen, prf)); err != nil {
+			return nil, err
+		}
+	case alg.Algorithm.Equal(oidPbeSHA13DES):
+		var params pkcs12PbeParams
+		if _, err := asn1.Unmarshal(alg.Parameters.FullBytes, &params); err != nil {
+			return nil, fmt.Errorf("%w, invalid PBE parameters", gotool.ErrNotSupportFormat)
+		}
+		if params.Iterations < 1 || params.Iterations > pkcs12MaxIter {
+			return nil, fmt.Errorf("%w, PBE iterations %d", gotool.ErrNotSupportFormat, params.Iterations)
+		}
+		bmp := pkcs12BMPPassword(password)
+		var err error
+		if block, err = des.NewTripleDESCipher(pkcs12KDF(crypto.SHA1, bmp, params.Salt, 1, params.Iterations, 24)); err != nil {
+			return nil, err
+		}
+		iv = pkcs12KDF(crypto.SHA1, bmp, params.Salt, 2, params.Iterations, des.BlockSize)
+	default:
+		return nil, fmt.Errorf("%w, unsupported PKCS#12 encryption algorithm %v", gotool.ErrNotSupportFormat, alg.Algorithm)
+	}
+
+	if len(data) == 0 || len(data)%block.BlockSize() != 0 {
+		return nil, fmt.Errorf("%w, invalid PKCS#12 ciphertext length", gotool.ErrDecryptFailed)
+	}
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, data)
+	plain, err := pkcs7UnPadding(plain)
+	if err != nil {
+		return nil, fmt.Errorf("%w, %v", gotool.ErrDecryptFailed, err)
+	}
+	return plain, nil
+}
+
+// pkcs12BMPPassword 将密码编码为以 0 结尾的 BMPString (UTF-16BE)
+func pkcs12BMPPassword(password string) []byte {
+	var buf bytes.Buffer
+	for _, r := range utf16.Encode([]rune(password)) {
+		buf.WriteByte(byte(r >> 8))
+		buf.WriteByte(byte(r))
+	}
+	buf.Write([]byte{0, 0})
+	return buf.Bytes()
+}
+
+// pkcs12KDF PKCS#12 密钥派生函数 (RFC 7292 附录 B.2)
+//
+// # Params:
+//
+//	hash: 哈希算法
+//	password: BMPString 编码的密码
+//	salt: 盐
+//	id: 用途, 1 为加密密钥, 2 为 IV, 3 为 MAC 密钥
+//	iterations: 迭代次数
+//	size: 输出长度
+func pkcs12KDF(hash crypto.Hash, password, salt []byte, id byte, iterations, size int) []byte {
+	u := hash.Size()
+	v := hash.New().BlockSize()
+
+	fill := func(b []byte) []byte {
+		if len(b) == 0 {
+			return nil
+		}
+		out := make([]byte, v*((len(b)+v-1)/v))
+		for i := range out {
+			out[i] = b[i%len(b)]
+		}
+		return out
+	}
+	D := bytes.Repeat([]byte{id}, v)
+	I := append(fill(salt), fill(password)...)
+
+	out := make([]byte, 0, size+u)
+	one := big.NewInt(1)
+	for len(out) < size {
+		h := hash.New()
+		h.Write(D)
+		h.Write(I)
+		A := h.Sum(nil)
+		for i := 1; i < iterations; i++ {
+			h.Reset()
+			h.Write(A)
+			A = h.Sum(A[:0])
+		}
+		out = append(out, A...)
+		if len(out) >= size {
+			break
+		}
+
+		// I_j = (I_j + B + 1) mod 2^(v*8)
+		B := new(big.Int).SetBytes(fill(A)[:v])
+		B.Add(B, one)
+		for j := 0; j < len(I); j += v {
+			Ij := new(big.Int).SetBytes(I[j : j+v])
+			Ij.Add(Ij, B)
+			b := Ij.Bytes()
+			if len(b) > v {
+				b = b[len(b)-v:]
+			}
+			copy(I[j:j+v], make([]byte, v))
+			copy(I[j+v-len(b):j+v], b)
+		}
+	}
+	return out[:size]
+}
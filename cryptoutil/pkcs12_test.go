@@ -0,0 +1,99 @@
+package cryptoutil
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/up-zero/gotool"
+	"github.com/up-zero/gotool/testutil"
+)
+
+// OpenSSL 3 生成的 PKCS#12, 密码为 get:
+//
+//	openssl pkcs12 -export -in o.crt -inkey o.key -out o_def.p12
+//	openssl pkcs12 -export -in o.crt -inkey o.key -keypbe PBE-SHA1-3DES -certpbe PBE-SHA1-3DES -macalg sha1 -out o_3des.p12
+const testOpensslP12 = "" +
+	"MIIEDAIBAzCCA8IGCSqGSIb3DQEHAaCCA7MEggOvMIIDqzCCAmIGCSqGSIb3DQEHBqCCAlMwggJPAgEAMIICSAYJKoZIhvcNAQcB" +
+	"MFcGCSqGSIb3DQEFDTBKMCkGCSqGSIb3DQEFDDAcBAizIiJGMdjXhQICCAAwDAYIKoZIhvcNAgkFADAdBglghkgBZQMEASoEEL4K" +
+	"6aXcXzLBwhyAoDsRWGSAggHgbnaw637sXYMhAS3OmfOHJXDqfp/rOL53z7N/jWQAgqq++loTGQcRDYZyijWTCaRaMxLbGrQ0chVI" +
+	"lqEmBDAd+3/PnDFbHsT/hw2tQu+4lyoIJH0QlyDqXuAXZ35pseo+vQeuTbLg0a6+xQAKZtWfPIOHJ6RNG7cepdqXP8ZICcdIqIG5" +
+	"1uPFRZ2/50u4Dp6i/RDtJFeZqdw+DGoOe934e3oFb61UqRrIkQ8d2Ag+PHMFlo6VOANB+EYDtWprNHBh43ypGUgZ2SupGXT5081J" +
+	"R+o3SV2DQjR1Lk7A4mfvC2T2aO+JFFcMgX7qfkURbTTs32ghiXhEssL/oXRPf5zJLj7C3i121AlCyVuhnC761oLwKdQhFQt7Etjq" +
+	"pc9Cwpcwlm2QjASRthVCa1+Jei6T+kZQCS/gWzIfPpurWpjP2b7+VekQgxWQ2IefqxeW8vL4phDi3hsP6jlh1QGH3/NRyhZWsZQr" +
+	"dFabjmYUK0yzaQO+SXZ99JobwrEIUnhZedZgN/Roi+L/3gMN9yIHVfTzHurAh1yBW8d7vaP4ygl7EccD+43VTYaB4Feyr8Mcuemr" +
+	"lJW6c7wbKl94RhozyFerH/5ZicBc+GzBBCZrEgys8pxKuZ5EiWKyT6YtSb1yhu07MIIBQQYJKoZIhvcNAQcBoIIBMgSCAS4wggEq" +
+	"MIIBJgYLKoZIhvcNAQwKAQKgge8wgewwVwYJKoZIhvcNAQUNMEowKQYJKoZIhvcNAQUMMBwECAZDb4VZe8jqAgIIADAMBggqhkiG" +
+	"9w0CCQUAMB0GCWCGSAFlAwQBKgQQpfsi+JQlh/LzUmGZnEmFnwSBkNXyOEyLIzb/36OCO+b6UHGS2u4+e2M4rFEQnjXLla8rvZd2" +
+	"FQhyr/CLy09URFzbQ7EYjk/vZasOyQoE0yydS2QHqRaXuahjZKDQ0e4VYWGQmU8NR/0G0x09zofvIJSKvEXv9JxU4brh6A2I/e7H" +
+	"aF8HvXSVjzmhF17jYB7m6g93PMLzzgR2N38CzYIW+QsXNzElMCMGCSqGSIb3DQEJFTEWBBTH9Nrss+AZPWSZoaNyCuS+z//dyTBB" +
+	"MDEwDQYJYIZIAWUDBAIBBQAEIBWblDzp1ZEKispvlMrb2+axjpzAmc4lsMCzb20i4nXEBAi1UXC0UD2q+QICCAA="
+
+const testOpensslLegacyP12 = "" +
+	"MIIDegIBAzCCA0AGCSqGSIb3DQEHAaCCAzEEggMtMIIDKTCCAh8GCSqGSIb3DQEHBqCCAhAwggIMAgEAMIICBQYJKoZIhvcNAQcB" +
+	"MBwGCiqGSIb3DQEMAQMwDgQIvnyhAI2OytsCAggAgIIB2A6JyxZ2MANuAz2F0C/XB2Xw5xvDN1KQhycnjzZ/yce+jI1HmzcLYmUs" +
+	"wmVN/jMbrmh1BQN+vuq9EUykA7a4PwjWrSA7G+Be3a+pAu2tKqV2TRRXlk861uCLVFTlG27CX8yQPbwHDVBGKm1AycG0acz8yYkf" +
+	"2tY6rNdLth9wvUFY8NsJkm6fgY4rx/48ghYt/v6aUNLoog4gE/0nMVQKU9UQA99dx2pCuPCnEP15Tm7tON9+kSxITJsoTEF30QtE" +
+	"t/JykpJWjc3sZp2pQrJM8nl2Yk7OWvRNV2P7a538/+7NzlqlcQztIkR4Y5tm6jo0eqoCR8Edh35OVlDPEc5F/zkJARkkOtvnp8CD" +
+	"AsZ9dxbQHQcWs+bgjtBW2MU+872k9GrCUlum6xefiHD302zQi487/aPLl4qGq3fHuuEjjz6/oN386glb9AM1RCjJ9UVBNmeELvU0" +
+	"TBEzGtnJoMDKM7Qfy+V/pGXRmYm4I8q6I4pNsFzgBgN0NfY5u6DNFTEzTJ3qBhWCgtS0dAXH36jkUo8T7Uzn1n6ZO2wjNVWAlOSY" +
+	"0DLFbqklwMviUOAQ4aRkpvsOQQUblXdxHM09Y+28utOJ3plkhAy2M9BmW53JKFjv9/cz//X8fU0wggECBgkqhkiG9w0BBwGggfQE" +
+	"gfEwge4wgesGCyqGSIb3DQEMCgECoIG0MIGxMBwGCiqGSIb3DQEMAQMwDgQIPl8F4jitedACAggABIGQE1rJBE0hX3zvxyAohGoI" +
+	"J01IQXoNK66nGqWnPGp0anCyXmWAtauzSAOY22L3+VKCCUajsvCgD4kXuRWosDmr4nGQLmpbi/wK8m4fOMCPtqSPeSnAhZqf7VWD" +
+	"kGtlYVtpofb9zVW/VZfjhcDqjZj8+HE43gq1ROxOhakpG3Y9FLHOQWO/ERXVEOat3L968IgEMSUwIwYJKoZIhvcNAQkVMRYEFMf0" +
+	"2uyz4Bk9ZJmho3IK5L7P/93JMDEwITAJBgUrDgMCGgUABBSrUTNXZHuAlaXnIkX3N3n3m+GpCAQIhSGOUEzUCx8CAggA"
+
+func TestPkcs12Encode(t *testing.T) {
+	caCert, caKey, err := GenRootCA(CertConfig{CommonName: "Root", KeyType: CertKeyECDSA})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, keyType := range []string{CertKeyRSA, CertKeyECDSA, CertKeyEd25519} {
+		certPEM, keyPEM, err := IssueCert(CertConfig{CommonName: "svc", KeyType: keyType, RsaBits: 2048, Usage: CertUsageClient}, caCert, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := Pkcs12Encode(certPEM, keyPEM, "get", caCert)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gotCert, gotKey, gotCAs, err := Pkcs12Decode(data, "get")
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, gotCert, certPEM)
+		testutil.Equal(t, gotKey, keyPEM)
+		testutil.Equal(t, gotCAs, []string{caCert})
+
+		_, _, _, err = Pkcs12Decode(data, "got")
+		testutil.Equal(t, errors.Is(err, gotool.ErrDecryptFailed), true)
+	}
+
+	// 私钥与证书不匹配
+	_, otherKey, _ := IssueCert(CertConfig{CommonName: "other", KeyType: CertKeyECDSA}, caCert, caKey)
+	_, err = Pkcs12Encode(caCert, otherKey, "get")
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestPkcs12Decode(t *testing.T) {
+	for _, p12 := range []string{testOpensslP12, testOpensslLegacyP12} {
+		data, _ := base64.StdEncoding.DecodeString(p12)
+		certPEM, keyPEM, cas, err := Pkcs12Decode(data, "get")
+		if err != nil {
+			t.Fatal(err)
+		}
+		infos, err := ParseCert(certPEM)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, infos[0].CommonName, "openssl")
+		testutil.Equal(t, infos[0].KeyType, CertKeyECDSA)
+		testutil.Equal(t, len(cas), 0)
+		_, err = parseEcdsaPrivateKey(keyPEM)
+		testutil.Equal(t, err, nil)
+	}
+
+	_, _, _, err := Pkcs12Decode([]byte("not a pfx"), "get")
+	testutil.Equal(t, errors.Is(err, gotool.ErrNotSupportFormat), true)
+}
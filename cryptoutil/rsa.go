@@ -1,8 +1,10 @@
 package cryptoutil
 
 import (
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
@@ -11,6 +13,15 @@ import (
 	"os"
 )
 
+const (
+	// RsaPaddingPKCS1v15 PKCS#1 v1.5 填充, 用于加密和签名
+	RsaPaddingPKCS1v15 = "PKCS1v15"
+	// RsaPaddingOAEP OAEP 填充 (SHA-256), 用于加密
+	RsaPaddingOAEP = "OAEP"
+	// RsaPaddingPSS PSS 填充 (SHA-256), 用于签名
+	RsaPaddingPSS = "PSS"
+)
+
 // RsaGenerateKey 生成RSA密钥对，返回PEM格式的私钥和公钥字符串
 //
 // # Params:
@@ -55,12 +66,19 @@ func RsaGenerateKey(bits int) (prvKey, pubKey string, err error) {
 //
 //	data: 待加密的数据
 //	publicKey: PEM 格式的公钥字符串
-func RsaEncrypt(data []byte, publicKey string) ([]byte, error) {
+//	padding: 填充方式，默认为 RsaPaddingPKCS1v15，推荐使用 RsaPaddingOAEP
+func RsaEncrypt(data []byte, publicKey string, padding ...string) ([]byte, error) {
 	pub, err := parsePublicKey(publicKey)
 	if err != nil {
 		return nil, err
 	}
-	return rsa.EncryptPKCS1v15(rand.Reader, pub, data)
+	switch rsaPadding(RsaPaddingPKCS1v15, padding...) {
+	case RsaPaddingPKCS1v15:
+		return rsa.EncryptPKCS1v15(rand.Reader, pub, data)
+	case RsaPaddingOAEP:
+		return rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, data, nil)
+	}
+	return nil, fmt.Errorf("%w, unsupported rsa encryption padding: %s", gotool.ErrInvalidParam, padding[0])
 }
 
 // RsaDecrypt RSA 私钥解密
@@ -69,65 +87,94 @@ func RsaEncrypt(data []byte, publicKey string) ([]byte, error) {
 //
 //	ciphertext: 待解密的数据
 //	privateKey: PEM 格式的私钥字符串
-func RsaDecrypt(ciphertext []byte, privateKey string) ([]byte, error) {
+//	padding: 填充方式，默认为 RsaPaddingPKCS1v15，需与加密时一致
+func RsaDecrypt(ciphertext []byte, privateKey string, padding ...string) ([]byte, error) {
 	prv, err := parsePrivateKey(privateKey)
 	if err != nil {
 		return nil, err
 	}
-	return rsa.DecryptPKCS1v15(rand.Reader, prv, ciphertext)
+	switch rsaPadding(RsaPaddingPKCS1v15, padding...) {
+	case RsaPaddingPKCS1v15:
+		return rsa.DecryptPKCS1v15(rand.Reader, prv, ciphertext)
+	case RsaPaddingOAEP:
+		return rsa.DecryptOAEP(sha256.New(), rand.Reader, prv, ciphertext, nil)
+	}
+	return nil, fmt.Errorf("%w, unsupported rsa encryption padding: %s", gotool.ErrInvalidParam, padding[0])
 }
 
-// RsaEncryptFile 使用 RSA 公钥加密文件
+// RsaSign RSA 私钥签名，摘要算法为 SHA-256
 //
 // # Params:
 //
-//	srcPath: 源文件路径
-//	dstPath: 加密后的文件输出路径
-//	pubKey: PEM 格式公钥字符串
-func RsaEncryptFile(srcPath, dstPath, pubKey string) error {
-	pub, err := parsePublicKey(pubKey)
+//	data: 待签名的数据
+//	privateKey: PEM 格式的私钥字符串
+//	padding: 填充方式，默认为 RsaPaddingPKCS1v15，可选 RsaPaddingPSS
+func RsaSign(data []byte, privateKey string, padding ...string) ([]byte, error) {
+	prv, err := parsePrivateKey(privateKey)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	digest := sha256.Sum256(data)
+	switch rsaPadding(RsaPaddingPKCS1v15, padding...) {
+	case RsaPaddingPKCS1v15:
+		return rsa.SignPKCS1v15(rand.Reader, prv, crypto.SHA256, digest[:])
+	case RsaPaddingPSS:
+		return rsa.SignPSS(rand.Reader, prv, crypto.SHA256, digest[:], &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
 	}
+	return nil, fmt.Errorf("%w, unsupported rsa signature padding: %s", gotool.ErrInvalidParam, padding[0])
+}
 
-	srcFile, err := os.Open(srcPath)
+// RsaVerify RSA 公钥验签，签名有效时返回 nil
+//
+// # Params:
+//
+//	data: 原始数据
+//	signature: 签名
+//	publicKey: PEM 格式的公钥字符串
+//	padding: 填充方式，默认为 RsaPaddingPKCS1v15，需与签名时一致
+func RsaVerify(data, signature []byte, publicKey string, padding ...string) error {
+	pub, err := parsePublicKey(publicKey)
 	if err != nil {
 		return err
 	}
-	defer srcFile.Close()
-	dstFile, err := os.Create(dstPath)
-	if err != nil {
-		return err
+	digest := sha256.Sum256(data)
+	switch rsaPadding(RsaPaddingPKCS1v15, padding...) {
+	case RsaPaddingPKCS1v15:
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature)
+	case RsaPaddingPSS:
+		return rsa.VerifyPSS(pub, crypto.SHA256, digest[:], signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto})
 	}
-	defer dstFile.Close()
-
-	// 计算每一块的大小
-	chunkSize := pub.Size() - 11
-	buffer := make([]byte, chunkSize)
-
-	for {
-		n, err := srcFile.Read(buffer)
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return err
-		}
+	return fmt.Errorf("%w, unsupported rsa signature padding: %s", gotool.ErrInvalidParam, padding[0])
+}
 
-		cipherChunk, err := rsa.EncryptPKCS1v15(rand.Reader, pub, buffer[:n])
-		if err != nil {
-			return err
-		}
-		if _, err := dstFile.Write(cipherChunk); err != nil {
-			return err
-		}
+// rsaPadding 获取填充方式
+func rsaPadding(def string, padding ...string) string {
+	if len(padding) > 0 && padding[0] != "" {
+		return padding[0]
 	}
+	return def
+}
 
-	return nil
+// RsaEncryptFile 使用 RSA 公钥加密文件
+//
+// 采用信封加密: 随机数据密钥经 RSA-OAEP 封装, 文件内容使用 AES-GCM 分块加密, 见 EnvelopeEncryptFile
+//
+// # Params:
+//
+//	srcPath: 源文件路径
+//	dstPath: 加密后的文件输出路径
+//	pubKey: PEM 格式公钥字符串
+func RsaEncryptFile(srcPath, dstPath, pubKey string) error {
+	if _, err := parsePublicKey(pubKey); err != nil {
+		return err
+	}
+	return EnvelopeEncryptFile(srcPath, dstPath, pubKey)
 }
 
 // RsaDecryptFile 使用 RSA 私钥解密文件
 //
+// 兼容旧版本按 PKCS#1 v1.5 分块加密的文件
+//
 // # Params:
 //
 //	srcPath: 密文文件路径
@@ -139,6 +186,22 @@ func RsaDecryptFile(srcPath, dstPath, prvKey string) error {
 		return err
 	}
 
+	// 根据魔数区分信封格式与旧格式
+	magic := make([]byte, len(envelopeMagic))
+	srcFile, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	n, _ := io.ReadFull(srcFile, magic)
+	srcFile.Close()
+	if n == len(magic) && string(magic) == envelopeMagic {
+		return EnvelopeDecryptFile(srcPath, dstPath, prvKey)
+	}
+	return rsaDecryptFileLegacy(srcPath, dstPath, prv)
+}
+
+// rsaDecryptFileLegacy 解密旧版本按 PKCS#1 v1.5 分块加密的文件
+func rsaDecryptFileLegacy(srcPath, dstPath string, prv *rsa.PrivateKey) error {
 	srcFile, err := os.Open(srcPath)
 	if err != nil {
 		return err
@@ -64,3 +64,43 @@ func TestRsaEncryptFile(t *testing.T) {
 func TestRsaDecryptFile(t *testing.T) {
 	testutil.Equal(t, RsaDecryptFile("rsa.enc.txt", "rsa.dec.txt", testPubKey), nil)
 }
+
+func TestRsaPadding(t *testing.T) {
+	data := []byte("hello world")
+	for _, padding := range []string{RsaPaddingPKCS1v15, RsaPaddingOAEP} {
+		ciphertext, err := RsaEncrypt(data, testPrvKey, padding)
+		if err != nil {
+			t.Fatal(err)
+		}
+		plain, err := RsaDecrypt(ciphertext, testPubKey, padding)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, string(plain), string(data))
+	}
+
+	// 填充方式不一致时解密失败
+	ciphertext, _ := RsaEncrypt(data, testPrvKey, RsaPaddingOAEP)
+	_, err := RsaDecrypt(ciphertext, testPubKey)
+	testutil.NotEqual(t, err, nil)
+	_, err = RsaEncrypt(data, testPrvKey, RsaPaddingPSS)
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestRsaSign(t *testing.T) {
+	data := []byte("hello world")
+	for _, padding := range []string{RsaPaddingPKCS1v15, RsaPaddingPSS} {
+		signature, err := RsaSign(data, testPubKey, padding)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, RsaVerify(data, signature, testPrvKey, padding), nil)
+		testutil.NotEqual(t, RsaVerify([]byte("hello world!"), signature, testPrvKey, padding), nil)
+	}
+
+	// PKCS#1 v1.5 签名是确定性的
+	s1, _ := RsaSign(data, testPubKey)
+	s2, _ := RsaSign(data, testPubKey, RsaPaddingPKCS1v15)
+	testutil.Equal(t, s1, s2)
+	testutil.NotEqual(t, RsaVerify(data, s1, testPrvKey, RsaPaddingPSS), nil)
+}
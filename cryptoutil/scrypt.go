@@ -0,0 +1,125 @@
+package cryptoutil
+
+import (
+	"crypto"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+
+	"github.com/up-zero/gotool"
+)
+
+// Scrypt scrypt 密钥派生 (RFC 7914)
+//
+// # Params:
+//
+//	password: 密码
+//	salt: 盐
+//	N: CPU/内存代价, 必须是大于 1 的 2 的幂
+//	r: 块大小
+//	p: 并行度
+//	keyLen: 派生密钥的长度
+func Scrypt(password, salt []byte, N, r, p, keyLen int) ([]byte, error) {
+	if N <= 1 || N&(N-1) != 0 {
+		return nil, fmt.Errorf("%w, scrypt N must be a power of 2 greater than 1", gotool.ErrInvalidParam)
+	}
+	if r <= 0 || p <= 0 || keyLen <= 0 || uint64(r)*uint64(p) >= 1<<30 || r > (1<<31-1)/128/p || r > (1<<31-1)/256 || N > (1<<31-1)/128/r {
+		return nil, fmt.Errorf("%w, scrypt parameters are too large", gotool.ErrInvalidParam)
+	}
+
+	xy := make([]uint32, 64*r)
+	v := make([]uint32, 32*N*r)
+	b := Pbkdf2(password, salt, 1, p*128*r, crypto.SHA256)
+	for i := 0; i < p; i++ {
+		scryptROMix(b[i*128*r:], r, N, v, xy)
+	}
+	return Pbkdf2(password, b, 1, keyLen, crypto.SHA256), nil
+}
+
+// scryptROMix 顺序内存困难函数 ROMix, 原地修改 b 的前 128*r 字节
+func scryptROMix(b []byte, r, N int, v, xy []uint32) {
+	x := xy[:32*r]
+	y := xy[32*r:]
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint32(b[i*4:])
+	}
+	for i := 0; i < N; i += 2 {
+		copy(v[i*32*r:], x)
+		scryptBlockMix(x, y, r)
+		copy(v[(i+1)*32*r:], y)
+		scryptBlockMix(y, x, r)
+	}
+	for i := 0; i < N; i += 2 {
+		j := int(x[(2*r-1)*16] & uint32(N-1))
+		for k := range x {
+			x[k] ^= v[j*32*r+k]
+		}
+		scryptBlockMix(x, y, r)
+		j = int(y[(2*r-1)*16] & uint32(N-1))
+		for k := range y {
+			y[k] ^= v[j*32*r+k]
+		}
+		scryptBlockMix(y, x, r)
+	}
+	for i, w := range x {
+		binary.LittleEndian.PutUint32(b[i*4:], w)
+	}
+}
+
+// scryptBlockMix BlockMix, 结果中偶数块在前, 奇数块在后
+func scryptBlockMix(in, out []uint32, r int) {
+	var x [16]uint32
+	copy(x[:], in[(2*r-1)*16:])
+	for i := 0; i < 2*r; i++ {
+		for k := range x {
+			x[k] ^= in[i*16+k]
+		}
+		salsa208(&x)
+		copy(out[(i/2+(i%2)*r)*16:], x[:])
+	}
+}
+
+// salsa208 Salsa20/8 核心函数
+func salsa208(b *[16]uint32) {
+	x := *b
+	rotl := bits.RotateLeft32
+	for i := 0; i < 8; i += 2 {
+		// 列
+		x[4] ^= rotl(x[0]+x[12], 7)
+		x[8] ^= rotl(x[4]+x[0], 9)
+		x[12] ^= rotl(x[8]+x[4], 13)
+		x[0] ^= rotl(x[12]+x[8], 18)
+		x[9] ^= rotl(x[5]+x[1], 7)
+		x[13] ^= rotl(x[9]+x[5], 9)
+		x[1] ^= rotl(x[13]+x[9], 13)
+		x[5] ^= rotl(x[1]+x[13], 18)
+		x[14] ^= rotl(x[10]+x[6], 7)
+		x[2] ^= rotl(x[14]+x[10], 9)
+		x[6] ^= rotl(x[2]+x[14], 13)
+		x[10] ^= rotl(x[6]+x[2], 18)
+		x[3] ^= rotl(x[15]+x[11], 7)
+		x[7] ^= rotl(x[3]+x[15], 9)
+		x[11] ^= rotl(x[7]+x[3], 13)
+		x[15] ^= rotl(x[11]+x[7], 18)
+		// 行
+		x[1] ^= rotl(x[0]+x[3], 7)
+		x[2] ^= rotl(x[1]+x[0], 9)
+		x[3] ^= rotl(x[2]+x[1], 13)
+		x[0] ^= rotl(x[3]+x[2], 18)
+		x[6] ^= rotl(x[5]+x[4], 7)
+		x[7] ^= rotl(x[6]+x[5], 9)
+		x[4] ^= rotl(x[7]+x[6], 13)
+		x[5] ^= rotl(x[4]+x[7], 18)
+		x[11] ^= rotl(x[10]+x[9], 7)
+		x[8] ^= rotl(x[11]+x[10], 9)
+		x[9] ^= rotl(x[8]+x[11], 13)
+		x[10] ^= rotl(x[9]+x[8], 18)
+		x[12] ^= rotl(x[15]+x[14], 7)
+		x[13] ^= rotl(x[12]+x[15], 9)
+		x[14] ^= rotl(x[13]+x[12], 13)
+		x[15] ^= rotl(x[14]+x[13], 18)
+	}
+	for i := range b {
+		b[i] += x[i]
+	}
+}
@@ -0,0 +1,29 @@
+package cryptoutil
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestScrypt(t *testing.T) {
+	// RFC 7914
+	got, err := Scrypt(nil, nil, 16, 1, 1, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, hex.EncodeToString(got), "77d6576238657b203b19ca42c18a0497f16b4844e3074ae8dfdffa3fede21442fcd0069ded0948f8326a753a0fc81f17e8d3e0fb2e0d3628cf35e20c38d18906")
+
+	got, err = Scrypt([]byte("password"), []byte("NaCl"), 1024, 8, 16, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, hex.EncodeToString(got), "fdbabe1c9d3472007856e7190d01e9fe7c6ad7cbc8237830e77376634b3731622eaf30d92e22a3886ff109279d9830dac727afb94a83ee6d8360cbdfa2cc0640")
+
+	// N 不是 2 的幂
+	_, err = Scrypt([]byte("password"), []byte("NaCl"), 1000, 8, 1, 32)
+	testutil.NotEqual(t, err, nil)
+	_, err = Scrypt([]byte("password"), []byte("NaCl"), 16, 0, 1, 32)
+	testutil.NotEqual(t, err, nil)
+}
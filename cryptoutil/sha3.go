@@ -0,0 +1,120 @@
+package cryptoutil
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// keccakRC Keccak-f[1600] 轮常量
+var keccakRC = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808a, 0x8000000080008000,
+	0x000000000000808b, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008a, 0x0000000000000088, 0x0000000080008009, 0x000000008000000a,
+	0x000000008000808b, 0x800000000000008b, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800a, 0x800000008000000a,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// keccakRotc ρ 步骤的循环移位量，按 π 步骤的遍历顺序
+var keccakRotc = [24]int{1, 3, 6, 10, 15, 21, 28, 36, 45, 55, 2, 14, 27, 41, 56, 8, 25, 43, 62, 18, 39, 61, 20, 44}
+
+// keccakPiln π 步骤的遍历顺序
+var keccakPiln = [24]int{10, 7, 11, 17, 18, 3, 5, 16, 8, 21, 24, 4, 15, 23, 19, 13, 12, 2, 20, 14, 22, 9, 6, 1}
+
+// keccakF1600 Keccak-f[1600] 置换
+func keccakF1600(a *[25]uint64) {
+	var bc [5]uint64
+	for round := 0; round < 24; round++ {
+		// θ
+		for i := 0; i < 5; i++ {
+			bc[i] = a[i] ^ a[i+5] ^ a[i+10] ^ a[i+15] ^ a[i+20]
+		}
+		for i := 0; i < 5; i++ {
+			t := bc[(i+4)%5] ^ bits.RotateLeft64(bc[(i+1)%5], 1)
+			for j := 0; j < 25; j += 5 {
+				a[j+i] ^= t
+			}
+		}
+		// ρ 和 π
+		t := a[1]
+		for i := 0; i < 24; i++ {
+			j := keccakPiln[i]
+			t, a[j] = a[j], bits.RotateLeft64(t, keccakRotc[i])
+		}
+		// χ
+		for j := 0; j < 25; j += 5 {
+			copy(bc[:], a[j:j+5])
+			for i := 0; i < 5; i++ {
+				a[j+i] ^= ^bc[(i+1)%5] & bc[(i+2)%5]
+			}
+		}
+		// ι
+		a[0] ^= keccakRC[round]
+	}
+}
+
+// sha3Digest SHA-3 哈希 (FIPS 202)
+type sha3Digest struct {
+	a    [25]uint64
+	buf  []byte
+	rate int
+	size int
+}
+
+// newSha3 创建输出长度为 size 字节的 SHA-3 哈希
+func newSha3(size int) hash.Hash {
+	return &sha3Digest{rate: 200 - 2*size, size: size}
+}
+
+// Reset 重置状态
+func (d *sha3Digest) Reset() {
+	d.a = [25]uint64{}
+	d.buf = d.buf[:0]
+}
+
+// Size 输出长度
+func (d *sha3Digest) Size() int { return d.size }
+
+// BlockSize 块大小，即吸收速率
+func (d *sha3Digest) BlockSize() int { return d.rate }
+
+// Write 吸收数据
+func (d *sha3Digest) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		m := min(d.rate-len(d.buf), len(p))
+		d.buf = append(d.buf, p[:m]...)
+		p = p[m:]
+		if len(d.buf) == d.rate {
+			d.absorb(d.buf)
+			d.buf = d.buf[:0]
+		}
+	}
+	return n, nil
+}
+
+// absorb 将一个块异或到状态并执行置换
+func (d *sha3Digest) absorb(block []byte) {
+	for i := 0; i < d.rate/8; i++ {
+		d.a[i] ^= binary.LittleEndian.Uint64(block[i*8:])
+	}
+	keccakF1600(&d.a)
+}
+
+// Sum 追加哈希值, 不改变当前状态
+func (d *sha3Digest) Sum(in []byte) []byte {
+	c := *d
+	// 填充: 域分隔符 0x06 和末尾的 0x80
+	block := make([]byte, c.rate)
+	copy(block, c.buf)
+	block[len(c.buf)] ^= 0x06
+	block[c.rate-1] ^= 0x80
+	c.absorb(block)
+
+	out := make([]byte, c.rate)
+	for i := 0; i < c.rate/8; i++ {
+		binary.LittleEndian.PutUint64(out[i*8:], c.a[i])
+	}
+	return append(in, out[:c.size]...)
+}
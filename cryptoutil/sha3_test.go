@@ -0,0 +1,31 @@
+package cryptoutil
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestSha3(t *testing.T) {
+	for _, tt := range []struct {
+		size int
+		abc  string
+		long string // 200 个 'a'，跨越多个块
+	}{
+		{28, "e642824c3f8cf24ad09234ee7d3c766fc9a3a5168d0c94ad73b46fdf", "455e0ccfc6010738ed93a793dffd79aff36debbd1a7eb6621bd6c722"},
+		{32, "3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532", "cce34485baf2bf2aca99b94833892a4f52896d3d153f7b840cc4f9fe695f1387"},
+		{48, "ec01498288516fc926459f58e2c6ad8df9b473cb0fc08c2596da7cf0e49be4b298d88cea927ac7f539f1edf228376d25", "f97756776c1874724c94a8008f7f155553b4bf00fbf8fbeac246624ad59c258a3c0977d9f2543d7cbd75b9ac8fdc0d40"},
+		{64, "b751850b1a57168a5693cd924b6b096e08f621827444f70d884f5d0240d2712e10e116e9192af3c91a7ec57647e3934057340b4cf408d5a56592f8274eec53f0", "eae6c85c6904f11075de9f9d5e1064371d000510fa3d2d79d40cf9be34892fb01859d0a0234e138bcb0ad5c84f6c0dca226a414b0c9a2897cb695f5185fe36ec"},
+	} {
+		h := newSha3(tt.size)
+		h.Write([]byte("abc"))
+		testutil.Equal(t, hex.EncodeToString(h.Sum(nil)), tt.abc)
+
+		h.Reset()
+		h.Write([]byte(strings.Repeat("a", 150)))
+		h.Write([]byte(strings.Repeat("a", 50)))
+		testutil.Equal(t, hex.EncodeToString(h.Sum(nil)), tt.long)
+	}
+}
@@ -0,0 +1,145 @@
+package cryptoutil
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/up-zero/gotool"
+)
+
+// 份额格式: hex(threshold (1) | x (1) | id (4) | y)
+//
+// id 为拆分时随机生成的标识, 用于发现混用了不同拆分的份额;
+// y 与秘密等长, 第 i 个字节为第 i 个多项式在 x 处的取值
+const (
+	shamirHeaderSize = 6
+	shamirIDSize     = 4
+	shamirMaxShares  = 255
+)
+
+// gf256Mul GF(2^8) 乘法, 不可约多项式为 x^8+x^4+x^3+x+1 (0x11B), 与 AES 相同;
+// 不使用查表, 执行时间与输入无关
+func gf256Mul(a, b byte) byte {
+	var p byte
+	for i := 0; i < 8; i++ {
+		p ^= a & -(b & 1)
+		carry := -(a >> 7)
+		a = a<<1 ^ 0x1b&carry
+		b >>= 1
+	}
+	return p
+}
+
+// gf256Inv GF(2^8) 求逆, a^254 = a^-1
+func gf256Inv(a byte) byte {
+	r := a
+	for i := 0; i < 6; i++ {
+		r = gf256Mul(r, r)
+		r = gf256Mul(r, a)
+	}
+	return gf256Mul(r, r)
+}
+
+// ShamirSplit 使用 Shamir 秘密共享 (GF(256)) 将秘密拆分为多个份额，任意 threshold 个份额可以恢复秘密
+//
+// # Params:
+//
+//	secret: 秘密，如主密钥
+//	shares: 份额数量，2~255
+//	threshold: 恢复秘密需要的最少份额数量，2~shares
+//
+// # Examples:
+//
+//	parts, _ := ShamirSplit(masterKey, 5, 3)
+//	secret, _ := ShamirCombine(parts[1:4])
+func ShamirSplit(secret []byte, shares, threshold int) ([]string, error) {
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("secret %w", gotool.ErrCannotBeEmpty)
+	}
+	if threshold < 2 || shares < threshold || shares > shamirMaxShares {
+		return nil, fmt.Errorf("%w, shares=%d, threshold=%d", gotool.ErrInvalidParam, shares, threshold)
+	}
+
+	id := make([]byte, shamirIDSize)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	// 每个字节使用一个 threshold-1 次的随机多项式, 常数项为秘密
+	coeffs := make([]byte, len(secret)*(threshold-1))
+	if _, err := rand.Read(coeffs); err != nil {
+		return nil, err
+	}
+
+	out := make([]string, shares)
+	share := make([]byte, shamirHeaderSize+len(secret))
+	for i := 0; i < shares; i++ {
+		x := byte(i + 1)
+		share[0], share[1] = byte(threshold), x
+		copy(share[2:], id)
+		for j, s := range secret {
+			c := coeffs[j*(threshold-1) : (j+1)*(threshold-1)]
+			// 秦九韶算法求值
+			var y byte
+			for k := len(c) - 1; k >= 0; k-- {
+				y = gf256Mul(y, x) ^ c[k]
+			}
+			share[shamirHeaderSize+j] = gf256Mul(y, x) ^ s
+		}
+		out[i] = hex.EncodeToString(share)
+	}
+	return out, nil
+}
+
+// ShamirCombine 使用拉格朗日插值由份额恢复秘密
+//
+// # Params:
+//
+//	shares: ShamirSplit 生成的份额，数量不少于拆分时的 threshold，顺序任意
+func ShamirCombine(shares []string) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("shares %w", gotool.ErrCannotBeEmpty)
+	}
+
+	xs := make([]byte, 0, len(shares))
+	ys := make([][]byte, 0, len(shares))
+	var header []byte
+	for _, s := range shares {
+		b, err := hex.DecodeString(s)
+		if err != nil || len(b) <= shamirHeaderSize || b[1] == 0 {
+			return nil, fmt.Errorf("%w, invalid share", gotool.ErrInvalidParam)
+		}
+		if header == nil {
+			header = b
+		} else if b[0] != header[0] || string(b[2:shamirHeaderSize]) != string(header[2:shamirHeaderSize]) || len(b) != len(header) {
+			return nil, fmt.Errorf("%w, shares are from different splits", gotool.ErrInvalidParam)
+		}
+		for _, x := range xs {
+			if x == b[1] {
+				return nil, fmt.Errorf("%w, duplicate share %d", gotool.ErrInvalidParam, x)
+			}
+		}
+		xs = append(xs, b[1])
+		ys = append(ys, b[shamirHeaderSize:])
+	}
+	if threshold := int(header[0]); len(xs) < threshold {
+		return nil, fmt.Errorf("%w, need %d shares, got %d", gotool.ErrInvalidParam, threshold, len(xs))
+	}
+
+	// f(0) = Σ y_i · Π_{j≠i} x_j / (x_j - x_i), GF(2^8) 中减法即异或
+	secret := make([]byte, len(ys[0]))
+	for i, xi := range xs {
+		var num, den byte = 1, 1
+		for j, xj := range xs {
+			if i != j {
+				num = gf256Mul(num, xj)
+				den = gf256Mul(den, xj^xi)
+			}
+		}
+		l := gf256Mul(num, gf256Inv(den))
+		for k, y := range ys[i] {
+			secret[k] ^= gf256Mul(y, l)
+		}
+	}
+	return secret, nil
+}
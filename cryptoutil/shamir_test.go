@@ -0,0 +1,72 @@
+package cryptoutil
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/up-zero/gotool"
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestGf256(t *testing.T) {
+	// FIPS 197 4.2
+	testutil.Equal(t, gf256Mul(0x57, 0x83), byte(0xc1))
+	testutil.Equal(t, gf256Mul(0x57, 0x13), byte(0xfe))
+	testutil.Equal(t, gf256Inv(0x53), byte(0xca))
+	for a := 1; a < 256; a++ {
+		testutil.Equal(t, gf256Mul(byte(a), gf256Inv(byte(a))), byte(1))
+	}
+}
+
+func TestShamir(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	shares, err := ShamirSplit(secret, 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, len(shares), 5)
+
+	// 任意 3 个及以上份额都能恢复
+	for _, idx := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3}, {0, 1, 2, 3, 4}} {
+		parts := make([]string, 0, len(idx))
+		for _, i := range idx {
+			parts = append(parts, shares[i])
+		}
+		got, err := ShamirCombine(parts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, bytes.Equal(got, secret), true)
+	}
+
+	// 份额不足
+	_, err = ShamirCombine(shares[:2])
+	testutil.Equal(t, errors.Is(err, gotool.ErrInvalidParam), true)
+	// 重复份额
+	_, err = ShamirCombine([]string{shares[0], shares[1], shares[0]})
+	testutil.Equal(t, errors.Is(err, gotool.ErrInvalidParam), true)
+	// 混用不同拆分的份额
+	others, _ := ShamirSplit(secret, 5, 3)
+	_, err = ShamirCombine([]string{shares[0], shares[1], others[2]})
+	testutil.Equal(t, errors.Is(err, gotool.ErrInvalidParam), true)
+	// 格式错误
+	_, err = ShamirCombine([]string{"zz"})
+	testutil.Equal(t, errors.Is(err, gotool.ErrInvalidParam), true)
+}
+
+func TestShamirSplitParams(t *testing.T) {
+	shares, err := ShamirSplit([]byte{0x42}, 255, 255)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := ShamirCombine(shares)
+	testutil.Equal(t, got[0], byte(0x42))
+
+	for _, tt := range []struct{ shares, threshold int }{{3, 1}, {2, 3}, {256, 2}} {
+		_, err := ShamirSplit([]byte("secret"), tt.shares, tt.threshold)
+		testutil.Equal(t, errors.Is(err, gotool.ErrInvalidParam), true)
+	}
+	_, err = ShamirSplit(nil, 3, 2)
+	testutil.Equal(t, errors.Is(err, gotool.ErrCannotBeEmpty), true)
+}
@@ -0,0 +1,96 @@
+package cryptoutil
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/up-zero/gotool"
+)
+
+// 签名链接的查询参数
+const (
+	SignURLExpiresParam   = "expires"   // 过期时间，Unix 秒
+	SignURLSignatureParam = "signature" // HMAC-SHA256 签名，base64url 编码
+)
+
+// SignURL 为 URL 添加过期时间和 HMAC-SHA256 签名参数，生成有时效的下载链接
+//
+// 签名覆盖路径和全部查询参数 (按参数名排序)，不包括协议和主机，
+// 因此服务端可以直接使用 r.URL.RequestURI() 校验，不受反向代理改写主机的影响
+//
+// # Params:
+//
+//	rawURL: 原始链接
+//	key: 签名密钥
+//	ttl: 有效期
+//
+// # Examples:
+//
+//	SignURL("https://example.com/file.zip?v=1", key, time.Hour)
+//	// https://example.com/file.zip?expires=1700000000&signature=...&v=1
+func SignURL(rawURL string, key []byte, ttl time.Duration) (string, error) {
+	if ttl <= 0 {
+		return "", fmt.Errorf("%w, ttl must be positive", gotool.ErrInvalidParam)
+	}
+	return signURLAt(rawURL, key, time.Now().Add(ttl))
+}
+
+// VerifyURL 校验 SignURL 生成的链接，签名错误返回 gotool.ErrInvalidSignature，过期返回 gotool.ErrExpired
+//
+// # Params:
+//
+//	signedURL: 签名链接，可以是完整链接或 RequestURI
+//	key: 签名密钥
+func VerifyURL(signedURL string, key []byte) error {
+	return verifyURLAt(signedURL, key, time.Now())
+}
+
+// signURLAt 使用指定的过期时间签名
+func signURLAt(rawURL string, key []byte, expires time.Time) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Del(SignURLSignatureParam)
+	q.Set(SignURLExpiresParam, strconv.FormatInt(expires.Unix(), 10))
+	q.Set(SignURLSignatureParam, base64.RawURLEncoding.EncodeToString(signURLMac(u, q, key)))
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// verifyURLAt 以 now 为当前时间校验
+func verifyURLAt(signedURL string, key []byte, now time.Time) error {
+	u, err := url.Parse(signedURL)
+	if err != nil {
+		return err
+	}
+	q := u.Query()
+	sig, err := base64.RawURLEncoding.DecodeString(q.Get(SignURLSignatureParam))
+	if err != nil || len(sig) == 0 {
+		return gotool.ErrInvalidSignature
+	}
+	q.Del(SignURLSignatureParam)
+	expires, err := strconv.ParseInt(q.Get(SignURLExpiresParam), 10, 64)
+	if err != nil {
+		return gotool.ErrInvalidSignature
+	}
+	// 先校验签名, 避免泄露过期时间是否被篡改
+	if !hmac.Equal(sig, signURLMac(u, q, key)) {
+		return gotool.ErrInvalidSignature
+	}
+	if now.Unix() > expires {
+		return gotool.ErrExpired
+	}
+	return nil
+}
+
+// signURLMac 计算 路径 + "?" + 排序后的查询参数 的 HMAC-SHA256
+func signURLMac(u *url.URL, q url.Values, key []byte) []byte {
+	return hmacGenerate([]byte(u.EscapedPath()+"?"+q.Encode()), key, crypto.SHA256)
+}
@@ -0,0 +1,57 @@
+package cryptoutil
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/up-zero/gotool"
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestSignURL(t *testing.T) {
+	key := []byte("secret")
+	signed, err := SignURL("https://example.com/files/a.zip?v=1&name=%E6%96%87%E4%BB%B6", key, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(signed)
+	testutil.Equal(t, u.Host, "example.com")
+	testutil.Equal(t, u.Query().Get("v"), "1")
+	testutil.NotEqual(t, u.Query().Get(SignURLExpiresParam), "")
+	testutil.NotEqual(t, u.Query().Get(SignURLSignatureParam), "")
+
+	testutil.Equal(t, VerifyURL(signed, key), nil)
+	// 服务端使用 RequestURI 校验
+	testutil.Equal(t, VerifyURL(u.RequestURI(), key), nil)
+
+	testutil.Equal(t, errors.Is(VerifyURL(signed, []byte("other")), gotool.ErrInvalidSignature), true)
+	testutil.Equal(t, errors.Is(VerifyURL(strings.Replace(signed, "v=1", "v=2", 1), key), gotool.ErrInvalidSignature), true)
+	testutil.Equal(t, errors.Is(VerifyURL(strings.Replace(signed, "/a.zip", "/b.zip", 1), key), gotool.ErrInvalidSignature), true)
+	testutil.Equal(t, errors.Is(VerifyURL("https://example.com/files/a.zip", key), gotool.ErrInvalidSignature), true)
+
+	_, err = SignURL("https://example.com/a", key, 0)
+	testutil.Equal(t, errors.Is(err, gotool.ErrInvalidParam), true)
+}
+
+func TestVerifyURLExpired(t *testing.T) {
+	key := []byte("secret")
+	now := time.Now()
+	signed, _ := signURLAt("/download?id=1", key, now.Add(time.Minute))
+	testutil.Equal(t, verifyURLAt(signed, key, now), nil)
+	testutil.Equal(t, errors.Is(verifyURLAt(signed, key, now.Add(2*time.Minute)), gotool.ErrExpired), true)
+
+	// 篡改过期时间
+	u, _ := url.Parse(signed)
+	q := u.Query()
+	q.Set(SignURLExpiresParam, "9999999999")
+	u.RawQuery = q.Encode()
+	testutil.Equal(t, errors.Is(verifyURLAt(u.String(), key, now), gotool.ErrInvalidSignature), true)
+
+	// 重新签名会替换旧签名
+	resigned, _ := signURLAt(signed, key, now.Add(time.Hour))
+	testutil.Equal(t, strings.Count(resigned, SignURLSignatureParam+"="), 1)
+	testutil.Equal(t, verifyURLAt(resigned, key, now.Add(2*time.Minute)), nil)
+}
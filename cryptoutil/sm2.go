@@ -0,0 +1,491 @@
+package cryptoutil
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/subtle"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/up-zero/gotool"
+)
+
+const (
+	// Sm2C1C3C2 SM2 密文格式 C1||C3||C2，GB/T 32918.4-2016 规定的格式
+	Sm2C1C3C2 = "C1C3C2"
+	// Sm2C1C2C3 SM2 密文格式 C1||C2||C3，旧版标准及部分实现使用的格式
+	Sm2C1C2C3 = "C1C2C3"
+)
+
+// sm2DefaultUID 签名默认的用户身份标识 (GM/T 0009-2012)
+var sm2DefaultUID = []byte("1234567812345678")
+
+var (
+	// oidPublicKeyECDSA 椭圆曲线公钥算法标识
+	oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	// oidNamedCurveSM2 SM2 曲线标识
+	oidNamedCurveSM2 = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 301}
+)
+
+var (
+	sm2Once   sync.Once
+	sm2Params *elliptic.CurveParams
+)
+
+// sm2Curve SM2 推荐曲线 sm2p256v1，a = p - 3，只用于保存曲线参数，
+// 点运算使用 sm2_curve.go 中的常量时间实现
+func sm2Curve() *elliptic.CurveParams {
+	sm2Once.Do(func() {
+		hex := func(s string) *big.Int {
+			n, _ := new(big.Int).SetString(s, 16)
+			return n
+		}
+		sm2Params = &elliptic.CurveParams{
+			Name:    "SM2",
+			BitSize: 256,
+			P:       hex("FFFFFFFEFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF00000000FFFFFFFFFFFFFFFF"),
+			N:       hex("FFFFFFFEFFFFFFFFFFFFFFFFFFFFFFFF7203DF6B21C6052B53BBF40939D54123"),
+			B:       hex("28E9FA9E9D9F5E344D5A9E4BCF6509A7F39789F515AB8F92DDBCBD414D940E93"),
+			Gx:      hex("32C4AE2C1F1981195F9904466A39C9948FE30BBFF2660BE1715A4589334C74C7"),
+			Gy:      hex("BC3736A2F4F6779C59BDCEE36B692153D0A9877CC62A474002DF32E52139F0A0"),
+		}
+	})
+	return sm2Params
+}
+
+// sm2ECPrivateKey SEC 1 私钥结构
+type sm2ECPrivateKey struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+// sm2PKCS8 PKCS#8 私钥结构
+type sm2PKCS8 struct {
+	Version    int
+	Algo       pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+// sm2PublicKeyInfo PKIX 公钥结构
+type sm2PublicKeyInfo struct {
+	Algo      pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// sm2Signature ASN.1 签名结构
+type sm2Signature struct {
+	R, S *big.Int
+}
+
+// sm2AlgorithmIdentifier SM2 公钥算法标识，参数为曲线 OID
+func sm2AlgorithmIdentifier() pkix.AlgorithmIdentifier {
+	params, _ := asn1.Marshal(oidNamedCurveSM2)
+	return pkix.AlgorithmIdentifier{Algorithm: oidPublicKeyECDSA, Parameters: asn1.RawValue{FullBytes: params}}
+}
+
+// Sm2GenerateKey 生成 SM2 密钥对，返回 PEM 格式的私钥 (PKCS#8) 和公钥 (PKIX) 字符串，与 OpenSSL 兼容
+func Sm2GenerateKey() (prvKey, pubKey string, err error) {
+	d, err := sm2RandScalar(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	key := sm2NewPrivateKey(new(big.Int).SetBytes(d))
+
+	der, err := sm2MarshalPrivateKey(key)
+	if err != nil {
+		return "", "", err
+	}
+	prvKey = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+
+	der, err = sm2MarshalPublicKey(&key.PublicKey)
+	if err != nil {
+		return "", "", err
+	}
+	pubKey = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	return prvKey, pubKey, nil
+}
+
+// sm2NewPrivateKey 根据私钥标量计算公钥
+func sm2NewPrivateKey(d *big.Int) *ecdsa.PrivateKey {
+	c := sm2Curve()
+	key := &ecdsa.PrivateKey{D: d}
+	key.Curve = c
+	key.X, key.Y = sm2ScalarBaseMult(d.FillBytes(make([]byte, 32)))
+	return key
+}
+
+// sm2RandScalar 生成 [1, n-2] 范围内的 32 字节大端序随机数，私钥 d 需满足 1 + d 可逆
+func sm2RandScalar(r io.Reader) ([]byte, error) {
+	zero := make([]byte, 32)
+	nMinus1 := new(big.Int).Sub(sm2Curve().N, big.NewInt(1)).FillBytes(make([]byte, 32))
+	for {
+		b := make([]byte, 32)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		if sm2N.valid(b) && subtle.ConstantTimeCompare(b, zero)|subtle.ConstantTimeCompare(b, nMinus1) == 0 {
+			return b, nil
+		}
+	}
+}
+
+// sm2MarshalPrivateKey 编码为 PKCS#8 DER
+func sm2MarshalPrivateKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	ec, err := asn1.Marshal(sm2ECPrivateKey{
+		Version:    1,
+		PrivateKey: key.D.FillBytes(make([]byte, 32)),
+		PublicKey:  asn1.BitString{Bytes: sm2MarshalPoint(key.X, key.Y), BitLength: 65 * 8},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(sm2PKCS8{Algo: sm2AlgorithmIdentifier(), PrivateKey: ec})
+}
+
+// sm2MarshalPublicKey 编码为 PKIX DER
+func sm2MarshalPublicKey(key *ecdsa.PublicKey) ([]byte, error) {
+	point := sm2MarshalPoint(key.X, key.Y)
+	return asn1.Marshal(sm2PublicKeyInfo{
+		Algo:      sm2AlgorithmIdentifier(),
+		PublicKey: asn1.BitString{Bytes: point, BitLength: len(point) * 8},
+	})
+}
+
+// sm2MarshalPoint 编码为未压缩的点 04||X||Y
+func sm2MarshalPoint(x, y *big.Int) []byte {
+	point := make([]byte, 65)
+	point[0] = 4
+	x.FillBytes(point[1:33])
+	y.FillBytes(point[33:])
+	return point
+}
+
+// sm2UnmarshalPoint 解析未压缩的点，并检查是否在曲线上
+func sm2UnmarshalPoint(point []byte) (x, y *big.Int, ok bool) {
+	if len(point) != 65 || point[0] != 4 {
+		return nil, nil, false
+	}
+	if _, ok := sm2NewPoint(point[1:33], point[33:]); !ok {
+		return nil, nil, false
+	}
+	return new(big.Int).SetBytes(point[1:33]), new(big.Int).SetBytes(point[33:]), true
+}
+
+// sm2ParsePrivateKey 解析 PEM 格式的 SM2 私钥，支持 PKCS#8 和 SEC 1 (EC PRIVATE KEY 或 SM2 PRIVATE KEY)
+func sm2ParsePrivateKey(prvKeyStr string) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(prvKeyStr))
+	if block == nil {
+		return nil, fmt.Errorf("pem decode error: %w", gotool.ErrInvalidParam)
+	}
+	der := block.Bytes
+	if block.Type == "PRIVATE KEY" {
+		var p8 sm2PKCS8
+		if _, err := asn1.Unmarshal(der, &p8); err != nil {
+			return nil, fmt.Errorf("%w, parse PKCS#8 private key: %v", gotool.ErrInvalidParam, err)
+		}
+		var curve asn1.ObjectIdentifier
+		if !p8.Algo.Algorithm.Equal(oidPublicKeyECDSA) {
+			return nil, fmt.Errorf("%w, not an SM2 private key", gotool.ErrInvalidParam)
+		}
+		if _, err := asn1.Unmarshal(p8.Algo.Parameters.FullBytes, &curve); err != nil || !curve.Equal(oidNamedCurveSM2) {
+			return nil, fmt.Errorf("%w, not an SM2 private key", gotool.ErrInvalidParam)
+		}
+		der = p8.PrivateKey
+	} else if block.Type != "EC PRIVATE KEY" && block.Type != "SM2 PRIVATE KEY" {
+		return nil, fmt.Errorf("%w, unsupported private key type: %s", gotool.ErrInvalidParam, block.Type)
+	}
+
+	var ec sm2ECPrivateKey
+	if _, err := asn1.Unmarshal(der, &ec); err != nil {
+		return nil, fmt.Errorf("%w, parse EC private key: %v", gotool.ErrInvalidParam, err)
+	}
+	if len(ec.NamedCurveOID) > 0 && !ec.NamedCurveOID.Equal(oidNamedCurveSM2) {
+		return nil, fmt.Errorf("%w, not an SM2 private key", gotool.ErrInvalidParam)
+	}
+	// 私钥范围为 [1, n-2]
+	d := new(big.Int).SetBytes(ec.PrivateKey)
+	if d.Sign() <= 0 || new(big.Int).Add(d, big.NewInt(1)).Cmp(sm2Curve().N) >= 0 {
+		return nil, fmt.Errorf("%w, invalid SM2 private key", gotool.ErrInvalidParam)
+	}
+	return sm2NewPrivateKey(d), nil
+}
+
+// sm2ParsePublicKey 解析 PEM 格式的 SM2 公钥 (PKIX)
+func sm2ParsePublicKey(pubKeyStr string) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(pubKeyStr))
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("pem decode error: %w", gotool.ErrInvalidParam)
+	}
+	var info sm2PublicKeyInfo
+	if _, err := asn1.Unmarshal(block.Bytes, &info); err != nil {
+		return nil, fmt.Errorf("%w, parse public key: %v", gotool.ErrInvalidParam, err)
+	}
+	var curve asn1.ObjectIdentifier
+	if !info.Algo.Algorithm.Equal(oidPublicKeyECDSA) {
+		return nil, fmt.Errorf("%w, not an SM2 public key", gotool.ErrInvalidParam)
+	}
+	if _, err := asn1.Unmarshal(info.Algo.Parameters.FullBytes, &curve); err != nil || !curve.Equal(oidNamedCurveSM2) {
+		return nil, fmt.Errorf("%w, not an SM2 public key", gotool.ErrInvalidParam)
+	}
+	x, y, ok := sm2UnmarshalPoint(info.PublicKey.Bytes)
+	if !ok {
+		return nil, fmt.Errorf("%w, invalid SM2 public key", gotool.ErrInvalidParam)
+	}
+	return &ecdsa.PublicKey{Curve: sm2Curve(), X: x, Y: y}, nil
+}
+
+// sm2Digest 计算 e = SM3(Z || M)，Z = SM3(ENTL || ID || a || b || xG || yG || xA || yA)
+func sm2Digest(pub *ecdsa.PublicKey, data, uid []byte) *big.Int {
+	c := sm2Curve()
+	a := new(big.Int).Sub(c.P, big.NewInt(3))
+	var entl [2]byte
+	binary.BigEndian.PutUint16(entl[:], uint16(len(uid)*8))
+	z := sm3Sum(entl[:], uid,
+		a.FillBytes(make([]byte, 32)), c.B.FillBytes(make([]byte, 32)),
+		c.Gx.FillBytes(make([]byte, 32)), c.Gy.FillBytes(make([]byte, 32)),
+		pub.X.FillBytes(make([]byte, 32)), pub.Y.FillBytes(make([]byte, 32)),
+	)
+	return new(big.Int).SetBytes(sm3Sum(z, data))
+}
+
+// sm2UID 获取用户身份标识
+func sm2UID(uid ...[]byte) []byte {
+	if len(uid) > 0 && uid[0] != nil {
+		return uid[0]
+	}
+	return sm2DefaultUID
+}
+
+// Sm2Sign SM2 私钥签名，摘要算法为 SM3，输出 ASN.1 DER 编码的签名
+//
+// # Params:
+//
+//	data: 待签名的数据
+//	privateKey: PEM 格式的私钥字符串
+//	uid: 用户身份标识，默认 "1234567812345678"，OpenSSL 命令行未指定 distid 时使用空标识，此时传入 []byte{}
+func Sm2Sign(data []byte, privateKey string, uid ...[]byte) ([]byte, error) {
+	key, err := sm2ParsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	r, s, err := sm2Sign(rand.Reader, key, sm2Digest(&key.PublicKey, data, sm2UID(uid...)))
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(sm2Signature{R: r, S: s})
+}
+
+// sm2Sign 对摘要 e 签名，涉及私钥和随机数 k 的运算均为常量时间
+func sm2Sign(random io.Reader, key *ecdsa.PrivateKey, e *big.Int) (r, s *big.Int, err error) {
+	n := sm2Curve().N
+	d := sm2N.fromBytes(key.D.FillBytes(make([]byte, 32)))
+	// (1 + d)^-1
+	one := sm2N.one()
+	var dInv sm2Element
+	sm2N.add(&dInv, &d, &one)
+	sm2N.inv(&dInv, &dInv)
+	for {
+		kb, err := sm2RandScalar(random)
+		if err != nil {
+			return nil, nil, err
+		}
+		x1, _ := sm2ScalarBaseMult(kb)
+		r = new(big.Int).Add(e, x1)
+		r.Mod(r, n)
+		k := sm2N.fromBytes(kb)
+		re := sm2N.fromBytes(r.FillBytes(make([]byte, 32)))
+		var t sm2Element
+		sm2N.add(&t, &re, &k)
+		if r.Sign() == 0 || t.isZero() == 1 {
+			continue
+		}
+		// s = (1 + d)^-1 * (k - r * d) mod n
+		sm2N.mul(&t, &re, &d)
+		sm2N.sub(&t, &k, &t)
+		sm2N.mul(&t, &t, &dInv)
+		s = new(big.Int).SetBytes(sm2N.toBytes(&t))
+		if s.Sign() != 0 {
+			return r, s, nil
+		}
+	}
+}
+
+// Sm2Verify SM2 公钥验签，签名有效时返回 nil
+//
+// # Params:
+//
+//	data: 原始数据
+//	signature: ASN.1 DER 编码的签名
+//	publicKey: PEM 格式的公钥字符串
+//	uid: 用户身份标识，需与签名时一致，默认 "1234567812345678"
+func Sm2Verify(data, signature []byte, publicKey string, uid ...[]byte) error {
+	pub, err := sm2ParsePublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+	var sig sm2Signature
+	if rest, err := asn1.Unmarshal(signature, &sig); err != nil || len(rest) > 0 {
+		return gotool.ErrInvalidSignature
+	}
+	if !sm2Verify(pub, sm2Digest(pub, data, sm2UID(uid...)), sig.R, sig.S) {
+		return gotool.ErrInvalidSignature
+	}
+	return nil
+}
+
+// sm2Verify 校验摘要 e 的签名
+func sm2Verify(pub *ecdsa.PublicKey, e, r, s *big.Int) bool {
+	c := sm2Curve()
+	n := c.N
+	if r.Sign() <= 0 || r.Cmp(n) >= 0 || s.Sign() <= 0 || s.Cmp(n) >= 0 {
+		return false
+	}
+	t := new(big.Int).Add(r, s)
+	t.Mod(t, n)
+	if t.Sign() == 0 {
+		return false
+	}
+	p, ok := sm2NewPoint(pub.X.FillBytes(make([]byte, 32)), pub.Y.FillBytes(make([]byte, 32)))
+	if !ok {
+		return false
+	}
+	// (x1, y1) = [s]G + [t]P
+	sum := new(sm2Point).scalarMult(&sm2G, s.FillBytes(make([]byte, 32)))
+	sum.add(sum, new(sm2Point).scalarMult(p, t.FillBytes(make([]byte, 32))))
+	if sum.z.isZero() == 1 {
+		return false
+	}
+	xb, _ := sum.affine()
+	x1 := new(big.Int).SetBytes(xb)
+	x1.Add(x1, e)
+	x1.Mod(x1, n)
+	return x1.Cmp(r) == 0
+}
+
+// sm2Mode 获取密文格式
+func sm2Mode(mode ...string) (string, error) {
+	if len(mode) == 0 || mode[0] == "" {
+		return Sm2C1C3C2, nil
+	}
+	if mode[0] != Sm2C1C3C2 && mode[0] != Sm2C1C2C3 {
+		return "", fmt.Errorf("%w, unsupported sm2 ciphertext mode: %s", gotool.ErrInvalidParam, mode[0])
+	}
+	return mode[0], nil
+}
+
+// sm2KDF 密钥派生函数，基于 SM3
+func sm2KDF(z []byte, length int) []byte {
+	out := make([]byte, 0, length+sm3Size)
+	var ct [4]byte
+	for i := uint32(1); len(out) < length; i++ {
+		binary.BigEndian.PutUint32(ct[:], i)
+		out = append(out, sm3Sum(z, ct[:])...)
+	}
+	return out[:length]
+}
+
+// Sm2Encrypt SM2 公钥加密
+//
+// # Params:
+//
+//	data: 待加密的数据
+//	publicKey: PEM 格式的公钥字符串
+//	mode: 密文格式，默认为 Sm2C1C3C2，可选 Sm2C1C2C3
+func Sm2Encrypt(data []byte, publicKey string, mode ...string) ([]byte, error) {
+	m, err := sm2Mode(mode...)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := sm2ParsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return sm2Encrypt(rand.Reader, pub, data, m)
+}
+
+// sm2Encrypt 加密数据，C1 为随机点，C2 为密文，C3 为 SM3(x2 || M || y2)
+func sm2Encrypt(random io.Reader, pub *ecdsa.PublicKey, data []byte, mode string) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w, sm2 plaintext is empty", gotool.ErrInvalidParam)
+	}
+	for {
+		kb, err := sm2RandScalar(random)
+		if err != nil {
+			return nil, err
+		}
+		x1, y1 := sm2ScalarBaseMult(kb)
+		x2, y2 := sm2ScalarMult(pub.X, pub.Y, kb)
+		x2b, y2b := x2.FillBytes(make([]byte, 32)), y2.FillBytes(make([]byte, 32))
+
+		t := sm2KDF(append(append([]byte{}, x2b...), y2b...), len(data))
+		if subtle.ConstantTimeCompare(t, make([]byte, len(t))) == 1 {
+			continue
+		}
+		c1 := sm2MarshalPoint(x1, y1)
+		c2 := make([]byte, len(data))
+		subtle.XORBytes(c2, data, t)
+		c3 := sm3Sum(x2b, data, y2b)
+
+		if mode == Sm2C1C2C3 {
+			return bytes.Join([][]byte{c1, c2, c3}, nil), nil
+		}
+		return bytes.Join([][]byte{c1, c3, c2}, nil), nil
+	}
+}
+
+// Sm2Decrypt SM2 私钥解密
+//
+// # Params:
+//
+//	ciphertext: 待解密的数据
+//	privateKey: PEM 格式的私钥字符串
+//	mode: 密文格式，默认为 Sm2C1C3C2，需与加密时一致
+func Sm2Decrypt(ciphertext []byte, privateKey string, mode ...string) ([]byte, error) {
+	m, err := sm2Mode(mode...)
+	if err != nil {
+		return nil, err
+	}
+	key, err := sm2ParsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return sm2Decrypt(key, ciphertext, m)
+}
+
+// sm2Decrypt 解密数据并校验 C3
+func sm2Decrypt(key *ecdsa.PrivateKey, ciphertext []byte, mode string) ([]byte, error) {
+	if len(ciphertext) <= 65+sm3Size {
+		return nil, gotool.ErrDecryptFailed
+	}
+	x1, y1, ok := sm2UnmarshalPoint(ciphertext[:65])
+	if !ok {
+		return nil, gotool.ErrDecryptFailed
+	}
+	var c2, c3 []byte
+	if mode == Sm2C1C2C3 {
+		c2, c3 = ciphertext[65:len(ciphertext)-sm3Size], ciphertext[len(ciphertext)-sm3Size:]
+	} else {
+		c3, c2 = ciphertext[65:65+sm3Size], ciphertext[65+sm3Size:]
+	}
+
+	x2, y2 := sm2ScalarMult(x1, y1, key.D.FillBytes(make([]byte, 32)))
+	x2b, y2b := x2.FillBytes(make([]byte, 32)), y2.FillBytes(make([]byte, 32))
+	t := sm2KDF(append(append([]byte{}, x2b...), y2b...), len(c2))
+	data := make([]byte, len(c2))
+	subtle.XORBytes(data, c2, t)
+	if subtle.ConstantTimeCompare(sm3Sum(x2b, data, y2b), c3) != 1 {
+		return nil, gotool.ErrDecryptFailed
+	}
+	return data, nil
+}
@@ -0,0 +1,393 @@
+package cryptoutil
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+)
+
+// sm2p256v1 的常量时间实现: 素数域和标量域使用 4 个 64 位字的 Montgomery 表示,
+// 点运算使用射影坐标下的完备公式 (Renes-Costello-Batina, https://eprint.iacr.org/2015/1060),
+// 标量乘法使用 4 位固定窗口, 查表时遍历整张表, 运算时间与标量和点的取值无关
+
+// sm2Element 模 m 的剩余, Montgomery 形式 (x * 2^256 mod m), 小端序
+type sm2Element [4]uint64
+
+// sm2Field 奇素数模数的 Montgomery 运算
+type sm2Field struct {
+	m   sm2Element // 模数
+	r2  sm2Element // 2^512 mod m, 用于转换为 Montgomery 形式
+	n0  uint64     // -m^-1 mod 2^64
+	exp []byte     // m - 2, 大端序, 用于费马小定理求逆
+}
+
+// newSm2Field 根据十六进制模数创建 Montgomery 运算参数
+func newSm2Field(hexModulus string) *sm2Field {
+	m, _ := new(big.Int).SetString(hexModulus, 16)
+	f := &sm2Field{}
+	f.m = sm2Limbs(m.FillBytes(make([]byte, 32)))
+	r2 := new(big.Int).Lsh(big.NewInt(1), 512)
+	f.r2 = sm2Limbs(r2.Mod(r2, m).FillBytes(make([]byte, 32)))
+	// 牛顿迭代求 m^-1 mod 2^64, 每次迭代精度翻倍
+	inv := uint64(1)
+	for i := 0; i < 6; i++ {
+		inv *= 2 - f.m[0]*inv
+	}
+	f.n0 = -inv
+	f.exp = new(big.Int).Sub(m, big.NewInt(2)).FillBytes(make([]byte, 32))
+	return f
+}
+
+var (
+	// sm2P 素数域 p
+	sm2P = newSm2Field("FFFFFFFEFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF00000000FFFFFFFFFFFFFFFF")
+	// sm2N 标量域, 基点的阶 n
+	sm2N = newSm2Field("FFFFFFFEFFFFFFFFFFFFFFFFFFFFFFFF7203DF6B21C6052B53BBF40939D54123")
+)
+
+// sm2Limbs 32 字节大端序转为小端序的字
+func sm2Limbs(b []byte) sm2Element {
+	var x sm2Element
+	for i := 0; i < 4; i++ {
+		x[i] = binary.BigEndian.Uint64(b[24-i*8:])
+	}
+	return x
+}
+
+// reduce 将小于 2m 的 5 字数 (t4:x) 约减到 [0, m)
+func (f *sm2Field) reduce(z *sm2Element, x *sm2Element, t4 uint64) {
+	var d sm2Element
+	var b uint64
+	d[0], b = bits.Sub64(x[0], f.m[0], 0)
+	d[1], b = bits.Sub64(x[1], f.m[1], b)
+	d[2], b = bits.Sub64(x[2], f.m[2], b)
+	d[3], b = bits.Sub64(x[3], f.m[3], b)
+	_, b = bits.Sub64(t4, 0, b)
+	// b 为 1 表示 x < m, 保留 x
+	mask := -b
+	for i := range z {
+		z[i] = x[i]&mask | d[i]&^mask
+	}
+}
+
+// mul z = x * y * 2^-256 mod m (CIOS Montgomery 乘法)
+func (f *sm2Field) mul(z, x, y *sm2Element) {
+	var t [5]uint64
+	for i := 0; i < 4; i++ {
+		// t += x * y[i]
+		var c, carry uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(x[j], y[i])
+			lo, carry = bits.Add64(lo, t[j], 0)
+			hi += carry
+			lo, carry = bits.Add64(lo, c, 0)
+			hi += carry
+			t[j], c = lo, hi
+		}
+		var t5 uint64
+		t[4], t5 = bits.Add64(t[4], c, 0)
+
+		// t = (t + u * m) / 2^64, 选择 u 使最低字为 0
+		u := t[0] * f.n0
+		hi, lo := bits.Mul64(u, f.m[0])
+		_, carry = bits.Add64(lo, t[0], 0)
+		c = hi + carry
+		for j := 1; j < 4; j++ {
+			hi, lo := bits.Mul64(u, f.m[j])
+			lo, carry = bits.Add64(lo, t[j], 0)
+			hi += carry
+			lo, carry = bits.Add64(lo, c, 0)
+			hi += carry
+			t[j-1], c = lo, hi
+		}
+		t[3], carry = bits.Add64(t[4], c, 0)
+		t[4] = t5 + carry
+	}
+	f.reduce(z, (*sm2Element)(t[:4]), t[4])
+}
+
+// add z = x + y mod m
+func (f *sm2Field) add(z, x, y *sm2Element) {
+	var s sm2Element
+	var c uint64
+	s[0], c = bits.Add64(x[0], y[0], 0)
+	s[1], c = bits.Add64(x[1], y[1], c)
+	s[2], c = bits.Add64(x[2], y[2], c)
+	s[3], c = bits.Add64(x[3], y[3], c)
+	f.reduce(z, &s, c)
+}
+
+// sub z = x - y mod m
+func (f *sm2Field) sub(z, x, y *sm2Element) {
+	var d sm2Element
+	var b, c uint64
+	d[0], b = bits.Sub64(x[0], y[0], 0)
+	d[1], b = bits.Sub64(x[1], y[1], b)
+	d[2], b = bits.Sub64(x[2], y[2], b)
+	d[3], b = bits.Sub64(x[3], y[3], b)
+	// 有借位时加上 m
+	mask := -b
+	z[0], c = bits.Add64(d[0], f.m[0]&mask, 0)
+	z[1], c = bits.Add64(d[1], f.m[1]&mask, c)
+	z[2], c = bits.Add64(d[2], f.m[2]&mask, c)
+	z[3], _ = bits.Add64(d[3], f.m[3]&mask, c)
+}
+
+// inv z = x^-1 mod m, 即 x^(m-2), 指数公开, x 为 0 时结果为 0
+func (f *sm2Field) inv(z, x *sm2Element) {
+	r := f.one()
+	base := *x
+	for _, b := range f.exp {
+		for i := 7; i >= 0; i-- {
+			f.mul(&r, &r, &r)
+			if b>>uint(i)&1 == 1 {
+				f.mul(&r, &r, &base)
+			}
+		}
+	}
+	*z = r
+}
+
+// one Montgomery 形式的 1
+func (f *sm2Field) one() sm2Element {
+	one := sm2Element{1}
+	f.mul(&one, &one, &f.r2)
+	return one
+}
+
+// valid 判断 32 字节大端序的 b 是否在 [0, m) 范围内
+func (f *sm2Field) valid(b []byte) bool {
+	x := sm2Limbs(b)
+	var borrow uint64
+	for i := range x {
+		_, borrow = bits.Sub64(x[i], f.m[i], borrow)
+	}
+	return borrow == 1
+}
+
+// fromBytes 32 字节大端序 (需小于 m) 转为 Montgomery 形式
+func (f *sm2Field) fromBytes(b []byte) sm2Element {
+	x := sm2Limbs(b)
+	f.mul(&x, &x, &f.r2)
+	return x
+}
+
+// toBytes 从 Montgomery 形式转为 32 字节大端序
+func (f *sm2Field) toBytes(x *sm2Element) []byte {
+	one := sm2Element{1}
+	var y sm2Element
+	f.mul(&y, x, &one)
+	b := make([]byte, 32)
+	for i := 0; i < 4; i++ {
+		binary.BigEndian.PutUint64(b[24-i*8:], y[i])
+	}
+	return b
+}
+
+// isZero 常量时间判断是否为 0, 返回 1 或 0
+func (x *sm2Element) isZero() int {
+	return subtle.ConstantTimeEq(int32(bits.OnesCount64(x[0]|x[1]|x[2]|x[3])), 0)
+}
+
+// sm2Point 射影坐标 (X:Y:Z) 表示的点, 无穷远点为 (0:1:0)
+type sm2Point struct {
+	x, y, z sm2Element
+}
+
+var (
+	// sm2B 曲线参数 b
+	sm2B = sm2P.fromBytes(sm2Hex("28E9FA9E9D9F5E344D5A9E4BCF6509A7F39789F515AB8F92DDBCBD414D940E93"))
+	// sm2G 基点
+	sm2G = sm2Point{
+		x: sm2P.fromBytes(sm2Hex("32C4AE2C1F1981195F9904466A39C9948FE30BBFF2660BE1715A4589334C74C7")),
+		y: sm2P.fromBytes(sm2Hex("BC3736A2F4F6779C59BDCEE36B692153D0A9877CC62A474002DF32E52139F0A0")),
+		z: sm2P.one(),
+	}
+)
+
+// sm2Hex 解析十六进制常量
+func sm2Hex(s string) []byte {
+	n, _ := new(big.Int).SetString(s, 16)
+	return n.FillBytes(make([]byte, 32))
+}
+
+// sm2NewPoint 根据 32 字节大端序的仿射坐标创建点, 并检查坐标范围及 y^2 = x^3 - 3x + b
+func sm2NewPoint(x, y []byte) (*sm2Point, bool) {
+	if !sm2P.valid(x) || !sm2P.valid(y) {
+		return nil, false
+	}
+	p := &sm2Point{x: sm2P.fromBytes(x), y: sm2P.fromBytes(y), z: sm2P.one()}
+	var lhs, rhs, t sm2Element
+	sm2P.mul(&lhs, &p.y, &p.y)
+	sm2P.mul(&rhs, &p.x, &p.x)
+	sm2P.mul(&rhs, &rhs, &p.x)
+	sm2P.add(&t, &p.x, &p.x)
+	sm2P.add(&t, &t, &p.x)
+	sm2P.sub(&rhs, &rhs, &t)
+	sm2P.add(&rhs, &rhs, &sm2B)
+	if lhs != rhs {
+		return nil, false
+	}
+	return p, true
+}
+
+// sm2Identity 无穷远点
+func sm2Identity() *sm2Point {
+	return &sm2Point{y: sm2P.one()}
+}
+
+// add q = p1 + p2, a = -3 的完备加法公式, 对任意输入 (包括相同点和无穷远点) 都成立
+func (q *sm2Point) add(p1, p2 *sm2Point) *sm2Point {
+	f := sm2P
+	var t0, t1, t2, t3, t4, x3, y3, z3 sm2Element
+	f.mul(&t0, &p1.x, &p2.x)
+	f.mul(&t1, &p1.y, &p2.y)
+	f.mul(&t2, &p1.z, &p2.z)
+	f.add(&t3, &p1.x, &p1.y)
+	f.add(&t4, &p2.x, &p2.y)
+	f.mul(&t3, &t3, &t4)
+	f.add(&t4, &t0, &t1)
+	f.sub(&t3, &t3, &t4)
+	f.add(&t4, &p1.y, &p1.z)
+	f.add(&x3, &p2.y, &p2.z)
+	f.mul(&t4, &t4, &x3)
+	f.add(&x3, &t1, &t2)
+	f.sub(&t4, &t4, &x3)
+	f.add(&x3, &p1.x, &p1.z)
+	f.add(&y3, &p2.x, &p2.z)
+	f.mul(&x3, &x3, &y3)
+	f.add(&y3, &t0, &t2)
+	f.sub(&y3, &x3, &y3)
+	f.mul(&z3, &sm2B, &t2)
+	f.sub(&x3, &y3, &z3)
+	f.add(&z3, &x3, &x3)
+	f.add(&x3, &x3, &z3)
+	f.sub(&z3, &t1, &x3)
+	f.add(&x3, &t1, &x3)
+	f.mul(&y3, &sm2B, &y3)
+	f.add(&t1, &t2, &t2)
+	f.add(&t2, &t1, &t2)
+	f.sub(&y3, &y3, &t2)
+	f.sub(&y3, &y3, &t0)
+	f.add(&t1, &y3, &y3)
+	f.add(&y3, &t1, &y3)
+	f.add(&t1, &t0, &t0)
+	f.add(&t0, &t1, &t0)
+	f.sub(&t0, &t0, &t2)
+	f.mul(&t1, &t4, &y3)
+	f.mul(&t2, &t0, &y3)
+	f.mul(&y3, &x3, &z3)
+	f.add(&y3, &y3, &t2)
+	f.mul(&x3, &t3, &x3)
+	f.sub(&x3, &x3, &t1)
+	f.mul(&z3, &t4, &z3)
+	f.mul(&t1, &t3, &t0)
+	f.add(&z3, &z3, &t1)
+	q.x, q.y, q.z = x3, y3, z3
+	return q
+}
+
+// double q = 2p, a = -3 的完备倍点公式
+func (q *sm2Point) double(p *sm2Point) *sm2Point {
+	f := sm2P
+	var t0, t1, t2, t3, x3, y3, z3 sm2Element
+	f.mul(&t0, &p.x, &p.x)
+	f.mul(&t1, &p.y, &p.y)
+	f.mul(&t2, &p.z, &p.z)
+	f.mul(&t3, &p.x, &p.y)
+	f.add(&t3, &t3, &t3)
+	f.mul(&z3, &p.x, &p.z)
+	f.add(&z3, &z3, &z3)
+	f.mul(&y3, &sm2B, &t2)
+	f.sub(&y3, &y3, &z3)
+	f.add(&x3, &y3, &y3)
+	f.add(&y3, &x3, &y3)
+	f.sub(&x3, &t1, &y3)
+	f.add(&y3, &t1, &y3)
+	f.mul(&y3, &x3, &y3)
+	f.mul(&x3, &x3, &t3)
+	f.add(&t3, &t2, &t2)
+	f.add(&t2, &t2, &t3)
+	f.mul(&z3, &sm2B, &z3)
+	f.sub(&z3, &z3, &t2)
+	f.sub(&z3, &z3, &t0)
+	f.add(&t3, &z3, &z3)
+	f.add(&z3, &z3, &t3)
+	f.add(&t3, &t0, &t0)
+	f.add(&t0, &t3, &t0)
+	f.sub(&t0, &t0, &t2)
+	f.mul(&t0, &t0, &z3)
+	f.add(&y3, &y3, &t0)
+	f.mul(&t0, &p.y, &p.z)
+	f.add(&t0, &t0, &t0)
+	f.mul(&z3, &t0, &z3)
+	f.sub(&x3, &x3, &z3)
+	f.mul(&z3, &t0, &t1)
+	f.add(&z3, &z3, &z3)
+	f.add(&z3, &z3, &z3)
+	q.x, q.y, q.z = x3, y3, z3
+	return q
+}
+
+// selectFrom 常量时间地从 table 中取出第 idx 个点
+func (q *sm2Point) selectFrom(table *[16]sm2Point, idx byte) {
+	*q = sm2Point{}
+	for i := range table {
+		mask := -uint64(subtle.ConstantTimeByteEq(byte(i), idx))
+		for j := 0; j < 4; j++ {
+			q.x[j] |= table[i].x[j] & mask
+			q.y[j] |= table[i].y[j] & mask
+			q.z[j] |= table[i].z[j] & mask
+		}
+	}
+}
+
+// scalarMult q = [k]p, k 为 32 字节大端序, 4 位固定窗口
+func (q *sm2Point) scalarMult(p *sm2Point, k []byte) *sm2Point {
+	var table [16]sm2Point
+	table[0] = *sm2Identity()
+	for i := 1; i < 16; i++ {
+		table[i].add(&table[i-1], p)
+	}
+
+	r := sm2Identity()
+	var t sm2Point
+	for _, b := range k {
+		for _, w := range [2]byte{b >> 4, b & 0x0F} {
+			r.double(r)
+			r.double(r)
+			r.double(r)
+			r.double(r)
+			t.selectFrom(&table, w)
+			r.add(r, &t)
+		}
+	}
+	*q = *r
+	return q
+}
+
+// affine 转换为 32 字节大端序的仿射坐标, 无穷远点返回 (0, 0)
+func (p *sm2Point) affine() (x, y []byte) {
+	var zInv, ax, ay sm2Element
+	sm2P.inv(&zInv, &p.z)
+	sm2P.mul(&ax, &p.x, &zInv)
+	sm2P.mul(&ay, &p.y, &zInv)
+	return sm2P.toBytes(&ax), sm2P.toBytes(&ay)
+}
+
+// sm2ScalarBaseMult 计算 [k]G, 返回仿射坐标
+func sm2ScalarBaseMult(k []byte) (x, y *big.Int) {
+	xb, yb := new(sm2Point).scalarMult(&sm2G, k).affine()
+	return new(big.Int).SetBytes(xb), new(big.Int).SetBytes(yb)
+}
+
+// sm2ScalarMult 计算 [k](x, y), 点需已通过 sm2NewPoint 检查
+func sm2ScalarMult(x, y *big.Int, k []byte) (*big.Int, *big.Int) {
+	p, ok := sm2NewPoint(x.FillBytes(make([]byte, 32)), y.FillBytes(make([]byte, 32)))
+	if !ok {
+		return new(big.Int), new(big.Int)
+	}
+	xb, yb := new(sm2Point).scalarMult(p, k).affine()
+	return new(big.Int).SetBytes(xb), new(big.Int).SetBytes(yb)
+}
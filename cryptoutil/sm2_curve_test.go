@@ -0,0 +1,93 @@
+package cryptoutil
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestSm2Field(t *testing.T) {
+	c := sm2Curve()
+	for f, m := range map[*sm2Field]*big.Int{sm2P: c.P, sm2N: c.N} {
+		testutil.Equal(t, f.valid(m.FillBytes(make([]byte, 32))), false)
+		testutil.Equal(t, f.valid(new(big.Int).Sub(m, big.NewInt(1)).FillBytes(make([]byte, 32))), true)
+
+		for _, pair := range [][2]*big.Int{
+			{big.NewInt(0), big.NewInt(1)},
+			{new(big.Int).Sub(m, big.NewInt(1)), new(big.Int).Sub(m, big.NewInt(1))},
+			{new(big.Int).Sub(m, big.NewInt(1)), big.NewInt(1)},
+		} {
+			sm2CheckField(t, f, m, pair[0], pair[1])
+		}
+		for i := 0; i < 100; i++ {
+			a, _ := rand.Int(rand.Reader, m)
+			b, _ := rand.Int(rand.Reader, m)
+			sm2CheckField(t, f, m, a, b)
+		}
+	}
+}
+
+// sm2CheckField 与 big.Int 的计算结果比较
+func sm2CheckField(t *testing.T, f *sm2Field, m, a, b *big.Int) {
+	x, y := f.fromBytes(a.FillBytes(make([]byte, 32))), f.fromBytes(b.FillBytes(make([]byte, 32)))
+	got := func(z sm2Element) string { return new(big.Int).SetBytes(f.toBytes(&z)).Text(16) }
+
+	var z sm2Element
+	f.mul(&z, &x, &y)
+	testutil.Equal(t, got(z), new(big.Int).Mod(new(big.Int).Mul(a, b), m).Text(16))
+	f.add(&z, &x, &y)
+	testutil.Equal(t, got(z), new(big.Int).Mod(new(big.Int).Add(a, b), m).Text(16))
+	f.sub(&z, &x, &y)
+	testutil.Equal(t, got(z), new(big.Int).Mod(new(big.Int).Sub(a, b), m).Text(16))
+	if b.Sign() != 0 {
+		f.inv(&z, &y)
+		testutil.Equal(t, got(z), new(big.Int).ModInverse(b, m).Text(16))
+	}
+}
+
+func TestSm2ScalarMult(t *testing.T) {
+	c := sm2Curve()
+	nMinus1 := new(big.Int).Sub(c.N, big.NewInt(1))
+	scalars := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(15), big.NewInt(16), nMinus1}
+	for i := 0; i < 20; i++ {
+		k, _ := rand.Int(rand.Reader, c.N)
+		scalars = append(scalars, k)
+	}
+	for _, k := range scalars {
+		kb := k.FillBytes(make([]byte, 32))
+		// 与通用的 big.Int 实现比较
+		wantX, wantY := c.ScalarBaseMult(kb)
+		x, y := sm2ScalarBaseMult(kb)
+		testutil.Equal(t, x.Text(16), wantX.Text(16))
+		testutil.Equal(t, y.Text(16), wantY.Text(16))
+
+		wantX, wantY = c.ScalarMult(wantX, wantY, kb)
+		x, y = sm2ScalarMult(x, y, kb)
+		testutil.Equal(t, x.Text(16), wantX.Text(16))
+		testutil.Equal(t, y.Text(16), wantY.Text(16))
+	}
+
+	// [0]G 和 G + (-G) 为无穷远点
+	p := new(sm2Point).scalarMult(&sm2G, make([]byte, 32))
+	testutil.Equal(t, p.z.isZero(), 1)
+	neg := new(sm2Point).scalarMult(&sm2G, nMinus1.FillBytes(make([]byte, 32)))
+	testutil.Equal(t, new(sm2Point).add(&sm2G, neg).z.isZero(), 1)
+
+	// 完备公式: 相同点相加等于倍点, 加无穷远点不变
+	a := new(sm2Point).add(&sm2G, &sm2G)
+	b := new(sm2Point).double(&sm2G)
+	ax, ay := a.affine()
+	bx, by := b.affine()
+	testutil.Equal(t, ax, bx)
+	testutil.Equal(t, ay, by)
+	ax, _ = new(sm2Point).add(sm2Identity(), &sm2G).affine()
+	testutil.Equal(t, new(big.Int).SetBytes(ax).Text(16), c.Gx.Text(16))
+
+	// 不在曲线上的点
+	_, ok := sm2NewPoint(c.Gx.FillBytes(make([]byte, 32)), c.Gx.FillBytes(make([]byte, 32)))
+	testutil.Equal(t, ok, false)
+	_, ok = sm2NewPoint(c.P.FillBytes(make([]byte, 32)), c.Gy.FillBytes(make([]byte, 32)))
+	testutil.Equal(t, ok, false)
+}
@@ -0,0 +1,153 @@
+package cryptoutil
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+const (
+	sm3BlockSize = 64
+	sm3Size      = 32
+)
+
+// sm3IV SM3 初始值
+var sm3IV = [8]uint32{
+	0x7380166f, 0x4914b2b9, 0x172442d7, 0xda8a0600,
+	0xa96f30bc, 0x163138aa, 0xe38dee4d, 0xb0fb0e4e,
+}
+
+// sm3Digest SM3 哈希 (GB/T 32905-2016)
+type sm3Digest struct {
+	h   [8]uint32
+	buf [sm3BlockSize]byte
+	n   int
+	len uint64
+}
+
+// newSm3 创建 SM3 哈希
+func newSm3() hash.Hash {
+	d := &sm3Digest{}
+	d.Reset()
+	return d
+}
+
+// sm3Sum 计算 SM3 哈希
+func sm3Sum(data ...[]byte) []byte {
+	h := newSm3()
+	for _, v := range data {
+		h.Write(v)
+	}
+	return h.Sum(nil)
+}
+
+// Sm3 获取SM3值
+//
+// # Params:
+//
+//	p: 待处理的数据
+//	salt: 密码盐
+func Sm3(p any, salt ...string) (string, error) {
+	return shaCommon(newSm3, p, salt...)
+}
+
+// Sm3File 获取文件SM3值
+func Sm3File(path string) (string, error) {
+	return shaFileCommon(newSm3, path)
+}
+
+// Reset 重置状态
+func (d *sm3Digest) Reset() {
+	d.h = sm3IV
+	d.n = 0
+	d.len = 0
+}
+
+// Size 输出长度
+func (d *sm3Digest) Size() int { return sm3Size }
+
+// BlockSize 块大小
+func (d *sm3Digest) BlockSize() int { return sm3BlockSize }
+
+// Write 写入数据
+func (d *sm3Digest) Write(p []byte) (int, error) {
+	n := len(p)
+	d.len += uint64(n)
+	for len(p) > 0 {
+		m := copy(d.buf[d.n:], p)
+		d.n += m
+		p = p[m:]
+		if d.n == sm3BlockSize {
+			d.compress(d.buf[:])
+			d.n = 0
+		}
+	}
+	return n, nil
+}
+
+// Sum 追加哈希值, 不改变当前状态
+func (d *sm3Digest) Sum(in []byte) []byte {
+	c := *d
+	// 填充: 0x80, 若干 0x00, 64 位消息长度 (比特)
+	var pad [sm3BlockSize + 8]byte
+	pad[0] = 0x80
+	padLen := (sm3BlockSize + 56 - int(c.len%sm3BlockSize)) % sm3BlockSize
+	if padLen == 0 {
+		padLen = sm3BlockSize
+	}
+	binary.BigEndian.PutUint64(pad[padLen:], c.len<<3)
+	c.Write(pad[:padLen+8])
+
+	var out [sm3Size]byte
+	for i, v := range c.h {
+		binary.BigEndian.PutUint32(out[i*4:], v)
+	}
+	return append(in, out[:]...)
+}
+
+// compress 压缩一个分组
+func (d *sm3Digest) compress(block []byte) {
+	var w [68]uint32
+	for i := 0; i < 16; i++ {
+		w[i] = binary.BigEndian.Uint32(block[i*4:])
+	}
+	for j := 16; j < 68; j++ {
+		x := w[j-16] ^ w[j-9] ^ bits.RotateLeft32(w[j-3], 15)
+		w[j] = x ^ bits.RotateLeft32(x, 15) ^ bits.RotateLeft32(x, 23) ^ bits.RotateLeft32(w[j-13], 7) ^ w[j-6]
+	}
+
+	a, b, c, dd, e, f, g, h := d.h[0], d.h[1], d.h[2], d.h[3], d.h[4], d.h[5], d.h[6], d.h[7]
+	for j := 0; j < 64; j++ {
+		var t, ff, gg uint32
+		if j < 16 {
+			t = 0x79cc4519
+			ff = a ^ b ^ c
+			gg = e ^ f ^ g
+		} else {
+			t = 0x7a879d8a
+			ff = (a & b) | (a & c) | (b & c)
+			gg = (e & f) | (^e & g)
+		}
+		a12 := bits.RotateLeft32(a, 12)
+		ss1 := bits.RotateLeft32(a12+e+bits.RotateLeft32(t, j%32), 7)
+		ss2 := ss1 ^ a12
+		tt1 := ff + dd + ss2 + (w[j] ^ w[j+4])
+		tt2 := gg + h + ss1 + w[j]
+		dd = c
+		c = bits.RotateLeft32(b, 9)
+		b = a
+		a = tt1
+		h = g
+		g = bits.RotateLeft32(f, 19)
+		f = e
+		e = tt2 ^ bits.RotateLeft32(tt2, 9) ^ bits.RotateLeft32(tt2, 17)
+	}
+	d.h[0] ^= a
+	d.h[1] ^= b
+	d.h[2] ^= c
+	d.h[3] ^= dd
+	d.h[4] ^= e
+	d.h[5] ^= f
+	d.h[6] ^= g
+	d.h[7] ^= h
+}
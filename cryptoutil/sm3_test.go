@@ -0,0 +1,35 @@
+package cryptoutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestSm3(t *testing.T) {
+	// GB/T 32905-2016 附录 A
+	s, err := Sm3("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, s, "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0")
+
+	s, _ = Sm3([]byte(strings.Repeat("abcd", 16)))
+	testutil.Equal(t, s, "debe9ff92275b8a138604889c18e5a4d6fdb70e5387e5765293dcba39c0c5732")
+
+	s, _ = Sm3("ab", "c")
+	testutil.Equal(t, s, "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0")
+
+	// 逐字节写入与一次写入结果一致
+	data := []byte(strings.Repeat("0123456789", 20))
+	h := newSm3()
+	for _, b := range data {
+		h.Write([]byte{b})
+	}
+	testutil.Equal(t, h.Sum(nil), sm3Sum(data))
+}
+
+func TestSm3File(t *testing.T) {
+	t.Log(Sm3File("../LICENSE"))
+}
@@ -0,0 +1,87 @@
+package cryptoutil
+
+import (
+	"crypto/cipher"
+	"encoding/hex"
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestSm4Cipher(t *testing.T) {
+	// GB/T 32907-2016 附录 A
+	key, _ := hex.DecodeString("0123456789abcdeffedcba9876543210")
+	block, err := newSm4Cipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := make([]byte, 16)
+	block.Encrypt(dst, key)
+	testutil.Equal(t, hex.EncodeToString(dst), "681edf34d206965e86b3e94f536e4246")
+	block.Decrypt(dst, dst)
+	testutil.Equal(t, dst, key)
+
+	// 加密 1000000 次
+	copy(dst, key)
+	for i := 0; i < 1000000; i++ {
+		block.Encrypt(dst, dst)
+	}
+	testutil.Equal(t, hex.EncodeToString(dst), "595298c7c6fd271f0402f804c33d3f66")
+
+	_, err = newSm4Cipher(key[:8])
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestSm4Gcm(t *testing.T) {
+	// RFC 8998 附录 A.1
+	key, _ := hex.DecodeString("0123456789ABCDEFFEDCBA9876543210")
+	nonce, _ := hex.DecodeString("00001234567800000000ABCD")
+	aad, _ := hex.DecodeString("FEEDFACEDEADBEEFFEEDFACEDEADBEEFABADDAD2")
+	plain, _ := hex.DecodeString("AAAAAAAAAAAAAAAABBBBBBBBBBBBBBBBCCCCCCCCCCCCCCCCDDDDDDDDDDDDDDDDEEEEEEEEEEEEEEEEFFFFFFFFFFFFFFFFEEEEEEEEEEEEEEEEAAAAAAAAAAAAAAAA")
+	block, _ := newSm4Cipher(key)
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed := gcm.Seal(nil, nonce, plain, aad)
+	testutil.Equal(t, hex.EncodeToString(sealed), "17f399f08c67d5ee19d0dc9969c4bb7d5fd46fd3756489069157b282bb200735d82710ca5c22f0ccfa7cbf93d496ac15a56834cbcf98c397b4024a2691233b8d"+"83de3541e4c2b58177e065a9bf7b62ec")
+}
+
+func TestSm4CbcEncrypt(t *testing.T) {
+	key := []byte("1234567890123456")
+	s, err := Sm4CbcEncrypt("123456", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := Sm4CbcDecrypt(s, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, p, "123456")
+}
+
+func TestSm4CbcDecrypt(t *testing.T) {
+	// openssl enc -sm4-cbc -K 0123456789abcdeffedcba9876543210 -iv 000102030405060708090a0b0c0d0e0f
+	key, _ := hex.DecodeString("0123456789abcdeffedcba9876543210")
+	p, err := Sm4CbcDecrypt("AAECAwQFBgcICQoLDA0ODy0GxK48PzQCXvOxpPNCkJc=", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, p, "hello sm4 cbc")
+}
+
+func TestSm4GcmEncrypt(t *testing.T) {
+	key := []byte("1234567890123456")
+	s, err := Sm4GcmEncrypt("123456", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := Sm4GcmDecrypt(s, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, p, "123456")
+
+	_, err = Sm4GcmDecrypt(s, []byte("6543210987654321"))
+	testutil.NotEqual(t, err, nil)
+}
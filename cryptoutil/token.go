@@ -0,0 +1,206 @@
+package cryptoutil
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/up-zero/gotool"
+)
+
+// 令牌格式:
+//
+//	local:  gt1.local.base64url(nonce (24) | 密文 | tag (16))[.base64url(footer)]
+//	public: v4.public.base64url(payload | 签名 (64))[.base64url(footer)]
+//
+// local 令牌使用 XChaCha20-Poly1305 加密, 附加数据为 PAE(header, nonce, footer, implicit),
+// 与 PASETO v4.local (XChaCha20 + BLAKE2b-MAC) 不同, 因此使用独立的版本前缀, 避免被误认为 PASETO 令牌;
+// public 令牌使用 Ed25519 对 PAE(header, payload, footer, implicit) 签名, 与 PASETO v4.public 兼容。
+//
+// footer 不加密但受认证保护, 可用于存放 kid 等信息; implicit 为隐式断言,
+// 不出现在令牌中, 但签发和校验时必须一致
+const (
+	// TokenLocalHeader local 令牌头，gotool 自定义格式，不与 PASETO 互通
+	TokenLocalHeader = "gt1.local."
+	// TokenPublicHeader public 令牌头，与 PASETO v4.public 兼容
+	TokenPublicHeader = "v4.public."
+
+	tokenLocalKeySize = chachaKeySize
+)
+
+// GenerateLocalTokenKey 生成 local 令牌使用的 32 字节随机密钥
+func GenerateLocalTokenKey() ([]byte, error) {
+	key := make([]byte, tokenLocalKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// LocalTokenEncrypt 生成加密的 local 令牌
+//
+// # Params:
+//
+//	payload: 负载，通常为 JSON
+//	key: 32 字节对称密钥
+//	footer: 页脚，明文但不可篡改，可为空
+//	implicit: 隐式断言，可选
+func LocalTokenEncrypt(payload, key, footer []byte, implicit ...[]byte) (string, error) {
+	aead, err := newXChaCha20Poly1305(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	ad := tokenPAE([]byte(TokenLocalHeader), nonce, footer, tokenImplicit(implicit))
+	body := aead.Seal(nonce, nonce, payload, ad)
+	return tokenEncode(TokenLocalHeader, body, footer), nil
+}
+
+// LocalTokenDecrypt 解密并校验 local 令牌
+//
+// # Params:
+//
+//	token: 令牌
+//	key: 32 字节对称密钥
+//	implicit: 隐式断言，需与生成时一致
+func LocalTokenDecrypt(token string, key []byte, implicit ...[]byte) (payload, footer []byte, err error) {
+	aead, err := newXChaCha20Poly1305(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	body, footer, err := tokenDecode(token, TokenLocalHeader)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(body) < aead.NonceSize()+aead.Overhead() {
+		return nil, nil, gotool.ErrInvalidToken
+	}
+	nonce, ciphertext := body[:aead.NonceSize()], body[aead.NonceSize():]
+	ad := tokenPAE([]byte(TokenLocalHeader), nonce, footer, tokenImplicit(implicit))
+	payload, err = aead.Open(nil, nonce, ciphertext, ad)
+	if err != nil {
+		return nil, nil, err
+	}
+	return payload, footer, nil
+}
+
+// PublicTokenSign 生成签名的 public 令牌，负载不加密
+//
+// # Params:
+//
+//	payload: 负载，通常为 JSON
+//	privateKey: PEM 格式的 Ed25519 私钥
+//	footer: 页脚，明文但不可篡改，可为空
+//	implicit: 隐式断言，可选
+func PublicTokenSign(payload []byte, privateKey string, footer []byte, implicit ...[]byte) (string, error) {
+	prv, err := parseEd25519PrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+	m2 := tokenPAE([]byte(TokenPublicHeader), payload, footer, tokenImplicit(implicit))
+	body := append(bytes.Clone(payload), ed25519.Sign(prv, m2)...)
+	return tokenEncode(TokenPublicHeader, body, footer), nil
+}
+
+// PublicTokenVerify 校验 public 令牌的签名
+//
+// # Params:
+//
+//	token: 令牌
+//	publicKey: PEM 格式的 Ed25519 公钥
+//	implicit: 隐式断言，需与生成时一致
+func PublicTokenVerify(token, publicKey string, implicit ...[]byte) (payload, footer []byte, err error) {
+	pub, err := parseEd25519PublicKey(publicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	body, footer, err := tokenDecode(token, TokenPublicHeader)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(body) < ed25519.SignatureSize {
+		return nil, nil, gotool.ErrInvalidToken
+	}
+	payload, sig := body[:len(body)-ed25519.SignatureSize], body[len(body)-ed25519.SignatureSize:]
+	m2 := tokenPAE([]byte(TokenPublicHeader), payload, footer, tokenImplicit(implicit))
+	if !ed25519.Verify(pub, m2, sig) {
+		return nil, nil, gotool.ErrInvalidSignature
+	}
+	return payload, footer, nil
+}
+
+// TokenFooter 读取令牌的页脚，不做任何校验，可用于在校验前根据 kid 查找密钥
+//
+// # Params:
+//
+//	token: 令牌
+func TokenFooter(token string) ([]byte, error) {
+	for _, header := range []string{TokenLocalHeader, TokenPublicHeader} {
+		if strings.HasPrefix(token, header) {
+			_, footer, err := tokenDecode(token, header)
+			return footer, err
+		}
+	}
+	return nil, gotool.ErrInvalidToken
+}
+
+// tokenEncode 拼接令牌
+func tokenEncode(header string, body, footer []byte) string {
+	token := header + base64.RawURLEncoding.EncodeToString(body)
+	if len(footer) > 0 {
+		token += "." + base64.RawURLEncoding.EncodeToString(footer)
+	}
+	return token
+}
+
+// tokenDecode 校验令牌头并解码负载和页脚
+func tokenDecode(token, header string) (body, footer []byte, err error) {
+	if !strings.HasPrefix(token, header) {
+		return nil, nil, fmt.Errorf("%w, unexpected header", gotool.ErrInvalidToken)
+	}
+	parts := strings.Split(token[len(header):], ".")
+	if len(parts) > 2 {
+		return nil, nil, gotool.ErrInvalidToken
+	}
+	if body, err = base64.RawURLEncoding.DecodeString(parts[0]); err != nil {
+		return nil, nil, fmt.Errorf("%w, %v", gotool.ErrInvalidToken, err)
+	}
+	if len(parts) == 2 {
+		if footer, err = base64.RawURLEncoding.DecodeString(parts[1]); err != nil {
+			return nil, nil, fmt.Errorf("%w, %v", gotool.ErrInvalidToken, err)
+		}
+	}
+	return body, footer, nil
+}
+
+// tokenImplicit 取可选的隐式断言
+func tokenImplicit(implicit [][]byte) []byte {
+	if len(implicit) > 0 {
+		return implicit[0]
+	}
+	return nil
+}
+
+// tokenPAE 预认证编码 (Pre-Authentication Encoding)，避免拼接歧义
+//
+//	PAE(p...) = LE64(len(p)) | LE64(len(p[0])) | p[0] | ...
+func tokenPAE(pieces ...[]byte) []byte {
+	size := 8
+	for _, p := range pieces {
+		size += 8 + len(p)
+	}
+	out := make([]byte, 0, size)
+	out = binary.LittleEndian.AppendUint64(out, uint64(len(pieces))&^(1<<63))
+	for _, p := range pieces {
+		out = binary.LittleEndian.AppendUint64(out, uint64(len(p))&^(1<<63))
+		out = append(out, p...)
+	}
+	return out
+}
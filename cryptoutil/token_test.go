@@ -0,0 +1,118 @@
+package cryptoutil
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/up-zero/gotool"
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestLocalToken(t *testing.T) {
+	key, err := GenerateLocalTokenKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := []byte(`{"sub":"user-1","exp":"2030-01-01T00:00:00Z"}`)
+	footer := []byte(`{"kid":"k1"}`)
+
+	token, err := LocalTokenEncrypt(payload, key, footer, []byte("ctx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, strings.HasPrefix(token, "gt1.local."), true)
+	testutil.Equal(t, strings.Contains(token, "user-1"), false)
+
+	got, gotFooter, err := LocalTokenDecrypt(token, key, []byte("ctx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, string(got), string(payload))
+	testutil.Equal(t, string(gotFooter), string(footer))
+
+	f, _ := TokenFooter(token)
+	testutil.Equal(t, string(f), string(footer))
+
+	// 隐式断言不一致
+	_, _, err = LocalTokenDecrypt(token, key, []byte("other"))
+	testutil.Equal(t, errors.Is(err, gotool.ErrDecryptFailed), true)
+	// 篡改页脚
+	tampered := token[:strings.LastIndex(token, ".")+1] + "eyJraWQiOiJrMiJ9"
+	_, _, err = LocalTokenDecrypt(tampered, key, []byte("ctx"))
+	testutil.Equal(t, errors.Is(err, gotool.ErrDecryptFailed), true)
+	// 错误的密钥
+	other, _ := GenerateLocalTokenKey()
+	_, _, err = LocalTokenDecrypt(token, other, []byte("ctx"))
+	testutil.Equal(t, errors.Is(err, gotool.ErrDecryptFailed), true)
+	// 错误的令牌类型
+	_, _, err = LocalTokenDecrypt("v4.public."+token[len(TokenLocalHeader):], key)
+	testutil.Equal(t, errors.Is(err, gotool.ErrInvalidToken), true)
+	// 不接受 PASETO v4.local 令牌头
+	_, _, err = LocalTokenDecrypt("v4.local."+token[len(TokenLocalHeader):], key)
+	testutil.Equal(t, errors.Is(err, gotool.ErrInvalidToken), true)
+
+	// 无页脚
+	token, _ = LocalTokenEncrypt(payload, key, nil)
+	testutil.Equal(t, strings.Count(token, "."), 2)
+	got, gotFooter, err = LocalTokenDecrypt(token, key)
+	testutil.Equal(t, err, nil)
+	testutil.Equal(t, string(got), string(payload))
+	testutil.Equal(t, len(gotFooter), 0)
+
+	_, err = LocalTokenEncrypt(payload, key[:16], nil)
+	testutil.Equal(t, errors.Is(err, gotool.ErrInvalidParam), true)
+}
+
+func TestPublicToken(t *testing.T) {
+	prvKey, pubKey, err := Ed25519GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := []byte(`{"data":"hello"}`)
+	token, err := PublicTokenSign(payload, prvKey, []byte("kid-1"), []byte("ctx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, footer, err := PublicTokenVerify(token, pubKey, []byte("ctx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, string(got), string(payload))
+	testutil.Equal(t, string(footer), "kid-1")
+
+	_, _, err = PublicTokenVerify(token, pubKey)
+	testutil.Equal(t, errors.Is(err, gotool.ErrInvalidSignature), true)
+	_, _, err = PublicTokenVerify(token+"x", pubKey, []byte("ctx"))
+	testutil.NotEqual(t, err, nil)
+	_, _, err = PublicTokenVerify("v4.public.AAAA", pubKey)
+	testutil.Equal(t, errors.Is(err, gotool.ErrInvalidToken), true)
+}
+
+func TestPublicTokenVector(t *testing.T) {
+	// PASETO 官方测试向量 4-S-1
+	seed, _ := hex.DecodeString("b4cbfb43df4ce210727d953e4a713307fa19bb7d9f85041438d9e11b942a3774")
+	key := ed25519.NewKeyFromSeed(seed)
+	prv, _ := Ed25519MarshalPrivateKey(key, KeyFormatPEM)
+	pub, _ := Ed25519MarshalPublicKey(key.Public().(ed25519.PublicKey), KeyFormatPEM)
+	payload := `{"data":"this is a signed message","exp":"2022-01-01T00:00:00+00:00"}`
+	want := "v4.public.eyJkYXRhIjoidGhpcyBpcyBhIHNpZ25lZCBtZXNzYWdlIiwiZXhwIjoiMjAyMi0wMS0wMVQwMDowMDowMCswMDowMCJ9bg_XBBzds8lTZShVlwwKSgeKpLT3yukTw6JUz3W4h_ExsQV-P0V54zemZDcAxFaSeef1QlXEFtkqxT1ciiQEDA"
+
+	token, err := PublicTokenSign([]byte(payload), string(prv), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, token, want)
+
+	got, _, err := PublicTokenVerify(want, string(pub))
+	testutil.Equal(t, err, nil)
+	testutil.Equal(t, string(got), payload)
+}
+
+func TestTokenPAE(t *testing.T) {
+	testutil.Equal(t, hex.EncodeToString(tokenPAE()), "0000000000000000")
+	testutil.Equal(t, hex.EncodeToString(tokenPAE([]byte{})), "01000000000000000000000000000000")
+	testutil.Equal(t, hex.EncodeToString(tokenPAE([]byte("test"))), "0100000000000000040000000000000074657374")
+}
@@ -0,0 +1,101 @@
+package cryptoutil
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/up-zero/gotool"
+)
+
+// X25519GenerateKey 生成 X25519 密钥对，返回 PEM 格式的私钥 (PKCS8) 和公钥 (PKIX) 字符串
+func X25519GenerateKey() (prvKey, pubKey string, err error) {
+	privateKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+
+	derPrivateStream, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return "", "", err
+	}
+	prvKey = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: derPrivateStream}))
+
+	derPublicStream, err := x509.MarshalPKIXPublicKey(privateKey.PublicKey())
+	if err != nil {
+		return "", "", err
+	}
+	pubKey = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: derPublicStream}))
+
+	return prvKey, pubKey, nil
+}
+
+// X25519MarshalPrivateKey 按格式编码 X25519 私钥
+//
+// # Params:
+//
+//	key: 私钥
+//	format: 格式，可选值为 KeyFormatPEM (PKCS8)、KeyFormatPKCS8、KeyFormatDER、KeyFormatJWK
+func X25519MarshalPrivateKey(key *ecdh.PrivateKey, format string) ([]byte, error) {
+	if key.Curve() != ecdh.X25519() {
+		return nil, fmt.Errorf("%w, not a X25519 key", gotool.ErrInvalidParam)
+	}
+	return marshalAnyPrivateKey(key, format)
+}
+
+// X25519MarshalPublicKey 按格式编码 X25519 公钥，X25519 没有 SSH 格式
+//
+// # Params:
+//
+//	key: 公钥
+//	format: 格式，可选值为 KeyFormatPEM、KeyFormatDER、KeyFormatJWK
+func X25519MarshalPublicKey(key *ecdh.PublicKey, format string) ([]byte, error) {
+	if key.Curve() != ecdh.X25519() {
+		return nil, fmt.Errorf("%w, not a X25519 key", gotool.ErrInvalidParam)
+	}
+	return marshalAnyPublicKey(key, format)
+}
+
+// X25519ParsePrivateKey 解析 X25519 私钥，自动识别 PEM、DER 和 JWK 格式
+//
+// # Params:
+//
+//	data: 私钥数据
+func X25519ParsePrivateKey(data []byte) (*ecdh.PrivateKey, error) {
+	key, err := parseAnyPrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+	if prv, ok := key.(*ecdh.PrivateKey); ok && prv.Curve() == ecdh.X25519() {
+		return prv, nil
+	}
+	return nil, fmt.Errorf("parse X25519 private key error: %w", gotool.ErrInvalidParam)
+}
+
+// X25519ParsePublicKey 解析 X25519 公钥，自动识别 PEM、DER 和 JWK 格式
+//
+// # Params:
+//
+//	data: 公钥数据
+func X25519ParsePublicKey(data []byte) (*ecdh.PublicKey, error) {
+	key, err := parseAnyPublicKey(data)
+	if err != nil {
+		return nil, err
+	}
+	if pub, ok := key.(*ecdh.PublicKey); ok && pub.Curve() == ecdh.X25519() {
+		return pub, nil
+	}
+	return nil, fmt.Errorf("PKIX pub key assert error: %w", gotool.ErrInvalidParam)
+}
+
+// parseX25519PublicKey 解析 X25519 公钥
+func parseX25519PublicKey(pubKeyStr string) (*ecdh.PublicKey, error) {
+	return X25519ParsePublicKey([]byte(pubKeyStr))
+}
+
+// parseX25519PrivateKey 解析 X25519 私钥
+func parseX25519PrivateKey(prvKeyStr string) (*ecdh.PrivateKey, error) {
+	return X25519ParsePrivateKey([]byte(prvKeyStr))
+}
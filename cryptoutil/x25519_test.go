@@ -0,0 +1,27 @@
+package cryptoutil
+
+import (
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestX25519GenerateKey(t *testing.T) {
+	prvKey, pubKey, err := X25519GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prv, err := parseX25519PrivateKey(prvKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := parseX25519PublicKey(pubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, prv.PublicKey().Equal(pub), true)
+
+	_, edPub, _ := Ed25519GenerateKey()
+	_, err = parseX25519PublicKey(edPub)
+	testutil.NotEqual(t, err, nil)
+}
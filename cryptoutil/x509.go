@@ -1,14 +1,38 @@
 package cryptoutil
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"fmt"
 	"math/big"
 	"net"
+	"net/url"
+	"strings"
 	"time"
+
+	"github.com/up-zero/gotool"
+)
+
+const (
+	// CertKeyRSA RSA 密钥
+	CertKeyRSA = "RSA"
+	// CertKeyECDSA ECDSA 密钥
+	CertKeyECDSA = "ECDSA"
+	// CertKeyEd25519 Ed25519 密钥
+	CertKeyEd25519 = "Ed25519"
+
+	// CertUsageServer 服务端证书
+	CertUsageServer = "server"
+	// CertUsageClient 客户端证书
+	CertUsageClient = "client"
+	// CertUsageServerClient 同时用于服务端和客户端 (mTLS)
+	CertUsageServerClient = "server,client"
 )
 
 // CertConfig 证书配置信息
@@ -16,7 +40,31 @@ type CertConfig struct {
 	CommonName   string   // 证书的主域名或IP
 	Organization []string // 组织名称，例如 ["UpZero"]
 	Years        int      // 有效年限
-	Hosts        []string // IP 或 域名
+	Hosts        []string // IP 或 域名，也支持邮箱和 URI (例如 spiffe://cluster/ns/svc)
+	Days         int      // 有效天数，设置后忽略 Years
+	KeyType      string   // 密钥类型，可选值为 CertKeyRSA、CertKeyECDSA、CertKeyEd25519，默认 CertKeyRSA
+	RsaBits      int      // RSA 密钥长度，默认 4096
+	Curve        string   // ECDSA 曲线，默认 EcdsaP256
+	Usage        string   // 叶子证书用途，可选值为 CertUsageServer、CertUsageClient、CertUsageServerClient，默认 CertUsageServer
+}
+
+// setDefaults 设置默认值
+func (c *CertConfig) setDefaults() {
+	if c.Years <= 0 {
+		c.Years = 1
+	}
+	if c.KeyType == "" {
+		c.KeyType = CertKeyRSA
+	}
+	if c.RsaBits <= 0 {
+		c.RsaBits = 4096
+	}
+	if c.Curve == "" {
+		c.Curve = EcdsaP256
+	}
+	if c.Usage == "" {
+		c.Usage = CertUsageServer
+	}
 }
 
 // GenSelfSignedCert 生成自签名证书和私钥
@@ -31,21 +79,49 @@ type CertConfig struct {
 //	keyPEM: 私钥字符串
 //	err: 错误信息
 func GenSelfSignedCert(conf CertConfig) (certPEM, keyPEM string, err error) {
-	if conf.Years <= 0 {
-		conf.Years = 1
-	}
+	conf.setDefaults()
 
-	privateKey, err := rsa.GenerateKey(rand.Reader, 4096)
+	privateKey, err := genCertKey(conf)
 	if err != nil {
 		return "", "", err
 	}
 
 	// 证书模板配置
+	template, err := newCertTemplate(conf)
+	if err != nil {
+		return "", "", err
+	}
+	if err := setLeafUsage(template, conf, privateKey.Public()); err != nil {
+		return "", "", err
+	}
+
+	// 自签名
+	derBytes, err := x509.CreateCertificate(rand.Reader, template, template, privateKey.Public(), privateKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	// 编码输出
+	keyPEM, err = marshalCertKey(privateKey)
+	if err != nil {
+		return "", "", err
+	}
+	return encodeCertPEM(derBytes), keyPEM, nil
+}
+
+// newCertTemplate 根据配置生成证书模板，包括随机序列号、主题、有效期和 SAN
+func newCertTemplate(conf CertConfig) (*x509.Certificate, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
 	notBefore := time.Now()
 	notAfter := notBefore.AddDate(conf.Years, 0, 0)
-	serialNumber, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if conf.Days > 0 {
+		notAfter = notBefore.AddDate(0, 0, conf.Days)
+	}
 
-	template := x509.Certificate{
+	template := &x509.Certificate{
 		SerialNumber: serialNumber,
 		Subject: pkix.Name{
 			Organization: conf.Organization,
@@ -53,28 +129,142 @@ func GenSelfSignedCert(conf CertConfig) (certPEM, keyPEM string, err error) {
 		},
 		NotBefore:             notBefore,
 		NotAfter:              notAfter,
-		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
-		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
 		BasicConstraintsValid: true,
 	}
+	if err := setCertHosts(template, conf.Hosts); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
 
-	for _, h := range conf.Hosts {
+// setCertHosts 将主机列表按类型写入证书模板的 SAN
+func setCertHosts(template *x509.Certificate, hosts []string) error {
+	template.DNSNames, template.IPAddresses, template.EmailAddresses, template.URIs = nil, nil, nil, nil
+	for _, h := range hosts {
 		if ip := net.ParseIP(h); ip != nil {
 			template.IPAddresses = append(template.IPAddresses, ip)
+		} else if strings.Contains(h, "://") {
+			u, err := url.Parse(h)
+			if err != nil {
+				return fmt.Errorf("%w, invalid uri %q", gotool.ErrInvalidParam, h)
+			}
+			template.URIs = append(template.URIs, u)
+		} else if strings.Contains(h, "@") {
+			template.EmailAddresses = append(template.EmailAddresses, h)
 		} else {
 			template.DNSNames = append(template.DNSNames, h)
 		}
 	}
+	return nil
+}
 
-	// 自签名
-	derBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &privateKey.PublicKey, privateKey)
+// setLeafUsage 设置叶子证书的密钥用途
+func setLeafUsage(template *x509.Certificate, conf CertConfig, pub crypto.PublicKey) error {
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	if _, ok := pub.(*rsa.PublicKey); ok {
+		template.KeyUsage |= x509.KeyUsageKeyEncipherment
+	}
+	switch conf.Usage {
+	case CertUsageServer:
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	case CertUsageClient:
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	case CertUsageServerClient:
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	default:
+		return fmt.Errorf("%w, unsupported cert usage: %s", gotool.ErrInvalidParam, conf.Usage)
+	}
+	return nil
+}
+
+// genCertKey 根据配置生成私钥
+func genCertKey(conf CertConfig) (crypto.Signer, error) {
+	switch conf.KeyType {
+	case CertKeyRSA:
+		return rsa.GenerateKey(rand.Reader, conf.RsaBits)
+	case CertKeyECDSA:
+		c, err := ecdsaCurve(conf.Curve)
+		if err != nil {
+			return nil, err
+		}
+		return ecdsa.GenerateKey(c, rand.Reader)
+	case CertKeyEd25519:
+		_, prv, err := ed25519.GenerateKey(rand.Reader)
+		return prv, err
+	}
+	return nil, fmt.Errorf("%w, unsupported key type: %s", gotool.ErrInvalidParam, conf.KeyType)
+}
+
+// marshalCertKey 将私钥编码为 PEM, RSA 使用 PKCS1, ECDSA 使用 SEC 1, Ed25519 使用 PKCS8
+func marshalCertKey(key crypto.Signer) (string, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)})), nil
+	case *ecdsa.PrivateKey:
+		der, err := x509.MarshalECPrivateKey(k)
+		if err != nil {
+			return "", err
+		}
+		return string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})), nil
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
 	if err != nil {
-		return "", "", err
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+}
+
+// parseCertKey 解析 PEM 格式的 RSA、ECDSA 或 Ed25519 私钥
+func parseCertKey(keyPEM string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(keyPEM))
+	if block == nil {
+		return nil, fmt.Errorf("pem decode error: %w", gotool.ErrInvalidParam)
+	}
+	if prv, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return prv, nil
+	}
+	if prv, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return prv, nil
+	}
+	prvAny, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key error: %w", gotool.ErrInvalidParam)
+	}
+	if prv, ok := prvAny.(crypto.Signer); ok {
+		switch prv.(type) {
+		case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey:
+			return prv, nil
+		}
 	}
+	return nil, fmt.Errorf("%w, unsupported private key type %T", gotool.ErrInvalidParam, prvAny)
+}
 
-	// 编码输出
-	certPEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derBytes}))
-	keyPEM = string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}))
+// parseCertPEM 解析 PEM 中的所有证书
+func parseCertPEM(certPEM string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := []byte(certPEM)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%w, parse certificate: %v", gotool.ErrInvalidParam, err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("%w, no certificate found", gotool.ErrInvalidParam)
+	}
+	return certs, nil
+}
 
-	return certPEM, keyPEM, nil
+// encodeCertPEM 将 DER 证书编码为 PEM
+func encodeCertPEM(der []byte) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
 }
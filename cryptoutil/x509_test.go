@@ -1,6 +1,7 @@
 package cryptoutil
 
 import (
+	"crypto/tls"
 	"fmt"
 	"testing"
 )
@@ -17,3 +18,23 @@ func TestGenSelfSignedCert(t *testing.T) {
 	}
 	fmt.Printf("%v \n%v\n", certPEM, keyPEM)
 }
+
+func TestGenSelfSignedCertKeyType(t *testing.T) {
+	for _, keyType := range []string{CertKeyECDSA, CertKeyEd25519} {
+		certPEM, keyPEM, err := GenSelfSignedCert(CertConfig{
+			CommonName: "getcharzp.cn",
+			Hosts:      []string{"getcharzp.cn"},
+			KeyType:    keyType,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, _, err := GenSelfSignedCert(CertConfig{KeyType: "DSA"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
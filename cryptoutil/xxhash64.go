@@ -0,0 +1,121 @@
+package cryptoutil
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+const (
+	xxh64Prime1 uint64 = 0x9e3779b185ebca87
+	xxh64Prime2 uint64 = 0xc2b2ae3d27d4eb4f
+	xxh64Prime3 uint64 = 0x165667b19e3779f9
+	xxh64Prime4 uint64 = 0x85ebca77c2b2ae63
+	xxh64Prime5 uint64 = 0x27d4eb2f165667c5
+)
+
+// xxh64Digest xxHash64 非加密哈希，输出为大端序
+type xxh64Digest struct {
+	seed  uint64
+	v     [4]uint64
+	total uint64
+	buf   [32]byte
+	n     int
+}
+
+// newXxh64 创建 xxHash64 哈希
+func newXxh64(seed uint64) hash.Hash64 {
+	d := &xxh64Digest{seed: seed}
+	d.Reset()
+	return d
+}
+
+// Reset 重置状态
+func (d *xxh64Digest) Reset() {
+	d.v = [4]uint64{d.seed + xxh64Prime1 + xxh64Prime2, d.seed + xxh64Prime2, d.seed, d.seed - xxh64Prime1}
+	d.total = 0
+	d.n = 0
+}
+
+// Size 输出长度
+func (d *xxh64Digest) Size() int { return 8 }
+
+// BlockSize 块大小
+func (d *xxh64Digest) BlockSize() int { return 32 }
+
+// Write 写入数据
+func (d *xxh64Digest) Write(p []byte) (int, error) {
+	n := len(p)
+	d.total += uint64(n)
+	if d.n > 0 {
+		m := copy(d.buf[d.n:], p)
+		d.n += m
+		p = p[m:]
+		if d.n < 32 {
+			return n, nil
+		}
+		d.stripe(d.buf[:])
+		d.n = 0
+	}
+	for ; len(p) >= 32; p = p[32:] {
+		d.stripe(p)
+	}
+	d.n = copy(d.buf[:], p)
+	return n, nil
+}
+
+// stripe 处理 32 字节
+func (d *xxh64Digest) stripe(p []byte) {
+	for i := range d.v {
+		d.v[i] = xxh64Round(d.v[i], binary.LittleEndian.Uint64(p[i*8:]))
+	}
+}
+
+// Sum64 计算哈希值, 不改变当前状态
+func (d *xxh64Digest) Sum64() uint64 {
+	var h uint64
+	if d.total >= 32 {
+		h = bits.RotateLeft64(d.v[0], 1) + bits.RotateLeft64(d.v[1], 7) + bits.RotateLeft64(d.v[2], 12) + bits.RotateLeft64(d.v[3], 18)
+		for _, v := range d.v {
+			h ^= xxh64Round(0, v)
+			h = h*xxh64Prime1 + xxh64Prime4
+		}
+	} else {
+		h = d.seed + xxh64Prime5
+	}
+	h += d.total
+
+	p := d.buf[:d.n]
+	for ; len(p) >= 8; p = p[8:] {
+		h ^= xxh64Round(0, binary.LittleEndian.Uint64(p))
+		h = bits.RotateLeft64(h, 27)*xxh64Prime1 + xxh64Prime4
+	}
+	if len(p) >= 4 {
+		h ^= uint64(binary.LittleEndian.Uint32(p)) * xxh64Prime1
+		h = bits.RotateLeft64(h, 23)*xxh64Prime2 + xxh64Prime3
+		p = p[4:]
+	}
+	for _, b := range p {
+		h ^= uint64(b) * xxh64Prime5
+		h = bits.RotateLeft64(h, 11) * xxh64Prime1
+	}
+
+	h ^= h >> 33
+	h *= xxh64Prime2
+	h ^= h >> 29
+	h *= xxh64Prime3
+	h ^= h >> 32
+	return h
+}
+
+// Sum 追加大端序哈希值
+func (d *xxh64Digest) Sum(in []byte) []byte {
+	return binary.BigEndian.AppendUint64(in, d.Sum64())
+}
+
+// xxh64Round 累加一个 64 位输入
+func xxh64Round(acc, input uint64) uint64 {
+	acc += input * xxh64Prime2
+	acc = bits.RotateLeft64(acc, 31)
+	return acc * xxh64Prime1
+}
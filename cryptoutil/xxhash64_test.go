@@ -0,0 +1,32 @@
+package cryptoutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestXxh64(t *testing.T) {
+	for _, tt := range []struct {
+		data string
+		want uint64
+	}{
+		{"", 0xef46db3751d8e999},
+		{"a", 0xd24ec4f1a98c6e5b},
+		{"abc", 0x44bc2cf5ad770999},
+		{"hello world", 0x45ab6734b21e6968},
+		{strings.Repeat("0123456789", 10), 0xf80e7b96315afffa},
+	} {
+		h := newXxh64(0)
+		h.Write([]byte(tt.data))
+		testutil.Equal(t, h.Sum64(), tt.want)
+
+		// 分多次写入
+		h.Reset()
+		for i := 0; i < len(tt.data); i += 7 {
+			h.Write([]byte(tt.data[i:min(i+7, len(tt.data))]))
+		}
+		testutil.Equal(t, h.Sum64(), tt.want)
+	}
+}
@@ -22,6 +22,12 @@ var (
 	ErrExpiredJwt = errors.New("expired jwt")
 	// ErrInvalidJwtAlgorithm jwt 算法不支持
 	ErrInvalidJwtAlgorithm = errors.New("invalid jwt algorithm")
+	// ErrJwtNotValidYet jwt 尚未生效
+	ErrJwtNotValidYet = errors.New("jwt not valid yet")
+	// ErrInvalidJwtClaims jwt 声明校验失败
+	ErrInvalidJwtClaims = errors.New("invalid jwt claims")
+	// ErrJwkNotFound jwk 不存在
+	ErrJwkNotFound = errors.New("jwk not found")
 
 	// ErrSrcDstCannotBeNil 源和目标不能为空
 	ErrSrcDstCannotBeNil = errors.New("src and dst cannot be nil")
@@ -44,6 +50,17 @@ var (
 	// ErrCannotBeEmpty 不能为空
 	ErrCannotBeEmpty = errors.New("cannot be empty")
 
+	// ErrDecryptFailed 解密或认证失败
+	ErrDecryptFailed = errors.New("decrypt failed")
+	// ErrInvalidSignature 签名校验失败
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrInvalidToken 令牌格式错误
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrExpired 已过期
+	ErrExpired = errors.New("expired")
+	// ErrChecksumMismatch 校验和不匹配
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+
 	// ErrInvalidParam 参数错误
 	ErrInvalidParam = errors.New("invalid parameters")
 )
@@ -0,0 +1,573 @@
+package imageutil
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/up-zero/gotool"
+)
+
+const (
+	// QRLevelL 纠错等级 L，可恢复约 7% 的数据
+	QRLevelL = "L"
+	// QRLevelM 纠错等级 M，可恢复约 15% 的数据
+	QRLevelM = "M"
+	// QRLevelQ 纠错等级 Q，可恢复约 25% 的数据
+	QRLevelQ = "Q"
+	// QRLevelH 纠错等级 H，可恢复约 30% 的数据
+	QRLevelH = "H"
+)
+
+// qrQuietZone 二维码四周的空白区域宽度 (模块数)
+const qrQuietZone = 4
+
+// qrLevel 纠错等级信息
+type qrLevel struct {
+	index      int // 在纠错表中的下标
+	formatBits int // 格式信息中的纠错等级编码
+}
+
+var qrLevels = map[string]qrLevel{
+	QRLevelL: {index: 0, formatBits: 1},
+	QRLevelM: {index: 1, formatBits: 0},
+	QRLevelQ: {index: 2, formatBits: 3},
+	QRLevelH: {index: 3, formatBits: 2},
+}
+
+// qrEccCodewordsPerBlock 每个块的纠错码字数，按 [纠错等级][版本] 索引
+var qrEccCodewordsPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+// qrNumEccBlocks 纠错块数，按 [纠错等级][版本] 索引
+var qrNumEccBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// qrCode 二维码矩阵
+type qrCode struct {
+	version    int
+	size       int
+	level      qrLevel
+	modules    [][]bool // true 表示深色模块
+	isFunction [][]bool // 功能图形 (定位、校正、时序、格式信息等)，不参与掩码
+}
+
+// QRCode 生成二维码图片，使用字节模式编码，自动选择最小的版本和最佳掩码
+//
+// # Params:
+//
+//	content: 二维码内容
+//	size: 图片边长 (像素)，小于二维码所需的最小尺寸时自动放大
+//	level: 纠错等级，默认 QRLevelM
+//
+// # Example:
+//
+//	img, _ := QRCode("https://github.com/up-zero/gotool", 256)
+//	Save("qrcode.png", img, 100)
+func QRCode(content string, size int, level ...string) (image.Image, error) {
+	lv := QRLevelM
+	if len(level) > 0 && level[0] != "" {
+		lv = level[0]
+	}
+	qr, err := qrEncode([]byte(content), lv)
+	if err != nil {
+		return nil, err
+	}
+	return qr.image(size), nil
+}
+
+// QRCodeFile 生成二维码图片文件
+//
+// # Params:
+//
+//	content: 二维码内容
+//	dstFile: 目标图片文件
+//	size: 图片边长 (像素)
+//	level: 纠错等级，默认 QRLevelM
+func QRCodeFile(content, dstFile string, size int, level ...string) error {
+	img, err := QRCode(content, size, level...)
+	if err != nil {
+		return err
+	}
+	return Save(dstFile, img, 100)
+}
+
+// image 将二维码矩阵绘制为灰度图片，四周保留空白区域并居中
+func (q *qrCode) image(size int) image.Image {
+	total := q.size + 2*qrQuietZone
+	scale := max(size/total, 1)
+	size = max(size, total*scale)
+	offset := (size-total*scale)/2 + qrQuietZone*scale
+
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			if !q.modules[y][x] {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetGray(offset+x*scale+dx, offset+y*scale+dy, color.Gray{})
+				}
+			}
+		}
+	}
+	return img
+}
+
+// qrEncode 使用字节模式编码数据并生成二维码矩阵
+func qrEncode(data []byte, level string) (*qrCode, error) {
+	lv, ok := qrLevels[level]
+	if !ok {
+		return nil, fmt.Errorf("%w, unsupported QR code level: %s", gotool.ErrInvalidParam, level)
+	}
+
+	version := 0
+	for v := 1; v <= 40; v++ {
+		if 4+qrCharCountBits(v)+len(data)*8 <= qrNumDataCodewords(v, lv)*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, fmt.Errorf("%w, content too long for QR code: %d bytes", gotool.ErrInvalidParam, len(data))
+	}
+
+	// 模式指示符、字符计数、数据
+	capacity := qrNumDataCodewords(version, lv) * 8
+	bb := &qrBitBuffer{}
+	bb.append(0x4, 4)
+	bb.append(len(data), qrCharCountBits(version))
+	for _, b := range data {
+		bb.append(int(b), 8)
+	}
+	// 终止符，并补齐到字节边界
+	bb.append(0, min(4, capacity-bb.len))
+	bb.append(0, (8-bb.len%8)%8)
+	// 填充字节
+	for pad := 0xEC; bb.len < capacity; pad ^= 0xEC ^ 0x11 {
+		bb.append(pad, 8)
+	}
+
+	q := newQrCode(version, lv)
+	q.drawCodewords(q.addEccAndInterleave(bb.bytes()))
+
+	// 选择惩罚分最低的掩码
+	bestMask, minPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.drawFormatBits(mask)
+		if penalty := q.penaltyScore(); minPenalty < 0 || penalty < minPenalty {
+			bestMask, minPenalty = mask, penalty
+		}
+		q.applyMask(mask)
+	}
+	q.applyMask(bestMask)
+	q.drawFormatBits(bestMask)
+	return q, nil
+}
+
+// qrCharCountBits 字节模式下字符计数的位数
+func qrCharCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+// qrNumRawDataModules 除功能图形外可用于存放数据的模块数
+func qrNumRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+// qrNumDataCodewords 可用于存放数据的码字数
+func qrNumDataCodewords(version int, lv qrLevel) int {
+	return qrNumRawDataModules(version)/8 - qrEccCodewordsPerBlock[lv.index][version]*qrNumEccBlocks[lv.index][version]
+}
+
+// qrAlignmentPositions 校正图形的中心坐标
+func qrAlignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	numAlign := version/7 + 2
+	step := (version*8 + numAlign*3 + 5) / (numAlign*4 - 4) * 2
+	result := make([]int, numAlign)
+	result[0] = 6
+	for i, pos := numAlign-1, version*4+10; i >= 1; i, pos = i-1, pos-step {
+		result[i] = pos
+	}
+	return result
+}
+
+// newQrCode 创建二维码矩阵并绘制功能图形
+func newQrCode(version int, lv qrLevel) *qrCode {
+	size := version*4 + 17
+	q := &qrCode{
+		version:    version,
+		size:       size,
+		level:      lv,
+		modules:    make([][]bool, size),
+		isFunction: make([][]bool, size),
+	}
+	for i := 0; i < size; i++ {
+		q.modules[i] = make([]bool, size)
+		q.isFunction[i] = make([]bool, size)
+	}
+
+	// 时序图形
+	for i := 0; i < size; i++ {
+		q.setFunction(6, i, i%2 == 0)
+		q.setFunction(i, 6, i%2 == 0)
+	}
+	// 定位图形
+	q.drawFinder(3, 3)
+	q.drawFinder(size-4, 3)
+	q.drawFinder(3, size-4)
+	// 校正图形，跳过与定位图形重叠的三个角
+	positions := qrAlignmentPositions(version)
+	n := len(positions)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if (i == 0 && j == 0) || (i == 0 && j == n-1) || (i == n-1 && j == 0) {
+				continue
+			}
+			q.drawAlignment(positions[i], positions[j])
+		}
+	}
+	// 预留格式信息区域
+	q.drawFormatBits(0)
+	q.drawVersion()
+	return q
+}
+
+// setFunction 设置功能图形模块
+func (q *qrCode) setFunction(x, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.isFunction[y][x] = true
+}
+
+// drawFinder 绘制以 (x, y) 为中心的定位图形及其分隔符
+func (q *qrCode) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= q.size || yy < 0 || yy >= q.size {
+				continue
+			}
+			dist := max(qrAbs(dx), qrAbs(dy))
+			q.setFunction(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+// drawAlignment 绘制以 (x, y) 为中心的校正图形
+func (q *qrCode) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			q.setFunction(x+dx, y+dy, max(qrAbs(dx), qrAbs(dy)) != 1)
+		}
+	}
+}
+
+// drawFormatBits 绘制纠错等级和掩码的格式信息 (BCH(15,5) 编码)
+func (q *qrCode) drawFormatBits(mask int) {
+	data := q.level.formatBits<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+
+	// 左上角
+	for i := 0; i <= 5; i++ {
+		q.setFunction(8, i, qrBit(bits, i))
+	}
+	q.setFunction(8, 7, qrBit(bits, 6))
+	q.setFunction(8, 8, qrBit(bits, 7))
+	q.setFunction(7, 8, qrBit(bits, 8))
+	for i := 9; i < 15; i++ {
+		q.setFunction(14-i, 8, qrBit(bits, i))
+	}
+	// 右上角和左下角
+	for i := 0; i < 8; i++ {
+		q.setFunction(q.size-1-i, 8, qrBit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		q.setFunction(8, q.size-15+i, qrBit(bits, i))
+	}
+	q.setFunction(8, q.size-8, true)
+}
+
+// drawVersion 绘制版本信息 (BCH(18,6) 编码)，仅版本 7 及以上
+func (q *qrCode) drawVersion() {
+	if q.version < 7 {
+		return
+	}
+	rem := q.version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := q.version<<12 | rem
+	for i := 0; i < 18; i++ {
+		a, b := q.size-11+i%3, i/3
+		q.setFunction(a, b, qrBit(bits, i))
+		q.setFunction(b, a, qrBit(bits, i))
+	}
+}
+
+// addEccAndInterleave 分块计算 Reed-Solomon 纠错码，并交错排列数据码字和纠错码字
+func (q *qrCode) addEccAndInterleave(data []byte) []byte {
+	numBlocks := qrNumEccBlocks[q.level.index][q.version]
+	eccLen := qrEccCodewordsPerBlock[q.level.index][q.version]
+	rawCodewords := qrNumRawDataModules(q.version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortDataLen := rawCodewords/numBlocks - eccLen
+
+	divisor := qrReedSolomonDivisor(eccLen)
+	dataBlocks := make([][]byte, numBlocks)
+	eccBlocks := make([][]byte, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		n := shortDataLen
+		if i >= numShortBlocks {
+			n++
+		}
+		dataBlocks[i] = data[k : k+n]
+		eccBlocks[i] = qrReedSolomonRemainder(dataBlocks[i], divisor)
+		k += n
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := 0; i <= shortDataLen; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < eccLen; i++ {
+		for _, block := range eccBlocks {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
+
+// drawCodewords 按之字形顺序将码字填入非功能模块，剩余位保持为浅色
+func (q *qrCode) drawCodewords(data []byte) {
+	i := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < q.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if upward {
+					y = q.size - 1 - vert
+				}
+				if q.isFunction[y][x] || i >= len(data)*8 {
+					continue
+				}
+				q.modules[y][x] = data[i>>3]>>(7-i&7)&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+// applyMask 对数据模块应用掩码，再次调用可撤销
+func (q *qrCode) applyMask(mask int) {
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !q.isFunction[y][x] {
+				q.modules[y][x] = !q.modules[y][x]
+			}
+		}
+	}
+}
+
+// penaltyScore 计算掩码惩罚分
+func (q *qrCode) penaltyScore() int {
+	penalty := 0
+	get := func(x, y int, horizontal bool) bool {
+		if horizontal {
+			return q.modules[y][x]
+		}
+		return q.modules[x][y]
+	}
+	finderA := []bool{true, false, true, true, true, false, true, false, false, false, false}
+	finderB := []bool{false, false, false, false, true, false, true, true, true, false, true}
+
+	for _, horizontal := range []bool{true, false} {
+		for y := 0; y < q.size; y++ {
+			// 规则 1：连续 5 个及以上同色模块
+			run := 1
+			for x := 1; x < q.size; x++ {
+				if get(x, y, horizontal) == get(x-1, y, horizontal) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					penalty += run - 2
+				}
+				run = 1
+			}
+			if run >= 5 {
+				penalty += run - 2
+			}
+			// 规则 3：类似定位图形的 1:1:3:1:1 图案
+			for x := 0; x+len(finderA) <= q.size; x++ {
+				matchA, matchB := true, true
+				for k := range finderA {
+					v := get(x+k, y, horizontal)
+					matchA = matchA && v == finderA[k]
+					matchB = matchB && v == finderB[k]
+				}
+				if matchA {
+					penalty += 40
+				}
+				if matchB {
+					penalty += 40
+				}
+			}
+		}
+	}
+
+	// 规则 2：2x2 同色区块
+	dark := 0
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			if q.modules[y][x] {
+				dark++
+			}
+			if x > 0 && y > 0 {
+				c := q.modules[y][x]
+				if c == q.modules[y][x-1] && c == q.modules[y-1][x] && c == q.modules[y-1][x-1] {
+					penalty += 3
+				}
+			}
+		}
+	}
+	// 规则 4：深色模块比例偏离 50%
+	total := q.size * q.size
+	penalty += qrAbs(dark*100/total-50) / 5 * 10
+	return penalty
+}
+
+// qrReedSolomonDivisor 计算指定次数的 Reed-Solomon 生成多项式 (不含最高次项系数)
+func qrReedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = qrGfMul(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = qrGfMul(root, 0x02)
+	}
+	return result
+}
+
+// qrReedSolomonRemainder 计算数据多项式除以生成多项式的余数，即纠错码字
+func qrReedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range divisor {
+			result[i] ^= qrGfMul(d, factor)
+		}
+	}
+	return result
+}
+
+// qrGfMul GF(2^8) 乘法，本原多项式 x^8 + x^4 + x^3 + x^2 + 1
+func qrGfMul(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
+
+// qrBitBuffer 按位写入的缓冲区
+type qrBitBuffer struct {
+	data []byte
+	len  int
+}
+
+// append 写入 val 的低 n 位，高位在前
+func (b *qrBitBuffer) append(val, n int) {
+	for i := n - 1; i >= 0; i-- {
+		if b.len%8 == 0 {
+			b.data = append(b.data, 0)
+		}
+		if val>>i&1 == 1 {
+			b.data[b.len/8] |= 0x80 >> (b.len % 8)
+		}
+		b.len++
+	}
+}
+
+// bytes 返回缓冲区内容
+func (b *qrBitBuffer) bytes() []byte {
+	return b.data
+}
+
+// qrBit 获取第 i 位
+func qrBit(x, i int) bool {
+	return x>>i&1 == 1
+}
+
+// qrAbs 绝对值
+func qrAbs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
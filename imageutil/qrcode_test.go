@@ -0,0 +1,181 @@
+package imageutil
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+// qrReadBack 从二维码矩阵中读取格式信息、去掉掩码并还原数据码字，校验纠错码字
+func qrReadBack(t *testing.T, q *qrCode) []byte {
+	bits := qrFormatBitsAt(q)
+	bits ^= 0x5412
+	mask := bits >> 10 & 7
+	testutil.Equal(t, bits>>13, q.level.formatBits)
+
+	q.applyMask(mask)
+	defer q.applyMask(mask)
+	var raw []byte
+	n := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < q.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = q.size - 1 - vert
+				}
+				if q.isFunction[y][x] {
+					continue
+				}
+				if n%8 == 0 {
+					raw = append(raw, 0)
+				}
+				if q.modules[y][x] {
+					raw[n/8] |= 0x80 >> (n % 8)
+				}
+				n++
+			}
+		}
+	}
+
+	numBlocks := qrNumEccBlocks[q.level.index][q.version]
+	eccLen := qrEccCodewordsPerBlock[q.level.index][q.version]
+	rawCodewords := qrNumRawDataModules(q.version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortDataLen := rawCodewords/numBlocks - eccLen
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i <= shortDataLen; i++ {
+		for j := range blocks {
+			if i < shortDataLen || j >= numShortBlocks {
+				blocks[j] = append(blocks[j], raw[k])
+				k++
+			}
+		}
+	}
+	divisor := qrReedSolomonDivisor(eccLen)
+	var data []byte
+	for j, block := range blocks {
+		var ecc []byte
+		for i := 0; i < eccLen; i++ {
+			ecc = append(ecc, raw[k+i*numBlocks+j])
+		}
+		if !bytes.Equal(ecc, qrReedSolomonRemainder(block, divisor)) {
+			t.Fatalf("block %d ecc mismatch", j)
+		}
+		data = append(data, block...)
+	}
+	return data
+}
+
+func TestQRCode(t *testing.T) {
+	img, err := QRCode("otpauth://totp/gotool:admin?secret=JBSWY3DPEHPK3PXP&issuer=gotool", 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, img.Bounds().Dx(), 256)
+	testutil.Equal(t, img.Bounds().Dy(), 256)
+
+	_, err = QRCode("hello", 100, "X")
+	testutil.NotEqual(t, err, nil)
+	_, err = QRCode(strings.Repeat("a", 2954), 100, QRLevelL)
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestQRCodeFile(t *testing.T) {
+	if err := QRCodeFile("https://github.com/up-zero/gotool", filepath.Join(t.TempDir(), "qrcode.png"), 200); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestQRCodeEncode(t *testing.T) {
+	for _, tt := range []struct {
+		length  int
+		level   string
+		version int
+	}{
+		{14, QRLevelM, 1},
+		{15, QRLevelM, 2},
+		{17, QRLevelL, 1},
+		{213, QRLevelM, 10},
+		{2331, QRLevelM, 40},
+		{2953, QRLevelL, 40},
+		{1273, QRLevelH, 40},
+	} {
+		content := bytes.Repeat([]byte{'0'}, tt.length)
+		for i := range content {
+			content[i] = byte('a' + i%26)
+		}
+		q, err := qrEncode(content, tt.level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, q.version, tt.version)
+
+		data := qrReadBack(t, q)
+		headerBits := 4 + qrCharCountBits(q.version)
+		testutil.Equal(t, int(data[0]>>4), 0x4)
+		var length int
+		for i := 4; i < headerBits; i++ {
+			length = length<<1 | int(data[i/8]>>(7-i%8)&1)
+		}
+		testutil.Equal(t, length, tt.length)
+		decoded := make([]byte, length)
+		for i := range decoded {
+			for b := 0; b < 8; b++ {
+				n := headerBits + i*8 + b
+				decoded[i] = decoded[i]<<1 | data[n/8]>>(7-n%8)&1
+			}
+		}
+		testutil.Equal(t, string(decoded), string(content))
+	}
+}
+
+func TestQRCodeReedSolomon(t *testing.T) {
+	// "HELLO WORLD" 1-M
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	ecc := qrReedSolomonRemainder(data, qrReedSolomonDivisor(10))
+	testutil.Equal(t, ecc, []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23})
+}
+
+func TestQRCodeFormatBits(t *testing.T) {
+	q := newQrCode(1, qrLevels[QRLevelM])
+	q.drawFormatBits(0)
+	var bits string
+	for i := 14; i >= 0; i-- {
+		bits += map[bool]string{true: "1", false: "0"}[qrBit(qrFormatBitsAt(q), i)]
+	}
+	testutil.Equal(t, bits, "101010000010010")
+
+	// 版本 7 的版本信息
+	q = newQrCode(7, qrLevels[QRLevelL])
+	version := 0
+	for i := 0; i < 18; i++ {
+		if q.modules[i/3][q.size-11+i%3] {
+			version |= 1 << i
+		}
+	}
+	testutil.Equal(t, version, 0x07C94)
+}
+
+// qrFormatBitsAt 读取右上角和左下角的格式信息
+func qrFormatBitsAt(q *qrCode) int {
+	bits := 0
+	for i := 0; i < 8; i++ {
+		if q.modules[8][q.size-1-i] {
+			bits |= 1 << i
+		}
+	}
+	for i := 8; i < 15; i++ {
+		if q.modules[q.size-15+i][8] {
+			bits |= 1 << i
+		}
+	}
+	return bits
+}
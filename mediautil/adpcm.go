@@ -0,0 +1,450 @@
+package mediautil
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/up-zero/gotool"
+)
+
+// imaIndexTable IMA ADPCM 步长索引调整表
+var imaIndexTable = [16]int{-1, -1, -1, -1, 2, 4, 6, 8, -1, -1, -1, -1, 2, 4, 6, 8}
+
+// imaStepTable IMA ADPCM 量化步长表
+var imaStepTable = [89]int{
+	7, 8, 9, 10, 11, 12, 13, 14, 16, 17,
+	19, 21, 23, 25, 28, 31, 34, 37, 41, 45,
+	50, 55, 60, 66, 73, 80, 88, 97, 107, 118,
+	130, 143, 157, 173, 190, 209, 230, 253, 279, 307,
+	337, 371, 408, 449, 494, 544, 598, 658, 724, 796,
+	876, 963, 1060, 1166, 1282, 1411, 1552, 1707, 1878, 2066,
+	2272, 2499, 2749, 3024, 3327, 3660, 4026, 4428, 4871, 5358,
+	5894, 6484, 7132, 7845, 8630, 9493, 10442, 11487, 12635, 13899,
+	15289, 16818, 18500, 20350, 22385, 24623, 27086, 29794, 32767,
+}
+
+// msAdaptationTable MS ADPCM 步长自适应表
+var msAdaptationTable = [16]int{230, 230, 230, 230, 307, 409, 512, 614, 768, 614, 512, 409, 307, 230, 230, 230}
+
+// msAdpcmCoeffs MS ADPCM 标准预测系数
+var msAdpcmCoeffs = [][2]int{{256, 0}, {512, -256}, {0, 0}, {192, 64}, {240, 0}, {460, -208}, {392, -232}}
+
+// clampInt16 限制在 int16 范围内
+func clampInt16(v int) int {
+	if v > 32767 {
+		return 32767
+	}
+	if v < -32768 {
+		return -32768
+	}
+	return v
+}
+
+// AdpcmBlockAlign 根据采样率计算 ADPCM 常用的块大小 (字节)
+//
+// 每声道 256 字节 (11.025KHz 及以下), 512 字节 (22.05KHz 及以下), 1024 字节 (其余)
+//
+// # Params:
+//
+//	sampleRate: 采样率
+//	channels: 声道数
+func AdpcmBlockAlign(sampleRate, channels int) int {
+	size := 256
+	if sampleRate > 22050 {
+		size = 1024
+	} else if sampleRate > 11025 {
+		size = 512
+	}
+	return size * channels
+}
+
+// imaSamplesPerBlock IMA ADPCM 每块包含的采样帧数
+func imaSamplesPerBlock(blockAlign, channels int) int {
+	return (blockAlign-4*channels)*2/channels + 1
+}
+
+// msSamplesPerBlock MS ADPCM 每块包含的采样帧数
+func msSamplesPerBlock(blockAlign, channels int) int {
+	return (blockAlign-7*channels)*2/channels + 2
+}
+
+// checkImaBlock 校验 IMA ADPCM 块参数, 块数据部分必须为每声道 4 字节的整数倍
+func checkImaBlock(channels, blockAlign int) error {
+	if channels <= 0 || blockAlign <= 4*channels || blockAlign%(4*channels) != 0 {
+		return fmt.Errorf("%w, ima adpcm chan=%d, blockAlign=%d", gotool.ErrInvalidParam, channels, blockAlign)
+	}
+	return nil
+}
+
+// checkMsBlock 校验 MS ADPCM 块参数
+func checkMsBlock(channels, blockAlign int) error {
+	if channels <= 0 || channels > 2 || blockAlign <= 7*channels || (blockAlign-7*channels)*2%channels != 0 {
+		return fmt.Errorf("%w, ms adpcm chan=%d, blockAlign=%d", gotool.ErrInvalidParam, channels, blockAlign)
+	}
+	return nil
+}
+
+// imaState IMA ADPCM 单声道编解码状态
+type imaState struct {
+	predictor int
+	index     int
+}
+
+// encode 编码一个采样点, 返回 4 bit 码字
+func (s *imaState) encode(sample int) byte {
+	step := imaStepTable[s.index]
+	diff := sample - s.predictor
+	var nibble byte
+	if diff < 0 {
+		nibble = 8
+		diff = -diff
+	}
+	vpdiff := step >> 3
+	if diff >= step {
+		nibble |= 4
+		diff -= step
+		vpdiff += step
+	}
+	step >>= 1
+	if diff >= step {
+		nibble |= 2
+		diff -= step
+		vpdiff += step
+	}
+	step >>= 1
+	if diff >= step {
+		nibble |= 1
+		vpdiff += step
+	}
+	s.update(nibble, vpdiff)
+	return nibble
+}
+
+// decode 解码一个 4 bit 码字
+func (s *imaState) decode(nibble byte) int {
+	step := imaStepTable[s.index]
+	vpdiff := step >> 3
+	if nibble&4 != 0 {
+		vpdiff += step
+	}
+	if nibble&2 != 0 {
+		vpdiff += step >> 1
+	}
+	if nibble&1 != 0 {
+		vpdiff += step >> 2
+	}
+	s.update(nibble, vpdiff)
+	return s.predictor
+}
+
+// update 更新预测值和步长索引
+func (s *imaState) update(nibble byte, vpdiff int) {
+	if nibble&8 != 0 {
+		s.predictor = clampInt16(s.predictor - vpdiff)
+	} else {
+		s.predictor = clampInt16(s.predictor + vpdiff)
+	}
+	s.index += imaIndexTable[nibble]
+	if s.index < 0 {
+		s.index = 0
+	} else if s.index > 88 {
+		s.index = 88
+	}
+}
+
+// ImaAdpcmEncode IMA ADPCM 编码 (WAV 格式 0x11 的块结构)，压缩比约 4:1
+//
+// 最后一个块不足时以静音补齐
+//
+// # Params:
+//
+//	samples: 音频数据, 多声道时为交错排列
+//	channels: 声道数
+//	blockAlign: 块大小 (字节), 必须为 4*channels 的整数倍, 可使用 AdpcmBlockAlign 计算
+func ImaAdpcmEncode(samples []float32, channels, blockAlign int) ([]byte, error) {
+	if err := checkImaBlock(channels, blockAlign); err != nil {
+		return nil, err
+	}
+	if len(samples)%channels != 0 {
+		return nil, fmt.Errorf("%w, samples length is not aligned with channels", gotool.ErrInvalidParam)
+	}
+
+	frames := len(samples) / channels
+	spb := imaSamplesPerBlock(blockAlign, channels)
+	numBlocks := (frames + spb - 1) / spb
+	output := make([]byte, numBlocks*blockAlign)
+	states := make([]imaState, channels)
+	sampleAt := func(frame, ch int) int {
+		if frame >= frames {
+			return 0
+		}
+		return float32ToInt16(samples[frame*channels+ch])
+	}
+
+	for b := 0; b < numBlocks; b++ {
+		block := output[b*blockAlign : (b+1)*blockAlign]
+		first := b * spb
+
+		// 块头: 每声道 int16 初始采样 + uint8 步长索引 + 保留字节
+		for ch := 0; ch < channels; ch++ {
+			states[ch].predictor = sampleAt(first, ch)
+			binary.LittleEndian.PutUint16(block[ch*4:], uint16(int16(states[ch].predictor)))
+			block[ch*4+2] = byte(states[ch].index)
+		}
+
+		// 数据: 每声道 8 个采样 (4 字节) 为一组交错排列, 低 4 位在前
+		offset := 4 * channels
+		for f := 1; f < spb; f += 8 {
+			for ch := 0; ch < channels; ch++ {
+				for k := 0; k < 8; k++ {
+					nibble := states[ch].encode(sampleAt(first+f+k, ch))
+					block[offset+k/2] |= nibble << (4 * uint(k%2))
+				}
+				offset += 4
+			}
+		}
+	}
+	return output, nil
+}
+
+// ImaAdpcmDecode IMA ADPCM 解码 (WAV 格式 0x11 的块结构)
+//
+// # Params:
+//
+//	data: 编码数据
+//	channels: 声道数
+//	blockAlign: 块大小 (字节)
+func ImaAdpcmDecode(data []byte, channels, blockAlign int) ([]float32, error) {
+	if err := checkImaBlock(channels, blockAlign); err != nil {
+		return nil, err
+	}
+
+	spb := imaSamplesPerBlock(blockAlign, channels)
+	output := make([]float32, 0, (len(data)/blockAlign+1)*spb*channels)
+	states := make([]imaState, channels)
+	for start := 0; start+4*channels <= len(data); start += blockAlign {
+		end := start + blockAlign
+		if end > len(data) {
+			end = len(data)
+		}
+		block := data[start:end]
+
+		for ch := 0; ch < channels; ch++ {
+			states[ch].predictor = int(int16(binary.LittleEndian.Uint16(block[ch*4:])))
+			states[ch].index = int(block[ch*4+2])
+			if states[ch].index > 88 {
+				return nil, fmt.Errorf("%w, ima adpcm step index %d out of range", gotool.ErrInvalidParam, states[ch].index)
+			}
+			output = append(output, int16ToFloat32(states[ch].predictor))
+		}
+
+		// 每组包含每声道 8 个采样帧, 不完整的组被忽略
+		frames := make([]float32, 8*channels)
+		for offset := 4 * channels; offset+4*channels <= len(block); {
+			for ch := 0; ch < channels; ch++ {
+				for k := 0; k < 8; k++ {
+					nibble := block[offset+k/2] >> (4 * uint(k%2)) & 0x0F
+					frames[k*channels+ch] = int16ToFloat32(states[ch].decode(nibble))
+				}
+				offset += 4
+			}
+			output = append(output, frames...)
+		}
+	}
+	return output, nil
+}
+
+// msState MS ADPCM 单声道编解码状态
+type msState struct {
+	coeff1, coeff2 int
+	delta          int
+	sample1        int // 最近的采样
+	sample2        int // 次近的采样
+}
+
+// predict 预测下一个采样点
+func (s *msState) predict() int {
+	return (s.sample1*s.coeff1 + s.sample2*s.coeff2) >> 8
+}
+
+// encode 编码一个采样点, 返回 4 bit 码字
+func (s *msState) encode(sample int) byte {
+	predictor := s.predict()
+	diff := sample - predictor
+	bias := s.delta / 2
+	if diff < 0 {
+		bias = -bias
+	}
+	code := (diff + bias) / s.delta
+	if code > 7 {
+		code = 7
+	} else if code < -8 {
+		code = -8
+	}
+	nibble := byte(code) & 0x0F
+	s.decode(nibble)
+	return nibble
+}
+
+// decode 解码一个 4 bit 码字
+func (s *msState) decode(nibble byte) int {
+	code := int(nibble)
+	if code >= 8 {
+		code -= 16
+	}
+	sample := clampInt16(s.predict() + code*s.delta)
+	s.sample2 = s.sample1
+	s.sample1 = sample
+	s.delta = msAdaptationTable[nibble] * s.delta >> 8
+	if s.delta < 16 {
+		s.delta = 16
+	}
+	return sample
+}
+
+// MsAdpcmEncode Microsoft ADPCM 编码 (WAV 格式 0x02 的块结构)，压缩比约 4:1
+//
+// 每个块为各声道分别选择误差最小的标准预测系数，最后一个块不足时以静音补齐
+//
+// # Params:
+//
+//	samples: 音频数据, 多声道时为交错排列
+//	channels: 声道数, 支持 1, 2
+//	blockAlign: 块大小 (字节), 可使用 AdpcmBlockAlign 计算
+func MsAdpcmEncode(samples []float32, channels, blockAlign int) ([]byte, error) {
+	if err := checkMsBlock(channels, blockAlign); err != nil {
+		return nil, err
+	}
+	if len(samples)%channels != 0 {
+		return nil, fmt.Errorf("%w, samples length is not aligned with channels", gotool.ErrInvalidParam)
+	}
+
+	frames := len(samples) / channels
+	spb := msSamplesPerBlock(blockAlign, channels)
+	numBlocks := (frames + spb - 1) / spb
+	output := make([]byte, numBlocks*blockAlign)
+	deltas := make([]int, channels)
+	for ch := range deltas {
+		deltas[ch] = 16
+	}
+	channel := make([]int, spb)
+
+	for b := 0; b < numBlocks; b++ {
+		block := output[b*blockAlign : (b+1)*blockAlign]
+		first := b * spb
+		states := make([]msState, channels)
+
+		for ch := 0; ch < channels; ch++ {
+			for f := range channel {
+				channel[f] = 0
+				if first+f < frames {
+					channel[f] = float32ToInt16(samples[(first+f)*channels+ch])
+				}
+			}
+
+			// 选择误差最小的预测系数
+			bestErr := -1.0
+			for p, c := range msAdpcmCoeffs {
+				s := msState{coeff1: c[0], coeff2: c[1], delta: deltas[ch], sample1: channel[1], sample2: channel[0]}
+				var sqErr float64
+				for _, v := range channel[2:] {
+					s.encode(v)
+					d := float64(s.sample1 - v)
+					sqErr += d * d
+				}
+				if bestErr < 0 || sqErr < bestErr {
+					bestErr = sqErr
+					block[ch] = byte(p)
+				}
+			}
+			c := msAdpcmCoeffs[block[ch]]
+			states[ch] = msState{coeff1: c[0], coeff2: c[1], delta: deltas[ch], sample1: channel[1], sample2: channel[0]}
+
+			// 块头: 预测系数索引, 初始步长, 采样 1 (较新), 采样 2 (较旧), 各字段按声道依次排列
+			binary.LittleEndian.PutUint16(block[channels+ch*2:], uint16(int16(deltas[ch])))
+			binary.LittleEndian.PutUint16(block[3*channels+ch*2:], uint16(int16(channel[1])))
+			binary.LittleEndian.PutUint16(block[5*channels+ch*2:], uint16(int16(channel[0])))
+		}
+
+		// 数据: 声道交错, 每字节高 4 位在前
+		for i := 0; i < (spb-2)*channels; i++ {
+			frame, ch := first+2+i/channels, i%channels
+			sample := 0
+			if frame < frames {
+				sample = float32ToInt16(samples[frame*channels+ch])
+			}
+			nibble := states[ch].encode(sample)
+			if i%2 == 0 {
+				block[7*channels+i/2] |= nibble << 4
+			} else {
+				block[7*channels+i/2] |= nibble
+			}
+		}
+		for ch := range deltas {
+			deltas[ch] = states[ch].delta
+		}
+	}
+	return output, nil
+}
+
+// MsAdpcmDecode Microsoft ADPCM 解码 (WAV 格式 0x02 的块结构)
+//
+// # Params:
+//
+//	data: 编码数据
+//	channels: 声道数, 支持 1, 2
+//	blockAlign: 块大小 (字节)
+func MsAdpcmDecode(data []byte, channels, blockAlign int) ([]float32, error) {
+	return msAdpcmDecode(data, channels, blockAlign, msAdpcmCoeffs)
+}
+
+// msAdpcmDecode 使用指定的预测系数表解码 MS ADPCM
+func msAdpcmDecode(data []byte, channels, blockAlign int, coeffs [][2]int) ([]float32, error) {
+	if err := checkMsBlock(channels, blockAlign); err != nil {
+		return nil, err
+	}
+
+	spb := msSamplesPerBlock(blockAlign, channels)
+	output := make([]float32, 0, (len(data)/blockAlign+1)*spb*channels)
+	states := make([]msState, channels)
+	for start := 0; start+7*channels <= len(data); start += blockAlign {
+		end := start + blockAlign
+		if end > len(data) {
+			end = len(data)
+		}
+		block := data[start:end]
+
+		for ch := 0; ch < channels; ch++ {
+			p := int(block[ch])
+			if p >= len(coeffs) {
+				return nil, fmt.Errorf("%w, ms adpcm predictor %d out of range", gotool.ErrInvalidParam, p)
+			}
+			states[ch] = msState{
+				coeff1:  coeffs[p][0],
+				coeff2:  coeffs[p][1],
+				delta:   int(int16(binary.LittleEndian.Uint16(block[channels+ch*2:]))),
+				sample1: int(int16(binary.LittleEndian.Uint16(block[3*channels+ch*2:]))),
+				sample2: int(int16(binary.LittleEndian.Uint16(block[5*channels+ch*2:]))),
+			}
+		}
+		for ch := 0; ch < channels; ch++ {
+			output = append(output, int16ToFloat32(states[ch].sample2))
+		}
+		for ch := 0; ch < channels; ch++ {
+			output = append(output, int16ToFloat32(states[ch].sample1))
+		}
+
+		// 截断的块只解码完整的采样帧
+		nibbles := (len(block) - 7*channels) * 2
+		nibbles -= nibbles % channels
+		for i := 0; i < nibbles; i++ {
+			b := block[7*channels+i/2]
+			nibble := b >> 4
+			if i%2 == 1 {
+				nibble = b & 0x0F
+			}
+			output = append(output, int16ToFloat32(states[i%channels].decode(nibble)))
+		}
+	}
+	return output, nil
+}
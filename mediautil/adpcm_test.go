@@ -0,0 +1,93 @@
+package mediautil
+
+import (
+	"math"
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+// snrDB 计算重建信号的信噪比
+func snrDB(original, decoded []float32) float64 {
+	var signal, noise float64
+	for i, v := range original {
+		d := float64(decoded[i] - v)
+		signal += float64(v) * float64(v)
+		noise += d * d
+	}
+	return 10 * math.Log10(signal/noise)
+}
+
+func TestImaAdpcmEncode(t *testing.T) {
+	blockAlign := AdpcmBlockAlign(SampleRate8K, 1)
+	testutil.Equal(t, blockAlign, 256)
+	testutil.Equal(t, imaSamplesPerBlock(blockAlign, 1), 505)
+
+	samples := sineWave(440, SampleRate8K, 1200)
+	encoded, err := ImaAdpcmEncode(samples, 1, blockAlign)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, len(encoded), 3*blockAlign)
+
+	decoded, err := ImaAdpcmDecode(encoded, 1, blockAlign)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, len(decoded), 3*505)
+	if snr := snrDB(samples, decoded[:len(samples)]); snr < 20 {
+		t.Fatalf("ima adpcm snr too low: %.2f dB", snr)
+	}
+
+	_, err = ImaAdpcmEncode(samples, 1, 250)
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestImaAdpcmStereo(t *testing.T) {
+	left := sineWave(440, SampleRate16K, 2000)
+	right := sineWave(1000, SampleRate16K, 2000)
+	samples := make([]float32, 0, 4000)
+	for i := range left {
+		samples = append(samples, left[i], right[i])
+	}
+
+	blockAlign := AdpcmBlockAlign(SampleRate16K, 2)
+	encoded, err := ImaAdpcmEncode(samples, 2, blockAlign)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := ImaAdpcmDecode(encoded, 2, blockAlign)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snr := snrDB(samples, decoded[:len(samples)]); snr < 20 {
+		t.Fatalf("ima adpcm stereo snr too low: %.2f dB", snr)
+	}
+}
+
+func TestMsAdpcmEncode(t *testing.T) {
+	blockAlign := AdpcmBlockAlign(SampleRate8K, 1)
+	testutil.Equal(t, msSamplesPerBlock(blockAlign, 1), 500)
+
+	for _, channels := range []int{1, 2} {
+		samples := make([]float32, 0, 1200*channels)
+		for _, v := range sineWave(440, SampleRate8K, 1200) {
+			for c := 0; c < channels; c++ {
+				samples = append(samples, v*float32(c+1)/2)
+			}
+		}
+		blockAlign := AdpcmBlockAlign(SampleRate8K, channels)
+		encoded, err := MsAdpcmEncode(samples, channels, blockAlign)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := MsAdpcmDecode(encoded, channels, blockAlign)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, len(decoded), 3*500*channels)
+		if snr := snrDB(samples, decoded[:len(samples)]); snr < 20 {
+			t.Fatalf("ms adpcm snr too low: %.2f dB, chan=%d", snr, channels)
+		}
+	}
+}
@@ -0,0 +1,346 @@
+package mediautil
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/up-zero/gotool"
+)
+
+// ErrNotAiffFile 不是有效的 AIFF/AIFF-C 文件
+var ErrNotAiffFile = errors.New("not a valid AIFF/AIFF-C file")
+
+// AIFF-C 压缩类型
+const (
+	// AiffCompressionNone 大端序 PCM
+	AiffCompressionNone = "NONE"
+	// AiffCompressionSowt 小端序 PCM
+	AiffCompressionSowt = "sowt"
+	// AiffCompressionFloat32 32 bit 浮点
+	AiffCompressionFloat32 = "fl32"
+	// AiffCompressionFloat64 64 bit 浮点
+	AiffCompressionFloat64 = "fl64"
+	// AiffCompressionALaw G.711 A-law
+	AiffCompressionALaw = "alaw"
+	// AiffCompressionULaw G.711 μ-law
+	AiffCompressionULaw = "ulaw"
+)
+
+// aiffCompressionNames AIFF-C 压缩类型的描述 (COMM 块中的 pascal 字符串)
+var aiffCompressionNames = map[string]string{
+	AiffCompressionNone:    "not compressed",
+	AiffCompressionSowt:    "",
+	AiffCompressionFloat32: "32-bit floating point",
+	AiffCompressionFloat64: "64-bit floating point",
+	AiffCompressionALaw:    "ALaw 2:1",
+	AiffCompressionULaw:    "uLaw 2:1",
+}
+
+// aiffcVersion AIFF-C FVER 块中的版本时间戳
+const aiffcVersion = 0xA2805140
+
+// AiffInfo AIFF/AIFF-C 音频信息
+type AiffInfo struct {
+	Channels      int    // 声道数
+	SampleRate    int    // 采样率
+	BitsPerSample int    // 位深 (COMM 中的 sampleSize)
+	SampleFrames  int    // 每声道采样帧数
+	Compression   string // AIFF-C 压缩类型, 普通 AIFF 为空
+}
+
+// GetDuration 根据头部信息计算音频时长
+func (a *AiffInfo) GetDuration() time.Duration {
+	if a.SampleRate == 0 {
+		return 0
+	}
+	return samplesToDuration(a.SampleFrames, a.SampleRate)
+}
+
+// ParseAiffInfo 解析 AIFF/AIFF-C 头部
+//
+// # Params:
+//
+//	data: AIFF 文件数据
+func ParseAiffInfo(data []byte) (*AiffInfo, error) {
+	info, _, err := readAiff(data)
+	return info, err
+}
+
+// DecodeAiff AIFF/AIFF-C 解码为 float32 数组
+//
+// 输出的缩放方式与 PcmBytesToFloat32 一致, 多声道时为交错排列
+//
+// # Params:
+//
+//	data: AIFF 文件数据
+func DecodeAiff(data []byte) (*AiffInfo, []float32, error) {
+	info, sound, err := readAiff(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	count := info.SampleFrames * info.Channels
+	var samples []float32
+	switch strings.ToLower(info.Compression) {
+	case "", "none", "twos", "sowt":
+		bits := info.BitsPerSample
+		if bits < 1 || bits > 32 {
+			return nil, nil, fmt.Errorf("%w, bit=%d", ErrUnsupportedBitDepth, bits)
+		}
+		// 采样点左对齐存储在 ceil(bits/8) 字节中
+		width := (bits + 7) / 8
+		littleEndian := info.Compression == AiffCompressionSowt
+		if count > len(sound)/width {
+			count = len(sound) / width
+		}
+		samples = make([]float32, count)
+		for i := range samples {
+			var v int64
+			for b := 0; b < width; b++ {
+				idx := i*width + b
+				if littleEndian {
+					idx = i*width + width - 1 - b
+				}
+				v = v<<8 | int64(sound[idx])
+			}
+			v = v << uint(64-width*8) >> uint(64-bits)
+			samples[i] = sampleToFloat32(v, bits)
+		}
+	case AiffCompressionFloat32:
+		if count > len(sound)/4 {
+			count = len(sound) / 4
+		}
+		samples = make([]float32, count)
+		for i := range samples {
+			samples[i] = math.Float32frombits(binary.BigEndian.Uint32(sound[i*4:]))
+		}
+	case AiffCompressionFloat64:
+		if count > len(sound)/8 {
+			count = len(sound) / 8
+		}
+		samples = make([]float32, count)
+		for i := range samples {
+			samples[i] = float32(math.Float64frombits(binary.BigEndian.Uint64(sound[i*8:])))
+		}
+	case AiffCompressionALaw:
+		if count > len(sound) {
+			count = len(sound)
+		}
+		samples = ALawDecode(sound[:count])
+	case AiffCompressionULaw:
+		if count > len(sound) {
+			count = len(sound)
+		}
+		samples = MuLawDecode(sound[:count])
+	default:
+		return nil, nil, fmt.Errorf("%w, aiff compression %q", gotool.ErrNotSupportFormat, info.Compression)
+	}
+
+	// 去除不完整的采样帧
+	samples = samples[:len(samples)/info.Channels*info.Channels]
+	return info, samples, nil
+}
+
+// EncodeAiff 将 float32 音频数据编码为 AIFF 或 AIFF-C 文件字节流
+//
+// # Params:
+//
+//	samples: 音频数据, 多声道时为交错排列
+//	sampleRate: 采样率
+//	channels: 声道数
+//	bitsPerSample: 位深, PCM 支持 8, 16, 24, 32, 浮点和 G.711 压缩时忽略
+//	compression: AIFF-C 压缩类型 (可选), 不指定时输出普通 AIFF
+func EncodeAiff(samples []float32, sampleRate, channels, bitsPerSample int, compression ...string) ([]byte, error) {
+	if sampleRate <= 0 || channels <= 0 || bitsPerSample <= 0 {
+		return nil, fmt.Errorf("%w, rate=%d, chan=%d, bit=%d", gotool.ErrInvalidParam, sampleRate, channels, bitsPerSample)
+	}
+	if len(samples)%channels != 0 {
+		return nil, fmt.Errorf("%w, samples length is not aligned with channels", gotool.ErrInvalidParam)
+	}
+	isAiffc := len(compression) > 0
+	comp := AiffCompressionNone
+	if isAiffc {
+		comp = compression[0]
+	}
+	if _, ok := aiffCompressionNames[comp]; !ok {
+		return nil, fmt.Errorf("%w, aiff compression %q", gotool.ErrNotSupportFormat, comp)
+	}
+
+	var sound []byte
+	switch comp {
+	case AiffCompressionNone, AiffCompressionSowt:
+		if bitsPerSample != 8 && bitsPerSample != 16 && bitsPerSample != 24 && bitsPerSample != 32 {
+			return nil, ErrUnsupportedBitDepth
+		}
+		width := bitsPerSample / 8
+		sound = make([]byte, len(samples)*width)
+		for i, v := range samples {
+			n := float32ToSample(v, bitsPerSample)
+			for b := 0; b < width; b++ {
+				idx := i*width + width - 1 - b
+				if comp == AiffCompressionSowt {
+					idx = i*width + b
+				}
+				sound[idx] = byte(n >> (8 * uint(b)))
+			}
+		}
+	case AiffCompressionFloat32:
+		bitsPerSample = 32
+		sound = make([]byte, 0, len(samples)*4)
+		for _, v := range samples {
+			sound = binary.BigEndian.AppendUint32(sound, math.Float32bits(v))
+		}
+	case AiffCompressionFloat64:
+		bitsPerSample = 64
+		sound = make([]byte, 0, len(samples)*8)
+		for _, v := range samples {
+			sound = binary.BigEndian.AppendUint64(sound, math.Float64bits(float64(v)))
+		}
+	case AiffCompressionALaw:
+		bitsPerSample = 16
+		sound = ALawEncode(samples)
+	case AiffCompressionULaw:
+		bitsPerSample = 16
+		sound = MuLawEncode(samples)
+	}
+
+	// COMM 块
+	comm := binary.BigEndian.AppendUint16(nil, uint16(channels))
+	comm = binary.BigEndian.AppendUint32(comm, uint32(len(samples)/channels))
+	comm = binary.BigEndian.AppendUint16(comm, uint16(bitsPerSample))
+	rate := float64ToExtended(float64(sampleRate))
+	comm = append(comm, rate[:]...)
+	if isAiffc {
+		name := aiffCompressionNames[comp]
+		comm = append(comm, comp...)
+		comm = append(comm, byte(len(name)))
+		comm = append(comm, name...)
+		// pascal 字符串总长度补齐为偶数
+		if (len(name)+1)%2 != 0 {
+			comm = append(comm, 0)
+		}
+	}
+
+	// SSND 块: offset, blockSize, 数据
+	ssnd := make([]byte, 8, 8+len(sound))
+	ssnd = append(ssnd, sound...)
+
+	form := []byte("AIFF")
+	if isAiffc {
+		form = []byte("AIFC")
+		form = appendAiffChunk(form, "FVER", binary.BigEndian.AppendUint32(nil, aiffcVersion))
+	}
+	form = appendAiffChunk(form, "COMM", comm)
+	form = appendAiffChunk(form, "SSND", ssnd)
+	return appendAiffChunk(nil, "FORM", form), nil
+}
+
+// appendAiffChunk 追加一个 IFF 块 (大端序长度, 奇数长度补齐 1 字节)
+func appendAiffChunk(dst []byte, id string, body []byte) []byte {
+	dst = append(dst, id...)
+	dst = binary.BigEndian.AppendUint32(dst, uint32(len(body)))
+	dst = append(dst, body...)
+	if len(body)%2 != 0 {
+		dst = append(dst, 0)
+	}
+	return dst
+}
+
+// readAiff 解析 AIFF/AIFF-C 的 COMM 和 SSND 块, 返回头部信息和声音数据
+func readAiff(data []byte) (*AiffInfo, []byte, error) {
+	if len(data) < 12 || string(data[:4]) != "FORM" {
+		return nil, nil, ErrNotAiffFile
+	}
+	formType := string(data[8:12])
+	if formType != "AIFF" && formType != "AIFC" {
+		return nil, nil, ErrNotAiffFile
+	}
+
+	var info *AiffInfo
+	var sound []byte
+	for offset := 12; offset+8 <= len(data); {
+		id := string(data[offset : offset+4])
+		size := int(binary.BigEndian.Uint32(data[offset+4:]))
+		offset += 8
+		if size < 0 || size > len(data)-offset {
+			size = len(data) - offset
+		}
+		body := data[offset : offset+size]
+		offset += size + size%2
+
+		switch id {
+		case "COMM":
+			if len(body) < 18 {
+				return nil, nil, fmt.Errorf("%w, invalid COMM chunk", ErrNotAiffFile)
+			}
+			var rate [10]byte
+			copy(rate[:], body[8:18])
+			info = &AiffInfo{
+				Channels:      int(binary.BigEndian.Uint16(body[0:])),
+				SampleFrames:  int(binary.BigEndian.Uint32(body[2:])),
+				BitsPerSample: int(binary.BigEndian.Uint16(body[6:])),
+				SampleRate:    int(math.Round(extendedToFloat64(rate))),
+			}
+			if formType == "AIFC" {
+				if len(body) < 22 {
+					return nil, nil, fmt.Errorf("%w, invalid AIFF-C COMM chunk", ErrNotAiffFile)
+				}
+				info.Compression = string(body[18:22])
+			}
+		case "SSND":
+			if len(body) < 8 {
+				return nil, nil, fmt.Errorf("%w, invalid SSND chunk", ErrNotAiffFile)
+			}
+			start := 8 + int(binary.BigEndian.Uint32(body))
+			if start > len(body) {
+				start = len(body)
+			}
+			sound = body[start:]
+		}
+	}
+
+	if info == nil {
+		return nil, nil, fmt.Errorf("%w, COMM chunk not found", ErrNotAiffFile)
+	}
+	if info.Channels <= 0 {
+		return nil, nil, fmt.Errorf("%w, channels must be positive", gotool.ErrInvalidParam)
+	}
+	// 无声音数据时 SSND 块可省略
+	return info, sound, nil
+}
+
+// float64ToExtended 转换为 80 bit IEEE 754 扩展精度浮点 (大端序)
+func float64ToExtended(v float64) [10]byte {
+	var out [10]byte
+	if v == 0 || math.IsNaN(v) {
+		return out
+	}
+	var sign uint16
+	if v < 0 {
+		sign = 0x8000
+		v = -v
+	}
+	frac, exp := math.Frexp(v) // v = frac * 2^exp, frac ∈ [0.5, 1)
+	binary.BigEndian.PutUint16(out[0:], sign|uint16(exp-1+16383))
+	binary.BigEndian.PutUint64(out[2:], uint64(math.Ldexp(frac, 64)))
+	return out
+}
+
+// extendedToFloat64 80 bit IEEE 754 扩展精度浮点 (大端序) 转 float64
+func extendedToFloat64(b [10]byte) float64 {
+	se := binary.BigEndian.Uint16(b[0:])
+	mantissa := binary.BigEndian.Uint64(b[2:])
+	exp := int(se & 0x7FFF)
+	if exp == 0 && mantissa == 0 {
+		return 0
+	}
+	v := math.Ldexp(float64(mantissa), exp-16383-63)
+	if se&0x8000 != 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,65 @@
+package mediautil
+
+import (
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestFloat64ToExtended(t *testing.T) {
+	testutil.Equal(t, float64ToExtended(44100), [10]byte{0x40, 0x0E, 0xAC, 0x44})
+	testutil.Equal(t, float64ToExtended(8000), [10]byte{0x40, 0x0B, 0xFA})
+	for _, v := range []float64{0, 1, 22050, 48000, 11025.5, -3} {
+		testutil.Equal(t, extendedToFloat64(float64ToExtended(v)), v)
+	}
+}
+
+func TestEncodeAiff(t *testing.T) {
+	samples := flacTestSignal(1001, 2)
+	for _, bits := range []int{8, 16, 24, 32} {
+		data, err := EncodeAiff(samples, SampleRate44K, 2, bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, string(data[8:12]), "AIFF")
+		info, decoded, err := DecodeAiff(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, *info, AiffInfo{Channels: 2, SampleRate: SampleRate44K, BitsPerSample: bits, SampleFrames: 1001})
+		if bits <= 24 {
+			// 与 FLAC 相同的整数量化, 可无损还原
+			data2, _ := EncodeAiff(decoded, SampleRate44K, 2, bits)
+			testutil.Equal(t, data2, data)
+		}
+		testutil.EqualFloat(t, decoded, samples, 1.0/127)
+	}
+}
+
+func TestEncodeAiffc(t *testing.T) {
+	samples := sineWave(440, SampleRate8K, 801)
+	for _, comp := range []string{AiffCompressionNone, AiffCompressionSowt, AiffCompressionFloat32,
+		AiffCompressionFloat64, AiffCompressionALaw, AiffCompressionULaw} {
+		data, err := EncodeAiff(samples, SampleRate8K, 1, 16, comp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, string(data[8:12]), "AIFC")
+		testutil.Equal(t, len(data)%2, 0)
+		info, decoded, err := DecodeAiff(data)
+		if err != nil {
+			t.Fatalf("%s: %v", comp, err)
+		}
+		testutil.Equal(t, info.Compression, comp)
+		testutil.Equal(t, info.SampleFrames, 801)
+		testutil.Equal(t, len(decoded), 801)
+		if snr := snrDB(samples, decoded); snr < 30 {
+			t.Fatalf("%s snr too low: %.2f dB", comp, snr)
+		}
+	}
+
+	_, err := EncodeAiff(samples, SampleRate8K, 1, 16, "ima4")
+	testutil.NotEqual(t, err, nil)
+	_, _, err = DecodeAiff([]byte("FORM\x00\x00\x00\x04WAVE"))
+	testutil.NotEqual(t, err, nil)
+}
@@ -0,0 +1,485 @@
+package mediautil
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/up-zero/gotool"
+)
+
+// FrameSeries 逐帧分析结果
+type FrameSeries struct {
+	Times  []time.Duration // 每帧中心对应的时间
+	Values []float64       // 每帧的特征值
+}
+
+// PitchTrack 基频跟踪结果
+type PitchTrack struct {
+	Times       []time.Duration // 每帧中心对应的时间
+	Frequencies []float64       // 基频 (Hz), 清音或静音帧为 0
+	Confidences []float64       // 置信度 (0~1), 即 1 - YIN 累积均值归一化差分值
+}
+
+// Chromagram 色度图
+type Chromagram struct {
+	Times  []time.Duration // 每帧中心对应的时间
+	Chroma [][]float64     // [帧][12], 依次对应 C, C#, D, ..., B, 每帧按最大值归一化
+}
+
+// Analyzer 音频分析配置
+type Analyzer struct {
+	SampleRate  int    // 采样率
+	Channels    int    // 声道数, 多声道输入会先混合为单声道
+	FrameLength int    // 帧长 (采样点数), 默认 2048
+	HopLength   int    // 帧移 (采样点数), 默认 512
+	Window      string // 频谱分析使用的窗函数, 默认 WindowHann
+}
+
+// NewAnalyzer 根据 WAV 头部创建音频分析配置
+//
+// # Params:
+//
+//	header: WAV 头部, 用于获取采样率与声道数
+func NewAnalyzer(header *WavHeader) *Analyzer {
+	return &Analyzer{
+		SampleRate:  int(header.SampleRate),
+		Channels:    int(header.NumChannels),
+		FrameLength: 2048,
+		HopLength:   512,
+		Window:      WindowHann,
+	}
+}
+
+// validate 校验配置
+func (a *Analyzer) validate() error {
+	if a.SampleRate <= 0 || a.Channels <= 0 || a.FrameLength <= 0 || a.HopLength <= 0 {
+		return fmt.Errorf("%w, rate=%d, chan=%d, frame=%d, hop=%d",
+			gotool.ErrInvalidParam, a.SampleRate, a.Channels, a.FrameLength, a.HopLength)
+	}
+	return nil
+}
+
+// frameTimes 计算长度为 n 的单声道数据分帧后每帧中心对应的时间
+func (a *Analyzer) frameTimes(n int) []time.Duration {
+	if n < a.FrameLength {
+		return []time.Duration{}
+	}
+	times := make([]time.Duration, 1+(n-a.FrameLength)/a.HopLength)
+	for i := range times {
+		times[i] = samplesToDuration(i*a.HopLength+a.FrameLength/2, a.SampleRate)
+	}
+	return times
+}
+
+// frames 校验配置并将音频混合为单声道后分帧
+func (a *Analyzer) frames(samples []float32) ([][]float32, []time.Duration, error) {
+	if err := a.validate(); err != nil {
+		return nil, nil, err
+	}
+	mono := downmix(samples, a.Channels)
+	times := a.frameTimes(len(mono))
+	frames := make([][]float32, len(times))
+	for i := range frames {
+		start := i * a.HopLength
+		frames[i] = mono[start : start+a.FrameLength]
+	}
+	return frames, times, nil
+}
+
+// powerSpectra 计算每帧加窗后的功率谱
+func (a *Analyzer) powerSpectra(samples []float32) ([][]float64, []time.Duration, error) {
+	frames, times, err := a.frames(samples)
+	if err != nil {
+		return nil, nil, err
+	}
+	window, err := GetWindow(a.Window, a.FrameLength)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	spectra := make([][]float64, len(frames))
+	buf := make([]float32, a.FrameLength)
+	for i, frame := range frames {
+		for j, v := range frame {
+			buf[j] = v * window[j]
+		}
+		spec := RFFT(buf)
+		power := make([]float64, len(spec))
+		for k, v := range spec {
+			power[k] = real(v)*real(v) + imag(v)*imag(v)
+		}
+		spectra[i] = power
+	}
+	return spectra, times, nil
+}
+
+// binFrequency 频点对应的频率
+func (a *Analyzer) binFrequency(k int) float64 {
+	return float64(k) * float64(a.SampleRate) / float64(a.FrameLength)
+}
+
+// RMS 计算每帧的均方根幅度 (线性值)
+//
+// # Params:
+//
+//	samples: 音频数据, 多声道时为交错排列
+func (a *Analyzer) RMS(samples []float32) (FrameSeries, error) {
+	frames, times, err := a.frames(samples)
+	if err != nil {
+		return FrameSeries{}, err
+	}
+	values := make([]float64, len(frames))
+	for i, frame := range frames {
+		var sum float64
+		for _, v := range frame {
+			sum += float64(v) * float64(v)
+		}
+		values[i] = math.Sqrt(sum / float64(len(frame)))
+	}
+	return FrameSeries{Times: times, Values: values}, nil
+}
+
+// LoudnessEnvelope 计算每帧的 K 计权响度 (LUFS)，用于观察响度随时间的变化
+//
+// # Params:
+//
+//	samples: 音频数据, 多声道时为交错排列
+func (a *Analyzer) LoudnessEnvelope(samples []float32) (FrameSeries, error) {
+	if err := a.validate(); err != nil {
+		return FrameSeries{}, err
+	}
+
+	// 各声道分别 K 计权后按声道求和均方值
+	frameCount := len(samples) / a.Channels
+	squared := make([]float64, frameCount)
+	channel := make([]float64, frameCount)
+	for c := 0; c < a.Channels; c++ {
+		for i := 0; i < frameCount; i++ {
+			channel[i] = float64(samples[i*a.Channels+c])
+		}
+		for i, v := range kWeighting(channel, a.SampleRate) {
+			squared[i] += v * v
+		}
+	}
+
+	times := a.frameTimes(frameCount)
+	values := make([]float64, len(times))
+	for i := range values {
+		start := i * a.HopLength
+		var sum float64
+		for _, v := range squared[start : start+a.FrameLength] {
+			sum += v
+		}
+		values[i] = -0.691 + 10*math.Log10(sum/float64(a.FrameLength)+1e-12)
+	}
+	return FrameSeries{Times: times, Values: values}, nil
+}
+
+// ZeroCrossingRate 计算每帧的过零率 (0~1)
+//
+// # Params:
+//
+//	samples: 音频数据, 多声道时为交错排列
+func (a *Analyzer) ZeroCrossingRate(samples []float32) (FrameSeries, error) {
+	frames, times, err := a.frames(samples)
+	if err != nil {
+		return FrameSeries{}, err
+	}
+	values := make([]float64, len(frames))
+	for i, frame := range frames {
+		values[i] = zeroCrossingRate(frame)
+	}
+	return FrameSeries{Times: times, Values: values}, nil
+}
+
+// SpectralCentroid 计算每帧的频谱质心 (Hz)
+//
+// 公式: Σ f[k] * |X[k]| / Σ |X[k]|
+//
+// # Params:
+//
+//	samples: 音频数据, 多声道时为交错排列
+func (a *Analyzer) SpectralCentroid(samples []float32) (FrameSeries, error) {
+	spectra, times, err := a.powerSpectra(samples)
+	if err != nil {
+		return FrameSeries{}, err
+	}
+	values := make([]float64, len(spectra))
+	for i, power := range spectra {
+		var weighted, total float64
+		for k, p := range power {
+			mag := math.Sqrt(p)
+			weighted += a.binFrequency(k) * mag
+			total += mag
+		}
+		if total > 0 {
+			values[i] = weighted / total
+		}
+	}
+	return FrameSeries{Times: times, Values: values}, nil
+}
+
+// SpectralRolloff 计算每帧的频谱滚降点 (Hz)，即累计能量达到指定比例时的频率
+//
+// # Params:
+//
+//	samples: 音频数据, 多声道时为交错排列
+//	percent: 能量比例 (0~1), 常用 0.85
+func (a *Analyzer) SpectralRolloff(samples []float32, percent float64) (FrameSeries, error) {
+	if percent <= 0 || percent > 1 {
+		return FrameSeries{}, fmt.Errorf("%w, percent must be in (0, 1]", gotool.ErrInvalidParam)
+	}
+	spectra, times, err := a.powerSpectra(samples)
+	if err != nil {
+		return FrameSeries{}, err
+	}
+	values := make([]float64, len(spectra))
+	for i, power := range spectra {
+		var total float64
+		for _, p := range power {
+			total += p
+		}
+		if total == 0 {
+			continue
+		}
+		var cumulative float64
+		for k, p := range power {
+			cumulative += p
+			if cumulative >= percent*total {
+				values[i] = a.binFrequency(k)
+				break
+			}
+		}
+	}
+	return FrameSeries{Times: times, Values: values}, nil
+}
+
+// SpectralFlatness 计算每帧的频谱平坦度 (0~1)，越接近 1 越像白噪声，越接近 0 越像纯音
+//
+// 公式: 几何平均(功率谱) / 算术平均(功率谱)
+//
+// # Params:
+//
+//	samples: 音频数据, 多声道时为交错排列
+func (a *Analyzer) SpectralFlatness(samples []float32) (FrameSeries, error) {
+	spectra, times, err := a.powerSpectra(samples)
+	if err != nil {
+		return FrameSeries{}, err
+	}
+	const amin = 1e-10
+	values := make([]float64, len(spectra))
+	for i, power := range spectra {
+		var logSum, sum float64
+		for _, p := range power {
+			p = math.Max(p, amin)
+			logSum += math.Log(p)
+			sum += p
+		}
+		n := float64(len(power))
+		values[i] = math.Exp(logSum/n) / (sum / n)
+	}
+	return FrameSeries{Times: times, Values: values}, nil
+}
+
+// OnsetStrength 计算起音强度包络 (对数幅度谱的正向谱通量)
+//
+// # Params:
+//
+//	samples: 音频数据, 多声道时为交错排列
+func (a *Analyzer) OnsetStrength(samples []float32) (FrameSeries, error) {
+	spectra, times, err := a.powerSpectra(samples)
+	if err != nil {
+		return FrameSeries{}, err
+	}
+	values := make([]float64, len(spectra))
+	var prev []float64
+	for i, power := range spectra {
+		logMag := make([]float64, len(power))
+		for k, p := range power {
+			logMag[k] = 10 * math.Log10(p+1e-10)
+		}
+		if prev != nil {
+			var flux float64
+			for k := range logMag {
+				if d := logMag[k] - prev[k]; d > 0 {
+					flux += d
+				}
+			}
+			values[i] = flux / float64(len(logMag))
+		}
+		prev = logMag
+	}
+	return FrameSeries{Times: times, Values: values}, nil
+}
+
+// DetectOnsets 检测起音时间点，在起音强度包络上做自适应阈值峰值检测
+//
+// # Params:
+//
+//	samples: 音频数据, 多声道时为交错排列
+//	minInterval: 相邻起音的最小间隔
+func (a *Analyzer) DetectOnsets(samples []float32, minInterval time.Duration) ([]time.Duration, error) {
+	envelope, err := a.OnsetStrength(samples)
+	if err != nil {
+		return nil, err
+	}
+	values := envelope.Values
+	onsets := []time.Duration{}
+	if len(values) < 3 {
+		return onsets, nil
+	}
+
+	// 阈值: 均值 + 标准差
+	var sum, sumSq float64
+	for _, v := range values {
+		sum += v
+		sumSq += v * v
+	}
+	mean := sum / float64(len(values))
+	threshold := mean + math.Sqrt(math.Max(sumSq/float64(len(values))-mean*mean, 0))
+
+	for i := 1; i < len(values)-1; i++ {
+		if values[i] < threshold || values[i] < values[i-1] || values[i] < values[i+1] {
+			continue
+		}
+		t := envelope.Times[i]
+		if n := len(onsets); n > 0 && t-onsets[n-1] < minInterval {
+			continue
+		}
+		onsets = append(onsets, t)
+	}
+	return onsets, nil
+}
+
+// Chroma 计算色度图 (12 个音级的能量分布)
+//
+// # Params:
+//
+//	samples: 音频数据, 多声道时为交错排列
+func (a *Analyzer) Chroma(samples []float32) (Chromagram, error) {
+	spectra, times, err := a.powerSpectra(samples)
+	if err != nil {
+		return Chromagram{}, err
+	}
+
+	// 预先计算每个频点对应的音级, 以 A4 = 440Hz 为基准, 忽略 20Hz 以下的频点
+	pitchClass := make([]int, a.FrameLength/2+1)
+	for k := range pitchClass {
+		f := a.binFrequency(k)
+		if f < 20 {
+			pitchClass[k] = -1
+			continue
+		}
+		midi := int(math.Round(12*math.Log2(f/440) + 69))
+		pitchClass[k] = ((midi % 12) + 12) % 12
+	}
+
+	chroma := make([][]float64, len(spectra))
+	for i, power := range spectra {
+		row := make([]float64, 12)
+		for k, p := range power {
+			if pc := pitchClass[k]; pc >= 0 {
+				row[pc] += p
+			}
+		}
+		var peak float64
+		for _, v := range row {
+			peak = math.Max(peak, v)
+		}
+		if peak > 0 {
+			for j := range row {
+				row[j] /= peak
+			}
+		}
+		chroma[i] = row
+	}
+	return Chromagram{Times: times, Chroma: chroma}, nil
+}
+
+// PitchYIN 使用 YIN 算法跟踪基频
+//
+// 帧长需大于 2 * sampleRate / fMin
+//
+// # Params:
+//
+//	samples: 音频数据, 多声道时为交错排列
+//	fMin: 最低基频 (Hz), 例如 60
+//	fMax: 最高基频 (Hz), 例如 500
+//	threshold: 累积均值归一化差分的绝对阈值, 常用 0.1
+func (a *Analyzer) PitchYIN(samples []float32, fMin, fMax, threshold float64) (PitchTrack, error) {
+	if fMin <= 0 || fMax <= fMin || threshold <= 0 {
+		return PitchTrack{}, fmt.Errorf("%w, fmin=%v, fmax=%v, threshold=%v", gotool.ErrInvalidParam, fMin, fMax, threshold)
+	}
+	frames, times, err := a.frames(samples)
+	if err != nil {
+		return PitchTrack{}, err
+	}
+	minLag := int(float64(a.SampleRate) / fMax)
+	maxLag := int(math.Ceil(float64(a.SampleRate) / fMin))
+	if minLag < 1 {
+		minLag = 1
+	}
+	if a.FrameLength <= 2*maxLag {
+		return PitchTrack{}, fmt.Errorf("%w, frame length %d is too short for fmin %v", gotool.ErrInvalidParam, a.FrameLength, fMin)
+	}
+	width := a.FrameLength - maxLag
+
+	track := PitchTrack{
+		Times:       times,
+		Frequencies: make([]float64, len(frames)),
+		Confidences: make([]float64, len(frames)),
+	}
+	diff := make([]float64, maxLag+2)
+	cmnd := make([]float64, maxLag+2)
+	for i, frame := range frames {
+		// 差分函数 d(τ) = Σ (x[j] - x[j+τ])²
+		for tau := 1; tau <= maxLag+1; tau++ {
+			var sum float64
+			for j := 0; j < width-1; j++ {
+				d := float64(frame[j]) - float64(frame[j+tau])
+				sum += d * d
+			}
+			diff[tau] = sum
+		}
+
+		// 累积均值归一化 d'(τ) = d(τ) / ((1/τ) Σ d(j))
+		cmnd[0] = 1
+		var running float64
+		for tau := 1; tau <= maxLag+1; tau++ {
+			running += diff[tau]
+			if running == 0 {
+				cmnd[tau] = 1
+			} else {
+				cmnd[tau] = diff[tau] * float64(tau) / running
+			}
+		}
+
+		// 绝对阈值: 取第一个低于阈值的局部最小值
+		best := -1
+		for tau := minLag; tau <= maxLag; tau++ {
+			if cmnd[tau] < threshold {
+				for tau+1 <= maxLag && cmnd[tau+1] < cmnd[tau] {
+					tau++
+				}
+				best = tau
+				break
+			}
+		}
+		if best < 0 {
+			continue
+		}
+
+		// 抛物线插值提高精度
+		period := float64(best)
+		if best > 1 && best < maxLag+1 {
+			s0, s1, s2 := cmnd[best-1], cmnd[best], cmnd[best+1]
+			if denom := s0 - 2*s1 + s2; denom != 0 {
+				period += (s0 - s2) / (2 * denom)
+			}
+		}
+		track.Frequencies[i] = float64(a.SampleRate) / period
+		track.Confidences[i] = 1 - cmnd[best]
+	}
+	return track, nil
+}
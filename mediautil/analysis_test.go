@@ -0,0 +1,100 @@
+package mediautil
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func testAnalyzer() *Analyzer {
+	return NewAnalyzer(&WavHeader{SampleRate: SampleRate16K, NumChannels: 1})
+}
+
+func TestAnalyzerRMS(t *testing.T) {
+	a := testAnalyzer()
+	series, err := a.RMS(sineWave(440, SampleRate16K, SampleRate16K))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, len(series.Values), 1+(SampleRate16K-2048)/512)
+	testutil.Equal(t, series.Times[0], 64*time.Millisecond)
+	// 幅度 0.5 的正弦波 RMS 为 0.5/√2
+	testutil.EqualFloat(t, series.Values[3], 0.353553, 1e-3)
+}
+
+func TestAnalyzerSpectral(t *testing.T) {
+	a := testAnalyzer()
+	tone := sineWave(1000, SampleRate16K, SampleRate16K)
+
+	centroid, err := a.SpectralCentroid(tone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.EqualFloat(t, centroid.Values[0], 1000.0, 50)
+
+	rolloff, _ := a.SpectralRolloff(tone, 0.85)
+	testutil.EqualFloat(t, rolloff.Values[0], 1000.0, 20)
+
+	noise := make([]float32, SampleRate16K)
+	for i := range noise {
+		noise[i] = rand.Float32()*2 - 1
+	}
+	toneFlatness, _ := a.SpectralFlatness(tone)
+	noiseFlatness, _ := a.SpectralFlatness(noise)
+	testutil.Equal(t, toneFlatness.Values[0] < 0.01, true)
+	testutil.Equal(t, noiseFlatness.Values[0] > 0.3, true)
+
+	zcr, _ := a.ZeroCrossingRate(tone)
+	testutil.EqualFloat(t, zcr.Values[0], 2*1000.0/SampleRate16K, 1e-3)
+}
+
+func TestAnalyzerPitchYIN(t *testing.T) {
+	a := testAnalyzer()
+	track, err := a.PitchYIN(sineWave(220, SampleRate16K, SampleRate16K), 60, 500, 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range track.Frequencies {
+		testutil.EqualFloat(t, f, 220.0, 1)
+	}
+
+	track, _ = a.PitchYIN(make([]float32, SampleRate16K), 60, 500, 0.1)
+	testutil.EqualFloat(t, track.Frequencies[0], 0.0)
+}
+
+func TestAnalyzerChroma(t *testing.T) {
+	a := testAnalyzer()
+	a.FrameLength = 4096
+	chroma, err := a.Chroma(sineWave(440, SampleRate16K, SampleRate16K))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A 音级 (索引 9) 能量最大
+	testutil.EqualFloat(t, chroma.Chroma[0][9], 1.0)
+}
+
+func TestAnalyzerDetectOnsets(t *testing.T) {
+	a := testAnalyzer()
+	// 两个快速起音、缓慢衰减的音符
+	note, _ := FadeOut(sineWave(880, SampleRate16K, SampleRate16K/4), SampleRate16K, 1, 200*time.Millisecond, FadeCurveLinear)
+	samples := make([]float32, SampleRate16K*2)
+	copy(samples[SampleRate16K/2:], note)
+	copy(samples[SampleRate16K*3/2:], note)
+	onsets, err := a.DetectOnsets(samples, 100*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, len(onsets), 2)
+	testutil.EqualFloat(t, onsets[0].Seconds(), 0.5, 0.07)
+}
+
+func TestAnalyzerLoudnessEnvelope(t *testing.T) {
+	a := NewAnalyzer(&WavHeader{SampleRate: SampleRate48K, NumChannels: 1})
+	series, err := a.LoudnessEnvelope(ApplyGain(sineWave(1000, SampleRate48K, SampleRate48K), GainToDb(2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.EqualFloat(t, series.Values[10], -3.01, 0.1)
+}
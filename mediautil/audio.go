@@ -1,8 +1,11 @@
 package mediautil
 
 import (
+	"fmt"
 	"math"
 	"math/cmplx"
+
+	"github.com/up-zero/gotool"
 )
 
 // PreEmphasis 预加重滤波器，提升高频部分，平衡频谱能量
@@ -63,8 +66,72 @@ func HannWindow(size int) []float32 {
 	return window
 }
 
+// PoveyWindow 生成 Povey 窗 (Kaldi 默认窗函数)
+//
+// Povey 公式: (0.5 - 0.5 * cos(2πn / (N-1)))^0.85
+//
+// # Params:
+//
+//	size: 窗口大小
+func PoveyWindow(size int) []float32 {
+	if size <= 0 {
+		return nil
+	}
+	if size == 1 {
+		return []float32{1.0}
+	}
+
+	window := make([]float32, size)
+	factor := 2.0 * math.Pi / float64(size-1)
+	for i := 0; i < size; i++ {
+		window[i] = float32(math.Pow(0.5-0.5*math.Cos(factor*float64(i)), 0.85))
+	}
+	return window
+}
+
+const (
+	// WindowRectangular 矩形窗
+	WindowRectangular = "rectangular"
+	// WindowHamming 汉明窗
+	WindowHamming = "hamming"
+	// WindowHann 汉宁窗
+	WindowHann = "hann"
+	// WindowPovey Povey 窗
+	WindowPovey = "povey"
+)
+
+// GetWindow 根据窗函数名称生成窗口
+//
+// # Params:
+//
+//	windowType: 窗函数类型, 可选值为 WindowRectangular、WindowHamming、WindowHann、WindowPovey
+//	size: 窗口大小
+func GetWindow(windowType string, size int) ([]float32, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("%w, window size must be positive", gotool.ErrInvalidParam)
+	}
+	switch windowType {
+	case WindowRectangular:
+		window := make([]float32, size)
+		for i := range window {
+			window[i] = 1.0
+		}
+		return window, nil
+	case WindowHamming:
+		return HammingWindow(size), nil
+	case WindowHann:
+		return HannWindow(size), nil
+	case WindowPovey:
+		return PoveyWindow(size), nil
+	default:
+		return nil, fmt.Errorf("%w, unsupported window type: %s", gotool.ErrInvalidParam, windowType)
+	}
+}
+
 // FFT 快速傅里叶变换，时域转频域
 //
+// 长度为 2 的幂时使用基 2 算法，其余长度使用 Bluestein 算法
+//
 // # Params:
 //
 //	x: 时域数据（波形）
@@ -73,6 +140,9 @@ func FFT(x []complex128) []complex128 {
 	if n <= 1 {
 		return x
 	}
+	if n&(n-1) != 0 {
+		return bluestein(x)
+	}
 
 	// 分治
 	even := make([]complex128, n/2)
@@ -0,0 +1,454 @@
+package mediautil
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/up-zero/gotool"
+)
+
+const (
+	// FadeCurveLinear 线性曲线
+	FadeCurveLinear = "linear"
+	// FadeCurveExponential 指数曲线 (按 dB 线性变化, 起始慢结束快)
+	FadeCurveExponential = "exponential"
+	// FadeCurveLogarithmic 对数曲线 (起始快结束慢)
+	FadeCurveLogarithmic = "logarithmic"
+	// FadeCurveSCurve S 形曲线 (半个余弦周期)
+	FadeCurveSCurve = "s-curve"
+	// FadeCurveEqualPower 等功率曲线 (四分之一正弦周期), 适用于交叉淡化
+	FadeCurveEqualPower = "equal-power"
+)
+
+// DbToGain 分贝转线性增益
+//
+// 公式: gain = 10^(dB / 20)
+//
+// # Params:
+//
+//	db: 分贝值
+func DbToGain(db float64) float64 {
+	return math.Pow(10, db/20)
+}
+
+// GainToDb 线性增益转分贝
+//
+// 公式: dB = 20 * log10(gain)
+//
+// # Params:
+//
+//	gain: 线性增益
+func GainToDb(gain float64) float64 {
+	if gain <= 0 {
+		return math.Inf(-1)
+	}
+	return 20 * math.Log10(gain)
+}
+
+// ApplyGain 按分贝调整音量，返回新的切片
+//
+// # Params:
+//
+//	samples: 音频数据
+//	gainDB: 增益 (dB), 正数放大、负数衰减
+func ApplyGain(samples []float32, gainDB float64) []float32 {
+	gain := float32(DbToGain(gainDB))
+	output := make([]float32, len(samples))
+	for i, v := range samples {
+		output[i] = v * gain
+	}
+	return output
+}
+
+// PeakLevel 计算峰值电平 (dBFS)
+//
+// # Params:
+//
+//	samples: 音频数据
+func PeakLevel(samples []float32) float64 {
+	var peak float64
+	for _, v := range samples {
+		if a := math.Abs(float64(v)); a > peak {
+			peak = a
+		}
+	}
+	return GainToDb(peak)
+}
+
+// RMSLevel 计算均方根电平 (dBFS)
+//
+// # Params:
+//
+//	samples: 音频数据
+func RMSLevel(samples []float32) float64 {
+	if len(samples) == 0 {
+		return math.Inf(-1)
+	}
+	var sum float64
+	for _, v := range samples {
+		sum += float64(v) * float64(v)
+	}
+	return GainToDb(math.Sqrt(sum / float64(len(samples))))
+}
+
+// PeakNormalize 峰值归一化，使峰值达到目标电平
+//
+// # Params:
+//
+//	samples: 音频数据
+//	targetDB: 目标峰值电平 (dBFS), 例如 -1
+func PeakNormalize(samples []float32, targetDB float64) []float32 {
+	current := PeakLevel(samples)
+	if math.IsInf(current, -1) {
+		return append([]float32{}, samples...)
+	}
+	return ApplyGain(samples, targetDB-current)
+}
+
+// RMSNormalize 均方根归一化，使 RMS 电平达到目标值
+//
+// 注意: 放大后的采样点可能超出 [-1, 1]，可配合 PeakNormalize 或 SoftClip 使用
+//
+// # Params:
+//
+//	samples: 音频数据
+//	targetDB: 目标 RMS 电平 (dBFS), 例如 -20
+func RMSNormalize(samples []float32, targetDB float64) []float32 {
+	current := RMSLevel(samples)
+	if math.IsInf(current, -1) {
+		return append([]float32{}, samples...)
+	}
+	return ApplyGain(samples, targetDB-current)
+}
+
+// Loudness 计算积分响度 (LUFS)
+//
+// 参照 ITU-R BS.1770: K 计权滤波、400ms 分块 (75% 重叠)、-70 LUFS 绝对门限和 -10 LU 相对门限，各声道权重均为 1
+//
+// # Params:
+//
+//	samples: 音频数据, 多声道时为交错排列
+//	sampleRate: 采样率
+//	channels: 声道数
+func Loudness(samples []float32, sampleRate, channels int) (float64, error) {
+	if sampleRate <= 0 || channels <= 0 {
+		return 0, fmt.Errorf("%w, rate=%d, chan=%d", gotool.ErrInvalidParam, sampleRate, channels)
+	}
+	frames := len(samples) / channels
+	if frames == 0 {
+		return math.Inf(-1), nil
+	}
+
+	// 各声道分别做 K 计权, 并计算平方值
+	squared := make([][]float64, channels)
+	for c := 0; c < channels; c++ {
+		channel := make([]float64, frames)
+		for i := 0; i < frames; i++ {
+			channel[i] = float64(samples[i*channels+c])
+		}
+		weighted := kWeighting(channel, sampleRate)
+		for i, v := range weighted {
+			weighted[i] = v * v
+		}
+		squared[c] = weighted
+	}
+
+	// 分块计算均方值
+	blockSize := sampleRate * 400 / 1000
+	step := blockSize / 4
+	if frames < blockSize {
+		blockSize = frames
+		step = frames
+	}
+	var blocks []float64
+	for start := 0; start+blockSize <= frames; start += step {
+		var z float64
+		for c := 0; c < channels; c++ {
+			var sum float64
+			for _, v := range squared[c][start : start+blockSize] {
+				sum += v
+			}
+			z += sum / float64(blockSize)
+		}
+		blocks = append(blocks, z)
+	}
+
+	blockLoudness := func(z float64) float64 {
+		return -0.691 + 10*math.Log10(z)
+	}
+	gatedMean := func(threshold float64) (float64, int) {
+		var sum float64
+		var count int
+		for _, z := range blocks {
+			if z > 0 && blockLoudness(z) > threshold {
+				sum += z
+				count++
+			}
+		}
+		if count == 0 {
+			return 0, 0
+		}
+		return sum / float64(count), count
+	}
+
+	// 绝对门限 -70 LUFS
+	mean, count := gatedMean(-70)
+	if count == 0 {
+		return math.Inf(-1), nil
+	}
+	// 相对门限 -10 LU
+	mean, count = gatedMean(blockLoudness(mean) - 10)
+	if count == 0 {
+		return math.Inf(-1), nil
+	}
+	return blockLoudness(mean), nil
+}
+
+// LoudnessNormalize 响度归一化，使积分响度达到目标值
+//
+// # Params:
+//
+//	samples: 音频数据, 多声道时为交错排列
+//	sampleRate: 采样率
+//	channels: 声道数
+//	targetLUFS: 目标响度 (LUFS), 例如 -23 (EBU R128)、-16 (流媒体)
+func LoudnessNormalize(samples []float32, sampleRate, channels int, targetLUFS float64) ([]float32, error) {
+	current, err := Loudness(samples, sampleRate, channels)
+	if err != nil {
+		return nil, err
+	}
+	if math.IsInf(current, -1) {
+		return append([]float32{}, samples...), nil
+	}
+	return ApplyGain(samples, targetLUFS-current), nil
+}
+
+// kWeighting BS.1770 K 计权滤波: 高频搁架滤波器 + 高通滤波器
+func kWeighting(x []float64, sampleRate int) []float64 {
+	fs := float64(sampleRate)
+
+	// 第一级: 高频搁架, +4dB @ 1681.97Hz
+	gain, fc, q := 3.99984385397, 1681.9744509555319, 0.7071752369554193
+	k := math.Tan(math.Pi * fc / fs)
+	vh := math.Pow(10, gain/20)
+	vb := math.Pow(vh, 0.4996667741545416)
+	a0 := 1 + k/q + k*k
+	shelf := BiquadCoeffs{
+		B0: (vh + vb*k/q + k*k) / a0, B1: 2 * (k*k - vh) / a0, B2: (vh - vb*k/q + k*k) / a0,
+		A1: 2 * (k*k - 1) / a0, A2: (1 - k/q + k*k) / a0,
+	}
+
+	// 第二级: 高通 @ 38.13Hz
+	fc, q = 38.13547087613982, 0.5003270373253953
+	k = math.Tan(math.Pi * fc / fs)
+	a0 = 1 + k/q + k*k
+	highPass := BiquadCoeffs{
+		B0: 1, B1: -2, B2: 1,
+		A1: 2 * (k*k - 1) / a0, A2: (1 - k/q + k*k) / a0,
+	}
+
+	return highPass.apply(shelf.apply(x))
+}
+
+// fadeGain 计算淡入曲线在位置 t (0~1) 处的增益
+func fadeGain(curve string, t float64) (float64, error) {
+	switch curve {
+	case FadeCurveLinear, "":
+		return t, nil
+	case FadeCurveExponential:
+		return (math.Pow(10, 3*t) - 1) / 999, nil
+	case FadeCurveLogarithmic:
+		return math.Log10(1 + 9*t), nil
+	case FadeCurveSCurve:
+		return 0.5 - 0.5*math.Cos(math.Pi*t), nil
+	case FadeCurveEqualPower:
+		return math.Sin(math.Pi / 2 * t), nil
+	default:
+		return 0, fmt.Errorf("%w, unsupported fade curve: %s", gotool.ErrInvalidParam, curve)
+	}
+}
+
+// FadeIn 淡入，返回新的切片
+//
+// # Params:
+//
+//	samples: 音频数据, 多声道时为交错排列
+//	sampleRate: 采样率
+//	channels: 声道数
+//	duration: 淡入时长
+//	curve: 曲线类型, 可选值为 FadeCurveLinear、FadeCurveExponential、FadeCurveLogarithmic、FadeCurveSCurve、FadeCurveEqualPower
+func FadeIn(samples []float32, sampleRate, channels int, duration time.Duration, curve string) ([]float32, error) {
+	return applyFade(samples, sampleRate, channels, duration, curve, true)
+}
+
+// FadeOut 淡出，返回新的切片
+//
+// # Params:
+//
+//	samples: 音频数据, 多声道时为交错排列
+//	sampleRate: 采样率
+//	channels: 声道数
+//	duration: 淡出时长
+//	curve: 曲线类型, 参考 FadeIn
+func FadeOut(samples []float32, sampleRate, channels int, duration time.Duration, curve string) ([]float32, error) {
+	return applyFade(samples, sampleRate, channels, duration, curve, false)
+}
+
+// applyFade 淡入淡出的通用实现
+func applyFade(samples []float32, sampleRate, channels int, duration time.Duration, curve string, fadeIn bool) ([]float32, error) {
+	if sampleRate <= 0 || channels <= 0 {
+		return nil, fmt.Errorf("%w, rate=%d, chan=%d", gotool.ErrInvalidParam, sampleRate, channels)
+	}
+	if _, err := fadeGain(curve, 0); err != nil {
+		return nil, err
+	}
+	output := append([]float32{}, samples...)
+	frames := len(samples) / channels
+	fadeFrames := int(duration.Seconds() * float64(sampleRate))
+	if fadeFrames > frames {
+		fadeFrames = frames
+	}
+
+	for i := 0; i < fadeFrames; i++ {
+		frame := i
+		t := float64(i) / float64(fadeFrames)
+		if !fadeIn {
+			// 淡出为淡入曲线的时间反转
+			frame = frames - fadeFrames + i
+			t = float64(fadeFrames-1-i) / float64(fadeFrames)
+		}
+		gain, _ := fadeGain(curve, t)
+		for c := 0; c < channels; c++ {
+			output[frame*channels+c] *= float32(gain)
+		}
+	}
+	return output, nil
+}
+
+// Mix 混合多条音轨，输出长度为最长音轨的长度
+//
+// 混合结果峰值超过 0dBFS 时整体缩放，防止削波
+//
+// # Params:
+//
+//	tracks: 多条声道数相同的音频数据
+func Mix(tracks ...[]float32) []float32 {
+	length := 0
+	for _, track := range tracks {
+		if len(track) > length {
+			length = len(track)
+		}
+	}
+
+	output := make([]float32, length)
+	var peak float32
+	for _, track := range tracks {
+		for i, v := range track {
+			output[i] += v
+		}
+	}
+	for _, v := range output {
+		if v > peak {
+			peak = v
+		} else if -v > peak {
+			peak = -v
+		}
+	}
+	if peak > 1 {
+		scale := 1 / peak
+		for i := range output {
+			output[i] *= scale
+		}
+	}
+	return output
+}
+
+// Concat 拼接多条音轨，相邻音轨之间使用等功率曲线交叉淡化
+//
+// # Params:
+//
+//	sampleRate: 采样率
+//	channels: 声道数
+//	crossfade: 交叉淡化时长, 0 表示直接拼接
+//	tracks: 多条音频数据, 多声道时为交错排列
+func Concat(sampleRate, channels int, crossfade time.Duration, tracks ...[]float32) ([]float32, error) {
+	if sampleRate <= 0 || channels <= 0 {
+		return nil, fmt.Errorf("%w, rate=%d, chan=%d", gotool.ErrInvalidParam, sampleRate, channels)
+	}
+	fadeFrames := int(crossfade.Seconds() * float64(sampleRate))
+
+	var output []float32
+	for idx, track := range tracks {
+		track = track[:len(track)/channels*channels]
+		if idx == 0 || fadeFrames <= 0 {
+			output = append(output, track...)
+			continue
+		}
+
+		// 交叉淡化长度不超过两段中较短者
+		overlap := fadeFrames
+		if n := len(output) / channels; n < overlap {
+			overlap = n
+		}
+		if n := len(track) / channels; n < overlap {
+			overlap = n
+		}
+		base := len(output) - overlap*channels
+		for i := 0; i < overlap; i++ {
+			t := (float64(i) + 0.5) / float64(overlap)
+			gainOut := float32(math.Cos(math.Pi / 2 * t))
+			gainIn := float32(math.Sin(math.Pi / 2 * t))
+			for c := 0; c < channels; c++ {
+				pos := i*channels + c
+				output[base+pos] = output[base+pos]*gainOut + track[pos]*gainIn
+			}
+		}
+		output = append(output, track[overlap*channels:]...)
+	}
+	if output == nil {
+		output = []float32{}
+	}
+	return output, nil
+}
+
+// RemoveDCOffset 去除直流偏移 (各声道减去自身均值)，返回新的切片
+//
+// # Params:
+//
+//	samples: 音频数据, 多声道时为交错排列
+//	channels: 声道数
+func RemoveDCOffset(samples []float32, channels int) ([]float32, error) {
+	if channels <= 0 {
+		return nil, fmt.Errorf("%w, chan=%d", gotool.ErrInvalidParam, channels)
+	}
+	output := append([]float32{}, samples...)
+	frames := len(samples) / channels
+	if frames == 0 {
+		return output, nil
+	}
+	for c := 0; c < channels; c++ {
+		var sum float64
+		for i := 0; i < frames; i++ {
+			sum += float64(samples[i*channels+c])
+		}
+		mean := float32(sum / float64(frames))
+		for i := 0; i < frames; i++ {
+			output[i*channels+c] -= mean
+		}
+	}
+	return output, nil
+}
+
+// SoftClip 软削波，使用 tanh 曲线将采样点平滑压缩至 (-1, 1)，返回新的切片
+//
+// # Params:
+//
+//	samples: 音频数据
+func SoftClip(samples []float32) []float32 {
+	output := make([]float32, len(samples))
+	for i, v := range samples {
+		output[i] = float32(math.Tanh(float64(v)))
+	}
+	return output
+}
@@ -0,0 +1,71 @@
+package mediautil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestApplyGain(t *testing.T) {
+	testutil.EqualFloat(t, ApplyGain([]float32{0.5, -0.25}, 6.0205999), []float32{1.0, -0.5})
+}
+
+func TestPeakNormalize(t *testing.T) {
+	testutil.EqualFloat(t, PeakNormalize([]float32{0.25, -0.5}, 0), []float32{0.5, -1.0})
+	testutil.EqualFloat(t, RMSLevel(RMSNormalize(sineWave(440, SampleRate16K, 1600), -20)), -20.0, 1e-4)
+}
+
+func TestLoudness(t *testing.T) {
+	// BS.1770: 单声道 0dBFS 的 1kHz 正弦波约为 -3.01 LUFS
+	samples := sineWave(1000, SampleRate48K, SampleRate48K*2)
+	samples = ApplyGain(samples, GainToDb(2)) // sineWave 幅度为 0.5
+	lufs, err := Loudness(samples, SampleRate48K, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.EqualFloat(t, lufs, -3.01, 0.1)
+
+	normalized, err := LoudnessNormalize(samples, SampleRate48K, 1, -23)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lufs, _ = Loudness(normalized, SampleRate48K, 1)
+	testutil.EqualFloat(t, lufs, -23.0, 0.01)
+}
+
+func TestFadeIn(t *testing.T) {
+	samples := []float32{1, 1, 1, 1, 1, 1, 1, 1}
+	output, err := FadeIn(samples, 4, 2, time.Second, FadeCurveLinear)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.EqualFloat(t, output, []float32{0, 0, 0.25, 0.25, 0.5, 0.5, 0.75, 0.75})
+
+	output, err = FadeOut(samples, 4, 1, time.Second, FadeCurveLinear)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.EqualFloat(t, output, []float32{1, 1, 1, 1, 0.75, 0.5, 0.25, 0})
+}
+
+func TestMix(t *testing.T) {
+	testutil.EqualFloat(t, Mix([]float32{0.2, 0.2}, []float32{0.3}), []float32{0.5, 0.2})
+	testutil.EqualFloat(t, Mix([]float32{0.8, 0.4}, []float32{0.8, 0.4}), []float32{1.0, 0.5})
+}
+
+func TestConcat(t *testing.T) {
+	output, err := Concat(10, 1, 200*time.Millisecond, []float32{1, 1, 1, 1}, []float32{1, 1, 1, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, len(output), 6)
+}
+
+func TestRemoveDCOffset(t *testing.T) {
+	output, err := RemoveDCOffset([]float32{1, 0, 3, 2}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.EqualFloat(t, output, []float32{-1, -1, 1, 1})
+}
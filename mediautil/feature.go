@@ -0,0 +1,543 @@
+package mediautil
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/up-zero/gotool"
+)
+
+const (
+	// FeatureStyleKaldi Kaldi 风格: 帧不居中 (snip edges)、去直流、逐帧预加重、Povey 窗、峰值为 1 的 Mel 滤波器、自然对数
+	FeatureStyleKaldi = "kaldi"
+	// FeatureStyleLibrosa librosa 风格: 帧居中并反射补齐、Hann 窗、Slaney 归一化的 Mel 滤波器、dB 刻度
+	FeatureStyleLibrosa = "librosa"
+)
+
+const (
+	// FeatureFbank 对数 Mel 滤波器组特征 (log-mel)
+	FeatureFbank = "fbank"
+	// FeatureMFCC 梅尔频率倒谱系数
+	FeatureMFCC = "mfcc"
+)
+
+// FeatureExtractor 音频特征提取配置
+//
+// 建议通过 NewFeatureExtractor 创建，再按需修改字段
+type FeatureExtractor struct {
+	Style       string  // 计算约定, 可选值为 FeatureStyleKaldi、FeatureStyleLibrosa
+	SampleRate  int     // 采样率
+	FrameLength int     // 帧长 (采样点数)
+	HopLength   int     // 帧移 (采样点数)
+	NFFT        int     // FFT 点数, 不小于帧长, 0 表示自动取不小于帧长的最小 2 的幂
+	NMels       int     // Mel 频带数量
+	NMfcc       int     // MFCC 维数
+	FMin        float64 // Mel 滤波器最小频率
+	FMax        float64 // Mel 滤波器最大频率, 0 表示采样率的一半
+	Window      string  // 窗函数类型, 参考 GetWindow
+	PreEmphasis float32 // 预加重系数, 0 表示不做预加重
+	Dither      float32 // 抖动强度 (相对于 [-1, 1] 的浮点幅度), 0 表示不加抖动
+	CepLifter   int     // 倒谱提升系数, 0 表示不做提升
+	TopDB       float64 // 仅 librosa 风格: 对数谱相对最大值的动态范围限制 (dB), 0 表示不限制
+}
+
+// NewFeatureExtractor 创建指定风格的特征提取配置，帧长 25ms，帧移 10ms
+//
+// # Params:
+//
+//	style: 计算约定, 可选值为 FeatureStyleKaldi、FeatureStyleLibrosa
+//	sampleRate: 采样率
+func NewFeatureExtractor(style string, sampleRate int) *FeatureExtractor {
+	fe := &FeatureExtractor{
+		Style:       style,
+		SampleRate:  sampleRate,
+		FrameLength: sampleRate * 25 / 1000,
+		HopLength:   sampleRate * 10 / 1000,
+		NMels:       80,
+		NMfcc:       13,
+	}
+	switch style {
+	case FeatureStyleLibrosa:
+		fe.Window = WindowHann
+		fe.TopDB = 80
+	default:
+		fe.Window = WindowPovey
+		fe.PreEmphasis = 0.97
+		fe.Dither = 1.0 / 32768
+		fe.CepLifter = 22
+		fe.FMin = 20
+	}
+	return fe
+}
+
+// fftSize 计算实际使用的 FFT 点数
+func (fe *FeatureExtractor) fftSize() int {
+	if fe.NFFT > 0 {
+		return fe.NFFT
+	}
+	n := 1
+	for n < fe.FrameLength {
+		n <<= 1
+	}
+	return n
+}
+
+// validate 校验配置
+func (fe *FeatureExtractor) validate() error {
+	if fe.Style != FeatureStyleKaldi && fe.Style != FeatureStyleLibrosa {
+		return fmt.Errorf("%w, unsupported feature style: %s", gotool.ErrInvalidParam, fe.Style)
+	}
+	if fe.SampleRate <= 0 || fe.FrameLength <= 0 || fe.HopLength <= 0 {
+		return fmt.Errorf("%w, rate=%d, frame=%d, hop=%d", gotool.ErrInvalidParam, fe.SampleRate, fe.FrameLength, fe.HopLength)
+	}
+	nfft := fe.fftSize()
+	if nfft < fe.FrameLength {
+		return fmt.Errorf("%w, n_fft must not be less than frame length", gotool.ErrInvalidParam)
+	}
+	if fe.NMels <= 0 {
+		return fmt.Errorf("%w, n_mels must be positive", gotool.ErrInvalidParam)
+	}
+	return nil
+}
+
+// Frames 对音频分帧，并完成抖动、去直流、预加重和加窗，每帧长度为 NFFT (不足部分补零)
+//
+// # Params:
+//
+//	samples: 单声道音频数据
+func (fe *FeatureExtractor) Frames(samples []float32) ([][]float32, error) {
+	if err := fe.validate(); err != nil {
+		return nil, err
+	}
+	window, err := GetWindow(fe.Window, fe.FrameLength)
+	if err != nil {
+		return nil, err
+	}
+	nfft := fe.fftSize()
+
+	// librosa 风格: 以帧为中心，两端各反射补齐 NFFT/2, 窗口居中放置于 NFFT 内
+	signal := samples
+	frameSize := fe.FrameLength
+	winOffset := 0
+	if fe.Style == FeatureStyleLibrosa {
+		signal = padReflect(samples, nfft/2)
+		frameSize = nfft
+		winOffset = (nfft - fe.FrameLength) / 2
+	}
+	if len(signal) < frameSize {
+		return [][]float32{}, nil
+	}
+	numFrames := 1 + (len(signal)-frameSize)/fe.HopLength
+
+	frames := make([][]float32, numFrames)
+	raw := make([]float32, fe.FrameLength)
+	for i := 0; i < numFrames; i++ {
+		start := i*fe.HopLength + winOffset
+		copy(raw, signal[start:start+fe.FrameLength])
+
+		// 抖动
+		if fe.Dither != 0 {
+			for j := range raw {
+				raw[j] += fe.Dither * float32(rand.NormFloat64())
+			}
+		}
+
+		if fe.Style == FeatureStyleKaldi {
+			// 去直流
+			var mean float32
+			for _, v := range raw {
+				mean += v
+			}
+			mean /= float32(len(raw))
+			for j := range raw {
+				raw[j] -= mean
+			}
+		}
+
+		// 预加重 (帧内进行，首个采样点与自身做差分，与 Kaldi 保持一致)
+		if fe.PreEmphasis != 0 {
+			for j := len(raw) - 1; j > 0; j-- {
+				raw[j] -= fe.PreEmphasis * raw[j-1]
+			}
+			raw[0] -= fe.PreEmphasis * raw[0]
+		}
+
+		frame := make([]float32, nfft)
+		for j := 0; j < fe.FrameLength; j++ {
+			frame[winOffset+j] = raw[j] * window[j]
+		}
+		frames[i] = frame
+	}
+	return frames, nil
+}
+
+// Spectrogram 计算功率谱，每帧 NFFT/2+1 个频点
+//
+// # Params:
+//
+//	samples: 单声道音频数据
+func (fe *FeatureExtractor) Spectrogram(samples []float32) ([][]float32, error) {
+	frames, err := fe.Frames(samples)
+	if err != nil {
+		return nil, err
+	}
+	spec := make([][]float32, len(frames))
+	for i, frame := range frames {
+		out := RFFT(frame)
+		power := make([]float32, len(out))
+		for k, v := range out {
+			re, im := real(v), imag(v)
+			power[k] = float32(re*re + im*im)
+		}
+		spec[i] = power
+	}
+	return spec, nil
+}
+
+// melFilters 生成当前配置下的 Mel 滤波器组
+func (fe *FeatureExtractor) melFilters() [][]float32 {
+	filters := MelFilters(fe.SampleRate, fe.fftSize(), fe.NMels, fe.FMin, fe.FMax)
+	if fe.Style == FeatureStyleKaldi {
+		// Kaldi 的三角滤波器不做面积归一化，峰值为 1
+		for _, filter := range filters {
+			var peak float32
+			for _, w := range filter {
+				if w > peak {
+					peak = w
+				}
+			}
+			if peak == 0 {
+				continue
+			}
+			for j := range filter {
+				filter[j] /= peak
+			}
+		}
+	}
+	return filters
+}
+
+// MelSpectrogram 计算 Mel 功率谱 (未取对数)
+//
+// # Params:
+//
+//	samples: 单声道音频数据
+func (fe *FeatureExtractor) MelSpectrogram(samples []float32) ([][]float32, error) {
+	spec, err := fe.Spectrogram(samples)
+	if err != nil {
+		return nil, err
+	}
+	filters := fe.melFilters()
+	mel := make([][]float32, len(spec))
+	for i, power := range spec {
+		row := make([]float32, len(filters))
+		for m, filter := range filters {
+			var sum float32
+			for k, w := range filter {
+				if w != 0 {
+					sum += w * power[k]
+				}
+			}
+			row[m] = sum
+		}
+		mel[i] = row
+	}
+	return mel, nil
+}
+
+// LogMel 计算对数 Mel 滤波器组特征 (Fbank)
+//
+// Kaldi 风格取自然对数，librosa 风格取 10 * log10 (dB)
+//
+// # Params:
+//
+//	samples: 单声道音频数据
+func (fe *FeatureExtractor) LogMel(samples []float32) ([][]float32, error) {
+	mel, err := fe.MelSpectrogram(samples)
+	if err != nil {
+		return nil, err
+	}
+
+	if fe.Style == FeatureStyleKaldi {
+		// 使用 float32 的机器精度作为下限，避免 log(0)
+		const floor = 1.1920929e-07
+		for _, row := range mel {
+			for j, v := range row {
+				row[j] = float32(math.Log(math.Max(float64(v), floor)))
+			}
+		}
+		return mel, nil
+	}
+
+	// librosa power_to_db: 10 * log10(max(S, 1e-10)), 并按 TopDB 截断
+	maxDB := math.Inf(-1)
+	for _, row := range mel {
+		for j, v := range row {
+			db := 10 * math.Log10(math.Max(float64(v), 1e-10))
+			row[j] = float32(db)
+			if db > maxDB {
+				maxDB = db
+			}
+		}
+	}
+	if fe.TopDB > 0 {
+		lower := float32(maxDB - fe.TopDB)
+		for _, row := range mel {
+			for j, v := range row {
+				if v < lower {
+					row[j] = lower
+				}
+			}
+		}
+	}
+	return mel, nil
+}
+
+// MFCC 计算梅尔频率倒谱系数
+//
+// 对 LogMel 结果做正交归一化的 DCT-II，保留前 NMfcc 维，并按 CepLifter 进行倒谱提升
+//
+// # Params:
+//
+//	samples: 单声道音频数据
+func (fe *FeatureExtractor) MFCC(samples []float32) ([][]float32, error) {
+	if fe.NMfcc <= 0 || fe.NMfcc > fe.NMels {
+		return nil, fmt.Errorf("%w, n_mfcc must be in (0, n_mels]", gotool.ErrInvalidParam)
+	}
+	logMel, err := fe.LogMel(samples)
+	if err != nil {
+		return nil, err
+	}
+
+	// 倒谱提升系数: 1 + (L/2) * sin(πn / L)
+	var lifter []float32
+	if fe.CepLifter > 0 {
+		lifter = make([]float32, fe.NMfcc)
+		for n := range lifter {
+			lifter[n] = float32(1 + float64(fe.CepLifter)/2*math.Sin(math.Pi*float64(n)/float64(fe.CepLifter)))
+		}
+	}
+
+	mfcc := make([][]float32, len(logMel))
+	for i, row := range logMel {
+		coeffs := DCT(row, fe.NMfcc)
+		for n := range lifter {
+			coeffs[n] *= lifter[n]
+		}
+		mfcc[i] = coeffs
+	}
+	return mfcc, nil
+}
+
+// Extract 按特征类型提取特征
+//
+// # Params:
+//
+//	samples: 单声道音频数据
+//	featureType: 特征类型, 可选值为 FeatureFbank、FeatureMFCC
+func (fe *FeatureExtractor) Extract(samples []float32, featureType string) ([][]float32, error) {
+	switch featureType {
+	case FeatureFbank:
+		return fe.LogMel(samples)
+	case FeatureMFCC:
+		return fe.MFCC(samples)
+	default:
+		return nil, fmt.Errorf("%w, unsupported feature type: %s", gotool.ErrInvalidParam, featureType)
+	}
+}
+
+// CorpusCMVN 在整个语料上提取特征并统计 CMVN 参数
+//
+// # Params:
+//
+//	corpus: 多条单声道音频数据
+//	featureType: 特征类型, 可选值为 FeatureFbank、FeatureMFCC
+func (fe *FeatureExtractor) CorpusCMVN(corpus [][]float32, featureType string) (*CMVNStats, error) {
+	stats := new(CMVNStats)
+	for _, samples := range corpus {
+		features, err := fe.Extract(samples, featureType)
+		if err != nil {
+			return nil, err
+		}
+		stats.Accumulate(features)
+	}
+	return stats, nil
+}
+
+// DCT 正交归一化的 DCT-II 变换
+//
+// 公式: X[k] = s(k) * Σ x[n] * cos(π/N * (n + 0.5) * k), s(0) = sqrt(1/N), s(k) = sqrt(2/N)
+//
+// # Params:
+//
+//	input: 输入数据
+//	numCoeffs: 输出的系数个数, 不大于输入长度
+func DCT(input []float32, numCoeffs int) []float32 {
+	n := len(input)
+	if numCoeffs > n {
+		numCoeffs = n
+	}
+	if numCoeffs <= 0 {
+		return []float32{}
+	}
+	output := make([]float32, numCoeffs)
+	for k := 0; k < numCoeffs; k++ {
+		var sum float64
+		for i, v := range input {
+			sum += float64(v) * math.Cos(math.Pi/float64(n)*(float64(i)+0.5)*float64(k))
+		}
+		scale := math.Sqrt(2.0 / float64(n))
+		if k == 0 {
+			scale = math.Sqrt(1.0 / float64(n))
+		}
+		output[k] = float32(sum * scale)
+	}
+	return output
+}
+
+// ComputeDeltas 计算差分特征 (回归公式)
+//
+// 公式: d[t] = Σ n * (c[t+n] - c[t-n]) / (2 * Σ n²), n = 1..window, 边界处复制首尾帧
+//
+// # Params:
+//
+//	features: 特征矩阵 [帧][维度]
+//	window: 回归窗口半径, Kaldi 默认 2
+func ComputeDeltas(features [][]float32, window int) [][]float32 {
+	if window <= 0 {
+		window = 2
+	}
+	numFrames := len(features)
+	deltas := make([][]float32, numFrames)
+	if numFrames == 0 {
+		return deltas
+	}
+
+	var denom float32
+	for n := 1; n <= window; n++ {
+		denom += float32(n * n)
+	}
+	denom *= 2
+
+	clamp := func(t int) int {
+		if t < 0 {
+			return 0
+		}
+		if t >= numFrames {
+			return numFrames - 1
+		}
+		return t
+	}
+
+	for t := 0; t < numFrames; t++ {
+		dim := len(features[t])
+		row := make([]float32, dim)
+		for n := 1; n <= window; n++ {
+			next := features[clamp(t+n)]
+			prev := features[clamp(t-n)]
+			for d := 0; d < dim; d++ {
+				row[d] += float32(n) * (next[d] - prev[d])
+			}
+		}
+		for d := range row {
+			row[d] /= denom
+		}
+		deltas[t] = row
+	}
+	return deltas
+}
+
+// AppendDeltas 将原始特征与其各阶差分拼接
+//
+// # Params:
+//
+//	features: 特征矩阵 [帧][维度]
+//	order: 差分阶数, 例如 2 表示拼接一阶和二阶差分
+//	window: 回归窗口半径
+func AppendDeltas(features [][]float32, order, window int) [][]float32 {
+	output := make([][]float32, len(features))
+	for t, row := range features {
+		output[t] = append([]float32{}, row...)
+	}
+	current := features
+	for i := 0; i < order; i++ {
+		current = ComputeDeltas(current, window)
+		for t := range output {
+			output[t] = append(output[t], current[t]...)
+		}
+	}
+	return output
+}
+
+// CMVNStats 倒谱均值方差归一化统计量，可跨多条语音累计
+type CMVNStats struct {
+	Sum   []float64 // 各维度累加和
+	SumSq []float64 // 各维度平方累加和
+	Count int64     // 累计帧数
+}
+
+// Accumulate 累加一条语音的特征
+//
+// # Params:
+//
+//	features: 特征矩阵 [帧][维度]
+func (s *CMVNStats) Accumulate(features [][]float32) {
+	for _, row := range features {
+		if s.Sum == nil {
+			s.Sum = make([]float64, len(row))
+			s.SumSq = make([]float64, len(row))
+		}
+		dim := len(row)
+		if dim > len(s.Sum) {
+			dim = len(s.Sum)
+		}
+		for j := 0; j < dim; j++ {
+			v := float64(row[j])
+			s.Sum[j] += v
+			s.SumSq[j] += v * v
+		}
+		s.Count++
+	}
+}
+
+// NegMeanInvStd 计算负均值与逆标准差向量，可直接用于 ApplyCMVN
+func (s *CMVNStats) NegMeanInvStd() (negMean []float32, invStd []float32) {
+	dim := len(s.Sum)
+	negMean = make([]float32, dim)
+	invStd = make([]float32, dim)
+	if s.Count == 0 {
+		for j := range invStd {
+			invStd[j] = 1
+		}
+		return negMean, invStd
+	}
+
+	count := float64(s.Count)
+	for j := 0; j < dim; j++ {
+		mean := s.Sum[j] / count
+		variance := s.SumSq[j]/count - mean*mean
+		// 方差下限，避免除零
+		if variance < 1e-10 {
+			variance = 1e-10
+		}
+		negMean[j] = float32(-mean)
+		invStd[j] = float32(1 / math.Sqrt(variance))
+	}
+	return negMean, invStd
+}
+
+// padReflect 两端反射补齐，信号过短时补零
+func padReflect(samples []float32, pad int) []float32 {
+	n := len(samples)
+	output := make([]float32, n+2*pad)
+	copy(output[pad:], samples)
+	if n <= pad {
+		return output
+	}
+	for i := 0; i < pad; i++ {
+		output[pad-1-i] = samples[i+1]
+		output[pad+n+i] = samples[n-2-i]
+	}
+	return output
+}
@@ -0,0 +1,82 @@
+package mediautil
+
+import (
+	"math"
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+// sineWave 生成测试用正弦波
+func sineWave(freq float64, sampleRate, n int) []float32 {
+	samples := make([]float32, n)
+	for i := range samples {
+		samples[i] = float32(0.5 * math.Sin(2*math.Pi*freq*float64(i)/float64(sampleRate)))
+	}
+	return samples
+}
+
+func TestFeatureExtractorFrames(t *testing.T) {
+	samples := sineWave(440, SampleRate16K, SampleRate16K)
+
+	kaldi := NewFeatureExtractor(FeatureStyleKaldi, SampleRate16K)
+	frames, err := kaldi.Frames(samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, len(frames), 1+(SampleRate16K-400)/160)
+	testutil.Equal(t, len(frames[0]), 512)
+
+	librosa := NewFeatureExtractor(FeatureStyleLibrosa, SampleRate16K)
+	frames, err = librosa.Frames(samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, len(frames), 1+SampleRate16K/160)
+}
+
+func TestFeatureExtractorMFCC(t *testing.T) {
+	fe := NewFeatureExtractor(FeatureStyleKaldi, SampleRate16K)
+	fe.Dither = 0
+	mfcc, err := fe.MFCC(sineWave(440, SampleRate16K, SampleRate16K))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, len(mfcc[0]), 13)
+
+	fe.NFFT = 256
+	_, err = fe.MFCC(nil)
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestFeatureExtractorLogMel(t *testing.T) {
+	fe := NewFeatureExtractor(FeatureStyleLibrosa, SampleRate16K)
+	logMel, err := fe.LogMel(sineWave(1000, SampleRate16K, 8000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, len(logMel[0]), 80)
+}
+
+func TestDCT(t *testing.T) {
+	// 常数输入只有直流分量: sqrt(N) * v
+	testutil.EqualFloat(t, DCT([]float32{1, 1, 1, 1}, 3), []float32{2, 0, 0})
+}
+
+func TestComputeDeltas(t *testing.T) {
+	features := [][]float32{{0}, {1}, {2}, {3}, {4}, {5}}
+	deltas := ComputeDeltas(features, 2)
+	testutil.EqualFloat(t, deltas[2], []float32{1})
+	testutil.Equal(t, len(AppendDeltas(features, 2, 2)[0]), 3)
+}
+
+func TestCMVNStats(t *testing.T) {
+	features := [][]float32{{1, 10}, {3, 20}}
+	stats := new(CMVNStats)
+	stats.Accumulate(features)
+	negMean, invStd := stats.NegMeanInvStd()
+	testutil.EqualFloat(t, negMean, []float32{-2, -15})
+
+	ApplyCMVN(features, negMean, invStd)
+	testutil.EqualFloat(t, features[0], []float32{-1, -1})
+}
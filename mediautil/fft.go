@@ -0,0 +1,245 @@
+package mediautil
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+
+	"github.com/up-zero/gotool"
+)
+
+// IFFT 快速傅里叶逆变换，频域转时域
+//
+// 公式: x[n] = conj(FFT(conj(X)))[n] / N
+//
+// # Params:
+//
+//	x: 频域数据
+func IFFT(x []complex128) []complex128 {
+	n := len(x)
+	if n == 0 {
+		return []complex128{}
+	}
+	buf := make([]complex128, n)
+	for i, v := range x {
+		buf[i] = cmplx.Conj(v)
+	}
+	buf = FFT(buf)
+	scale := 1.0 / float64(n)
+	for i, v := range buf {
+		buf[i] = complex(real(v)*scale, -imag(v)*scale)
+	}
+	return buf
+}
+
+// RFFT 实数输入的快速傅里叶变换，仅返回非负频率部分 (N/2+1 个频点)
+//
+// 偶数长度时将实数序列打包为 N/2 点复数序列计算，计算量约为 FFT 的一半
+//
+// # Params:
+//
+//	x: 实数时域数据
+func RFFT(x []float32) []complex128 {
+	n := len(x)
+	if n == 0 {
+		return []complex128{}
+	}
+
+	// 奇数长度: 直接使用复数 FFT
+	if n%2 != 0 {
+		buf := make([]complex128, n)
+		for i, v := range x {
+			buf[i] = complex(float64(v), 0)
+		}
+		return FFT(buf)[:n/2+1]
+	}
+
+	// 偶数长度: z[k] = x[2k] + i * x[2k+1]
+	half := n / 2
+	z := make([]complex128, half)
+	for k := 0; k < half; k++ {
+		z[k] = complex(float64(x[2*k]), float64(x[2*k+1]))
+	}
+	z = FFT(z)
+
+	// 拆分奇偶部分并合并:
+	// X[k] = E[k] + W^k * O[k], E[k] = (Z[k] + conj(Z[N/2-k])) / 2, O[k] = -i * (Z[k] - conj(Z[N/2-k])) / 2
+	output := make([]complex128, half+1)
+	for k := 0; k <= half; k++ {
+		zk := z[k%half]
+		zc := cmplx.Conj(z[(half-k)%half])
+		even := (zk + zc) / 2
+		odd := complex(0, -0.5) * (zk - zc)
+		angle := -2 * math.Pi * float64(k) / float64(n)
+		output[k] = even + cmplx.Exp(complex(0, angle))*odd
+	}
+	return output
+}
+
+// IRFFT RFFT 的逆变换，由非负频率部分恢复实数时域数据
+//
+// # Params:
+//
+//	x: 非负频率部分 (至少 n/2+1 个频点)
+//	n: 输出长度
+func IRFFT(x []complex128, n int) []float32 {
+	if n <= 0 || len(x) < n/2+1 {
+		return []float32{}
+	}
+
+	// 按共轭对称补全完整频谱
+	full := make([]complex128, n)
+	copy(full, x[:n/2+1])
+	for k := n/2 + 1; k < n; k++ {
+		full[k] = cmplx.Conj(x[n-k])
+	}
+	// 直流和 Nyquist 频点必须为实数
+	full[0] = complex(real(full[0]), 0)
+	if n%2 == 0 {
+		full[n/2] = complex(real(full[n/2]), 0)
+	}
+
+	timeDomain := IFFT(full)
+	output := make([]float32, n)
+	for i, v := range timeDomain {
+		output[i] = float32(real(v))
+	}
+	return output
+}
+
+// bluestein Bluestein (chirp-z) 算法，计算任意长度的 DFT
+//
+// 将 DFT 改写为卷积: X[k] = w[k] * Σ (x[n] * w[n]) * conj(w[k-n]), w[n] = exp(-iπn²/N)
+func bluestein(x []complex128) []complex128 {
+	n := len(x)
+	m := 1
+	for m < 2*n-1 {
+		m <<= 1
+	}
+
+	// chirp 序列, n² 对 2N 取模以保证大 n 时的精度
+	w := make([]complex128, n)
+	for k := 0; k < n; k++ {
+		kk := (k * k) % (2 * n)
+		w[k] = cmplx.Exp(complex(0, -math.Pi*float64(kk)/float64(n)))
+	}
+
+	a := make([]complex128, m)
+	b := make([]complex128, m)
+	for k := 0; k < n; k++ {
+		a[k] = x[k] * w[k]
+	}
+	b[0] = cmplx.Conj(w[0])
+	for k := 1; k < n; k++ {
+		b[k] = cmplx.Conj(w[k])
+		b[m-k] = b[k]
+	}
+
+	// 利用 2 的幂长度的 FFT 计算循环卷积
+	fa := FFT(a)
+	fb := FFT(b)
+	for i := range fa {
+		fa[i] *= fb[i]
+	}
+	conv := IFFT(fa)
+
+	output := make([]complex128, n)
+	for k := 0; k < n; k++ {
+		output[k] = conv[k] * w[k]
+	}
+	return output
+}
+
+// STFT 短时傅里叶变换
+//
+// 帧以采样点为中心 (两端各反射补齐 nfft/2)，每帧返回 nfft/2+1 个频点
+//
+// # Params:
+//
+//	samples: 单声道音频数据
+//	nfft: 帧长及 FFT 点数
+//	hopLength: 帧移 (采样点数)
+//	windowType: 窗函数类型, 参考 GetWindow
+func STFT(samples []float32, nfft, hopLength int, windowType string) ([][]complex128, error) {
+	if nfft <= 0 || hopLength <= 0 {
+		return nil, fmt.Errorf("%w, nfft=%d, hop=%d", gotool.ErrInvalidParam, nfft, hopLength)
+	}
+	window, err := GetWindow(windowType, nfft)
+	if err != nil {
+		return nil, err
+	}
+
+	padded := padReflect(samples, nfft/2)
+	if len(padded) < nfft {
+		return [][]complex128{}, nil
+	}
+	numFrames := 1 + (len(padded)-nfft)/hopLength
+
+	spec := make([][]complex128, numFrames)
+	frame := make([]float32, nfft)
+	for i := 0; i < numFrames; i++ {
+		start := i * hopLength
+		for j := 0; j < nfft; j++ {
+			frame[j] = padded[start+j] * window[j]
+		}
+		spec[i] = RFFT(frame)
+	}
+	return spec, nil
+}
+
+// ISTFT 短时傅里叶逆变换，使用加权重叠相加 (WOLA) 重建时域信号
+//
+// 参数需与 STFT 保持一致
+//
+// # Params:
+//
+//	spec: STFT 结果 [帧][频点]
+//	nfft: 帧长及 FFT 点数
+//	hopLength: 帧移 (采样点数)
+//	windowType: 窗函数类型, 参考 GetWindow
+//	length: 输出长度, 0 表示按帧数推算
+func ISTFT(spec [][]complex128, nfft, hopLength int, windowType string, length int) ([]float32, error) {
+	if nfft <= 0 || hopLength <= 0 {
+		return nil, fmt.Errorf("%w, nfft=%d, hop=%d", gotool.ErrInvalidParam, nfft, hopLength)
+	}
+	window, err := GetWindow(windowType, nfft)
+	if err != nil {
+		return nil, err
+	}
+	if len(spec) == 0 {
+		return []float32{}, nil
+	}
+
+	total := nfft + (len(spec)-1)*hopLength
+	output := make([]float64, total)
+	norm := make([]float64, total)
+	for i, bins := range spec {
+		if len(bins) < nfft/2+1 {
+			return nil, fmt.Errorf("%w, frame %d has %d bins, want %d", gotool.ErrInvalidParam, i, len(bins), nfft/2+1)
+		}
+		frame := IRFFT(bins, nfft)
+		start := i * hopLength
+		for j := 0; j < nfft; j++ {
+			w := float64(window[j])
+			output[start+j] += float64(frame[j]) * w
+			norm[start+j] += w * w
+		}
+	}
+
+	// 去除居中补齐部分
+	pad := nfft / 2
+	if length <= 0 {
+		length = total - 2*pad
+	}
+	result := make([]float32, length)
+	for i := 0; i < length; i++ {
+		idx := i + pad
+		if idx >= total {
+			break
+		}
+		if norm[idx] > 1e-10 {
+			result[i] = float32(output[idx] / norm[idx])
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,67 @@
+package mediautil
+
+import (
+	"math/cmplx"
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+// dft 朴素 DFT, 用于校验
+func dft(x []complex128) []complex128 {
+	n := len(x)
+	output := make([]complex128, n)
+	for k := 0; k < n; k++ {
+		for j := 0; j < n; j++ {
+			output[k] += x[j] * cmplx.Rect(1, -2*3.141592653589793*float64(j*k)/float64(n))
+		}
+	}
+	return output
+}
+
+func TestFFTBluestein(t *testing.T) {
+	x := []complex128{1, 2, 3, 4, 5, 6, 7}
+	got := FFT(x)
+	want := dft(x)
+	for i := range want {
+		testutil.EqualFloat(t, cmplx.Abs(got[i]-want[i]), 0.0, 1e-9)
+	}
+}
+
+func TestIFFT(t *testing.T) {
+	x := []complex128{1, -2, 3, 0.5, 2, 1}
+	got := IFFT(FFT(x))
+	for i := range x {
+		testutil.EqualFloat(t, cmplx.Abs(got[i]-x[i]), 0.0, 1e-9)
+	}
+}
+
+func TestRFFT(t *testing.T) {
+	x := []float32{1, 2, 0, -1, 3, 0.5, -2, 1}
+	full := make([]complex128, len(x))
+	for i, v := range x {
+		full[i] = complex(float64(v), 0)
+	}
+	want := FFT(full)
+	got := RFFT(x)
+	testutil.Equal(t, len(got), 5)
+	for i := range got {
+		testutil.EqualFloat(t, cmplx.Abs(got[i]-want[i]), 0.0, 1e-6)
+	}
+	testutil.EqualFloat(t, IRFFT(got, len(x)), x)
+}
+
+func TestSTFT(t *testing.T) {
+	samples := sineWave(440, SampleRate16K, 4000)
+	spec, err := STFT(samples, 512, 128, WindowHann)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, len(spec[0]), 257)
+
+	restored, err := ISTFT(spec, 512, 128, WindowHann, len(samples))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.EqualFloat(t, restored, samples, 1e-4)
+}
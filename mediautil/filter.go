@@ -0,0 +1,436 @@
+package mediautil
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+
+	"github.com/up-zero/gotool"
+)
+
+const (
+	// FilterLowPass 低通
+	FilterLowPass = "lowpass"
+	// FilterHighPass 高通
+	FilterHighPass = "highpass"
+	// FilterBandPass 带通 (峰值增益 0dB)
+	FilterBandPass = "bandpass"
+	// FilterBandStop 带阻
+	FilterBandStop = "bandstop"
+	// FilterNotch 陷波
+	FilterNotch = "notch"
+	// FilterPeaking 峰值均衡
+	FilterPeaking = "peaking"
+	// FilterLowShelf 低频搁架
+	FilterLowShelf = "lowshelf"
+	// FilterHighShelf 高频搁架
+	FilterHighShelf = "highshelf"
+)
+
+// Filter 有状态的音频滤波器，状态在多次 Process 调用之间保留，适用于流式处理
+type Filter interface {
+	// Process 处理一段音频数据 (多声道时为交错排列)，返回新的切片
+	Process(samples []float32) []float32
+	// Reset 清空内部状态
+	Reset()
+}
+
+// BiquadCoeffs 二阶 IIR 滤波器系数 (a0 已归一化为 1)
+//
+// 传递函数: H(z) = (B0 + B1*z^-1 + B2*z^-2) / (1 + A1*z^-1 + A2*z^-2)
+type BiquadCoeffs struct {
+	B0, B1, B2 float64
+	A1, A2     float64
+}
+
+// DesignBiquad 按 RBJ Audio EQ Cookbook 设计二阶滤波器
+//
+// # Params:
+//
+//	filterType: 滤波器类型, 可选值为 FilterLowPass、FilterHighPass、FilterBandPass、FilterNotch、FilterPeaking、FilterLowShelf、FilterHighShelf
+//	sampleRate: 采样率
+//	freq: 截止频率或中心频率 (Hz)
+//	q: 品质因数, 例如 0.7071 (巴特沃斯)
+//	gainDB: 增益 (dB), 仅 FilterPeaking、FilterLowShelf、FilterHighShelf 使用
+func DesignBiquad(filterType string, sampleRate, freq, q, gainDB float64) (BiquadCoeffs, error) {
+	if sampleRate <= 0 || freq <= 0 || freq >= sampleRate/2 || q <= 0 {
+		return BiquadCoeffs{}, fmt.Errorf("%w, rate=%v, freq=%v, q=%v", gotool.ErrInvalidParam, sampleRate, freq, q)
+	}
+
+	w0 := 2 * math.Pi * freq / sampleRate
+	cosW := math.Cos(w0)
+	alpha := math.Sin(w0) / (2 * q)
+	a := math.Pow(10, gainDB/40)
+	sqrtA2Alpha := 2 * math.Sqrt(a) * alpha
+
+	var b0, b1, b2, a0, a1, a2 float64
+	switch filterType {
+	case FilterLowPass:
+		b0, b1, b2 = (1-cosW)/2, 1-cosW, (1-cosW)/2
+		a0, a1, a2 = 1+alpha, -2*cosW, 1-alpha
+	case FilterHighPass:
+		b0, b1, b2 = (1+cosW)/2, -(1 + cosW), (1+cosW)/2
+		a0, a1, a2 = 1+alpha, -2*cosW, 1-alpha
+	case FilterBandPass:
+		b0, b1, b2 = alpha, 0, -alpha
+		a0, a1, a2 = 1+alpha, -2*cosW, 1-alpha
+	case FilterNotch:
+		b0, b1, b2 = 1, -2*cosW, 1
+		a0, a1, a2 = 1+alpha, -2*cosW, 1-alpha
+	case FilterPeaking:
+		b0, b1, b2 = 1+alpha*a, -2*cosW, 1-alpha*a
+		a0, a1, a2 = 1+alpha/a, -2*cosW, 1-alpha/a
+	case FilterLowShelf:
+		b0 = a * ((a + 1) - (a-1)*cosW + sqrtA2Alpha)
+		b1 = 2 * a * ((a - 1) - (a+1)*cosW)
+		b2 = a * ((a + 1) - (a-1)*cosW - sqrtA2Alpha)
+		a0 = (a + 1) + (a-1)*cosW + sqrtA2Alpha
+		a1 = -2 * ((a - 1) + (a+1)*cosW)
+		a2 = (a + 1) + (a-1)*cosW - sqrtA2Alpha
+	case FilterHighShelf:
+		b0 = a * ((a + 1) + (a-1)*cosW + sqrtA2Alpha)
+		b1 = -2 * a * ((a - 1) + (a+1)*cosW)
+		b2 = a * ((a + 1) + (a-1)*cosW - sqrtA2Alpha)
+		a0 = (a + 1) - (a-1)*cosW + sqrtA2Alpha
+		a1 = 2 * ((a - 1) - (a+1)*cosW)
+		a2 = (a + 1) - (a-1)*cosW - sqrtA2Alpha
+	default:
+		return BiquadCoeffs{}, fmt.Errorf("%w, unsupported biquad type: %s", gotool.ErrInvalidParam, filterType)
+	}
+
+	return BiquadCoeffs{
+		B0: b0 / a0, B1: b1 / a0, B2: b2 / a0,
+		A1: a1 / a0, A2: a2 / a0,
+	}, nil
+}
+
+// Response 计算指定频率处的幅度响应 (线性增益)
+//
+// # Params:
+//
+//	freq: 频率 (Hz)
+//	sampleRate: 采样率
+func (c BiquadCoeffs) Response(freq, sampleRate float64) float64 {
+	z1 := cmplx.Exp(complex(0, -2*math.Pi*freq/sampleRate))
+	z2 := z1 * z1
+	num := complex(c.B0, 0) + complex(c.B1, 0)*z1 + complex(c.B2, 0)*z2
+	den := 1 + complex(c.A1, 0)*z1 + complex(c.A2, 0)*z2
+	return cmplx.Abs(num / den)
+}
+
+// apply 对单声道 float64 数据做一次性滤波 (Direct Form I)
+func (c BiquadCoeffs) apply(x []float64) []float64 {
+	y := make([]float64, len(x))
+	var x1, x2, y1, y2 float64
+	for i, v := range x {
+		out := c.B0*v + c.B1*x1 + c.B2*x2 - c.A1*y1 - c.A2*y2
+		x2, x1 = x1, v
+		y2, y1 = y1, out
+		y[i] = out
+	}
+	return y
+}
+
+// Biquad 二阶 IIR 滤波器，按声道分别保存状态 (Transposed Direct Form II)
+type Biquad struct {
+	Coeffs   BiquadCoeffs
+	channels int
+	z1, z2   []float64
+}
+
+// NewBiquad 创建二阶 IIR 滤波器
+//
+// # Params:
+//
+//	coeffs: 滤波器系数, 可通过 DesignBiquad 生成
+//	channels: 声道数
+func NewBiquad(coeffs BiquadCoeffs, channels int) *Biquad {
+	if channels <= 0 {
+		channels = 1
+	}
+	return &Biquad{
+		Coeffs:   coeffs,
+		channels: channels,
+		z1:       make([]float64, channels),
+		z2:       make([]float64, channels),
+	}
+}
+
+// Process 处理一段音频数据 (多声道时为交错排列)
+func (f *Biquad) Process(samples []float32) []float32 {
+	c := f.Coeffs
+	output := make([]float32, len(samples))
+	for i, v := range samples {
+		ch := i % f.channels
+		x := float64(v)
+		y := c.B0*x + f.z1[ch]
+		f.z1[ch] = c.B1*x - c.A1*y + f.z2[ch]
+		f.z2[ch] = c.B2*x - c.A2*y
+		output[i] = float32(y)
+	}
+	return output
+}
+
+// Reset 清空内部状态
+func (f *Biquad) Reset() {
+	for i := range f.z1 {
+		f.z1[i] = 0
+		f.z2[i] = 0
+	}
+}
+
+// FilterChain 串联多个滤波器
+type FilterChain struct {
+	Filters []Filter
+}
+
+// NewFilterChain 创建串联滤波器
+//
+// # Params:
+//
+//	filters: 按顺序串联的滤波器
+func NewFilterChain(filters ...Filter) *FilterChain {
+	return &FilterChain{Filters: filters}
+}
+
+// Process 依次通过所有滤波器
+func (fc *FilterChain) Process(samples []float32) []float32 {
+	output := samples
+	for _, f := range fc.Filters {
+		output = f.Process(output)
+	}
+	if len(fc.Filters) == 0 {
+		output = append([]float32{}, samples...)
+	}
+	return output
+}
+
+// Reset 清空所有滤波器的内部状态
+func (fc *FilterChain) Reset() {
+	for _, f := range fc.Filters {
+		f.Reset()
+	}
+}
+
+// NewButterworth 创建任意阶数的巴特沃斯滤波器 (由二阶节级联而成, 奇数阶附加一个一阶节)
+//
+// # Params:
+//
+//	filterType: 滤波器类型, 可选值为 FilterLowPass、FilterHighPass
+//	order: 阶数
+//	sampleRate: 采样率
+//	cutoff: 截止频率 (Hz), 该频率处衰减 3dB
+//	channels: 声道数
+func NewButterworth(filterType string, order int, sampleRate, cutoff float64, channels int) (*FilterChain, error) {
+	if order <= 0 {
+		return nil, fmt.Errorf("%w, order must be positive", gotool.ErrInvalidParam)
+	}
+	if filterType != FilterLowPass && filterType != FilterHighPass {
+		return nil, fmt.Errorf("%w, butterworth only supports lowpass and highpass", gotool.ErrInvalidParam)
+	}
+
+	chain := NewFilterChain()
+	// 极点对与负实轴的夹角 ψ = π(n-1-2k)/(2n), 对应 Q = 1 / (2cos(ψ))
+	for k := 0; k < order/2; k++ {
+		psi := math.Pi * float64(order-1-2*k) / float64(2*order)
+		coeffs, err := DesignBiquad(filterType, sampleRate, cutoff, 1/(2*math.Cos(psi)), 0)
+		if err != nil {
+			return nil, err
+		}
+		chain.Filters = append(chain.Filters, NewBiquad(coeffs, channels))
+	}
+
+	// 奇数阶: 双线性变换得到的一阶节
+	if order%2 == 1 {
+		k := math.Tan(math.Pi * cutoff / sampleRate)
+		coeffs := BiquadCoeffs{A1: (k - 1) / (k + 1)}
+		if filterType == FilterLowPass {
+			coeffs.B0, coeffs.B1 = k/(1+k), k/(1+k)
+		} else {
+			coeffs.B0, coeffs.B1 = 1/(1+k), -1/(1+k)
+		}
+		chain.Filters = append(chain.Filters, NewBiquad(coeffs, channels))
+	}
+	return chain, nil
+}
+
+// DesignFIR 使用窗函数法 (加窗 sinc) 设计线性相位 FIR 滤波器
+//
+// # Params:
+//
+//	filterType: 滤波器类型, 可选值为 FilterLowPass、FilterHighPass、FilterBandPass、FilterBandStop
+//	numTaps: 抽头数, 高通和带阻必须为奇数
+//	sampleRate: 采样率
+//	low: 截止频率 (Hz), 带通/带阻时为下限频率
+//	high: 带通/带阻时的上限频率 (Hz), 其余类型忽略
+//	windowType: 窗函数类型, 参考 GetWindow, 例如 WindowHamming、WindowHann
+func DesignFIR(filterType string, numTaps int, sampleRate, low, high float64, windowType string) ([]float64, error) {
+	if numTaps <= 0 || sampleRate <= 0 || low <= 0 || low >= sampleRate/2 {
+		return nil, fmt.Errorf("%w, taps=%d, rate=%v, low=%v", gotool.ErrInvalidParam, numTaps, sampleRate, low)
+	}
+	if (filterType == FilterHighPass || filterType == FilterBandStop) && numTaps%2 == 0 {
+		return nil, fmt.Errorf("%w, %s requires an odd number of taps", gotool.ErrInvalidParam, filterType)
+	}
+	if (filterType == FilterBandPass || filterType == FilterBandStop) && (high <= low || high >= sampleRate/2) {
+		return nil, fmt.Errorf("%w, invalid band [%v, %v]", gotool.ErrInvalidParam, low, high)
+	}
+	window, err := symmetricWindow(windowType, numTaps)
+	if err != nil {
+		return nil, err
+	}
+
+	// 理想低通的冲激响应: 2fc * sinc(2fc * (n - M/2)), fc 为归一化频率
+	lowPass := func(cutoff float64) []float64 {
+		fc := cutoff / sampleRate
+		h := make([]float64, numTaps)
+		mid := float64(numTaps-1) / 2
+		for i := range h {
+			x := float64(i) - mid
+			if x == 0 {
+				h[i] = 2 * fc
+			} else {
+				h[i] = math.Sin(2*math.Pi*fc*x) / (math.Pi * x)
+			}
+		}
+		return h
+	}
+	// 谱反转: δ[n - M/2] - h[n]
+	invert := func(h []float64) []float64 {
+		for i := range h {
+			h[i] = -h[i]
+		}
+		h[numTaps/2] += 1
+		return h
+	}
+
+	var taps []float64
+	switch filterType {
+	case FilterLowPass:
+		taps = lowPass(low)
+	case FilterHighPass:
+		taps = invert(lowPass(low))
+	case FilterBandPass:
+		lp1, lp2 := lowPass(high), lowPass(low)
+		taps = make([]float64, numTaps)
+		for i := range taps {
+			taps[i] = lp1[i] - lp2[i]
+		}
+	case FilterBandStop:
+		lp1, lp2 := lowPass(high), lowPass(low)
+		taps = make([]float64, numTaps)
+		for i := range taps {
+			taps[i] = lp1[i] - lp2[i]
+		}
+		taps = invert(taps)
+	default:
+		return nil, fmt.Errorf("%w, unsupported fir type: %s", gotool.ErrInvalidParam, filterType)
+	}
+
+	for i := range taps {
+		taps[i] *= float64(window[i])
+	}
+
+	// 通带增益归一化
+	var refFreq float64
+	switch filterType {
+	case FilterHighPass:
+		refFreq = sampleRate / 2
+	case FilterBandPass:
+		refFreq = (low + high) / 2
+	}
+	gain := firResponse(taps, refFreq, sampleRate)
+	if gain > 0 {
+		for i := range taps {
+			taps[i] /= gain
+		}
+	}
+	return taps, nil
+}
+
+// firResponse 计算 FIR 滤波器在指定频率处的幅度响应
+func firResponse(taps []float64, freq, sampleRate float64) float64 {
+	var sum complex128
+	for i, h := range taps {
+		sum += complex(h, 0) * cmplx.Exp(complex(0, -2*math.Pi*freq/sampleRate*float64(i)))
+	}
+	return cmplx.Abs(sum)
+}
+
+// symmetricWindow 生成对称窗, 保证 FIR 的线性相位
+//
+// HannWindow 为周期窗, 长度 N 的对称 Hann 窗等价于长度 N-1 的周期窗末尾补 0
+func symmetricWindow(windowType string, size int) ([]float32, error) {
+	if windowType == WindowHann && size > 2 {
+		return append(HannWindow(size-1), 0), nil
+	}
+	return GetWindow(windowType, size)
+}
+
+// FIRFilter 有限冲激响应滤波器，按声道分别保存历史数据
+type FIRFilter struct {
+	Taps     []float64
+	channels int
+	history  [][]float64
+	pos      []int
+}
+
+// NewFIRFilter 创建 FIR 滤波器
+//
+// # Params:
+//
+//	taps: 滤波器系数, 可通过 DesignFIR 生成
+//	channels: 声道数
+func NewFIRFilter(taps []float64, channels int) *FIRFilter {
+	if channels <= 0 {
+		channels = 1
+	}
+	f := &FIRFilter{
+		Taps:     taps,
+		channels: channels,
+		history:  make([][]float64, channels),
+		pos:      make([]int, channels),
+	}
+	for c := range f.history {
+		f.history[c] = make([]float64, len(taps))
+	}
+	return f
+}
+
+// Process 处理一段音频数据 (多声道时为交错排列)
+func (f *FIRFilter) Process(samples []float32) []float32 {
+	n := len(f.Taps)
+	output := make([]float32, len(samples))
+	if n == 0 {
+		return output
+	}
+	for i, v := range samples {
+		ch := i % f.channels
+		hist := f.history[ch]
+		// 环形缓冲区写入最新采样点
+		pos := f.pos[ch]
+		hist[pos] = float64(v)
+
+		var acc float64
+		idx := pos
+		for _, h := range f.Taps {
+			acc += h * hist[idx]
+			idx--
+			if idx < 0 {
+				idx = n - 1
+			}
+		}
+		f.pos[ch] = (pos + 1) % n
+		output[i] = float32(acc)
+	}
+	return output
+}
+
+// Reset 清空内部状态
+func (f *FIRFilter) Reset() {
+	for c := range f.history {
+		for i := range f.history[c] {
+			f.history[c][i] = 0
+		}
+		f.pos[c] = 0
+	}
+}
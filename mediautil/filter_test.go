@@ -0,0 +1,89 @@
+package mediautil
+
+import (
+	"math"
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestDesignBiquad(t *testing.T) {
+	lp, err := DesignBiquad(FilterLowPass, SampleRate16K, 1000, 1/math.Sqrt2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.EqualFloat(t, lp.Response(0, SampleRate16K), 1.0, 1e-9)
+	testutil.EqualFloat(t, lp.Response(1000, SampleRate16K), 1/math.Sqrt2, 1e-9)
+
+	notch, _ := DesignBiquad(FilterNotch, SampleRate16K, 50, 10, 0)
+	testutil.EqualFloat(t, notch.Response(50, SampleRate16K), 0.0, 1e-9)
+
+	peak, _ := DesignBiquad(FilterPeaking, SampleRate16K, 2000, 1, 6)
+	testutil.EqualFloat(t, GainToDb(peak.Response(2000, SampleRate16K)), 6.0, 1e-9)
+
+	shelf, _ := DesignBiquad(FilterHighShelf, SampleRate16K, 2000, 0.7071, -6)
+	testutil.EqualFloat(t, GainToDb(shelf.Response(7999, SampleRate16K)), -6.0, 0.05)
+
+	_, err = DesignBiquad("unknown", SampleRate16K, 1000, 1, 0)
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestBiquadProcess(t *testing.T) {
+	coeffs, _ := DesignBiquad(FilterLowPass, SampleRate16K, 500, 0.7071, 0)
+	samples := sineWave(4000, SampleRate16K, SampleRate16K)
+
+	// 分块处理与整体处理的结果一致
+	whole := NewBiquad(coeffs, 1).Process(samples)
+	stream := NewBiquad(coeffs, 1)
+	var chunked []float32
+	for i := 0; i < len(samples); i += 1000 {
+		chunked = append(chunked, stream.Process(samples[i:i+1000])...)
+	}
+	testutil.EqualFloat(t, chunked, whole)
+
+	// 4kHz 信号被 500Hz 低通大幅衰减
+	testutil.Equal(t, RMSLevel(whole[1000:]) < RMSLevel(samples)-30, true)
+}
+
+func TestNewButterworth(t *testing.T) {
+	for _, order := range []int{1, 2, 3, 4, 5} {
+		chain, err := NewButterworth(FilterLowPass, order, SampleRate16K, 1000, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 截止频率处衰减 3dB
+		gain := 1.0
+		for _, f := range chain.Filters {
+			gain *= f.(*Biquad).Coeffs.Response(1000, SampleRate16K)
+		}
+		testutil.EqualFloat(t, gain, 1/math.Sqrt2, 1e-9)
+	}
+}
+
+func TestDesignFIR(t *testing.T) {
+	taps, err := DesignFIR(FilterLowPass, 101, SampleRate16K, 1000, 0, WindowHamming)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.EqualFloat(t, firResponse(taps, 0, SampleRate16K), 1.0, 1e-9)
+	testutil.Equal(t, firResponse(taps, 3000, SampleRate16K) < 0.01, true)
+	// 线性相位: 系数对称
+	testutil.EqualFloat(t, taps[0], taps[100], 1e-12)
+
+	taps, err = DesignFIR(FilterBandPass, 101, SampleRate16K, 1000, 2000, WindowHann)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.EqualFloat(t, taps[10], taps[90], 1e-12)
+
+	_, err = DesignFIR(FilterHighPass, 100, SampleRate16K, 1000, 0, WindowHamming)
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestFIRFilterProcess(t *testing.T) {
+	f := NewFIRFilter([]float64{0.5, 0.5}, 2)
+	testutil.EqualFloat(t, f.Process([]float32{1, 2, 3, 4}), []float32{0.5, 1, 2, 3})
+	testutil.EqualFloat(t, f.Process([]float32{5, 6}), []float32{4, 5})
+	f.Reset()
+	testutil.EqualFloat(t, f.Process([]float32{1, 2}), []float32{0.5, 1})
+}
@@ -0,0 +1,741 @@
+package mediautil
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/up-zero/gotool"
+)
+
+// ErrNotFlacFile 不是有效的 FLAC 文件
+var ErrNotFlacFile = errors.New("not a valid FLAC file")
+
+// FLAC 元数据块类型
+const (
+	flacBlockStreamInfo    = 0
+	flacBlockVorbisComment = 4
+)
+
+// FLAC 声道分配
+const (
+	flacChannelLeftSide  = 8
+	flacChannelSideRight = 9
+	flacChannelMidSide   = 10
+)
+
+// flacBlockSizes 帧头块大小编码表 (0 表示需要额外字段)
+var flacBlockSizes = [16]int{0, 192, 576, 1152, 2304, 4608, 0, 0, 256, 512, 1024, 2048, 4096, 8192, 16384, 32768}
+
+// flacSampleRates 帧头采样率编码表 (0 表示需要额外字段或从 STREAMINFO 获取)
+var flacSampleRates = [16]int{0, 88200, 176400, 192000, 8000, 16000, 22050, 24000, 32000, 44100, 48000, 96000, 0, 0, 0, 0}
+
+// flacSampleSizes 帧头位深编码表 (0 表示从 STREAMINFO 获取或保留)
+var flacSampleSizes = [8]int{0, 8, 12, 0, 16, 20, 24, 32}
+
+// FlacInfo FLAC 流信息 (STREAMINFO 与 VORBIS_COMMENT)
+type FlacInfo struct {
+	MinBlockSize  int               // 最小块大小 (采样帧)
+	MaxBlockSize  int               // 最大块大小 (采样帧)
+	MinFrameSize  int               // 最小帧大小 (字节), 0 表示未知
+	MaxFrameSize  int               // 最大帧大小 (字节), 0 表示未知
+	SampleRate    int               // 采样率
+	Channels      int               // 声道数
+	BitsPerSample int               // 位深
+	TotalSamples  int64             // 每声道采样帧数, 0 表示未知
+	MD5           [16]byte          // 原始采样数据的 MD5, 全 0 表示未计算
+	Vendor        string            // 编码器标识
+	Comments      map[string]string // 标签, 键为大写, 例如 TITLE, ARTIST
+}
+
+// GetDuration 根据流信息计算音频时长
+func (f *FlacInfo) GetDuration() time.Duration {
+	if f.SampleRate == 0 {
+		return 0
+	}
+	return samplesToDuration(int(f.TotalSamples), f.SampleRate)
+}
+
+// ParseFlacInfo 解析 FLAC 元数据
+//
+// # Params:
+//
+//	data: FLAC 文件数据
+func ParseFlacInfo(data []byte) (*FlacInfo, error) {
+	info, _, err := readFlacMetadata(data)
+	return info, err
+}
+
+// DecodeFlac FLAC 解码为 float32 数组
+//
+// 输出的缩放方式与 PcmBytesToFloat32 一致, 多声道时为交错排列
+//
+// # Params:
+//
+//	data: FLAC 文件数据
+func DecodeFlac(data []byte) (*FlacInfo, []float32, error) {
+	info, samples, err := decodeFlac(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	output := make([]float32, len(samples))
+	for i, v := range samples {
+		output[i] = sampleToFloat32(v, info.BitsPerSample)
+	}
+	return info, output, nil
+}
+
+// FlacToWavBytes FLAC 无损转换为 PCM WAV 文件字节流
+//
+// 位深为 16, 24, 32 以外时左移对齐到 16 或 24 bit 容器
+//
+// # Params:
+//
+//	data: FLAC 文件数据
+func FlacToWavBytes(data []byte) ([]byte, error) {
+	info, samples, err := decodeFlac(data)
+	if err != nil {
+		return nil, err
+	}
+
+	bits := info.BitsPerSample
+	container := 32
+	if bits <= 16 {
+		container = 16
+	} else if bits <= 24 {
+		container = 24
+	}
+	shift := uint(container - bits)
+	width := container / 8
+	pcm := make([]byte, len(samples)*width)
+	for i, v := range samples {
+		v <<= shift
+		for b := 0; b < width; b++ {
+			pcm[i*width+b] = byte(v >> (8 * uint(b)))
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := WriteWav(buf, pcm, info.SampleRate, info.Channels, container); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readFlacMetadata 解析 FLAC 元数据块, 返回第一个音频帧的偏移
+func readFlacMetadata(data []byte) (*FlacInfo, int, error) {
+	offset := 0
+	// 跳过 ID3v2 标签
+	if len(data) >= 10 && string(data[:3]) == "ID3" {
+		size := int(data[6]&0x7F)<<21 | int(data[7]&0x7F)<<14 | int(data[8]&0x7F)<<7 | int(data[9]&0x7F)
+		offset = 10 + size
+	}
+	if len(data) < offset+4 || string(data[offset:offset+4]) != "fLaC" {
+		return nil, 0, ErrNotFlacFile
+	}
+	offset += 4
+
+	info := &FlacInfo{Comments: map[string]string{}}
+	streamInfoFound := false
+	for last := false; !last; {
+		if offset+4 > len(data) {
+			return nil, 0, fmt.Errorf("%w, truncated metadata", ErrNotFlacFile)
+		}
+		last = data[offset]&0x80 != 0
+		blockType := int(data[offset] & 0x7F)
+		length := int(data[offset+1])<<16 | int(data[offset+2])<<8 | int(data[offset+3])
+		offset += 4
+		if offset+length > len(data) {
+			return nil, 0, fmt.Errorf("%w, truncated metadata", ErrNotFlacFile)
+		}
+		body := data[offset : offset+length]
+		offset += length
+
+		switch blockType {
+		case flacBlockStreamInfo:
+			if length < 34 {
+				return nil, 0, fmt.Errorf("%w, invalid STREAMINFO", ErrNotFlacFile)
+			}
+			r := &bitReader{data: body}
+			info.MinBlockSize = int(r.mustRead(16))
+			info.MaxBlockSize = int(r.mustRead(16))
+			info.MinFrameSize = int(r.mustRead(24))
+			info.MaxFrameSize = int(r.mustRead(24))
+			info.SampleRate = int(r.mustRead(20))
+			info.Channels = int(r.mustRead(3)) + 1
+			info.BitsPerSample = int(r.mustRead(5)) + 1
+			info.TotalSamples = int64(r.mustRead(36))
+			copy(info.MD5[:], body[18:34])
+			streamInfoFound = true
+		case flacBlockVorbisComment:
+			if err := parseVorbisComment(body, info); err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	if !streamInfoFound {
+		return nil, 0, fmt.Errorf("%w, STREAMINFO not found", ErrNotFlacFile)
+	}
+	return info, offset, nil
+}
+
+// parseVorbisComment 解析 VORBIS_COMMENT 块 (长度字段为小端序)
+func parseVorbisComment(body []byte, info *FlacInfo) error {
+	invalid := fmt.Errorf("%w, invalid VORBIS_COMMENT", ErrNotFlacFile)
+	readString := func() (string, error) {
+		if len(body) < 4 {
+			return "", invalid
+		}
+		n := int(binary.LittleEndian.Uint32(body))
+		if n < 0 || n > len(body)-4 {
+			return "", invalid
+		}
+		s := string(body[4 : 4+n])
+		body = body[4+n:]
+		return s, nil
+	}
+
+	vendor, err := readString()
+	if err != nil {
+		return err
+	}
+	info.Vendor = vendor
+	if len(body) < 4 {
+		return invalid
+	}
+	count := int(binary.LittleEndian.Uint32(body))
+	body = body[4:]
+	for i := 0; i < count; i++ {
+		comment, err := readString()
+		if err != nil {
+			return err
+		}
+		if key, value, ok := strings.Cut(comment, "="); ok {
+			info.Comments[strings.ToUpper(key)] = value
+		}
+	}
+	return nil
+}
+
+// decodeFlac FLAC 解码为交错排列的整数采样
+func decodeFlac(data []byte) (*FlacInfo, []int64, error) {
+	info, offset, err := readFlacMetadata(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.Channels <= 0 || info.BitsPerSample < 4 {
+		return nil, nil, fmt.Errorf("%w, invalid STREAMINFO", ErrNotFlacFile)
+	}
+
+	// total_samples 来自文件不可信, 预分配的容量不超过数据本身的比特数, 不足时由 append 扩容
+	capacity := min(info.TotalSamples*int64(info.Channels), int64(len(data))*8)
+	samples := make([]int64, 0, capacity)
+	for offset+2 <= len(data) {
+		// 查找帧同步码 0b11111111111110
+		if data[offset] != 0xFF || data[offset+1]&0xFE != 0xF8 {
+			offset++
+			continue
+		}
+		var n int
+		samples, n, err = decodeFlacFrame(data[offset:], info, samples)
+		if err != nil {
+			return nil, nil, err
+		}
+		offset += n
+	}
+
+	if info.TotalSamples > 0 && int64(len(samples)) > info.TotalSamples*int64(info.Channels) {
+		samples = samples[:info.TotalSamples*int64(info.Channels)]
+	}
+	if info.MD5 != [16]byte{} && flacMD5(samples, info.BitsPerSample) != info.MD5 {
+		return nil, nil, fmt.Errorf("%w, md5 mismatch", ErrNotFlacFile)
+	}
+	return info, samples, nil
+}
+
+// flacMD5 计算采样数据的 MD5 (小端序, 每个采样占 ceil(bits/8) 字节)
+func flacMD5(samples []int64, bits int) [16]byte {
+	width := (bits + 7) / 8
+	buf := make([]byte, len(samples)*width)
+	for i, v := range samples {
+		for b := 0; b < width; b++ {
+			buf[i*width+b] = byte(v >> (8 * uint(b)))
+		}
+	}
+	return md5.Sum(buf)
+}
+
+// decodeFlacFrame 解码一个音频帧, 采样追加到 output, 返回帧的字节长度
+func decodeFlacFrame(data []byte, info *FlacInfo, output []int64) ([]int64, int, error) {
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w, %s", ErrNotFlacFile, reason)
+	}
+	r := &bitReader{data: data}
+	r.mustRead(16) // 同步码, 保留位, 块策略
+	bsCode := int(r.mustRead(4))
+	srCode := int(r.mustRead(4))
+	chAssign := int(r.mustRead(4))
+	ssCode := int(r.mustRead(3))
+	r.mustRead(1)
+
+	// 帧号或采样号, UTF-8 方式编码
+	first, err := r.readBits(8)
+	if err != nil {
+		return nil, 0, invalid("truncated frame header")
+	}
+	for mask := uint64(0x40); first&0x80 != 0 && first&mask != 0 && mask > 1; mask >>= 1 {
+		if _, err := r.readBits(8); err != nil {
+			return nil, 0, invalid("truncated frame header")
+		}
+	}
+
+	blockSize := flacBlockSizes[bsCode]
+	switch bsCode {
+	case 0:
+		return nil, 0, invalid("reserved block size")
+	case 6:
+		v, err := r.readBits(8)
+		if err != nil {
+			return nil, 0, invalid("truncated frame header")
+		}
+		blockSize = int(v) + 1
+	case 7:
+		v, err := r.readBits(16)
+		if err != nil {
+			return nil, 0, invalid("truncated frame header")
+		}
+		blockSize = int(v) + 1
+	}
+	switch srCode {
+	case 12:
+		_, err = r.readBits(8)
+	case 13, 14:
+		_, err = r.readBits(16)
+	case 15:
+		return nil, 0, invalid("invalid sample rate")
+	}
+	if err != nil {
+		return nil, 0, invalid("truncated frame header")
+	}
+
+	bits := info.BitsPerSample
+	if ssCode != 0 {
+		if bits = flacSampleSizes[ssCode]; bits == 0 {
+			return nil, 0, invalid("reserved sample size")
+		}
+	}
+	channels := chAssign + 1
+	if chAssign >= flacChannelLeftSide {
+		if chAssign > flacChannelMidSide {
+			return nil, 0, invalid("reserved channel assignment")
+		}
+		channels = 2
+	}
+	if channels != info.Channels || bits != info.BitsPerSample {
+		return nil, 0, invalid("frame header does not match STREAMINFO")
+	}
+
+	headerLen := r.pos / 8
+	crc, err := r.readBits(8)
+	if err != nil || byte(crc) != crc8(data[:headerLen]) {
+		return nil, 0, invalid("frame header crc mismatch")
+	}
+
+	// 子帧, 立体声去相关时 side 声道多 1 bit
+	subframes := make([][]int64, channels)
+	for ch := range subframes {
+		chBits := bits
+		if (chAssign == flacChannelLeftSide || chAssign == flacChannelMidSide) && ch == 1 ||
+			chAssign == flacChannelSideRight && ch == 0 {
+			chBits++
+		}
+		if subframes[ch], err = decodeFlacSubframe(r, chBits, blockSize); err != nil {
+			return nil, 0, err
+		}
+	}
+	r.align()
+	frameLen := r.pos / 8
+	footer, err := r.readBits(16)
+	if err != nil || uint16(footer) != crc16(data[:frameLen]) {
+		return nil, 0, invalid("frame crc mismatch")
+	}
+
+	switch chAssign {
+	case flacChannelLeftSide:
+		for i, side := range subframes[1] {
+			subframes[1][i] = subframes[0][i] - side
+		}
+	case flacChannelSideRight:
+		for i, side := range subframes[0] {
+			subframes[0][i] = side + subframes[1][i]
+		}
+	case flacChannelMidSide:
+		for i, side := range subframes[1] {
+			mid := subframes[0][i]<<1 | side&1
+			subframes[0][i] = (mid + side) >> 1
+			subframes[1][i] = (mid - side) >> 1
+		}
+	}
+	for i := 0; i < blockSize; i++ {
+		for ch := 0; ch < channels; ch++ {
+			output = append(output, subframes[ch][i])
+		}
+	}
+	return output, frameLen + 2, nil
+}
+
+// decodeFlacSubframe 解码一个子帧
+func decodeFlacSubframe(r *bitReader, bits, blockSize int) ([]int64, error) {
+	truncated := fmt.Errorf("%w, truncated subframe", ErrNotFlacFile)
+	header, err := r.readBits(8)
+	if err != nil {
+		return nil, truncated
+	}
+	kind := int(header>>1) & 0x3F
+	wasted := 0
+	if header&1 != 0 {
+		k, err := r.readUnary()
+		if err != nil {
+			return nil, truncated
+		}
+		wasted = int(k) + 1
+		bits -= wasted
+		if bits <= 0 {
+			return nil, fmt.Errorf("%w, invalid wasted bits", ErrNotFlacFile)
+		}
+	}
+
+	samples := make([]int64, blockSize)
+	switch {
+	case kind == 0:
+		// CONSTANT
+		v, err := r.readSigned(bits)
+		if err != nil {
+			return nil, truncated
+		}
+		for i := range samples {
+			samples[i] = v
+		}
+	case kind == 1:
+		// VERBATIM
+		for i := range samples {
+			if samples[i], err = r.readSigned(bits); err != nil {
+				return nil, truncated
+			}
+		}
+	case kind >= 8 && kind <= 12:
+		// FIXED
+		order := kind - 8
+		if err := readFlacWarmup(r, samples, order, bits); err != nil {
+			return nil, err
+		}
+		if err := readFlacResidual(r, samples, order); err != nil {
+			return nil, err
+		}
+		restoreFixed(samples, order)
+	case kind >= 32:
+		// LPC
+		order := kind - 31
+		if err := readFlacWarmup(r, samples, order, bits); err != nil {
+			return nil, err
+		}
+		precision, err := r.readBits(4)
+		if err != nil {
+			return nil, truncated
+		}
+		if precision == 15 {
+			return nil, fmt.Errorf("%w, invalid lpc precision", ErrNotFlacFile)
+		}
+		shift, err := r.readSigned(5)
+		if err != nil {
+			return nil, truncated
+		}
+		if shift < 0 {
+			return nil, fmt.Errorf("%w, negative lpc shift", ErrNotFlacFile)
+		}
+		coeffs := make([]int64, order)
+		for i := range coeffs {
+			if coeffs[i], err = r.readSigned(int(precision) + 1); err != nil {
+				return nil, truncated
+			}
+		}
+		if err := readFlacResidual(r, samples, order); err != nil {
+			return nil, err
+		}
+		for i := order; i < blockSize; i++ {
+			var sum int64
+			for j, c := range coeffs {
+				sum += c * samples[i-1-j]
+			}
+			samples[i] += sum >> uint(shift)
+		}
+	default:
+		return nil, fmt.Errorf("%w, reserved subframe type %d", ErrNotFlacFile, kind)
+	}
+
+	if wasted > 0 {
+		for i := range samples {
+			samples[i] <<= uint(wasted)
+		}
+	}
+	return samples, nil
+}
+
+// readFlacWarmup 读取预测器的预热采样
+func readFlacWarmup(r *bitReader, samples []int64, order, bits int) error {
+	if order > len(samples) {
+		return fmt.Errorf("%w, predictor order exceeds block size", ErrNotFlacFile)
+	}
+	var err error
+	for i := 0; i < order; i++ {
+		if samples[i], err = r.readSigned(bits); err != nil {
+			return fmt.Errorf("%w, truncated subframe", ErrNotFlacFile)
+		}
+	}
+	return nil
+}
+
+// readFlacResidual 读取 Rice 编码的残差, 写入 samples[order:]
+func readFlacResidual(r *bitReader, samples []int64, order int) error {
+	truncated := fmt.Errorf("%w, truncated residual", ErrNotFlacFile)
+	method, err := r.readBits(2)
+	if err != nil {
+		return truncated
+	}
+	if method > 1 {
+		return fmt.Errorf("%w, reserved residual coding method", ErrNotFlacFile)
+	}
+	paramBits := 4 + int(method)
+	escape := uint64(1)<<paramBits - 1
+
+	partOrder, err := r.readBits(4)
+	if err != nil {
+		return truncated
+	}
+	parts := 1 << partOrder
+	blockSize := len(samples)
+	if blockSize%parts != 0 || blockSize/parts < order {
+		return fmt.Errorf("%w, invalid partition order", ErrNotFlacFile)
+	}
+
+	idx := order
+	for p := 0; p < parts; p++ {
+		n := blockSize / parts
+		if p == 0 {
+			n -= order
+		}
+		k, err := r.readBits(paramBits)
+		if err != nil {
+			return truncated
+		}
+		if k == escape {
+			raw, err := r.readBits(5)
+			if err != nil {
+				return truncated
+			}
+			for i := 0; i < n; i++ {
+				if samples[idx], err = r.readSigned(int(raw)); err != nil {
+					return truncated
+				}
+				idx++
+			}
+			continue
+		}
+		for i := 0; i < n; i++ {
+			q, err := r.readUnary()
+			if err != nil {
+				return truncated
+			}
+			low, err := r.readBits(int(k))
+			if err != nil {
+				return truncated
+			}
+			u := q<<k | low
+			samples[idx] = int64(u>>1) ^ -int64(u&1)
+			idx++
+		}
+	}
+	return nil
+}
+
+// restoreFixed 固定预测器还原, samples[order:] 中为残差
+func restoreFixed(s []int64, order int) {
+	for i := order; i < len(s); i++ {
+		switch order {
+		case 1:
+			s[i] += s[i-1]
+		case 2:
+			s[i] += 2*s[i-1] - s[i-2]
+		case 3:
+			s[i] += 3*s[i-1] - 3*s[i-2] + s[i-3]
+		case 4:
+			s[i] += 4*s[i-1] - 6*s[i-2] + 4*s[i-3] - s[i-4]
+		}
+	}
+}
+
+// crc8 FLAC 帧头校验 (多项式 0x07)
+func crc8(data []byte) byte {
+	var crc byte
+	for _, b := range data {
+		crc ^= b
+		for i := 0; i < 8; i++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// crc16 FLAC 帧校验 (多项式 0x8005)
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x8005
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// bitReader 大端序按位读取
+type bitReader struct {
+	data []byte
+	pos  int // 当前位偏移
+}
+
+// readBits 读取 n (<= 64) 位无符号整数
+func (r *bitReader) readBits(n int) (uint64, error) {
+	if r.pos+n > len(r.data)*8 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	var v uint64
+	for n > 0 {
+		avail := 8 - r.pos&7
+		take := avail
+		if take > n {
+			take = n
+		}
+		b := uint64(r.data[r.pos>>3]>>uint(avail-take)) & (1<<uint(take) - 1)
+		v = v<<uint(take) | b
+		r.pos += take
+		n -= take
+	}
+	return v, nil
+}
+
+// mustRead 读取已确认长度足够的字段
+func (r *bitReader) mustRead(n int) uint64 {
+	v, _ := r.readBits(n)
+	return v
+}
+
+// readSigned 读取 n 位有符号整数 (补码)
+func (r *bitReader) readSigned(n int) (int64, error) {
+	v, err := r.readBits(n)
+	if err != nil || n == 0 {
+		return 0, err
+	}
+	if v>>uint(n-1)&1 != 0 {
+		return int64(v) - int64(1)<<uint(n), nil
+	}
+	return int64(v), nil
+}
+
+// readUnary 读取一元编码 (若干个 0 后跟 1), 返回 0 的个数
+func (r *bitReader) readUnary() (uint64, error) {
+	var count uint64
+	for {
+		if r.pos >= len(r.data)*8 {
+			return 0, io.ErrUnexpectedEOF
+		}
+		// 整字节为 0 时快速跳过
+		if r.pos&7 == 0 && r.data[r.pos>>3] == 0 {
+			count += 8
+			r.pos += 8
+			continue
+		}
+		bit := r.data[r.pos>>3] >> uint(7-r.pos&7) & 1
+		r.pos++
+		if bit == 1 {
+			return count, nil
+		}
+		count++
+	}
+}
+
+// align 跳到下一个字节边界
+func (r *bitReader) align() {
+	r.pos = (r.pos + 7) &^ 7
+}
+
+// bitWriter 大端序按位写入
+type bitWriter struct {
+	data []byte
+	acc  uint64
+	n    uint // acc 中未输出的位数
+}
+
+// writeBits 写入 v 的低 n (<= 64) 位
+func (w *bitWriter) writeBits(v uint64, n int) {
+	for n > 0 {
+		take := n
+		if take > 32 {
+			take = 32
+		}
+		n -= take
+		w.acc = w.acc<<uint(take) | v>>uint(n)&(1<<uint(take)-1)
+		w.n += uint(take)
+		for w.n >= 8 {
+			w.n -= 8
+			w.data = append(w.data, byte(w.acc>>w.n))
+		}
+	}
+}
+
+// writeSigned 写入 n 位有符号整数 (补码)
+func (w *bitWriter) writeSigned(v int64, n int) {
+	w.writeBits(uint64(v), n)
+}
+
+// writeUnary 写入一元编码 (q 个 0 后跟 1)
+func (w *bitWriter) writeUnary(q uint64) {
+	for q >= 32 {
+		w.writeBits(0, 32)
+		q -= 32
+	}
+	w.writeBits(1, int(q)+1)
+}
+
+// align 以 0 填充到字节边界
+func (w *bitWriter) align() {
+	if w.n > 0 {
+		w.writeBits(0, int(8-w.n))
+	}
+}
+
+// bytes 返回已写入的完整字节
+func (w *bitWriter) bytes() []byte {
+	return w.data
+}
+
+// checkFlacParams 校验编码参数
+func checkFlacParams(sampleRate, channels, bitsPerSample int) error {
+	if sampleRate <= 0 || sampleRate >= 1<<20 || channels <= 0 || channels > 8 || bitsPerSample < 4 || bitsPerSample > 32 {
+		return fmt.Errorf("%w, rate=%d, chan=%d, bit=%d", gotool.ErrInvalidParam, sampleRate, channels, bitsPerSample)
+	}
+	return nil
+}
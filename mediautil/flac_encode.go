@@ -0,0 +1,650 @@
+package mediautil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+
+	"github.com/up-zero/gotool"
+)
+
+// FlacConfig FLAC 编码配置，零值字段使用默认值
+type FlacConfig struct {
+	BlockSize   int               // 每帧采样帧数 (16~65535), 默认 4096
+	MaxLPCOrder int               // LPC 最大阶数 (1~32), 默认 8, 负数表示仅使用固定预测器
+	Comments    map[string]string // VORBIS_COMMENT 标签, 例如 TITLE, ARTIST
+}
+
+// setDefaults 设置默认值
+func (c *FlacConfig) setDefaults() {
+	if c.BlockSize <= 0 {
+		c.BlockSize = 4096
+	}
+	if c.MaxLPCOrder < 0 {
+		c.MaxLPCOrder = 0
+	} else if c.MaxLPCOrder == 0 {
+		c.MaxLPCOrder = 8
+	} else if c.MaxLPCOrder > 32 {
+		c.MaxLPCOrder = 32
+	}
+}
+
+// flacVendor 写入 VORBIS_COMMENT 的编码器标识
+const flacVendor = "gotool mediautil"
+
+// flacSubframe 子帧编码方案
+type flacSubframe struct {
+	kind      int // 0 CONSTANT, 1 VERBATIM, 2 FIXED, 3 LPC
+	order     int
+	precision int
+	shift     int
+	coeffs    []int64
+	residual  []int64
+	partOrder int
+	params    []int
+	bits      int // 编码后的位数
+}
+
+// EncodeFlac 将 float32 音频数据编码为 FLAC
+//
+// 输入的缩放方式与 PcmBytesToFloat32 一致, 位深不超过 24 bit 时 DecodeFlac 可无损还原
+//
+// # Params:
+//
+//	samples: 音频数据, 多声道时为交错排列
+//	sampleRate: 采样率
+//	channels: 声道数 (1~8)
+//	bitsPerSample: 位深 (4~32)
+//	conf: 编码配置
+func EncodeFlac(samples []float32, sampleRate, channels, bitsPerSample int, conf FlacConfig) ([]byte, error) {
+	if err := checkFlacParams(sampleRate, channels, bitsPerSample); err != nil {
+		return nil, err
+	}
+	ints := make([]int64, len(samples))
+	for i, v := range samples {
+		ints[i] = float32ToSample(v, bitsPerSample)
+	}
+	return encodeFlac(ints, sampleRate, channels, bitsPerSample, conf)
+}
+
+// WavBytesToFlac PCM WAV 文件字节流无损转换为 FLAC
+//
+// 非 PCM 编码 (G.711、ADPCM、浮点等) 的 WAV 解码后以 16 bit 编码
+//
+// # Params:
+//
+//	wavData: 原始 WAV 文件数据
+//	conf: 编码配置
+func WavBytesToFlac(wavData []byte, conf FlacConfig) ([]byte, error) {
+	header, err := ParseWavHeader(wavData)
+	if err != nil {
+		return nil, err
+	}
+	bits := int(header.BitsPerSample)
+	if header.AudioFormat != WavFormatPCM || (bits != 16 && bits != 24 && bits != 32) {
+		header, samples, err := WavBytesToFloat32(wavData)
+		if err != nil {
+			return nil, err
+		}
+		return EncodeFlac(samples, int(header.SampleRate), int(header.NumChannels), BitsPerSample16, conf)
+	}
+
+	// 直接读取整数采样, 避免浮点转换
+	info, err := readWavInfo(bytes.NewReader(wavData))
+	if err != nil {
+		return nil, err
+	}
+	raw := wavData[info.dataOffset:]
+	if int64(header.Subchunk2Size) < int64(len(raw)) {
+		raw = raw[:header.Subchunk2Size]
+	}
+	channels := int(header.NumChannels)
+	width := bits / 8
+	frames := len(raw) / width / channels
+	ints := make([]int64, frames*channels)
+	for i := range ints {
+		var v int64
+		for b := width - 1; b >= 0; b-- {
+			v = v<<8 | int64(raw[i*width+b])
+		}
+		// 符号扩展
+		ints[i] = v << uint(64-bits) >> uint(64-bits)
+	}
+	if err := checkFlacParams(int(header.SampleRate), channels, bits); err != nil {
+		return nil, err
+	}
+	return encodeFlac(ints, int(header.SampleRate), channels, bits, conf)
+}
+
+// encodeFlac 编码交错排列的整数采样
+func encodeFlac(samples []int64, sampleRate, channels, bitsPerSample int, conf FlacConfig) ([]byte, error) {
+	conf.setDefaults()
+	if conf.BlockSize < 16 || conf.BlockSize > 65535 {
+		return nil, fmt.Errorf("%w, block size %d", gotool.ErrInvalidParam, conf.BlockSize)
+	}
+	if len(samples)%channels != 0 {
+		return nil, fmt.Errorf("%w, samples length is not aligned with channels", gotool.ErrInvalidParam)
+	}
+
+	totalFrames := len(samples) / channels
+	var frames []byte
+	minFrame, maxFrame := 0, 0
+	planes := make([][]int64, channels)
+	for start, number := 0, 0; start < totalFrames; start, number = start+conf.BlockSize, number+1 {
+		n := conf.BlockSize
+		if start+n > totalFrames {
+			n = totalFrames - start
+		}
+		for ch := range planes {
+			planes[ch] = make([]int64, n)
+			for i := 0; i < n; i++ {
+				planes[ch][i] = samples[(start+i)*channels+ch]
+			}
+		}
+		frame := encodeFlacFrame(planes, number, sampleRate, bitsPerSample, conf.MaxLPCOrder)
+		if minFrame == 0 || len(frame) < minFrame {
+			minFrame = len(frame)
+		}
+		if len(frame) > maxFrame {
+			maxFrame = len(frame)
+		}
+		frames = append(frames, frame...)
+	}
+
+	// STREAMINFO, 最小块大小不计最后一帧
+	w := &bitWriter{}
+	w.writeBits(uint64(conf.BlockSize), 16)
+	w.writeBits(uint64(conf.BlockSize), 16)
+	w.writeBits(uint64(minFrame), 24)
+	w.writeBits(uint64(maxFrame), 24)
+	w.writeBits(uint64(sampleRate), 20)
+	w.writeBits(uint64(channels-1), 3)
+	w.writeBits(uint64(bitsPerSample-1), 5)
+	w.writeBits(uint64(totalFrames), 36)
+	sum := flacMD5(samples, bitsPerSample)
+	streamInfo := append(w.bytes(), sum[:]...)
+
+	// VORBIS_COMMENT, 按键排序保证输出稳定
+	comment := binary.LittleEndian.AppendUint32(nil, uint32(len(flacVendor)))
+	comment = append(comment, flacVendor...)
+	comment = binary.LittleEndian.AppendUint32(comment, uint32(len(conf.Comments)))
+	keys := make([]string, 0, len(conf.Comments))
+	for k := range conf.Comments {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		entry := k + "=" + conf.Comments[k]
+		comment = binary.LittleEndian.AppendUint32(comment, uint32(len(entry)))
+		comment = append(comment, entry...)
+	}
+
+	output := make([]byte, 0, 4+4+len(streamInfo)+4+len(comment)+len(frames))
+	output = append(output, "fLaC"...)
+	output = append(output, flacBlockStreamInfo, 0, 0, byte(len(streamInfo)))
+	output = append(output, streamInfo...)
+	output = append(output, 0x80|flacBlockVorbisComment, byte(len(comment)>>16), byte(len(comment)>>8), byte(len(comment)))
+	output = append(output, comment...)
+	output = append(output, frames...)
+	return output, nil
+}
+
+// encodeFlacFrame 编码一个音频帧
+func encodeFlacFrame(planes [][]int64, number, sampleRate, bitsPerSample, maxLPCOrder int) []byte {
+	n := len(planes[0])
+	channels := len(planes)
+
+	// 立体声尝试 left/side, side/right, mid/side 去相关, 选择位数最少的方案
+	chAssign := channels - 1
+	var subframes []*flacSubframe
+	var sources [][]int64
+	if channels == 2 && bitsPerSample < 32 {
+		left, right := planes[0], planes[1]
+		mid := make([]int64, n)
+		side := make([]int64, n)
+		for i := range left {
+			mid[i] = (left[i] + right[i]) >> 1
+			side[i] = left[i] - right[i]
+		}
+		l := bestFlacSubframe(left, bitsPerSample, maxLPCOrder)
+		r := bestFlacSubframe(right, bitsPerSample, maxLPCOrder)
+		m := bestFlacSubframe(mid, bitsPerSample, maxLPCOrder)
+		s := bestFlacSubframe(side, bitsPerSample+1, maxLPCOrder)
+
+		chAssign, subframes, sources = 1, []*flacSubframe{l, r}, [][]int64{left, right}
+		best := l.bits + r.bits
+		if l.bits+s.bits < best {
+			best = l.bits + s.bits
+			chAssign, subframes, sources = flacChannelLeftSide, []*flacSubframe{l, s}, [][]int64{left, side}
+		}
+		if s.bits+r.bits < best {
+			best = s.bits + r.bits
+			chAssign, subframes, sources = flacChannelSideRight, []*flacSubframe{s, r}, [][]int64{side, right}
+		}
+		if m.bits+s.bits < best {
+			chAssign, subframes, sources = flacChannelMidSide, []*flacSubframe{m, s}, [][]int64{mid, side}
+		}
+	} else {
+		sources = planes
+		for _, plane := range planes {
+			subframes = append(subframes, bestFlacSubframe(plane, bitsPerSample, maxLPCOrder))
+		}
+	}
+
+	// 帧头
+	w := &bitWriter{}
+	w.writeBits(0x3FFE, 14) // 同步码
+	w.writeBits(0, 1)       // 保留位
+	w.writeBits(0, 1)       // 固定块大小
+
+	bsCode := 7
+	if n <= 256 {
+		bsCode = 6
+	}
+	for code, size := range flacBlockSizes {
+		if size == n {
+			bsCode = code
+			break
+		}
+	}
+	srCode := 0
+	for code, rate := range flacSampleRates {
+		if rate == sampleRate {
+			srCode = code
+			break
+		}
+	}
+	if srCode == 0 {
+		switch {
+		case sampleRate%1000 == 0 && sampleRate/1000 <= 255:
+			srCode = 12
+		case sampleRate <= 65535:
+			srCode = 13
+		case sampleRate%10 == 0 && sampleRate/10 <= 65535:
+			srCode = 14
+		}
+	}
+	ssCode := 0
+	for code, size := range flacSampleSizes {
+		if size == bitsPerSample {
+			ssCode = code
+			break
+		}
+	}
+	w.writeBits(uint64(bsCode), 4)
+	w.writeBits(uint64(srCode), 4)
+	w.writeBits(uint64(chAssign), 4)
+	w.writeBits(uint64(ssCode), 3)
+	w.writeBits(0, 1)
+	writeFlacNumber(w, uint64(number))
+	switch bsCode {
+	case 6:
+		w.writeBits(uint64(n-1), 8)
+	case 7:
+		w.writeBits(uint64(n-1), 16)
+	}
+	switch srCode {
+	case 12:
+		w.writeBits(uint64(sampleRate/1000), 8)
+	case 13:
+		w.writeBits(uint64(sampleRate), 16)
+	case 14:
+		w.writeBits(uint64(sampleRate/10), 16)
+	}
+	w.writeBits(uint64(crc8(w.bytes())), 8)
+
+	// 子帧
+	for ch, sf := range subframes {
+		chBits := bitsPerSample
+		if (chAssign == flacChannelLeftSide || chAssign == flacChannelMidSide) && ch == 1 ||
+			chAssign == flacChannelSideRight && ch == 0 {
+			chBits++
+		}
+		sf.write(w, sources[ch], chBits)
+	}
+	w.align()
+	w.writeBits(uint64(crc16(w.bytes())), 16)
+	return w.bytes()
+}
+
+// writeFlacNumber 以 UTF-8 方式写入帧号
+func writeFlacNumber(w *bitWriter, v uint64) {
+	if v < 0x80 {
+		w.writeBits(v, 8)
+		return
+	}
+	// 计算需要的续字节数, 首字节可容纳 6-n 位
+	n := 1
+	for v >= uint64(1)<<uint(6*n+6-n) {
+		n++
+	}
+	w.writeBits(uint64(0xFF00>>uint(n+1))&0xFF|v>>uint(6*n), 8)
+	for i := n - 1; i >= 0; i-- {
+		w.writeBits(0x80|v>>uint(6*i)&0x3F, 8)
+	}
+}
+
+// bestFlacSubframe 为一个声道选择位数最少的子帧编码方案
+func bestFlacSubframe(x []int64, bps, maxLPCOrder int) *flacSubframe {
+	n := len(x)
+	best := &flacSubframe{kind: 1, bits: 8 + n*bps}
+
+	constant := true
+	for _, v := range x[1:] {
+		if v != x[0] {
+			constant = false
+			break
+		}
+	}
+	if constant {
+		return &flacSubframe{kind: 0, bits: 8 + bps}
+	}
+
+	// 固定预测器 0~4 阶
+	for order := 0; order <= 4 && order < n; order++ {
+		residual := make([]int64, n-order)
+		for i := order; i < n; i++ {
+			var pred int64
+			switch order {
+			case 1:
+				pred = x[i-1]
+			case 2:
+				pred = 2*x[i-1] - x[i-2]
+			case 3:
+				pred = 3*x[i-1] - 3*x[i-2] + x[i-3]
+			case 4:
+				pred = 4*x[i-1] - 6*x[i-2] + 4*x[i-3] - x[i-4]
+			}
+			residual[i-order] = x[i] - pred
+		}
+		sf := &flacSubframe{kind: 2, order: order, residual: residual}
+		if sf.planResidual(n) {
+			sf.bits += 8 + order*bps
+			if sf.bits < best.bits {
+				best = sf
+			}
+		}
+	}
+
+	// LPC 预测器, Levinson-Durbin 求解各阶系数
+	if maxLPCOrder > n-1 {
+		maxLPCOrder = n - 1
+	}
+	if maxLPCOrder <= 0 {
+		return best
+	}
+	precision := flacLPCPrecision(n)
+	for order, lp := range lpcCoefficients(x, maxLPCOrder) {
+		order++
+		coeffs, shift, ok := quantizeLPC(lp, precision)
+		if !ok {
+			continue
+		}
+		residual := make([]int64, n-order)
+		for i := order; i < n; i++ {
+			var sum int64
+			for j, c := range coeffs {
+				sum += c * x[i-1-j]
+			}
+			residual[i-order] = x[i] - sum>>uint(shift)
+		}
+		sf := &flacSubframe{kind: 3, order: order, precision: precision, shift: shift, coeffs: coeffs, residual: residual}
+		if sf.planResidual(n) {
+			sf.bits += 8 + order*bps + 4 + 5 + order*precision
+			if sf.bits < best.bits {
+				best = sf
+			}
+		}
+	}
+	return best
+}
+
+// flacLPCPrecision 根据块大小选择 LPC 系数精度 (与 libFLAC 一致)
+func flacLPCPrecision(blockSize int) int {
+	switch {
+	case blockSize <= 192:
+		return 7
+	case blockSize <= 384:
+		return 8
+	case blockSize <= 576:
+		return 9
+	case blockSize <= 1152:
+		return 10
+	case blockSize <= 2304:
+		return 11
+	case blockSize <= 4608:
+		return 12
+	default:
+		return 13
+	}
+}
+
+// lpcCoefficients 对加窗 (Tukey 0.5) 信号计算 1~maxOrder 阶 LPC 系数
+//
+// 第 i 个结果为 i+1 阶系数, 预测值为 sum(coeffs[j] * x[n-1-j])
+func lpcCoefficients(x []int64, maxOrder int) [][]float64 {
+	n := len(x)
+	windowed := make([]float64, n)
+	taper := n / 4
+	for i, v := range x {
+		w := 1.0
+		if i < taper {
+			w = 0.5 - 0.5*math.Cos(math.Pi*float64(i)/float64(taper))
+		} else if i >= n-taper {
+			w = 0.5 - 0.5*math.Cos(math.Pi*float64(n-1-i)/float64(taper))
+		}
+		windowed[i] = float64(v) * w
+	}
+
+	autoc := make([]float64, maxOrder+1)
+	for lag := range autoc {
+		for i := lag; i < n; i++ {
+			autoc[lag] += windowed[i] * windowed[i-lag]
+		}
+	}
+	if autoc[0] == 0 {
+		return nil
+	}
+
+	var result [][]float64
+	lpc := make([]float64, maxOrder)
+	errSum := autoc[0]
+	for i := 0; i < maxOrder; i++ {
+		r := -autoc[i+1]
+		for j := 0; j < i; j++ {
+			r -= lpc[j] * autoc[i-j]
+		}
+		r /= errSum
+
+		lpc[i] = r
+		for j := 0; j < i/2; j++ {
+			tmp := lpc[j]
+			lpc[j] += r * lpc[i-1-j]
+			lpc[i-1-j] += r * tmp
+		}
+		if i%2 == 1 {
+			lpc[i/2] += lpc[i/2] * r
+		}
+		errSum *= 1 - r*r
+
+		coeffs := make([]float64, i+1)
+		for j := range coeffs {
+			coeffs[j] = -lpc[j]
+		}
+		result = append(result, coeffs)
+		if errSum <= 0 {
+			break
+		}
+	}
+	return result
+}
+
+// quantizeLPC 将 LPC 系数量化为指定精度的整数, 返回系数与右移位数
+func quantizeLPC(lp []float64, precision int) ([]int64, int, bool) {
+	var cmax float64
+	for _, c := range lp {
+		cmax = math.Max(cmax, math.Abs(c))
+	}
+	if cmax <= 0 || math.IsNaN(cmax) || math.IsInf(cmax, 0) {
+		return nil, 0, false
+	}
+	// 量化后的最大系数落在 [2^(precision-2), 2^(precision-1)) 区间
+	_, log2cmax := math.Frexp(cmax)
+	shift := precision - log2cmax - 1
+	if shift > 15 {
+		shift = 15
+	} else if shift < 0 {
+		return nil, 0, false
+	}
+
+	qmax := int64(1)<<uint(precision-1) - 1
+	coeffs := make([]int64, len(lp))
+	var errAcc float64
+	for i, c := range lp {
+		errAcc += c * float64(int64(1)<<uint(shift))
+		q := int64(math.Round(errAcc))
+		if q > qmax {
+			q = qmax
+		} else if q < -qmax-1 {
+			q = -qmax - 1
+		}
+		errAcc -= float64(q)
+		coeffs[i] = q
+	}
+	return coeffs, shift, true
+}
+
+// planResidual 选择 Rice 分区阶数和参数, 计算残差部分的位数
+//
+// 残差超出 32 bit 有符号范围时返回 false
+func (sf *flacSubframe) planResidual(blockSize int) bool {
+	u := make([]uint64, len(sf.residual))
+	for i, r := range sf.residual {
+		if r > math.MaxInt32 || r < math.MinInt32 {
+			return false
+		}
+		u[i] = uint64(r<<1) ^ uint64(r>>63)
+	}
+
+	bestBits := -1
+	for po := 0; po <= 8; po++ {
+		parts := 1 << uint(po)
+		if blockSize%parts != 0 || blockSize/parts <= sf.order {
+			break
+		}
+		params := make([]int, parts)
+		total := 0
+		maxParam := 0
+		idx := 0
+		for p := 0; p < parts; p++ {
+			count := blockSize / parts
+			if p == 0 {
+				count -= sf.order
+			}
+			k, cost := riceParam(u[idx : idx+count])
+			params[p] = k
+			total += cost
+			if params[p] > maxParam {
+				maxParam = params[p]
+			}
+			idx += count
+		}
+		paramBits := 4
+		if maxParam > 14 {
+			paramBits = 5
+		}
+		total += 2 + 4 + parts*paramBits
+		if bestBits < 0 || total < bestBits {
+			bestBits = total
+			sf.partOrder = po
+			sf.params = params
+		}
+	}
+	sf.bits = bestBits
+	return true
+}
+
+// riceParam 计算一组值的最优 Rice 参数和编码位数
+func riceParam(u []uint64) (int, int) {
+	if len(u) == 0 {
+		return 0, 0
+	}
+	var sum uint64
+	for _, v := range u {
+		sum += v
+	}
+	// 以均值估计参数, 在附近搜索精确最优值
+	guess := bits.Len64(sum/uint64(len(u))) - 1
+	bestK, bestCost := 0, -1
+	for k := guess - 1; k <= guess+1; k++ {
+		if k < 0 || k > 30 {
+			continue
+		}
+		cost := len(u) * (k + 1)
+		for _, v := range u {
+			cost += int(v >> uint(k))
+		}
+		if bestCost < 0 || cost < bestCost {
+			bestK, bestCost = k, cost
+		}
+	}
+	return bestK, bestCost
+}
+
+// write 写入子帧
+func (sf *flacSubframe) write(w *bitWriter, x []int64, bps int) {
+	switch sf.kind {
+	case 0:
+		w.writeBits(0, 8)
+		w.writeSigned(x[0], bps)
+		return
+	case 1:
+		w.writeBits(1<<1, 8)
+		for _, v := range x {
+			w.writeSigned(v, bps)
+		}
+		return
+	case 2:
+		w.writeBits(uint64(8+sf.order)<<1, 8)
+	case 3:
+		w.writeBits(uint64(31+sf.order)<<1, 8)
+	}
+
+	for _, v := range x[:sf.order] {
+		w.writeSigned(v, bps)
+	}
+	if sf.kind == 3 {
+		w.writeBits(uint64(sf.precision-1), 4)
+		w.writeSigned(int64(sf.shift), 5)
+		for _, c := range sf.coeffs {
+			w.writeSigned(c, sf.precision)
+		}
+	}
+
+	paramBits := 4
+	for _, k := range sf.params {
+		if k > 14 {
+			paramBits = 5
+		}
+	}
+	w.writeBits(uint64(paramBits-4), 2)
+	w.writeBits(uint64(sf.partOrder), 4)
+	idx := 0
+	parts := 1 << uint(sf.partOrder)
+	for p, k := range sf.params {
+		w.writeBits(uint64(k), paramBits)
+		count := len(x) / parts
+		if p == 0 {
+			count -= sf.order
+		}
+		for _, r := range sf.residual[idx : idx+count] {
+			v := uint64(r<<1) ^ uint64(r>>63)
+			w.writeUnary(v >> uint(k))
+			w.writeBits(v&(1<<uint(k)-1), k)
+		}
+		idx += count
+	}
+}
@@ -0,0 +1,169 @@
+package mediautil
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/rand"
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+// flacTestSignal 生成带噪声的正弦波测试信号
+func flacTestSignal(n, channels int) []float32 {
+	rng := rand.New(rand.NewSource(1))
+	sine := sineWave(440, SampleRate44K, n)
+	samples := make([]float32, 0, n*channels)
+	for _, v := range sine {
+		for c := 0; c < channels; c++ {
+			samples = append(samples, v*float32(c+1)/float32(channels)+float32(rng.NormFloat64()*0.01))
+		}
+	}
+	return samples
+}
+
+func TestDecodeFlac(t *testing.T) {
+	// RFC 9639 附录 D.1 示例: 双声道 16 bit, 1 个采样帧, 含 wasted bits 的 VERBATIM 子帧
+	data, _ := hex.DecodeString("664c61438000002210001000" +
+		"00000f00000f0ac442f00000" +
+		"00013e84b41807dc69030758" +
+		"6a3dad1a2e0ffff869180000" +
+		"bf0358fd03128baa9a")
+	info, samples, err := DecodeFlac(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, info.SampleRate, SampleRate44K)
+	testutil.Equal(t, info.Channels, 2)
+	testutil.Equal(t, info.BitsPerSample, 16)
+	testutil.Equal(t, info.TotalSamples, int64(1))
+	testutil.Equal(t, samples, []float32{sampleToFloat32(25588, 16), sampleToFloat32(10416, 16)})
+
+	// 篡改数据后校验失败
+	data[len(data)-3] ^= 0x01
+	_, _, err = DecodeFlac(data)
+	testutil.NotEqual(t, err, nil)
+
+	_, _, err = DecodeFlac([]byte("RIFF"))
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestDecodeFlacCorrupted(t *testing.T) {
+	samples := flacTestSignal(1000, 2)
+	encoded, err := EncodeFlac(samples, SampleRate44K, 2, 16, FlacConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 伪造 STREAMINFO 中 36 bit 的 total_samples 为最大值, 不能按其预分配内存
+	data := bytes.Clone(encoded)
+	data[21] |= 0x0F
+	copy(data[22:26], []byte{0xFF, 0xFF, 0xFF, 0xFF})
+	info, decoded, err := DecodeFlac(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, info.TotalSamples, int64(1<<36-1))
+	testutil.Equal(t, len(decoded), len(samples))
+
+	// 逐字节篡改只能返回错误, 不能导致崩溃
+	for i := range encoded {
+		data := bytes.Clone(encoded)
+		data[i] ^= 0xFF
+		DecodeFlac(data)
+	}
+}
+
+func TestEncodeFlac(t *testing.T) {
+	for _, bits := range []int{8, 16, 24} {
+		for _, channels := range []int{1, 2, 3} {
+			samples := flacTestSignal(10000, channels)
+			encoded, err := EncodeFlac(samples, SampleRate44K, channels, bits, FlacConfig{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			info, decoded, err := DecodeFlac(encoded)
+			if err != nil {
+				t.Fatalf("bit=%d, chan=%d: %v", bits, channels, err)
+			}
+			testutil.Equal(t, info.TotalSamples, int64(10000))
+			testutil.Equal(t, info.BitsPerSample, bits)
+			// 量化后的浮点值可无损还原
+			for i, v := range samples {
+				samples[i] = sampleToFloat32(float32ToSample(v, bits), bits)
+			}
+			testutil.Equal(t, decoded, samples)
+			if bits == 16 && len(encoded) >= len(samples)*2 {
+				t.Fatalf("flac not compressed: %d >= %d", len(encoded), len(samples)*2)
+			}
+		}
+	}
+}
+
+func TestEncodeFlacConfig(t *testing.T) {
+	samples := flacTestSignal(1000, 2)
+	samples = append(samples, make([]float32, 1000)...) // 静音部分使用 CONSTANT 子帧
+	for _, conf := range []FlacConfig{
+		{BlockSize: 192, MaxLPCOrder: -1},
+		{BlockSize: 1000, MaxLPCOrder: 32},
+		{BlockSize: 4608, Comments: map[string]string{"TITLE": "测试", "artist": "gotool"}},
+	} {
+		encoded, err := EncodeFlac(samples, 22050, 2, 16, conf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		info, decoded, err := DecodeFlac(encoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, info.SampleRate, 22050)
+		testutil.Equal(t, len(decoded), len(samples))
+		if conf.Comments != nil {
+			testutil.Equal(t, info.Comments, map[string]string{"TITLE": "测试", "ARTIST": "gotool"})
+			testutil.Equal(t, info.Vendor, flacVendor)
+		}
+	}
+
+	_, err := EncodeFlac(samples, 22050, 2, 16, FlacConfig{BlockSize: 8})
+	testutil.NotEqual(t, err, nil)
+	_, err = EncodeFlac(samples, 22050, 9, 16, FlacConfig{})
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestWavBytesToFlac(t *testing.T) {
+	for _, bits := range []int{16, 24, 32} {
+		wavData, err := Float32ToWavBytes(flacTestSignal(5000, 2), 12345, 2, bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		flacData, err := WavBytesToFlac(wavData, FlacConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		restored, err := FlacToWavBytes(flacData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, restored, wavData)
+	}
+}
+
+func TestWriteFlacNumber(t *testing.T) {
+	for _, v := range []uint64{0, 0x7F, 0x80, 0x7FF, 0x800, 0xFFFF, 0x10000, 1<<31 - 1, 1<<36 - 1} {
+		w := &bitWriter{}
+		writeFlacNumber(w, v)
+		// 与 UTF-8 扩展编码比较
+		r := &bitReader{data: w.bytes()}
+		first := r.mustRead(8)
+		n := 0
+		for mask := uint64(0x80); first&mask != 0; mask >>= 1 {
+			n++
+		}
+		got := first & (0xFF >> uint(n+1))
+		for i := 1; i < n; i++ {
+			got = got<<6 | r.mustRead(8)&0x3F
+		}
+		testutil.Equal(t, got, v)
+		testutil.Equal(t, len(w.bytes()), map[bool]int{true: 1, false: n}[n == 0])
+	}
+}
@@ -0,0 +1,167 @@
+package mediautil
+
+// G.711 μ-law 编码参数
+const (
+	muLawBias = 0x84
+	muLawClip = 8159
+)
+
+// muLawSegEnd μ-law 各段的上限 (14 bit 线性值)
+var muLawSegEnd = [8]int{0x3F, 0x7F, 0xFF, 0x1FF, 0x3FF, 0x7FF, 0xFFF, 0x1FFF}
+
+// aLawSegEnd A-law 各段的上限 (13 bit 线性值)
+var aLawSegEnd = [8]int{0x1F, 0x3F, 0x7F, 0xFF, 0x1FF, 0x3FF, 0x7FF, 0xFFF}
+
+// float32ToInt16 浮点采样转 16 bit 整数 (削波处理)，与 Float32ToPcmBytes 的量化方式一致
+func float32ToInt16(v float32) int {
+	if v != v {
+		return 0
+	}
+	if v > 1 {
+		v = 1
+	} else if v < -1 {
+		v = -1
+	}
+	return int(float64(v) * 32767.0)
+}
+
+// int16ToFloat32 16 bit 整数转浮点采样，与 PcmBytesToFloat32 的缩放方式一致
+func int16ToFloat32(v int) float32 {
+	return float32(float64(v) / 32767.0)
+}
+
+// linearToMuLaw 16 bit 线性值编码为 μ-law (与 ITU-T G.711 参考实现一致, 按 14 bit 量化)
+func linearToMuLaw(pcm int) byte {
+	pcm >>= 2
+	var mask byte = 0xFF
+	if pcm < 0 {
+		pcm = -pcm
+		mask = 0x7F
+	}
+	if pcm > muLawClip {
+		pcm = muLawClip
+	}
+	pcm += muLawBias >> 2
+
+	seg := 0
+	for seg < 8 && pcm > muLawSegEnd[seg] {
+		seg++
+	}
+	if seg >= 8 {
+		return 0x7F ^ mask
+	}
+	return byte(seg<<4|(pcm>>(seg+1))&0x0F) ^ mask
+}
+
+// muLawToLinear μ-law 解码为 16 bit 线性值
+func muLawToLinear(u byte) int {
+	u = ^u
+	exponent := int(u>>4) & 0x07
+	mantissa := int(u) & 0x0F
+	sample := ((mantissa << 3) + muLawBias) << exponent
+	sample -= muLawBias
+	if u&0x80 != 0 {
+		return -sample
+	}
+	return sample
+}
+
+// linearToALaw 16 bit 线性值编码为 A-law
+func linearToALaw(pcm int) byte {
+	pcm >>= 3
+	var mask byte
+	if pcm >= 0 {
+		mask = 0xD5
+	} else {
+		mask = 0x55
+		pcm = -pcm - 1
+	}
+
+	seg := 0
+	for seg < 8 && pcm > aLawSegEnd[seg] {
+		seg++
+	}
+	if seg >= 8 {
+		return 0x7F ^ mask
+	}
+
+	aval := seg << 4
+	if seg < 2 {
+		aval |= (pcm >> 1) & 0x0F
+	} else {
+		aval |= (pcm >> seg) & 0x0F
+	}
+	return byte(aval) ^ mask
+}
+
+// aLawToLinear A-law 解码为 16 bit 线性值
+func aLawToLinear(a byte) int {
+	a ^= 0x55
+	t := int(a&0x0F) << 4
+	seg := int(a&0x70) >> 4
+	switch seg {
+	case 0:
+		t += 8
+	case 1:
+		t += 0x108
+	default:
+		t += 0x108
+		t <<= seg - 1
+	}
+	if a&0x80 != 0 {
+		return t
+	}
+	return -t
+}
+
+// MuLawEncode G.711 μ-law 编码，每个采样点压缩为 1 字节
+//
+// # Params:
+//
+//	samples: 音频数据, 值域 [-1, 1]
+func MuLawEncode(samples []float32) []byte {
+	output := make([]byte, len(samples))
+	for i, v := range samples {
+		output[i] = linearToMuLaw(float32ToInt16(v))
+	}
+	return output
+}
+
+// MuLawDecode G.711 μ-law 解码
+//
+// # Params:
+//
+//	data: μ-law 编码数据
+func MuLawDecode(data []byte) []float32 {
+	output := make([]float32, len(data))
+	for i, v := range data {
+		output[i] = int16ToFloat32(muLawToLinear(v))
+	}
+	return output
+}
+
+// ALawEncode G.711 A-law 编码，每个采样点压缩为 1 字节
+//
+// # Params:
+//
+//	samples: 音频数据, 值域 [-1, 1]
+func ALawEncode(samples []float32) []byte {
+	output := make([]byte, len(samples))
+	for i, v := range samples {
+		output[i] = linearToALaw(float32ToInt16(v))
+	}
+	return output
+}
+
+// ALawDecode G.711 A-law 解码
+//
+// # Params:
+//
+//	data: A-law 编码数据
+func ALawDecode(data []byte) []float32 {
+	output := make([]float32, len(data))
+	for i, v := range data {
+		output[i] = int16ToFloat32(aLawToLinear(v))
+	}
+	return output
+}
@@ -0,0 +1,42 @@
+package mediautil
+
+import (
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestMuLawEncode(t *testing.T) {
+	testutil.Equal(t, MuLawEncode([]float32{0, 1, -1}), []byte{0xFF, 0x80, 0x00})
+	testutil.Equal(t, muLawToLinear(0x80), 32124)
+	testutil.Equal(t, muLawToLinear(0x00), -32124)
+
+	// 除 0x7F (负零) 外, 所有码字解码后再编码保持不变
+	for i := 0; i < 256; i++ {
+		if i == 0x7F {
+			continue
+		}
+		testutil.Equal(t, linearToMuLaw(muLawToLinear(byte(i))), byte(i))
+	}
+}
+
+func TestALawEncode(t *testing.T) {
+	testutil.Equal(t, ALawEncode([]float32{0, 1, -1}), []byte{0xD5, 0xAA, 0x2A})
+	testutil.Equal(t, aLawToLinear(0xAA), 32256)
+	testutil.Equal(t, aLawToLinear(0x2A), -32256)
+
+	for i := 0; i < 256; i++ {
+		testutil.Equal(t, linearToALaw(aLawToLinear(byte(i))), byte(i))
+	}
+}
+
+func TestG711Decode(t *testing.T) {
+	samples := sineWave(440, SampleRate8K, 800)
+	for _, decoded := range [][]float32{MuLawDecode(MuLawEncode(samples)), ALawDecode(ALawEncode(samples))} {
+		testutil.Equal(t, len(decoded), len(samples))
+		// 8 bit 对数量化, 信噪比约 38dB
+		if snr := snrDB(samples, decoded); snr < 30 {
+			t.Fatalf("g711 snr too low: %.2f dB", snr)
+		}
+	}
+}
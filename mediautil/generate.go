@@ -0,0 +1,418 @@
+package mediautil
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/up-zero/gotool"
+)
+
+const (
+	// ToneSine 正弦波
+	ToneSine = "sine"
+	// ToneSquare 方波
+	ToneSquare = "square"
+	// ToneSawtooth 锯齿波
+	ToneSawtooth = "sawtooth"
+	// ToneTriangle 三角波
+	ToneTriangle = "triangle"
+
+	// ChirpLinear 线性扫频
+	ChirpLinear = "linear"
+	// ChirpExponential 指数 (对数) 扫频
+	ChirpExponential = "exponential"
+)
+
+// dtmfRows DTMF 低频组 (行)
+var dtmfRows = [4]float64{697, 770, 852, 941}
+
+// dtmfCols DTMF 高频组 (列)
+var dtmfCols = [4]float64{1209, 1336, 1477, 1633}
+
+// dtmfKeys DTMF 按键布局, dtmfKeys[行][列]
+var dtmfKeys = [4][4]byte{
+	{'1', '2', '3', 'A'},
+	{'4', '5', '6', 'B'},
+	{'7', '8', '9', 'C'},
+	{'*', '0', '#', 'D'},
+}
+
+// DTMFDigit 检测到的 DTMF 按键
+type DTMFDigit struct {
+	Digit string        // 按键, 0-9, *, #, A-D
+	Start time.Duration // 开始时间
+	End   time.Duration // 结束时间
+}
+
+// durationToSamples 时长转采样点数
+func durationToSamples(d time.Duration, sampleRate int) int {
+	return int(math.Round(d.Seconds() * float64(sampleRate)))
+}
+
+// checkGenerateParams 校验生成参数
+func checkGenerateParams(duration time.Duration, sampleRate int) error {
+	if sampleRate <= 0 || duration < 0 {
+		return fmt.Errorf("%w, rate=%d, duration=%s", gotool.ErrInvalidParam, sampleRate, duration)
+	}
+	return nil
+}
+
+// toneValue 计算波形在相位 p (周期的小数部分, [0, 1)) 处的值, 与正弦波同相位
+func toneValue(waveform string, p float64) float64 {
+	switch waveform {
+	case ToneSquare:
+		if p < 0.5 {
+			return 1
+		}
+		return -1
+	case ToneSawtooth:
+		if p < 0.5 {
+			return 2 * p
+		}
+		return 2*p - 2
+	case ToneTriangle:
+		switch {
+		case p < 0.25:
+			return 4 * p
+		case p < 0.75:
+			return 2 - 4*p
+		default:
+			return 4*p - 4
+		}
+	default:
+		return math.Sin(2 * math.Pi * p)
+	}
+}
+
+// GenerateTone 生成单频信号
+//
+// 方波、锯齿波、三角波为非带限波形, 频率接近奈奎斯特频率时存在混叠
+//
+// # Params:
+//
+//	waveform: 波形, 例如: ToneSine, ToneSquare, ToneSawtooth, ToneTriangle
+//	freq: 频率 (Hz)
+//	duration: 时长
+//	sampleRate: 采样率
+//	amplitude: 幅度, 值域 [0, 1]
+func GenerateTone(waveform string, freq float64, duration time.Duration, sampleRate int, amplitude float64) ([]float32, error) {
+	if err := checkGenerateParams(duration, sampleRate); err != nil {
+		return nil, err
+	}
+	if freq < 0 {
+		return nil, fmt.Errorf("%w, freq=%f", gotool.ErrInvalidParam, freq)
+	}
+	switch waveform {
+	case ToneSine, ToneSquare, ToneSawtooth, ToneTriangle:
+	default:
+		return nil, fmt.Errorf("%w, unsupported waveform: %s", gotool.ErrInvalidParam, waveform)
+	}
+
+	output := make([]float32, durationToSamples(duration, sampleRate))
+	for i := range output {
+		_, p := math.Modf(freq * float64(i) / float64(sampleRate))
+		output[i] = float32(amplitude * toneValue(waveform, p))
+	}
+	return output, nil
+}
+
+// GenerateChirp 生成扫频正弦信号, 频率随时间从 f0 变化到 f1
+//
+// # Params:
+//
+//	method: 扫频方式, 例如: ChirpLinear, ChirpExponential (要求 f0, f1 均大于 0)
+//	f0: 起始频率 (Hz)
+//	f1: 结束频率 (Hz)
+//	duration: 时长
+//	sampleRate: 采样率
+//	amplitude: 幅度, 值域 [0, 1]
+func GenerateChirp(method string, f0, f1 float64, duration time.Duration, sampleRate int, amplitude float64) ([]float32, error) {
+	if err := checkGenerateParams(duration, sampleRate); err != nil {
+		return nil, err
+	}
+	if f0 < 0 || f1 < 0 {
+		return nil, fmt.Errorf("%w, f0=%f, f1=%f", gotool.ErrInvalidParam, f0, f1)
+	}
+
+	total := duration.Seconds()
+	var phase func(t float64) float64 // 瞬时相位 (周期数)
+	switch method {
+	case ChirpLinear:
+		phase = func(t float64) float64 {
+			return f0*t + (f1-f0)*t*t/(2*total)
+		}
+	case ChirpExponential:
+		if f0 <= 0 || f1 <= 0 {
+			return nil, fmt.Errorf("%w, exponential chirp requires positive frequencies", gotool.ErrInvalidParam)
+		}
+		k := f1 / f0
+		phase = func(t float64) float64 {
+			if k == 1 {
+				return f0 * t
+			}
+			return f0 * total / math.Log(k) * (math.Pow(k, t/total) - 1)
+		}
+	default:
+		return nil, fmt.Errorf("%w, unsupported chirp method: %s", gotool.ErrInvalidParam, method)
+	}
+
+	output := make([]float32, durationToSamples(duration, sampleRate))
+	for i := range output {
+		output[i] = float32(amplitude * math.Sin(2*math.Pi*phase(float64(i)/float64(sampleRate))))
+	}
+	return output, nil
+}
+
+// GenerateWhiteNoise 生成均匀分布的白噪声
+//
+// # Params:
+//
+//	duration: 时长
+//	sampleRate: 采样率
+//	amplitude: 幅度, 值域 [0, 1]
+//	seed: 随机种子, 相同种子生成相同的序列
+func GenerateWhiteNoise(duration time.Duration, sampleRate int, amplitude float64, seed int64) ([]float32, error) {
+	if err := checkGenerateParams(duration, sampleRate); err != nil {
+		return nil, err
+	}
+	rng := rand.New(rand.NewSource(seed))
+	output := make([]float32, durationToSamples(duration, sampleRate))
+	for i := range output {
+		output[i] = float32(amplitude * (2*rng.Float64() - 1))
+	}
+	return output, nil
+}
+
+// GeneratePinkNoise 生成粉红噪声 (功率谱密度与频率成反比, 每倍频程 -3dB)
+//
+// 使用 Paul Kellet 滤波器, 输出按峰值归一化到 amplitude
+//
+// # Params:
+//
+//	duration: 时长
+//	sampleRate: 采样率
+//	amplitude: 峰值幅度, 值域 [0, 1]
+//	seed: 随机种子, 相同种子生成相同的序列
+func GeneratePinkNoise(duration time.Duration, sampleRate int, amplitude float64, seed int64) ([]float32, error) {
+	white, err := GenerateWhiteNoise(duration, sampleRate, 1, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	var b0, b1, b2, b3, b4, b5, b6 float64
+	pink := make([]float64, len(white))
+	var peak float64
+	for i, v := range white {
+		w := float64(v)
+		b0 = 0.99886*b0 + w*0.0555179
+		b1 = 0.99332*b1 + w*0.0750759
+		b2 = 0.96900*b2 + w*0.1538520
+		b3 = 0.86650*b3 + w*0.3104856
+		b4 = 0.55000*b4 + w*0.5329522
+		b5 = -0.7616*b5 - w*0.0168980
+		pink[i] = b0 + b1 + b2 + b3 + b4 + b5 + b6 + w*0.5362
+		b6 = w * 0.115926
+		peak = math.Max(peak, math.Abs(pink[i]))
+	}
+
+	output := make([]float32, len(pink))
+	if peak == 0 {
+		return output, nil
+	}
+	for i, v := range pink {
+		output[i] = float32(v / peak * amplitude)
+	}
+	return output, nil
+}
+
+// GenerateSilence 生成静音
+//
+// # Params:
+//
+//	duration: 时长
+//	sampleRate: 采样率
+func GenerateSilence(duration time.Duration, sampleRate int) ([]float32, error) {
+	if err := checkGenerateParams(duration, sampleRate); err != nil {
+		return nil, err
+	}
+	return make([]float32, durationToSamples(duration, sampleRate)), nil
+}
+
+// GenerateDTMF 生成 DTMF 拨号音
+//
+// # Params:
+//
+//	digits: 按键序列, 支持 0-9, *, #, A-D (不区分大小写)
+//	sampleRate: 采样率, 不低于 4000
+//	toneDuration: 每个按键的持续时间, 通常不少于 40ms
+//	gapDuration: 按键之间的静音时长
+//	amplitude: 幅度, 值域 [0, 1], 两个频率分量各占一半
+func GenerateDTMF(digits string, sampleRate int, toneDuration, gapDuration time.Duration, amplitude float64) ([]float32, error) {
+	if sampleRate < 4000 || toneDuration <= 0 || gapDuration < 0 {
+		return nil, fmt.Errorf("%w, rate=%d, tone=%s, gap=%s", gotool.ErrInvalidParam, sampleRate, toneDuration, gapDuration)
+	}
+
+	toneLen := durationToSamples(toneDuration, sampleRate)
+	gapLen := durationToSamples(gapDuration, sampleRate)
+	var output []float32
+	for i, d := range strings.ToUpper(digits) {
+		row, col, ok := dtmfPosition(byte(d))
+		if !ok || d > 0x7F {
+			return nil, fmt.Errorf("%w, invalid dtmf digit: %q", gotool.ErrInvalidParam, d)
+		}
+		if i > 0 {
+			output = append(output, make([]float32, gapLen)...)
+		}
+		for n := 0; n < toneLen; n++ {
+			t := float64(n) / float64(sampleRate)
+			v := math.Sin(2*math.Pi*dtmfRows[row]*t) + math.Sin(2*math.Pi*dtmfCols[col]*t)
+			output = append(output, float32(v*amplitude/2))
+		}
+	}
+	return output, nil
+}
+
+// dtmfPosition 查找按键所在的行和列
+func dtmfPosition(d byte) (int, int, bool) {
+	for r, keys := range dtmfKeys {
+		for c, k := range keys {
+			if k == d {
+				return r, c, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// Goertzel 使用 Goertzel 算法计算信号在指定频率处的幅度
+//
+// 对于幅度为 A 且频率与 freq 一致的正弦信号, 返回值约为 A
+//
+// # Params:
+//
+//	samples: 音频数据 (单声道)
+//	sampleRate: 采样率
+//	freq: 目标频率 (Hz)
+func Goertzel(samples []float32, sampleRate int, freq float64) float64 {
+	if len(samples) == 0 || sampleRate <= 0 {
+		return 0
+	}
+	coeff := 2 * math.Cos(2*math.Pi*freq/float64(sampleRate))
+	var s1, s2 float64
+	for _, v := range samples {
+		s := float64(v) + coeff*s1 - s2
+		s2 = s1
+		s1 = s
+	}
+	power := s1*s1 + s2*s2 - coeff*s1*s2
+	if power < 0 {
+		power = 0
+	}
+	return 2 * math.Sqrt(power) / float64(len(samples))
+}
+
+// DetectDTMF 基于 Goertzel 算法的 DTMF 按键检测
+//
+// 以约 25ms 的块 (半重叠) 分析, 同一按键至少连续出现在 2 个块中才会输出
+//
+// # Params:
+//
+//	samples: 音频数据, 多声道时为交错排列
+//	sampleRate: 采样率
+//	channels: 声道数
+func DetectDTMF(samples []float32, sampleRate, channels int) ([]DTMFDigit, error) {
+	if sampleRate < 4000 || channels <= 0 {
+		return nil, fmt.Errorf("%w, rate=%d, chan=%d", gotool.ErrInvalidParam, sampleRate, channels)
+	}
+	const (
+		minAmplitude = 0.005 // 单个分量的最小幅度 (约 -46dBFS)
+		maxTwistDB   = 8.0   // 高低频组允许的最大能量差
+		dominance    = 2.0   // 组内最强频率与次强频率的最小幅度比 (6dB)
+		minPurity    = 0.6   // 两个分量的能量占块总能量的最小比例
+	)
+
+	mono := downmix(samples, channels)
+	blockLen := sampleRate * 256 / 10000 // 25.6ms, 8KHz 时为 205
+	hop := blockLen / 2
+
+	// 逐块识别按键, 0 表示无按键
+	numBlocks := 0
+	if len(mono) >= blockLen {
+		numBlocks = 1 + (len(mono)-blockLen)/hop
+	}
+	keys := make([]byte, numBlocks)
+	for b := range keys {
+		block := mono[b*hop : b*hop+blockLen]
+		row, rowAmp, rowSecond := strongestTone(block, sampleRate, dtmfRows)
+		col, colAmp, colSecond := strongestTone(block, sampleRate, dtmfCols)
+		if rowAmp < minAmplitude || colAmp < minAmplitude {
+			continue
+		}
+		if math.Abs(20*math.Log10(colAmp/rowAmp)) > maxTwistDB {
+			continue
+		}
+		if rowAmp < dominance*rowSecond || colAmp < dominance*colSecond {
+			continue
+		}
+		var energy float64
+		for _, v := range block {
+			energy += float64(v) * float64(v)
+		}
+		if (rowAmp*rowAmp+colAmp*colAmp)/2 < minPurity*energy/float64(blockLen) {
+			continue
+		}
+		keys[b] = dtmfKeys[row][col]
+	}
+
+	// 合并连续的相同按键
+	digits := make([]DTMFDigit, 0)
+	for b := 0; b < numBlocks; {
+		if keys[b] == 0 {
+			b++
+			continue
+		}
+		end := b
+		for end+1 < numBlocks && keys[end+1] == keys[b] {
+			end++
+		}
+		if end > b {
+			digits = append(digits, DTMFDigit{
+				Digit: string(keys[b]),
+				Start: samplesToDuration(b*hop, sampleRate),
+				End:   samplesToDuration(end*hop+blockLen, sampleRate),
+			})
+		}
+		b = end + 1
+	}
+	return digits, nil
+}
+
+// strongestTone 返回一组频率中幅度最强的索引、幅度以及次强幅度
+func strongestTone(block []float32, sampleRate int, freqs [4]float64) (int, float64, float64) {
+	best, bestAmp, second := 0, 0.0, 0.0
+	for i, f := range freqs {
+		amp := Goertzel(block, sampleRate, f)
+		if amp > bestAmp {
+			best, bestAmp, second = i, amp, bestAmp
+		} else if amp > second {
+			second = amp
+		}
+	}
+	return best, bestAmp, second
+}
+
+// DetectDTMFWav 检测 WAV 音频中的 DTMF 按键序列
+//
+// # Params:
+//
+//	wavData: 原始 WAV 文件数据
+func DetectDTMFWav(wavData []byte) ([]DTMFDigit, error) {
+	header, samples, err := WavBytesToFloat32(wavData)
+	if err != nil {
+		return nil, err
+	}
+	return DetectDTMF(samples, int(header.SampleRate), int(header.NumChannels))
+}
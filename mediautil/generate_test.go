@@ -0,0 +1,196 @@
+package mediautil
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestGenerateTone(t *testing.T) {
+	for _, waveform := range []string{ToneSine, ToneSquare, ToneSawtooth, ToneTriangle} {
+		samples, err := GenerateTone(waveform, 1000, 100*time.Millisecond, 16000, 0.5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, len(samples), 1600)
+		if waveform != ToneSquare {
+			testutil.Equal(t, samples[0], float32(0))
+		}
+
+		var peak float32
+		for _, v := range samples {
+			peak = max(peak, float32(math.Abs(float64(v))))
+		}
+		testutil.EqualFloat(t, peak, float32(0.5), 1e-3)
+
+		// 基频分量明显强于非谐波频率
+		fundamental := Goertzel(samples, 16000, 1000)
+		testutil.Equal(t, fundamental > 0.3, true)
+		testutil.Equal(t, Goertzel(samples, 16000, 1500) < 0.01, true)
+	}
+
+	sine, _ := GenerateTone(ToneSine, 440, time.Second, 8000, 0.8)
+	testutil.EqualFloat(t, Goertzel(sine, 8000, 440), 0.8, 1e-3)
+
+	_, err := GenerateTone("noise", 440, time.Second, 8000, 1)
+	testutil.NotEqual(t, err, nil)
+	_, err = GenerateTone(ToneSine, -1, time.Second, 8000, 1)
+	testutil.NotEqual(t, err, nil)
+	_, err = GenerateTone(ToneSine, 440, time.Second, 0, 1)
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestGenerateChirp(t *testing.T) {
+	for _, method := range []string{ChirpLinear, ChirpExponential} {
+		samples, err := GenerateChirp(method, 200, 2000, time.Second, 8000, 0.5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, len(samples), 8000)
+
+		// 开头的频率接近 f0, 结尾的频率接近 f1
+		head, tail := samples[:400], samples[len(samples)-400:]
+		testutil.Equal(t, Goertzel(head, 8000, 200) > Goertzel(head, 8000, 2000), true)
+		testutil.Equal(t, Goertzel(tail, 8000, 2000) > Goertzel(tail, 8000, 200), true)
+	}
+
+	_, err := GenerateChirp(ChirpExponential, 0, 1000, time.Second, 8000, 1)
+	testutil.NotEqual(t, err, nil)
+	_, err = GenerateChirp("quadratic", 100, 1000, time.Second, 8000, 1)
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestGenerateNoise(t *testing.T) {
+	white, err := GenerateWhiteNoise(time.Second, 8000, 0.5, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, len(white), 8000)
+	again, _ := GenerateWhiteNoise(time.Second, 8000, 0.5, 1)
+	testutil.Equal(t, white, again)
+	other, _ := GenerateWhiteNoise(time.Second, 8000, 0.5, 2)
+	testutil.NotEqual(t, white[0], other[0])
+	for _, v := range white {
+		if v < -0.5 || v > 0.5 {
+			t.Fatalf("white noise out of range: %f", v)
+		}
+	}
+
+	pink, err := GeneratePinkNoise(2*time.Second, 8000, 0.5, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var peak float32
+	for _, v := range pink {
+		peak = max(peak, float32(math.Abs(float64(v))))
+	}
+	testutil.EqualFloat(t, peak, float32(0.5), 1e-6)
+
+	// 粉红噪声低频能量高于高频, 白噪声大致相同
+	bandRatio := func(samples []float32) float64 {
+		var low, high float64
+		for f := 100.0; f < 400; f += 20 {
+			low += Goertzel(samples, 8000, f)
+		}
+		for f := 2000.0; f < 2300; f += 20 {
+			high += Goertzel(samples, 8000, f)
+		}
+		return low / high
+	}
+	testutil.Equal(t, bandRatio(pink) > 2, true)
+	whiteRatio := bandRatio(white)
+	testutil.Equal(t, whiteRatio > 0.5 && whiteRatio < 2, true)
+
+	silence, err := GenerateSilence(50*time.Millisecond, 16000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, silence, make([]float32, 800))
+}
+
+func TestGenerateDTMF(t *testing.T) {
+	samples, err := GenerateDTMF("1a", 8000, 100*time.Millisecond, 50*time.Millisecond, 0.8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, len(samples), 2000)
+
+	tone := samples[:800]
+	testutil.EqualFloat(t, Goertzel(tone, 8000, 697), 0.4, 1e-2)
+	testutil.EqualFloat(t, Goertzel(tone, 8000, 1209), 0.4, 1e-2)
+	testutil.Equal(t, samples[900], float32(0))
+
+	_, err = GenerateDTMF("12x", 8000, 100*time.Millisecond, 50*time.Millisecond, 0.8)
+	testutil.NotEqual(t, err, nil)
+	_, err = GenerateDTMF("1", 8000, 0, 0, 0.8)
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestDetectDTMF(t *testing.T) {
+	const digits = "0123456789*#ABCD"
+	tone, gap := 80*time.Millisecond, 60*time.Millisecond
+	for _, sampleRate := range []int{8000, 16000, 44100} {
+		samples, err := GenerateDTMF(digits, sampleRate, tone, gap, 0.6)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 叠加弱噪声
+		noise, _ := GenerateWhiteNoise(time.Duration(len(samples))*time.Second/time.Duration(sampleRate), sampleRate, 0.02, 1)
+		for i := range samples {
+			samples[i] += noise[i]
+		}
+
+		wavData, err := Float32ToWavBytes(samples, sampleRate, 1, 16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		detected, err := DetectDTMFWav(wavData)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var sb strings.Builder
+		for _, d := range detected {
+			sb.WriteString(d.Digit)
+		}
+		testutil.Equal(t, sb.String(), digits)
+
+		// 时间误差不超过一个分析块
+		tolerance := 30 * time.Millisecond
+		for i, d := range detected {
+			start := time.Duration(i) * (tone + gap)
+			testutil.Equal(t, (d.Start-start).Abs() <= tolerance, true)
+			testutil.Equal(t, (d.End-(start+tone)).Abs() <= tolerance, true)
+		}
+	}
+
+	// 立体声及重复按键
+	mono, _ := GenerateDTMF("55", 8000, 60*time.Millisecond, 40*time.Millisecond, 0.5)
+	stereo := make([]float32, len(mono)*2)
+	for i, v := range mono {
+		stereo[2*i], stereo[2*i+1] = v, v
+	}
+	detected, err := DetectDTMF(stereo, 8000, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, len(detected), 2)
+
+	// 单频、噪声、静音不应被识别
+	single, _ := GenerateTone(ToneSine, 697, time.Second, 8000, 0.5)
+	noise, _ := GenerateWhiteNoise(time.Second, 8000, 0.5, 3)
+	silence, _ := GenerateSilence(time.Second, 8000)
+	for _, samples := range [][]float32{single, noise, silence} {
+		detected, err := DetectDTMF(samples, 8000, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Equal(t, len(detected), 0)
+	}
+
+	_, err = DetectDTMF(mono, 1000, 1)
+	testutil.NotEqual(t, err, nil)
+}
@@ -0,0 +1,324 @@
+package mediautil
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/up-zero/gotool"
+	"github.com/up-zero/gotool/imageutil"
+)
+
+const (
+	// ColormapGray 灰度
+	ColormapGray = "gray"
+	// ColormapViridis viridis (matplotlib 默认)
+	ColormapViridis = "viridis"
+	// ColormapMagma magma
+	ColormapMagma = "magma"
+	// ColormapInferno inferno
+	ColormapInferno = "inferno"
+	// ColormapJet jet
+	ColormapJet = "jet"
+	// ColormapHot hot
+	ColormapHot = "hot"
+)
+
+// colormapStops 色彩映射的等间距控制点, 控制点之间线性插值
+var colormapStops = map[string][]uint32{
+	ColormapGray:    {0x000000, 0xffffff},
+	ColormapViridis: {0x440154, 0x482475, 0x414487, 0x355f8d, 0x2a788e, 0x21918c, 0x22a884, 0x44bf70, 0x7ad151, 0xbddf26, 0xfde725},
+	ColormapMagma:   {0x000004, 0x140e36, 0x3b0f70, 0x641a80, 0x8c2981, 0xb73779, 0xde4968, 0xf7705c, 0xfe9f6d, 0xfecf92, 0xfcfdbf},
+	ColormapInferno: {0x000004, 0x160b39, 0x420a68, 0x6a176e, 0x932667, 0xbc3754, 0xdd513a, 0xf37819, 0xfca50a, 0xf6d746, 0xfcffa4},
+	ColormapJet:     {0x000080, 0x0000ff, 0x0080ff, 0x00ffff, 0x80ff80, 0xffff00, 0xff8000, 0xff0000, 0x800000},
+	ColormapHot:     {0x000000, 0xff0000, 0xffff00, 0xffffff},
+}
+
+// GetColormap 获取色彩映射查找表, 第 i 个颜色对应归一化值 i/(size-1)
+//
+// # Params:
+//
+//	name: 色彩映射名称, 例如: ColormapViridis, ColormapMagma, ColormapGray
+//	size: 查找表大小
+func GetColormap(name string, size int) ([]color.RGBA, error) {
+	stops, ok := colormapStops[name]
+	if !ok {
+		return nil, fmt.Errorf("%w, unsupported colormap: %s", gotool.ErrInvalidParam, name)
+	}
+	if size <= 0 {
+		return nil, fmt.Errorf("%w, size=%d", gotool.ErrInvalidParam, size)
+	}
+
+	lut := make([]color.RGBA, size)
+	for i := range lut {
+		pos := 0.0
+		if size > 1 {
+			pos = float64(i) / float64(size-1) * float64(len(stops)-1)
+		}
+		idx := int(pos)
+		if idx >= len(stops)-1 {
+			idx = len(stops) - 2
+		}
+		frac := pos - float64(idx)
+		a, b := stops[idx], stops[idx+1]
+		channel := func(shift uint) uint8 {
+			ca := float64(a >> shift & 0xff)
+			cb := float64(b >> shift & 0xff)
+			return uint8(math.Round(ca + (cb-ca)*frac))
+		}
+		lut[i] = color.RGBA{R: channel(16), G: channel(8), B: channel(0), A: 255}
+	}
+	return lut, nil
+}
+
+// WaveformConfig 波形图配置，零值字段使用默认值
+type WaveformConfig struct {
+	Width      int         // 图片宽度, 默认 1000
+	Height     int         // 图片高度, 多声道时各声道平分, 默认 200
+	Background color.Color // 背景色, 默认白色
+	Foreground color.Color // 波形颜色, 默认 imageutil.ColorInfo
+	CenterLine color.Color // 中线颜色, nil 表示不绘制
+}
+
+// setDefaults 设置默认值
+func (c *WaveformConfig) setDefaults() {
+	if c.Width <= 0 {
+		c.Width = 1000
+	}
+	if c.Height <= 0 {
+		c.Height = 200
+	}
+	if c.Background == nil {
+		c.Background = imageutil.ColorWhite
+	}
+	if c.Foreground == nil {
+		c.Foreground = imageutil.ColorInfo
+	}
+}
+
+// RenderWaveform 渲染波形图, 每个像素列绘制该时间段内的最小值到最大值
+//
+// # Params:
+//
+//	samples: 音频数据, 多声道时为交错排列, 各声道自上而下依次绘制
+//	channels: 声道数
+//	conf: 波形图配置
+func RenderWaveform(samples []float32, channels int, conf WaveformConfig) (image.Image, error) {
+	if channels <= 0 {
+		return nil, fmt.Errorf("%w, chan=%d", gotool.ErrInvalidParam, channels)
+	}
+	conf.setDefaults()
+	if conf.Height < channels {
+		return nil, fmt.Errorf("%w, height %d is less than channels %d", gotool.ErrInvalidParam, conf.Height, channels)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, conf.Width, conf.Height))
+	imageutil.DrawFilledRect(img, img.Bounds(), conf.Background)
+
+	frames := len(samples) / channels
+	laneHeight := conf.Height / channels
+	for ch := 0; ch < channels; ch++ {
+		top := ch * laneHeight
+		// 幅度 [-1, 1] 映射到声道区域 [top+laneHeight-1, top]
+		toY := func(v float32) int {
+			if v > 1 {
+				v = 1
+			} else if v < -1 {
+				v = -1
+			}
+			return top + int(math.Round((1-float64(v))/2*float64(laneHeight-1)))
+		}
+		if conf.CenterLine != nil {
+			imageutil.DrawLine(img, image.Pt(0, toY(0)), image.Pt(conf.Width-1, toY(0)), conf.CenterLine)
+		}
+		if frames == 0 {
+			continue
+		}
+
+		for x := 0; x < conf.Width; x++ {
+			start := x * frames / conf.Width
+			end := (x + 1) * frames / conf.Width
+			if end <= start {
+				end = start + 1
+			}
+			minV, maxV := samples[start*channels+ch], samples[start*channels+ch]
+			for i := start + 1; i < end; i++ {
+				v := samples[i*channels+ch]
+				if v < minV {
+					minV = v
+				}
+				if v > maxV {
+					maxV = v
+				}
+			}
+			for y := toY(maxV); y <= toY(minV); y++ {
+				img.Set(x, y, conf.Foreground)
+			}
+		}
+	}
+	return img, nil
+}
+
+// SpectrogramConfig 频谱图配置，零值字段使用默认值
+type SpectrogramConfig struct {
+	NFFT      int     // FFT 大小, 默认 1024
+	HopLength int     // 帧移, 默认 NFFT/4
+	Window    string  // 窗函数类型, 默认 WindowHann
+	NMels     int     // 梅尔频带数, 大于 0 时渲染梅尔频谱图
+	FMin      float64 // 梅尔滤波器最低频率
+	FMax      float64 // 梅尔滤波器最高频率, 默认 sampleRate/2
+	TopDB     float64 // 显示的动态范围 (dB), 默认 80
+	Colormap  string  // 色彩映射, 默认 ColormapViridis
+	Width     int     // 图片宽度, 默认为帧数
+	Height    int     // 图片高度, 默认为频率 bin 数 (或梅尔频带数)
+}
+
+// setDefaults 设置默认值
+func (c *SpectrogramConfig) setDefaults() {
+	if c.NFFT <= 0 {
+		c.NFFT = 1024
+	}
+	if c.HopLength <= 0 {
+		c.HopLength = c.NFFT / 4
+	}
+	if c.Window == "" {
+		c.Window = WindowHann
+	}
+	if c.TopDB <= 0 {
+		c.TopDB = 80
+	}
+	if c.Colormap == "" {
+		c.Colormap = ColormapViridis
+	}
+}
+
+// RenderSpectrogram 渲染对数幅度频谱图 (或梅尔频谱图), 横轴为时间, 纵轴为频率 (低频在下)
+//
+// # Params:
+//
+//	samples: 音频数据, 多声道时为交错排列, 渲染前混合为单声道
+//	sampleRate: 采样率
+//	channels: 声道数
+//	conf: 频谱图配置
+func RenderSpectrogram(samples []float32, sampleRate, channels int, conf SpectrogramConfig) (image.Image, error) {
+	if sampleRate <= 0 || channels <= 0 {
+		return nil, fmt.Errorf("%w, rate=%d, chan=%d", gotool.ErrInvalidParam, sampleRate, channels)
+	}
+	conf.setDefaults()
+	lut, err := GetColormap(conf.Colormap, 256)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := STFT(downmix(samples, channels), conf.NFFT, conf.HopLength, conf.Window)
+	if err != nil {
+		return nil, err
+	}
+	if len(spec) == 0 {
+		return nil, fmt.Errorf("%w, audio is shorter than nfft %d", gotool.ErrInvalidParam, conf.NFFT)
+	}
+
+	// 功率谱, 可选映射到梅尔频带
+	var filters [][]float32
+	if conf.NMels > 0 {
+		filters = MelFilters(sampleRate, conf.NFFT, conf.NMels, conf.FMin, conf.FMax)
+	}
+	values := make([][]float64, len(spec))
+	maxDB := math.Inf(-1)
+	for t, frame := range spec {
+		power := make([]float64, len(frame))
+		for k, v := range frame {
+			power[k] = real(v)*real(v) + imag(v)*imag(v)
+		}
+		if filters != nil {
+			mel := make([]float64, len(filters))
+			for m, filter := range filters {
+				for k, w := range filter {
+					mel[m] += float64(w) * power[k]
+				}
+			}
+			power = mel
+		}
+		for k, p := range power {
+			power[k] = 10 * math.Log10(p+1e-10)
+			maxDB = math.Max(maxDB, power[k])
+		}
+		values[t] = power
+	}
+
+	width, height := conf.Width, conf.Height
+	if width <= 0 {
+		width = len(values)
+	}
+	if height <= 0 {
+		height = len(values[0])
+	}
+
+	// 在 dB 值上双线性插值缩放, 再映射颜色
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	frames, bins := len(values), len(values[0])
+	for x := 0; x < width; x++ {
+		ft := scalePosition(x, width, frames)
+		t0 := int(ft)
+		t1 := min(t0+1, frames-1)
+		dt := ft - float64(t0)
+		for y := 0; y < height; y++ {
+			fk := scalePosition(height-1-y, height, bins)
+			k0 := int(fk)
+			k1 := min(k0+1, bins-1)
+			dk := fk - float64(k0)
+			db := (values[t0][k0]*(1-dk)+values[t0][k1]*dk)*(1-dt) +
+				(values[t1][k0]*(1-dk)+values[t1][k1]*dk)*dt
+
+			level := (db - (maxDB - conf.TopDB)) / conf.TopDB
+			level = math.Max(0, math.Min(1, level))
+			img.SetRGBA(x, y, lut[int(math.Round(level*float64(len(lut)-1)))])
+		}
+	}
+	return img, nil
+}
+
+// scalePosition 目标坐标映射到源坐标 (像素中心对齐)
+func scalePosition(dst, dstSize, srcSize int) float64 {
+	pos := (float64(dst)+0.5)*float64(srcSize)/float64(dstSize) - 0.5
+	return math.Max(0, math.Min(pos, float64(srcSize-1)))
+}
+
+// SaveWaveform 将 WAV 音频渲染为波形图并保存
+//
+// # Params:
+//
+//	imagePath: 图片路径, 支持 .png, .jpg
+//	wavData: 原始 WAV 文件数据
+//	conf: 波形图配置
+func SaveWaveform(imagePath string, wavData []byte, conf WaveformConfig) error {
+	header, samples, err := WavBytesToFloat32(wavData)
+	if err != nil {
+		return err
+	}
+	img, err := RenderWaveform(samples, int(header.NumChannels), conf)
+	if err != nil {
+		return err
+	}
+	return imageutil.Save(imagePath, img, 100)
+}
+
+// SaveSpectrogram 将 WAV 音频渲染为频谱图并保存
+//
+// # Params:
+//
+//	imagePath: 图片路径, 支持 .png, .jpg
+//	wavData: 原始 WAV 文件数据
+//	conf: 频谱图配置
+func SaveSpectrogram(imagePath string, wavData []byte, conf SpectrogramConfig) error {
+	header, samples, err := WavBytesToFloat32(wavData)
+	if err != nil {
+		return err
+	}
+	img, err := RenderSpectrogram(samples, int(header.SampleRate), int(header.NumChannels), conf)
+	if err != nil {
+		return err
+	}
+	return imageutil.Save(imagePath, img, 100)
+}
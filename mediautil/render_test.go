@@ -0,0 +1,101 @@
+package mediautil
+
+import (
+	"image/color"
+	"path/filepath"
+	"testing"
+
+	"github.com/up-zero/gotool/imageutil"
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestGetColormap(t *testing.T) {
+	lut, err := GetColormap(ColormapViridis, 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, lut[0], color.RGBA{R: 0x44, G: 0x01, B: 0x54, A: 255})
+	testutil.Equal(t, lut[255], color.RGBA{R: 0xfd, G: 0xe7, B: 0x25, A: 255})
+
+	gray, _ := GetColormap(ColormapGray, 3)
+	testutil.Equal(t, gray, []color.RGBA{{A: 255}, {R: 128, G: 128, B: 128, A: 255}, {R: 255, G: 255, B: 255, A: 255}})
+
+	_, err = GetColormap("rainbow", 256)
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestRenderWaveform(t *testing.T) {
+	fg := color.RGBA{R: 255, A: 255}
+	samples := []float32{1, -1, 0, 0}
+	img, err := RenderWaveform(samples, 1, WaveformConfig{Width: 2, Height: 11, Foreground: fg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 第一列覆盖 [-1, 1] 全高, 第二列只有中线
+	for y := 0; y < 11; y++ {
+		testutil.Equal(t, img.At(0, y), color.Color(fg))
+	}
+	testutil.Equal(t, img.At(1, 5), color.Color(fg))
+	testutil.Equal(t, img.At(1, 4), color.Color(imageutil.ColorWhite))
+
+	// 双声道上下分栏
+	img, _ = RenderWaveform([]float32{1, 0, 1, 0}, 2, WaveformConfig{Width: 4, Height: 20, Foreground: fg})
+	testutil.Equal(t, img.At(0, 0), color.Color(fg))
+	testutil.Equal(t, img.At(0, 10), color.Color(imageutil.ColorWhite))
+
+	_, err = RenderWaveform(samples, 0, WaveformConfig{})
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestRenderSpectrogram(t *testing.T) {
+	samples := sineWave(2000, SampleRate8K, SampleRate8K)
+	img, err := RenderSpectrogram(samples, SampleRate8K, 1, SpectrogramConfig{NFFT: 256, Colormap: ColormapGray})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 帧数 x 频率 bin 数, 2kHz 位于 bin 64, 低频在下
+	testutil.Equal(t, img.Bounds().Dx(), SampleRate8K/64+1)
+	testutil.Equal(t, img.Bounds().Dy(), 129)
+	x := img.Bounds().Dx() / 2
+	testutil.Equal(t, img.At(x, 128-64), color.Color(color.RGBA{R: 255, G: 255, B: 255, A: 255}))
+	testutil.Equal(t, img.At(x, 0), color.Color(color.RGBA{A: 255}))
+
+	img, err = RenderSpectrogram(samples, SampleRate8K, 1, SpectrogramConfig{NFFT: 512, NMels: 40, Width: 300, Height: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, img.Bounds().Dx(), 300)
+	testutil.Equal(t, img.Bounds().Dy(), 100)
+
+	// 居中补齐后短于一帧的音频仍可渲染
+	img, err = RenderSpectrogram(samples[:100], SampleRate8K, 1, SpectrogramConfig{NFFT: 256})
+	testutil.Equal(t, err, nil)
+	testutil.Equal(t, img.Bounds().Dx(), 2)
+	_, err = RenderSpectrogram(samples, SampleRate8K, 1, SpectrogramConfig{Colormap: "rainbow"})
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestSaveSpectrogram(t *testing.T) {
+	wavData, _ := Float32ToWavBytes(sineWave(440, SampleRate16K, SampleRate16K), SampleRate16K, 1, 16)
+	dir := t.TempDir()
+
+	imagePath := filepath.Join(dir, "spectrogram.png")
+	if err := SaveSpectrogram(imagePath, wavData, SpectrogramConfig{NMels: 64, Colormap: ColormapMagma}); err != nil {
+		t.Fatal(err)
+	}
+	img, err := imageutil.Open(imagePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, img.Bounds().Dy(), 64)
+
+	imagePath = filepath.Join(dir, "waveform.png")
+	if err := SaveWaveform(imagePath, wavData, WaveformConfig{CenterLine: imageutil.ColorGray}); err != nil {
+		t.Fatal(err)
+	}
+	img, err = imageutil.Open(imagePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, img.Bounds().Dx(), 1000)
+}
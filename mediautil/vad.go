@@ -0,0 +1,290 @@
+package mediautil
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/up-zero/gotool"
+)
+
+// VadConfig 语音活动检测 (VAD) 配置，零值字段使用默认值
+type VadConfig struct {
+	FrameMs            int     // 帧长 (毫秒), 默认 20
+	HopMs              int     // 帧移 (毫秒), 默认 10
+	EnergyThresholdDB  float64 // 能量阈值 (dBFS), 高于该值视为语音, 默认 -40
+	ZcrThreshold       float64 // 过零率阈值, 能量略低 (阈值以下 10dB 内) 但过零率高于该值的帧视为清音, 用于扩展语音边界, 默认 0.25
+	UseSpectralEntropy bool    // 是否启用谱熵特征, 启用后谱熵高于 EntropyThreshold 的帧 (类噪声) 不视为语音
+	EntropyThreshold   float64 // 归一化谱熵阈值 (0~1), 默认 0.85
+	MinSpeechMs        int     // 最短语音段 (毫秒), 更短的语音段会被丢弃, 默认 250
+	MinSilenceMs       int     // 最短静音 (毫秒), 更短的静音间隔会被合并, 默认 300
+	PaddingMs          int     // 语音段前后保留的余量 (毫秒), 默认 100, 负数表示不保留余量
+}
+
+// SpeechSegment 语音片段
+type SpeechSegment struct {
+	Start       time.Duration // 开始时间
+	End         time.Duration // 结束时间
+	StartSample int           // 开始采样点 (单声道索引, 包含)
+	EndSample   int           // 结束采样点 (单声道索引, 不包含)
+}
+
+// setDefaults 设置默认值
+func (c *VadConfig) setDefaults() {
+	if c.FrameMs <= 0 {
+		c.FrameMs = 20
+	}
+	if c.HopMs <= 0 {
+		c.HopMs = 10
+	}
+	if c.EnergyThresholdDB == 0 {
+		c.EnergyThresholdDB = -40
+	}
+	if c.ZcrThreshold <= 0 {
+		c.ZcrThreshold = 0.25
+	}
+	if c.EntropyThreshold <= 0 {
+		c.EntropyThreshold = 0.85
+	}
+	if c.MinSpeechMs <= 0 {
+		c.MinSpeechMs = 250
+	}
+	if c.MinSilenceMs <= 0 {
+		c.MinSilenceMs = 300
+	}
+	if c.PaddingMs < 0 {
+		c.PaddingMs = 0
+	} else if c.PaddingMs == 0 {
+		c.PaddingMs = 100
+	}
+}
+
+// DetectSpeech 基于短时能量和过零率 (可选谱熵) 的语音活动检测
+//
+// # Params:
+//
+//	samples: 音频数据, 多声道时为交错排列, 例如 PcmBytesToFloat32 的输出
+//	sampleRate: 采样率
+//	channels: 声道数
+//	conf: VAD 配置
+func DetectSpeech(samples []float32, sampleRate, channels int, conf VadConfig) ([]SpeechSegment, error) {
+	if sampleRate <= 0 || channels <= 0 {
+		return nil, fmt.Errorf("%w, rate=%d, chan=%d", gotool.ErrInvalidParam, sampleRate, channels)
+	}
+	conf.setDefaults()
+
+	mono := downmix(samples, channels)
+	frameLen := sampleRate * conf.FrameMs / 1000
+	hop := sampleRate * conf.HopMs / 1000
+	if frameLen <= 1 || hop <= 0 {
+		return nil, fmt.Errorf("%w, frame too short for sample rate %d", gotool.ErrInvalidParam, sampleRate)
+	}
+	if len(mono) < frameLen {
+		return []SpeechSegment{}, nil
+	}
+	numFrames := 1 + (len(mono)-frameLen)/hop
+
+	// 逐帧判定: 1 为浊音/强语音, 2 为清音候选 (低能量高过零率), 0 为静音
+	states := make([]int, numFrames)
+	for i := 0; i < numFrames; i++ {
+		frame := mono[i*hop : i*hop+frameLen]
+		energy := frameEnergyDB(frame)
+		switch {
+		case energy >= conf.EnergyThresholdDB:
+			states[i] = 1
+		case energy >= conf.EnergyThresholdDB-10 && zeroCrossingRate(frame) >= conf.ZcrThreshold:
+			states[i] = 2
+		}
+		if states[i] == 1 && conf.UseSpectralEntropy && spectralEntropy(frame) > conf.EntropyThreshold {
+			states[i] = 0
+		}
+	}
+
+	// 以浊音帧为核心, 向两侧扩展相邻的清音帧
+	var ranges [][2]int
+	for i := 0; i < numFrames; {
+		if states[i] != 1 {
+			i++
+			continue
+		}
+		start := i
+		for start > 0 && states[start-1] == 2 {
+			start--
+		}
+		end := i
+		for end+1 < numFrames && states[end+1] != 0 {
+			end++
+		}
+		ranges = append(ranges, [2]int{start * hop, end*hop + frameLen})
+		i = end + 1
+	}
+
+	// 合并间隔过短的片段, 丢弃过短的片段, 并补充余量
+	minSilence := sampleRate * conf.MinSilenceMs / 1000
+	minSpeech := sampleRate * conf.MinSpeechMs / 1000
+	padding := sampleRate * conf.PaddingMs / 1000
+
+	var merged [][2]int
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && r[0]-merged[n-1][1] < minSilence {
+			merged[n-1][1] = r[1]
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	segments := make([]SpeechSegment, 0, len(merged))
+	for _, r := range merged {
+		if r[1]-r[0] < minSpeech {
+			continue
+		}
+		start := r[0] - padding
+		if start < 0 {
+			start = 0
+		}
+		end := r[1] + padding
+		if end > len(mono) {
+			end = len(mono)
+		}
+		// 补充余量后与上一段重叠则合并
+		if n := len(segments); n > 0 && start <= segments[n-1].EndSample {
+			segments[n-1].EndSample = end
+			segments[n-1].End = samplesToDuration(end, sampleRate)
+			continue
+		}
+		segments = append(segments, SpeechSegment{
+			Start:       samplesToDuration(start, sampleRate),
+			End:         samplesToDuration(end, sampleRate),
+			StartSample: start,
+			EndSample:   end,
+		})
+	}
+	return segments, nil
+}
+
+// TrimSilence 去除 WAV 音频首尾的静音
+//
+// # Params:
+//
+//	wavData: 原始 WAV 文件数据
+//	conf: VAD 配置
+func TrimSilence(wavData []byte, conf VadConfig) ([]byte, error) {
+	header, samples, err := WavBytesToFloat32(wavData)
+	if err != nil {
+		return nil, err
+	}
+	channels := int(header.NumChannels)
+	segments, err := DetectSpeech(samples, int(header.SampleRate), channels, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	var trimmed []float32
+	if len(segments) > 0 {
+		start := segments[0].StartSample * channels
+		end := segments[len(segments)-1].EndSample * channels
+		trimmed = samples[start:end]
+	}
+	return Float32ToWavBytes(trimmed, int(header.SampleRate), channels, int(header.BitsPerSample), int(header.AudioFormat))
+}
+
+// SplitOnSilence 按静音切分 WAV 音频，每个语音片段输出为一个 WAV 字节流
+//
+// # Params:
+//
+//	wavData: 原始 WAV 文件数据
+//	conf: VAD 配置
+func SplitOnSilence(wavData []byte, conf VadConfig) ([][]byte, error) {
+	header, samples, err := WavBytesToFloat32(wavData)
+	if err != nil {
+		return nil, err
+	}
+	channels := int(header.NumChannels)
+	segments, err := DetectSpeech(samples, int(header.SampleRate), channels, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make([][]byte, 0, len(segments))
+	for _, seg := range segments {
+		chunk, err := Float32ToWavBytes(samples[seg.StartSample*channels:seg.EndSample*channels],
+			int(header.SampleRate), channels, int(header.BitsPerSample), int(header.AudioFormat))
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
+
+// downmix 多声道交错数据混合为单声道
+func downmix(samples []float32, channels int) []float32 {
+	if channels == 1 {
+		return samples
+	}
+	mono := make([]float32, len(samples)/channels)
+	for i := range mono {
+		var sum float32
+		for c := 0; c < channels; c++ {
+			sum += samples[i*channels+c]
+		}
+		mono[i] = sum / float32(channels)
+	}
+	return mono
+}
+
+// frameEnergyDB 计算帧的均方能量 (dBFS)
+func frameEnergyDB(frame []float32) float64 {
+	var sum float64
+	for _, v := range frame {
+		sum += float64(v) * float64(v)
+	}
+	return 10 * math.Log10(sum/float64(len(frame))+1e-12)
+}
+
+// zeroCrossingRate 计算帧的过零率 (0~1)
+func zeroCrossingRate(frame []float32) float64 {
+	if len(frame) < 2 {
+		return 0
+	}
+	crossings := 0
+	for i := 1; i < len(frame); i++ {
+		if (frame[i] >= 0) != (frame[i-1] >= 0) {
+			crossings++
+		}
+	}
+	return float64(crossings) / float64(len(frame)-1)
+}
+
+// spectralEntropy 计算帧的归一化谱熵 (0~1), 值越大越接近白噪声
+func spectralEntropy(frame []float32) float64 {
+	windowed := make([]float32, len(frame))
+	window := HannWindow(len(frame))
+	for i, v := range frame {
+		windowed[i] = v * window[i]
+	}
+	spec := RFFT(windowed)
+
+	power := make([]float64, len(spec))
+	var total float64
+	for i, v := range spec {
+		power[i] = real(v)*real(v) + imag(v)*imag(v)
+		total += power[i]
+	}
+	if total <= 0 || len(power) < 2 {
+		return 1
+	}
+	var entropy float64
+	for _, p := range power {
+		if p > 0 {
+			prob := p / total
+			entropy -= prob * math.Log(prob)
+		}
+	}
+	return entropy / math.Log(float64(len(power)))
+}
+
+// samplesToDuration 采样点数转时长
+func samplesToDuration(n, sampleRate int) time.Duration {
+	return time.Duration(float64(n) / float64(sampleRate) * float64(time.Second))
+}
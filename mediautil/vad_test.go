@@ -0,0 +1,66 @@
+package mediautil
+
+import (
+	"testing"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+// speechLikeSamples 生成 静音(0.5s) + 正弦(1s) + 静音(1s) + 正弦(0.5s) + 静音(0.5s) 的测试数据
+func speechLikeSamples(sampleRate int) []float32 {
+	var samples []float32
+	samples = append(samples, make([]float32, sampleRate/2)...)
+	samples = append(samples, sineWave(300, sampleRate, sampleRate)...)
+	samples = append(samples, make([]float32, sampleRate)...)
+	samples = append(samples, sineWave(300, sampleRate, sampleRate/2)...)
+	samples = append(samples, make([]float32, sampleRate/2)...)
+	return samples
+}
+
+func TestDetectSpeech(t *testing.T) {
+	samples := speechLikeSamples(SampleRate16K)
+	segments, err := DetectSpeech(samples, SampleRate16K, 1, VadConfig{PaddingMs: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, len(segments), 2)
+	testutil.EqualFloat(t, segments[0].Start.Seconds(), 0.5, 0.02)
+	testutil.EqualFloat(t, segments[0].End.Seconds(), 1.5, 0.02)
+	testutil.EqualFloat(t, segments[1].Start.Seconds(), 2.5, 0.02)
+
+	// 谱熵: 纯音的谱熵很低, 仍被判定为语音
+	segments, err = DetectSpeech(samples, SampleRate16K, 1, VadConfig{UseSpectralEntropy: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, len(segments), 2)
+}
+
+func TestTrimSilence(t *testing.T) {
+	wavData, err := Float32ToWavBytes(speechLikeSamples(SampleRate16K), SampleRate16K, 1, BitsPerSample16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trimmed, err := TrimSilence(wavData, VadConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, err := ParseWavHeader(trimmed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1.5s 到 3.0s 之间的内容 (2.5s) 加上前后 100ms 余量
+	testutil.EqualFloat(t, header.GetDuration().Seconds(), 2.7, 0.03)
+}
+
+func TestSplitOnSilence(t *testing.T) {
+	wavData, err := Float32ToWavBytes(speechLikeSamples(SampleRate16K), SampleRate16K, 1, BitsPerSample16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks, err := SplitOnSilence(wavData, VadConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, len(chunks), 2)
+}
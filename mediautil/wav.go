@@ -18,6 +18,8 @@ var (
 	ErrNotWavFile = errors.New("not a valid RIFF/WAVE file")
 	// ErrUnsupportedBitDepth 不支持的位深
 	ErrUnsupportedBitDepth = errors.New("unsupported bit depth: only 16, 24, 32 are supported")
+	// ErrUnsupportedAudioFormat 不支持的 WAV 编码格式
+	ErrUnsupportedAudioFormat = errors.New("unsupported wav audio format")
 )
 
 const (
@@ -38,8 +40,31 @@ const (
 	BitsPerSample24 = 24
 	// BitsPerSample32 位深 32
 	BitsPerSample32 = 32
+
+	// WavFormatPCM 编码格式 PCM
+	WavFormatPCM = 0x0001
+	// WavFormatMSADPCM 编码格式 Microsoft ADPCM
+	WavFormatMSADPCM = 0x0002
+	// WavFormatIEEEFloat 编码格式 IEEE 浮点
+	WavFormatIEEEFloat = 0x0003
+	// WavFormatALaw 编码格式 G.711 A-law
+	WavFormatALaw = 0x0006
+	// WavFormatMuLaw 编码格式 G.711 μ-law
+	WavFormatMuLaw = 0x0007
+	// WavFormatIMAADPCM 编码格式 IMA ADPCM
+	WavFormatIMAADPCM = 0x0011
+	// WavFormatExtensible 扩展格式, 实际编码由 fmt 扩展中的 SubFormat 指定
+	WavFormatExtensible = 0xFFFE
 )
 
+// wavInfo WAV 分块解析结果
+type wavInfo struct {
+	header       *WavHeader
+	fmtExtra     []byte // fmt 扩展数据 (cbSize 之后的部分)
+	dataOffset   int    // data 数据在文件中的偏移
+	sampleFrames int    // fact 块中的采样帧数, -1 表示不存在
+}
+
 // WavHeader 定义了标准的 WAV 文件头 (44 bytes)
 // 对应 RIFF WAVE 格式标准
 type WavHeader struct {
@@ -91,19 +116,95 @@ func ParseWavHeader(data []byte) (*WavHeader, error) {
 		return nil, fmt.Errorf("%w, data length must be at least 44 bytes", gotool.ErrInvalidParam)
 	}
 
-	// 使用 LittleEndian 解析
-	reader := bytes.NewReader(data[:44])
-	header := &WavHeader{}
-	if err := binary.Read(reader, binary.LittleEndian, header); err != nil {
+	info, err := readWavInfo(bytes.NewReader(data))
+	if err != nil {
 		return nil, err
 	}
+	return info.header, nil
+}
+
+// readWavInfo 按 RIFF 分块解析 WAV 头部, 读取到 data 块的头部为止
+//
+// 支持 fmt 扩展 (非 PCM 编码) 以及 fact、LIST 等附加块
+func readWavInfo(r io.Reader) (*wavInfo, error) {
+	header := &WavHeader{}
+	riff := make([]byte, 12)
+	if _, err := io.ReadFull(r, riff); err != nil {
+		return nil, ErrNotWavFile
+	}
+	copy(header.ChunkID[:], riff[0:4])
+	header.ChunkSize = binary.LittleEndian.Uint32(riff[4:8])
+	copy(header.Format[:], riff[8:12])
 
 	// 校验标志位
 	if string(header.ChunkID[:]) != "RIFF" || string(header.Format[:]) != "WAVE" {
 		return nil, ErrNotWavFile
 	}
 
-	return header, nil
+	info := &wavInfo{header: header, dataOffset: 12, sampleFrames: -1}
+	fmtFound := false
+	chunk := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			return nil, fmt.Errorf("%w, data chunk not found", ErrNotWavFile)
+		}
+		id := string(chunk[:4])
+		size := int64(binary.LittleEndian.Uint32(chunk[4:]))
+		info.dataOffset += 8
+
+		if id == "data" {
+			if !fmtFound {
+				return nil, fmt.Errorf("%w, fmt chunk not found", ErrNotWavFile)
+			}
+			copy(header.Subchunk2ID[:], chunk[:4])
+			header.Subchunk2Size = uint32(size)
+			return info, nil
+		}
+
+		// 块大小为奇数时有 1 字节填充
+		skip := size + size%2
+		if id != "fmt " && id != "fact" {
+			if _, err := io.CopyN(io.Discard, r, skip); err != nil {
+				return nil, fmt.Errorf("%w, data chunk not found", ErrNotWavFile)
+			}
+			info.dataOffset += int(skip)
+			continue
+		}
+		if skip > 1<<16 {
+			return nil, fmt.Errorf("%w, %q chunk too large", ErrNotWavFile, id)
+		}
+		body := make([]byte, skip)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return nil, fmt.Errorf("%w, truncated %q chunk", ErrNotWavFile, id)
+		}
+		info.dataOffset += int(skip)
+
+		if id == "fact" {
+			if size >= 4 {
+				info.sampleFrames = int(binary.LittleEndian.Uint32(body))
+			}
+			continue
+		}
+		if size < 16 {
+			return nil, fmt.Errorf("%w, fmt chunk too small", ErrNotWavFile)
+		}
+		fmtFound = true
+		copy(header.Subchunk1ID[:], chunk[:4])
+		header.Subchunk1Size = uint32(size)
+		header.AudioFormat = binary.LittleEndian.Uint16(body[0:])
+		header.NumChannels = binary.LittleEndian.Uint16(body[2:])
+		header.SampleRate = binary.LittleEndian.Uint32(body[4:])
+		header.ByteRate = binary.LittleEndian.Uint32(body[8:])
+		header.BlockAlign = binary.LittleEndian.Uint16(body[12:])
+		header.BitsPerSample = binary.LittleEndian.Uint16(body[14:])
+		if size >= 18 {
+			cbSize := int64(binary.LittleEndian.Uint16(body[16:]))
+			if cbSize > size-18 {
+				cbSize = size - 18
+			}
+			info.fmtExtra = body[18 : 18+cbSize]
+		}
+	}
 }
 
 // ReadWavHeader 从文件中读取 WAV 头部
@@ -118,13 +219,12 @@ func ReadWavHeader(filePath string) (*WavHeader, error) {
 	}
 	defer file.Close()
 
-	// 只读取前 44 个字节，避免加载整个大文件
-	headerBytes := make([]byte, 44)
-	if _, err := io.ReadFull(file, headerBytes); err != nil {
+	// 只读取到 data 块头部，避免加载整个大文件
+	info, err := readWavInfo(file)
+	if err != nil {
 		return nil, err
 	}
-
-	return ParseWavHeader(headerBytes)
+	return info.header, nil
 }
 
 // WriteWav 将 PCM 数据封装为 WAV 格式写入 io.Writer
@@ -132,14 +232,21 @@ func ReadWavHeader(filePath string) (*WavHeader, error) {
 // # Params:
 //
 //	w: 写入目标
-//	pcmData: 原始 PCM 数据
+//	pcmData: 原始 PCM 数据, 指定编码格式时为对应编码后的数据
 //	sampleRate: 采样率
 //	channels: 声道数
 //	bitsPerSample: 位深
-func WriteWav(w io.Writer, pcmData []byte, sampleRate, channels, bitsPerSample int) error {
+//	audioFormat: 编码格式 (可选), 默认 WavFormatPCM
+//	 - WavFormatIEEEFloat: 位深 32 或 64
+//	 - WavFormatALaw, WavFormatMuLaw: 位深固定为 8
+//	 - WavFormatIMAADPCM, WavFormatMSADPCM: 位深固定为 4, 块大小为 AdpcmBlockAlign(sampleRate, channels)
+func WriteWav(w io.Writer, pcmData []byte, sampleRate, channels, bitsPerSample int, audioFormat ...int) error {
 	if sampleRate <= 0 || channels <= 0 || bitsPerSample <= 0 {
 		return fmt.Errorf("%w, rate=%d, chan=%d, bit=%d", gotool.ErrInvalidParam, sampleRate, channels, bitsPerSample)
 	}
+	if len(audioFormat) > 0 && audioFormat[0] != WavFormatPCM {
+		return writeWavExtended(w, pcmData, sampleRate, channels, bitsPerSample, audioFormat[0], -1)
+	}
 
 	dataSize := uint32(len(pcmData))
 
@@ -184,6 +291,92 @@ func WriteWav(w io.Writer, pcmData []byte, sampleRate, channels, bitsPerSample i
 	return nil
 }
 
+// writeWavExtended 写入非 PCM 编码的 WAV, fmt 块包含 cbSize 扩展并附带 fact 块
+//
+// # Params:
+//
+//	sampleFrames: 采样帧数, 负数表示根据数据长度推算
+func writeWavExtended(w io.Writer, data []byte, sampleRate, channels, bitsPerSample, audioFormat, sampleFrames int) error {
+	var blockAlign, framesPerBlock int
+	var extra []byte
+	switch audioFormat {
+	case WavFormatIEEEFloat:
+		if bitsPerSample != 32 && bitsPerSample != 64 {
+			return fmt.Errorf("%w, float wav bit=%d", ErrUnsupportedBitDepth, bitsPerSample)
+		}
+		blockAlign, framesPerBlock = channels*bitsPerSample/8, 1
+	case WavFormatALaw, WavFormatMuLaw:
+		bitsPerSample = 8
+		blockAlign, framesPerBlock = channels, 1
+	case WavFormatIMAADPCM:
+		bitsPerSample = 4
+		blockAlign = AdpcmBlockAlign(sampleRate, channels)
+		framesPerBlock = imaSamplesPerBlock(blockAlign, channels)
+		extra = binary.LittleEndian.AppendUint16(nil, uint16(framesPerBlock))
+	case WavFormatMSADPCM:
+		if err := checkMsBlock(channels, AdpcmBlockAlign(sampleRate, channels)); err != nil {
+			return err
+		}
+		bitsPerSample = 4
+		blockAlign = AdpcmBlockAlign(sampleRate, channels)
+		framesPerBlock = msSamplesPerBlock(blockAlign, channels)
+		extra = binary.LittleEndian.AppendUint16(nil, uint16(framesPerBlock))
+		extra = binary.LittleEndian.AppendUint16(extra, uint16(len(msAdpcmCoeffs)))
+		for _, c := range msAdpcmCoeffs {
+			extra = binary.LittleEndian.AppendUint16(extra, uint16(int16(c[0])))
+			extra = binary.LittleEndian.AppendUint16(extra, uint16(int16(c[1])))
+		}
+	default:
+		return fmt.Errorf("%w: %d", ErrUnsupportedAudioFormat, audioFormat)
+	}
+	if sampleFrames < 0 {
+		sampleFrames = len(data) / blockAlign * framesPerBlock
+	}
+
+	fmtSize := 18 + len(extra)
+	dataSize := len(data)
+	pad := dataSize % 2
+	buf := make([]byte, 0, 12+8+fmtSize+12+8)
+
+	// RIFF Chunk
+	buf = append(buf, "RIFF"...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(4+8+fmtSize+12+8+dataSize+pad))
+	buf = append(buf, "WAVE"...)
+
+	// fmt Chunk
+	buf = append(buf, "fmt "...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(fmtSize))
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(audioFormat))
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(channels))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(sampleRate))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(sampleRate*blockAlign/framesPerBlock))
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(blockAlign))
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(bitsPerSample))
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(len(extra)))
+	buf = append(buf, extra...)
+
+	// fact Chunk
+	buf = append(buf, "fact"...)
+	buf = binary.LittleEndian.AppendUint32(buf, 4)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(sampleFrames))
+
+	// data Chunk
+	buf = append(buf, "data"...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(dataSize))
+	if _, err := w.Write(buf); err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if pad > 0 {
+		if _, err := w.Write([]byte{0}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // SaveWav 将 PCM 数据保存为本地 WAV 文件
 //
 // # Params:
@@ -193,7 +386,8 @@ func WriteWav(w io.Writer, pcmData []byte, sampleRate, channels, bitsPerSample i
 //	sampleRate: 采样率,例如: 16000(16KHz), 44100(44.1KHz)
 //	channels: 声道数
 //	bitsPerSample: 位深,例如: 16(CD音质), 24(专业录音), 32
-func SaveWav(filePath string, pcmData []byte, sampleRate, channels, bitsPerSample int) error {
+//	audioFormat: 编码格式 (可选), 同 WriteWav
+func SaveWav(filePath string, pcmData []byte, sampleRate, channels, bitsPerSample int, audioFormat ...int) error {
 	// 创建文件
 	f, err := os.Create(filePath)
 	if err != nil {
@@ -201,7 +395,7 @@ func SaveWav(filePath string, pcmData []byte, sampleRate, channels, bitsPerSampl
 	}
 	defer f.Close()
 
-	return WriteWav(f, pcmData, sampleRate, channels, bitsPerSample)
+	return WriteWav(f, pcmData, sampleRate, channels, bitsPerSample, audioFormat...)
 }
 
 // Float32ToPcmBytes 将标准浮点音频数据转换为指定位深的 PCM 字节流
@@ -289,10 +483,14 @@ func Float32ToPcmBytes(data []float32, bitsPerSample int) ([]byte, error) {
 //	sampleRate: 采样率
 //	channels: 声道数
 //	bitsPerSample: 位深
-func Float32ToWavBytes(data []float32, sampleRate, channels, bitsPerSample int) ([]byte, error) {
+//	audioFormat: 编码格式 (可选), 默认 WavFormatPCM, G.711 和 ADPCM 编码时忽略 bitsPerSample
+func Float32ToWavBytes(data []float32, sampleRate, channels, bitsPerSample int, audioFormat ...int) ([]byte, error) {
 	if sampleRate <= 0 || channels <= 0 || bitsPerSample <= 0 {
 		return nil, fmt.Errorf("%w, rate=%d, chan=%d, bit=%d", gotool.ErrInvalidParam, sampleRate, channels, bitsPerSample)
 	}
+	if len(audioFormat) > 0 && audioFormat[0] != WavFormatPCM {
+		return encodeWavBytes(data, sampleRate, channels, bitsPerSample, audioFormat[0])
+	}
 
 	// 将 Float32 数据转换为裸 PCM 字节流
 	pcmBody, err := Float32ToPcmBytes(data, bitsPerSample)
@@ -310,6 +508,144 @@ func Float32ToWavBytes(data []float32, sampleRate, channels, bitsPerSample int)
 	return buf.Bytes(), nil
 }
 
+// encodeWavBytes 将浮点音频数据按指定编码格式转换为 WAV 文件字节流
+func encodeWavBytes(data []float32, sampleRate, channels, bitsPerSample, audioFormat int) ([]byte, error) {
+	if len(data)%channels != 0 {
+		return nil, fmt.Errorf("%w, samples length is not aligned with channels", gotool.ErrInvalidParam)
+	}
+
+	var body []byte
+	var err error
+	switch audioFormat {
+	case WavFormatIEEEFloat:
+		if bitsPerSample != 32 && bitsPerSample != 64 {
+			return nil, fmt.Errorf("%w, float wav bit=%d", ErrUnsupportedBitDepth, bitsPerSample)
+		}
+		body = make([]byte, 0, len(data)*bitsPerSample/8)
+		for _, v := range data {
+			if bitsPerSample == 32 {
+				body = binary.LittleEndian.AppendUint32(body, math.Float32bits(v))
+			} else {
+				body = binary.LittleEndian.AppendUint64(body, math.Float64bits(float64(v)))
+			}
+		}
+	case WavFormatALaw:
+		body = ALawEncode(data)
+	case WavFormatMuLaw:
+		body = MuLawEncode(data)
+	case WavFormatIMAADPCM:
+		body, err = ImaAdpcmEncode(data, channels, AdpcmBlockAlign(sampleRate, channels))
+	case WavFormatMSADPCM:
+		body, err = MsAdpcmEncode(data, channels, AdpcmBlockAlign(sampleRate, channels))
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedAudioFormat, audioFormat)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	if err := writeWavExtended(buf, body, sampleRate, channels, bitsPerSample, audioFormat, len(data)/channels); err != nil {
+		return nil, fmt.Errorf("write wav failed: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// WavBytesToFloat32 解析 WAV 文件字节流并解码为 float32 数组
+//
+// 根据 fmt 中的编码格式自动解码, 支持 PCM (16, 24, 32 bit), IEEE 浮点 (32, 64 bit),
+// G.711 A-law/μ-law, IMA ADPCM, Microsoft ADPCM, 扩展格式 (WavFormatExtensible) 的头部
+// AudioFormat 会被替换为实际的子格式
+//
+// # Params:
+//
+//	wavData: 原始 WAV 文件数据
+func WavBytesToFloat32(wavData []byte) (*WavHeader, []float32, error) {
+	info, err := readWavInfo(bytes.NewReader(wavData))
+	if err != nil {
+		return nil, nil, err
+	}
+	header := info.header
+	channels := int(header.NumChannels)
+	if channels == 0 {
+		return nil, nil, fmt.Errorf("%w, channels must be positive", gotool.ErrInvalidParam)
+	}
+
+	// 数据块长度以实际数据为准 (流式写入的文件可能未回填长度)
+	raw := wavData[info.dataOffset:]
+	if int64(header.Subchunk2Size) < int64(len(raw)) {
+		raw = raw[:header.Subchunk2Size]
+	}
+
+	// 扩展格式: cbSize 之后依次为 ValidBitsPerSample(2), ChannelMask(4), SubFormat GUID(16)
+	if header.AudioFormat == WavFormatExtensible {
+		if len(info.fmtExtra) < 22 {
+			return nil, nil, fmt.Errorf("%w, invalid extensible fmt chunk", ErrNotWavFile)
+		}
+		header.AudioFormat = binary.LittleEndian.Uint16(info.fmtExtra[6:])
+	}
+
+	var samples []float32
+	bits := int(header.BitsPerSample)
+	blockAlign := int(header.BlockAlign)
+	switch header.AudioFormat {
+	case WavFormatPCM:
+		if bits%8 == 0 && bits > 0 {
+			raw = raw[:len(raw)/(bits/8)*(bits/8)]
+		}
+		samples, err = PcmBytesToFloat32(raw, bits)
+	case WavFormatIEEEFloat:
+		switch bits {
+		case 32:
+			samples = make([]float32, len(raw)/4)
+			for i := range samples {
+				samples[i] = math.Float32frombits(binary.LittleEndian.Uint32(raw[i*4:]))
+			}
+		case 64:
+			samples = make([]float32, len(raw)/8)
+			for i := range samples {
+				samples[i] = float32(math.Float64frombits(binary.LittleEndian.Uint64(raw[i*8:])))
+			}
+		default:
+			err = ErrUnsupportedBitDepth
+		}
+	case WavFormatALaw:
+		samples = ALawDecode(raw)
+	case WavFormatMuLaw:
+		samples = MuLawDecode(raw)
+	case WavFormatIMAADPCM:
+		samples, err = ImaAdpcmDecode(raw, channels, blockAlign)
+	case WavFormatMSADPCM:
+		// fmt 扩展: SamplesPerBlock(2), NumCoef(2), Coef[NumCoef]{int16, int16}
+		coeffs := msAdpcmCoeffs
+		if extra := info.fmtExtra; len(extra) >= 4 {
+			num := int(binary.LittleEndian.Uint16(extra[2:]))
+			if num > 0 && len(extra) >= 4+num*4 {
+				coeffs = make([][2]int, num)
+				for i := range coeffs {
+					coeffs[i][0] = int(int16(binary.LittleEndian.Uint16(extra[4+i*4:])))
+					coeffs[i][1] = int(int16(binary.LittleEndian.Uint16(extra[6+i*4:])))
+				}
+			}
+		}
+		samples, err = msAdpcmDecode(raw, channels, blockAlign, coeffs)
+	default:
+		return nil, nil, fmt.Errorf("%w: %d", ErrUnsupportedAudioFormat, header.AudioFormat)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode wav failed: %w", err)
+	}
+
+	// ADPCM 最后一个块存在补齐数据, 以 fact 中的采样帧数为准
+	if info.sampleFrames >= 0 && info.sampleFrames*channels < len(samples) &&
+		(header.AudioFormat == WavFormatIMAADPCM || header.AudioFormat == WavFormatMSADPCM) {
+		samples = samples[:info.sampleFrames*channels]
+	}
+	// 去除不完整的采样帧
+	samples = samples[:len(samples)/channels*channels]
+	return header, samples, nil
+}
+
 // PcmBytesToFloat32 PCM 字节流转 float32 数组
 //
 // # Params:
@@ -358,9 +694,30 @@ func PcmBytesToFloat32(data []byte, bitPerSample int) ([]float32, error) {
 	return output, nil
 }
 
+// sampleToFloat32 整数采样转浮点, 缩放方式与 PcmBytesToFloat32 一致
+func sampleToFloat32(v int64, bits int) float32 {
+	return float32(float64(v) / float64(int64(1)<<(bits-1)-1))
+}
+
+// float32ToSample 浮点采样四舍五入量化为整数, 位深不超过 24 bit 时可无损还原 sampleToFloat32 的结果
+func float32ToSample(v float32, bits int) int64 {
+	if v != v {
+		return 0
+	}
+	scale := int64(1)<<(bits-1) - 1
+	n := math.Round(float64(v) * float64(scale))
+	if n > float64(scale) {
+		return scale
+	}
+	if n < float64(-scale-1) {
+		return -scale - 1
+	}
+	return int64(n)
+}
+
 // ReformatWavBytes WAV 字节流格式转换
 //
-// 支持：位深转换、采样率转换、声道转换
+// 支持：位深转换、采样率转换、声道转换，非 PCM 编码 (G.711、ADPCM 等) 的输入会被转换为 PCM
 //
 // # Params:
 //
@@ -374,6 +731,7 @@ func ReformatWavBytes(wavData []byte, targetRate, targetChannels, targetBitPerSa
 	if err != nil {
 		return nil, err
 	}
+	isPCM := header.AudioFormat == WavFormatPCM
 
 	// 当前的参数状态
 	currentRate := int(header.SampleRate)
@@ -381,18 +739,17 @@ func ReformatWavBytes(wavData []byte, targetRate, targetChannels, targetBitPerSa
 	currentBitPerSample := int(header.BitsPerSample)
 
 	// 不存在转换,直接返回
-	if targetRate == currentRate &&
+	if isPCM &&
+		targetRate == currentRate &&
 		targetChannels == currentChannels &&
 		targetBitPerSample == currentBitPerSample {
 		return wavData, nil
 	}
 
-	// 提取 PCM 数据并转为 float32
-	const headerSize = 44
-	pcmRaw := wavData[headerSize:]
-	samples, err := PcmBytesToFloat32(pcmRaw, int(header.BitsPerSample))
+	// 提取音频数据并转为 float32, 非 PCM 编码先解码
+	_, samples, err := WavBytesToFloat32(wavData)
 	if err != nil {
-		return nil, fmt.Errorf("decode pcm failed: %w", err)
+		return nil, err
 	}
 
 	// 声道转换
@@ -411,9 +768,12 @@ func ReformatWavBytes(wavData []byte, targetRate, targetChannels, targetBitPerSa
 		currentRate = targetRate
 	}
 
-	// 目标位深
+	// 目标位深, 非 PCM 编码的源默认输出 16 bit
 	if targetBitPerSample <= 0 {
 		targetBitPerSample = int(header.BitsPerSample)
+		if !isPCM {
+			targetBitPerSample = BitsPerSample16
+		}
 	}
 
 	// 编码回 WAV
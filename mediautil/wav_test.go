@@ -1,9 +1,13 @@
 package mediautil
 
 import (
+	"bytes"
+	"encoding/binary"
 	"math/rand"
 	"os"
 	"testing"
+
+	"github.com/up-zero/gotool/testutil"
 )
 
 func TestReadWavHeader(t *testing.T) {
@@ -46,3 +50,97 @@ func TestReformatWavBytes(t *testing.T) {
 	}
 	os.WriteFile("reformat.wav", newWavBytes, 0644)
 }
+
+func TestWavBytesToFloat32(t *testing.T) {
+	samples := sineWave(440, SampleRate8K, 1000)
+	for _, format := range []int{WavFormatPCM, WavFormatIEEEFloat, WavFormatALaw, WavFormatMuLaw, WavFormatIMAADPCM, WavFormatMSADPCM} {
+		wavData, err := Float32ToWavBytes(samples, SampleRate8K, 1, BitsPerSample32, format)
+		if err != nil {
+			t.Fatalf("format %d: %v", format, err)
+		}
+		header, decoded, err := WavBytesToFloat32(wavData)
+		if err != nil {
+			t.Fatalf("format %d: %v", format, err)
+		}
+		testutil.Equal(t, int(header.AudioFormat), format)
+		testutil.Equal(t, header.SampleRate, uint32(SampleRate8K))
+		testutil.Equal(t, len(decoded), len(samples))
+		if snr := snrDB(samples, decoded); snr < 20 {
+			t.Fatalf("format %d snr too low: %.2f dB", format, snr)
+		}
+	}
+}
+
+func TestWriteWavFormat(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := WriteWav(buf, MuLawEncode(sineWave(440, SampleRate8K, 801)), SampleRate8K, 1, 16, WavFormatMuLaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, err := ParseWavHeader(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, header.AudioFormat, uint16(WavFormatMuLaw))
+	testutil.Equal(t, header.BitsPerSample, uint16(8))
+	testutil.Equal(t, header.Subchunk1Size, uint32(18))
+	testutil.Equal(t, header.Subchunk2Size, uint32(801))
+	testutil.Equal(t, header.ByteRate, uint32(SampleRate8K))
+	// 数据长度为奇数时补齐 1 字节
+	testutil.Equal(t, int(header.ChunkSize)+8, buf.Len())
+	testutil.Equal(t, buf.Len()%2, 0)
+
+	// PCM 保持 44 字节的标准头部
+	buf.Reset()
+	_ = WriteWav(buf, make([]byte, 100), SampleRate16K, 1, 16)
+	testutil.Equal(t, buf.Len(), 144)
+
+	err = WriteWav(buf, nil, SampleRate8K, 1, 16, 0x55)
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestParseWavHeaderChunks(t *testing.T) {
+	pcm, _ := Float32ToWavBytes(sineWave(440, SampleRate16K, 160), SampleRate16K, 1, 16)
+
+	// 在 fmt 与 data 之间插入 LIST 块 (奇数长度, 含填充字节)
+	list := append([]byte("LIST"), binary.LittleEndian.AppendUint32(nil, 5)...)
+	list = append(list, 'I', 'N', 'F', 'O', 'x', 0)
+	wavData := append(append(append([]byte{}, pcm[:36]...), list...), pcm[36:]...)
+	binary.LittleEndian.PutUint32(wavData[4:], uint32(len(wavData)-8))
+
+	header, err := ParseWavHeader(wavData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, string(header.Subchunk2ID[:]), "data")
+	testutil.Equal(t, header.Subchunk2Size, uint32(320))
+
+	_, a, _ := WavBytesToFloat32(pcm)
+	_, b, err := WavBytesToFloat32(wavData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, b, a)
+
+	_, err = ParseWavHeader(append([]byte("RIFF\x00\x00\x00\x00WAVE"), make([]byte, 40)...))
+	testutil.NotEqual(t, err, nil)
+}
+
+func TestReformatWavBytesCodec(t *testing.T) {
+	samples := sineWave(440, SampleRate8K, 800)
+	wavData, err := Float32ToWavBytes(samples, SampleRate8K, 1, 8, WavFormatALaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pcm, err := ReformatWavBytes(wavData, SampleRate8K, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, err := ParseWavHeader(pcm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, header.AudioFormat, uint16(WavFormatPCM))
+	testutil.Equal(t, header.BitsPerSample, uint16(BitsPerSample16))
+	testutil.Equal(t, header.Subchunk2Size, uint32(1600))
+}
@@ -0,0 +1,224 @@
+package netutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/up-zero/gotool"
+)
+
+const (
+	contentTypeJSON   = "application/json;charset=UTF-8"
+	contentTypeForm   = "application/x-www-form-urlencoded"
+	contentTypeStream = "application/octet-stream"
+)
+
+// UploadProgressCallback 上传进度回调，totalBytes 未知时为 -1
+type UploadProgressCallback func(finishBytes, totalBytes int64)
+
+// MultipartFile multipart/form-data 中的文件
+type MultipartFile struct {
+	FieldName   string    // 表单字段名
+	FileName    string    // 文件名, 为空时取 Path 的基础名称
+	Path        string    // 文件路径, 上传时从磁盘流式读取
+	Reader      io.Reader // 文件内容, Path 为空时使用
+	ContentType string    // 内容类型, 默认 application/octet-stream
+}
+
+// MultipartForm multipart/form-data 表单，请求体边读边生成，不会将文件整体读入内存
+type MultipartForm struct {
+	Fields   map[string]string      // 普通字段
+	Files    []MultipartFile        // 文件
+	Progress UploadProgressCallback // 上传进度回调, 可选
+}
+
+// newRequestBody 根据 data 类型生成请求体
+//
+//	nil: 没有请求体
+//	[]byte: 原样发送, Content-Type 为 JSON
+//	url.Values: application/x-www-form-urlencoded
+//	*MultipartForm: multipart/form-data, 流式发送
+//	io.Reader: 原样流式发送, Content-Type 为 application/octet-stream
+//	其他: 编码为 JSON
+//
+// size 为请求体长度, 未知时为 -1
+func newRequestBody(data any) (body io.Reader, contentType string, size int64, err error) {
+	switch v := data.(type) {
+	case nil:
+		return nil, "", 0, nil
+	case []byte:
+		return bytes.NewReader(v), contentTypeJSON, int64(len(v)), nil
+	case url.Values:
+		s := v.Encode()
+		return strings.NewReader(s), contentTypeForm, int64(len(s)), nil
+	case *MultipartForm:
+		return v.body()
+	case io.Reader:
+		return v, contentTypeStream, -1, nil
+	default:
+		dataBytes, err := json.Marshal(data)
+		if err != nil {
+			return nil, "", 0, err
+		}
+		return bytes.NewReader(dataBytes), contentTypeJSON, int64(len(dataBytes)), nil
+	}
+}
+
+// countWriter 只统计写入的字节数
+type countWriter int64
+
+// Write 实现 io.Writer
+func (w *countWriter) Write(p []byte) (int, error) {
+	*w += countWriter(len(p))
+	return len(p), nil
+}
+
+// multipartBody 延迟生成的 multipart 请求体, 第一次读取时才开始打开文件,
+// 关闭时中止生成, 避免请求未发送或中途失败时泄露 goroutine 和文件句柄
+type multipartBody struct {
+	form     *MultipartForm
+	boundary string
+	total    int64
+	finish   int64
+	once     sync.Once
+	pr       *io.PipeReader
+}
+
+// body 校验表单并计算请求体长度
+func (f *MultipartForm) body() (io.Reader, string, int64, error) {
+	sizes := make([]int64, len(f.Files))
+	for i, file := range f.Files {
+		if file.FieldName == "" {
+			return nil, "", 0, fmt.Errorf("%w, multipart file field name is empty", gotool.ErrInvalidParam)
+		}
+		switch {
+		case file.Path != "":
+			info, err := os.Stat(file.Path)
+			if err != nil {
+				return nil, "", 0, err
+			}
+			sizes[i] = info.Size()
+		case file.Reader != nil && file.FileName != "":
+			sizes[i] = -1
+		default:
+			return nil, "", 0, fmt.Errorf("%w, multipart file %q has no path or reader", gotool.ErrInvalidParam, file.FieldName)
+		}
+	}
+
+	// 用相同的 boundary 写一遍除文件内容外的部分, 得到请求体长度
+	var cw countWriter
+	mw := multipart.NewWriter(&cw)
+	if err := f.writeTo(mw, func(io.Writer, MultipartFile) error { return nil }); err != nil {
+		return nil, "", 0, err
+	}
+	total := int64(cw)
+	for _, size := range sizes {
+		if size < 0 {
+			total = -1
+			break
+		}
+		total += size
+	}
+	b := &multipartBody{form: f, boundary: mw.Boundary(), total: total}
+	return b, mw.FormDataContentType(), total, nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// writeTo 按字段名顺序写入普通字段, 再写入文件, 文件内容由 copyFile 写入
+func (f *MultipartForm) writeTo(mw *multipart.Writer, copyFile func(w io.Writer, file MultipartFile) error) error {
+	keys := make([]string, 0, len(f.Fields))
+	for k := range f.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := mw.WriteField(k, f.Fields[k]); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range f.Files {
+		name := file.FileName
+		if name == "" {
+			name = filepath.Base(file.Path)
+		}
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = contentTypeStream
+		}
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			quoteEscaper.Replace(file.FieldName), quoteEscaper.Replace(name)))
+		h.Set("Content-Type", contentType)
+		part, err := mw.CreatePart(h)
+		if err != nil {
+			return err
+		}
+		if err := copyFile(part, file); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+// start 启动生成请求体的 goroutine
+func (b *multipartBody) start() {
+	pr, pw := io.Pipe()
+	b.pr = pr
+	go func() {
+		mw := multipart.NewWriter(pw)
+		if err := mw.SetBoundary(b.boundary); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		pw.CloseWithError(b.form.writeTo(mw, copyMultipartFile))
+	}()
+}
+
+// copyMultipartFile 写入文件内容
+func copyMultipartFile(w io.Writer, file MultipartFile) error {
+	if file.Path == "" {
+		_, err := io.Copy(w, file.Reader)
+		return err
+	}
+	r, err := os.Open(file.Path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	_, err = io.Copy(w, r)
+	return err
+}
+
+// Read 实现 io.Reader
+func (b *multipartBody) Read(p []byte) (int, error) {
+	b.once.Do(b.start)
+	n, err := b.pr.Read(p)
+	if n > 0 {
+		b.finish += int64(n)
+		if b.form.Progress != nil {
+			b.form.Progress(b.finish, b.total)
+		}
+	}
+	return n, err
+}
+
+// Close 实现 io.Closer, 中止请求体的生成
+func (b *multipartBody) Close() error {
+	b.once.Do(func() {})
+	if b.pr != nil {
+		b.pr.CloseWithError(io.ErrClosedPipe)
+	}
+	return nil
+}
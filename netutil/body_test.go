@@ -0,0 +1,152 @@
+package netutil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/up-zero/gotool"
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestClientPostForm(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		w.Write([]byte(r.Header.Get("Content-Type") + "|" + r.PostForm.Get("name") + "|" + r.PostForm.Get("tag")))
+	}))
+	defer srv.Close()
+
+	data, err := NewClient().PostForm(context.Background(), srv.URL, url.Values{"name": {"张三"}, "tag": {"a&b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, string(data), "application/x-www-form-urlencoded|张三|a&b")
+
+	data, _ = HttpPostForm(srv.URL, url.Values{"name": {"x"}})
+	testutil.Equal(t, string(data), "application/x-www-form-urlencoded|x|")
+}
+
+func TestClientReaderBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		w.Write([]byte(r.Header.Get("Content-Type") + "|" + string(b)))
+	}))
+	defer srv.Close()
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.Write([]byte("stream-"))
+		pw.Write([]byte("data"))
+		pw.Close()
+	}()
+	data, err := NewClient().Put(context.Background(), srv.URL, pr, map[string]string{"Content-Type": "text/plain"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, string(data), "text/plain|stream-data")
+}
+
+func TestClientUpload(t *testing.T) {
+	dir := t.TempDir()
+	content := bytes.Repeat([]byte("0123456789"), 10000)
+	path := filepath.Join(dir, "data.bin")
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var contentLength int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentLength = r.ContentLength
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f, h, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		b, _ := io.ReadAll(f)
+		var note []byte
+		if files := r.MultipartForm.File["note"]; len(files) > 0 {
+			n, _ := files[0].Open()
+			note, _ = io.ReadAll(n)
+		}
+		w.Write([]byte(strings.Join([]string{r.FormValue("user"), h.Filename, h.Header.Get("Content-Type"),
+			string(note), strconv.Itoa(len(b))}, "|")))
+		if !bytes.Equal(b, content) {
+			t.Error("uploaded content mismatch")
+		}
+	}))
+	defer srv.Close()
+
+	var finish, total int64
+	form := &MultipartForm{
+		Fields: map[string]string{"user": "alice"},
+		Files: []MultipartFile{
+			{FieldName: "file", Path: path, ContentType: "application/x-test"},
+			{FieldName: "note", FileName: "note.txt", Reader: strings.NewReader("hello")},
+		},
+		Progress: func(finishBytes, totalBytes int64) {
+			finish, total = finishBytes, totalBytes
+		},
+	}
+	data, err := NewClient().Upload(context.Background(), srv.URL, form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, string(data), "alice|data.bin|application/x-test|hello|100000")
+	// 含 Reader 的文件长度未知, 使用分块传输
+	testutil.Equal(t, contentLength, int64(-1))
+	testutil.Equal(t, total, int64(-1))
+	testutil.Equal(t, finish > int64(len(content)), true)
+
+	// 只有磁盘文件时设置 Content-Length, 并与实际发送的长度一致
+	form.Files = form.Files[:1]
+	data, err = HttpUpload(srv.URL, form)
+	testutil.Equal(t, err, nil)
+	testutil.Equal(t, string(data), "alice|data.bin|application/x-test||100000")
+	testutil.Equal(t, contentLength, total)
+	testutil.Equal(t, finish, total)
+}
+
+func TestMultipartFormInvalid(t *testing.T) {
+	_, err := NewClient().Upload(context.Background(), "http://127.0.0.1:1", &MultipartForm{
+		Files: []MultipartFile{{FieldName: "file", Path: filepath.Join(t.TempDir(), "missing")}},
+	})
+	testutil.Equal(t, errors.Is(err, os.ErrNotExist), true)
+
+	_, err = NewClient().Upload(context.Background(), "http://127.0.0.1:1", &MultipartForm{
+		Files: []MultipartFile{{FieldName: "file", Reader: strings.NewReader("x")}},
+	})
+	testutil.Equal(t, errors.Is(err, gotool.ErrInvalidParam), true)
+}
+
+func TestMultipartBodyClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.bin")
+	os.WriteFile(path, make([]byte, 1<<20), 0644)
+	body, _, _, err := newRequestBody(&MultipartForm{Files: []MultipartFile{{FieldName: "f", Path: path}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc := body.(io.ReadCloser)
+	// 未读取时关闭
+	testutil.Equal(t, rc.Close(), nil)
+
+	body, _, _, _ = newRequestBody(&MultipartForm{Files: []MultipartFile{{FieldName: "f", Path: path}}})
+	rc = body.(io.ReadCloser)
+	buf := make([]byte, 1024)
+	rc.Read(buf)
+	rc.Close()
+	_, err = rc.Read(buf)
+	testutil.Equal(t, errors.Is(err, io.ErrClosedPipe), true)
+}
@@ -0,0 +1,361 @@
+package netutil
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Handler 发送请求并返回响应
+type Handler func(req *http.Request) (*http.Response, error)
+
+// Middleware 中间件，包装每一次请求尝试，可用于添加鉴权头、记录日志、统计耗时等
+//
+// # Examples:
+//
+//	client.Use(func(next Handler) Handler {
+//		return func(req *http.Request) (*http.Response, error) {
+//			req.Header.Set("Authorization", "Bearer "+token)
+//			return next(req)
+//		}
+//	})
+type Middleware func(next Handler) Handler
+
+// RetryPolicy 重试策略，对网络错误、429 和 5xx (501 除外) 响应按指数退避加随机抖动重试，
+// 响应带有 Retry-After 时按其等待，不受 MaxBackoff 限制；Retry-After 超过 MaxRetryAfter
+// 或 context 的截止时间时不再重试，直接返回该响应
+type RetryPolicy struct {
+	MaxRetries    int                                       // 最大重试次数, 0 表示不重试
+	MinBackoff    time.Duration                             // 首次重试前的等待时间, 默认 100ms
+	MaxBackoff    time.Duration                             // 退避等待的最大时间, 默认 10s
+	MaxRetryAfter time.Duration                             // 允许按 Retry-After 等待的最长时间, 默认 5min
+	ShouldRetry   func(resp *http.Response, err error) bool // 自定义是否重试, 可选
+}
+
+// setDefaults 设置默认值
+func (p *RetryPolicy) setDefaults() {
+	if p.MinBackoff <= 0 {
+		p.MinBackoff = 100 * time.Millisecond
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 10 * time.Second
+	}
+	if p.MaxBackoff < p.MinBackoff {
+		p.MaxBackoff = p.MinBackoff
+	}
+	if p.MaxRetryAfter <= 0 {
+		p.MaxRetryAfter = 5 * time.Minute
+	}
+	if p.ShouldRetry == nil {
+		p.ShouldRetry = defaultShouldRetry
+	}
+}
+
+// defaultShouldRetry 网络错误、429 和 5xx (501 除外) 需要重试
+func defaultShouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented
+}
+
+// backoff 第 attempt 次重试前的等待时间, 取 [d/2, d) 之间的随机值, d = MinBackoff * 2^attempt
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.MaxBackoff
+	if attempt < 32 && p.MinBackoff<<attempt < p.MaxBackoff && p.MinBackoff<<attempt > 0 {
+		d = p.MinBackoff << attempt
+	}
+	half := int64(d / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// retryAfter 解析 Retry-After 响应头, 支持秒数和 HTTP 日期
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	v := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if v == "" {
+		return 0, false
+	}
+	if sec, err := strconv.Atoi(v); err == nil && sec >= 0 {
+		return time.Duration(sec) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// ClientConfig 客户端配置，零值字段使用默认值
+type ClientConfig struct {
+	BaseURL   string            // 基础地址, 请求地址不是完整 URL 时拼接在其后
+	Header    map[string]string // 默认请求头, 可被单次请求的请求头覆盖
+	Timeout   time.Duration     // 单次尝试的超时时间 (包括读取响应体), 0 表示只由 context 控制
+	Retry     RetryPolicy       // 重试策略
+	Transport http.RoundTripper // 底层传输, 默认与 HttpGet 等函数共享连接池
+}
+
+// Client 可配置的 HTTP 客户端，支持基础地址、默认请求头、context、重试和中间件，添加完中间件后可并发使用
+type Client struct {
+	conf        ClientConfig
+	client      *http.Client
+	middlewares []Middleware
+}
+
+// NewClient 创建 HTTP 客户端
+//
+// # Params:
+//
+//	conf: 客户端配置，可选
+//
+// # Examples:
+//
+//	client := NewClient(ClientConfig{
+//		BaseURL: "https://api.example.com/v1",
+//		Header:  map[string]string{"X-App": "demo"},
+//		Retry:   RetryPolicy{MaxRetries: 3},
+//	})
+//	data, err := client.Get(ctx, "/users")
+func NewClient(conf ...ClientConfig) *Client {
+	var c ClientConfig
+	if len(conf) > 0 {
+		c = conf[0]
+	}
+	c.Retry.setDefaults()
+	if c.Transport == nil {
+		c.Transport = httpClient.Transport
+	}
+	return &Client{
+		conf:   c,
+		client: &http.Client{Transport: c.Transport, Timeout: c.Timeout},
+	}
+}
+
+// Use 添加中间件，先添加的在外层
+//
+// # Params:
+//
+//	mw: 中间件
+func (c *Client) Use(mw ...Middleware) *Client {
+	c.middlewares = append(c.middlewares, mw...)
+	return c
+}
+
+// URL 拼接基础地址，完整 URL 原样返回
+//
+// # Params:
+//
+//	path: 请求路径或完整 URL
+func (c *Client) URL(path string) string {
+	if c.conf.BaseURL == "" || strings.Contains(path, "://") {
+		return path
+	}
+	if path == "" {
+		return c.conf.BaseURL
+	}
+	return strings.TrimRight(c.conf.BaseURL, "/") + "/" + strings.TrimLeft(path, "/")
+}
+
+// Do 发送请求，按重试策略重试，调用方负责关闭响应体
+//
+// 有请求体的请求只有在 req.GetBody 不为空时才会重试 (http.NewRequest 使用 bytes.Buffer、
+// bytes.Reader 或 strings.Reader 时会自动设置)
+//
+// # Params:
+//
+//	req: 请求
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range c.conf.Header {
+		if k != "" && v != "" && req.Header.Get(k) == "" {
+			req.Header.Set(k, v)
+		}
+	}
+	handler := Handler(c.client.Do)
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		handler = c.middlewares[i](handler)
+	}
+
+	ctx := req.Context()
+	policy := c.conf.Retry
+	for attempt := 0; ; attempt++ {
+		r := req
+		if attempt > 0 {
+			r = req.Clone(ctx)
+			if req.Body != nil && req.Body != http.NoBody {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				r.Body = body
+			}
+		}
+		resp, err := handler(r)
+		if attempt >= policy.MaxRetries || ctx.Err() != nil || !policy.ShouldRetry(resp, err) ||
+			req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return resp, err
+		}
+
+		wait, ok := retryAfter(resp, time.Now())
+		if ok {
+			// 服务端要求的等待时间不能缩短, 等不了时直接返回响应
+			deadline, hasDeadline := ctx.Deadline()
+			if wait > policy.MaxRetryAfter || hasDeadline && time.Now().Add(wait).After(deadline) {
+				return resp, err
+			}
+		} else {
+			wait = policy.backoff(attempt)
+		}
+		if resp != nil {
+			// 读完响应体以便复用连接
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Send 发送请求并读取响应，不检查状态码，可配合 DecodeResponse 使用
+//
+// # Params:
+//
+//	ctx: 上下文
+//	method: 请求方法
+//	url: 请求路径或完整 URL
+//	data: 请求参数，nil 表示没有请求体，[]byte 原样发送，url.Values 编码为表单，
+//	      *MultipartForm 流式上传文件，io.Reader 流式发送，其他类型编码为 JSON
+//	header: 请求头
+func (c *Client) Send(ctx context.Context, method, url string, data any, header ...map[string]string) (*Response, error) {
+	body, contentType, size, err := newRequestBody(data)
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequestWithContext(ctx, method, c.URL(url), body)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := data.(*MultipartForm); ok && size >= 0 {
+		request.ContentLength = size
+	}
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	// 处理 header
+	for k, v := range getHeader(header) {
+		if k != "" && v != "" {
+			request.Header.Set(k, v)
+		}
+	}
+
+	resp, err := c.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: respBytes}, nil
+}
+
+// Request 发送请求并读取响应体，不检查状态码
+//
+// # Params:
+//
+//	ctx: 上下文
+//	method: 请求方法
+//	url: 请求路径或完整 URL
+//	data: 请求参数，支持的类型同 Send
+//	header: 请求头
+func (c *Client) Request(ctx context.Context, method, url string, data any, header ...map[string]string) ([]byte, error) {
+	resp, err := c.Send(ctx, method, url, data, header...)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// PostForm 发送 application/x-www-form-urlencoded 表单
+//
+// # Params:
+//
+//	ctx: 上下文
+//	url: 请求路径或完整 URL
+//	form: 表单
+//	header: 请求头
+func (c *Client) PostForm(ctx context.Context, url string, form neturl.Values, header ...map[string]string) ([]byte, error) {
+	return c.Request(ctx, http.MethodPost, url, form, header...)
+}
+
+// Upload 使用 multipart/form-data 上传文件，文件从磁盘流式读取
+//
+// # Params:
+//
+//	ctx: 上下文
+//	url: 请求路径或完整 URL
+//	form: 表单
+//	header: 请求头
+func (c *Client) Upload(ctx context.Context, url string, form *MultipartForm, header ...map[string]string) ([]byte, error) {
+	return c.Request(ctx, http.MethodPost, url, form, header...)
+}
+
+// Get http get 请求
+//
+// # Params:
+//
+//	ctx: 上下文
+//	url: 请求路径或完整 URL
+//	header: 请求头
+func (c *Client) Get(ctx context.Context, url string, header ...map[string]string) ([]byte, error) {
+	return c.Request(ctx, http.MethodGet, url, nil, header...)
+}
+
+// Post http post 请求
+//
+// # Params:
+//
+//	ctx: 上下文
+//	url: 请求路径或完整 URL
+//	data: 请求参数
+//	header: 请求头
+func (c *Client) Post(ctx context.Context, url string, data any, header ...map[string]string) ([]byte, error) {
+	return c.Request(ctx, http.MethodPost, url, data, header...)
+}
+
+// Put http put 请求
+//
+// # Params:
+//
+//	ctx: 上下文
+//	url: 请求路径或完整 URL
+//	data: 请求参数
+//	header: 请求头
+func (c *Client) Put(ctx context.Context, url string, data any, header ...map[string]string) ([]byte, error) {
+	return c.Request(ctx, http.MethodPut, url, data, header...)
+}
+
+// Delete http delete 请求
+//
+// # Params:
+//
+//	ctx: 上下文
+//	url: 请求路径或完整 URL
+//	header: 请求头
+func (c *Client) Delete(ctx context.Context, url string, header ...map[string]string) ([]byte, error) {
+	return c.Request(ctx, http.MethodDelete, url, nil, header...)
+}
@@ -0,0 +1,237 @@
+package netutil
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+func TestClientRetry(t *testing.T) {
+	var calls int32
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	client := NewClient(ClientConfig{
+		BaseURL: srv.URL,
+		Retry:   RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond},
+	})
+	data, err := client.Post(context.Background(), "/submit", map[string]int{"a": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, string(data), "ok")
+	testutil.Equal(t, atomic.LoadInt32(&calls), int32(3))
+	// 每次重试都重新发送请求体
+	testutil.Equal(t, strings.Join(bodies, ","), `{"a":1},{"a":1},{"a":1}`)
+}
+
+func TestClientRetryExhausted(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte("slow down"))
+	}))
+	defer srv.Close()
+
+	client := NewClient(ClientConfig{Retry: RetryPolicy{MaxRetries: 2, MinBackoff: time.Hour}})
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	// Retry-After 为 0, 不使用 MinBackoff
+	testutil.Equal(t, time.Since(start) < time.Second, true)
+	testutil.Equal(t, resp.StatusCode, http.StatusTooManyRequests)
+	testutil.Equal(t, atomic.LoadInt32(&calls), int32(3))
+	body, _ := io.ReadAll(resp.Body)
+	testutil.Equal(t, string(body), "slow down")
+}
+
+func TestClientRetryAfterNotShortened(t *testing.T) {
+	var calls int32
+	retryAfter := "1"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	// Retry-After 大于 MaxBackoff 时仍按 Retry-After 等待
+	client := NewClient(ClientConfig{Retry: RetryPolicy{MaxRetries: 1, MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}})
+	start := time.Now()
+	data, err := client.Get(context.Background(), srv.URL)
+	testutil.Equal(t, err, nil)
+	testutil.Equal(t, string(data), "ok")
+	testutil.Equal(t, time.Since(start) >= time.Second, true)
+
+	// 超过 MaxRetryAfter 时不重试, 返回服务端的响应
+	atomic.StoreInt32(&calls, 0)
+	retryAfter = "3600"
+	start = time.Now()
+	resp, err := client.Send(context.Background(), http.MethodGet, srv.URL, nil)
+	testutil.Equal(t, err, nil)
+	testutil.Equal(t, resp.StatusCode, http.StatusServiceUnavailable)
+	testutil.Equal(t, atomic.LoadInt32(&calls), int32(1))
+	testutil.Equal(t, time.Since(start) < time.Second, true)
+
+	// 超过 context 的截止时间时不重试
+	atomic.StoreInt32(&calls, 0)
+	retryAfter = "1"
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	resp, err = client.Send(ctx, http.MethodGet, srv.URL, nil)
+	testutil.Equal(t, err, nil)
+	testutil.Equal(t, resp.StatusCode, http.StatusServiceUnavailable)
+	testutil.Equal(t, atomic.LoadInt32(&calls), int32(1))
+}
+
+func TestClientNoRetry(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotImplemented)
+	}))
+	defer srv.Close()
+
+	client := NewClient(ClientConfig{Retry: RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond}})
+	_, err := client.Get(context.Background(), srv.URL)
+	testutil.Equal(t, err, nil)
+	testutil.Equal(t, atomic.LoadInt32(&calls), int32(1))
+
+	// 请求体无法重放时不重试
+	atomic.StoreInt32(&calls, 0)
+	client = NewClient(ClientConfig{Retry: RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond,
+		ShouldRetry: func(resp *http.Response, err error) bool { return true }}})
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, io.NopCloser(strings.NewReader("data")))
+	resp, err := client.Do(req)
+	testutil.Equal(t, err, nil)
+	resp.Body.Close()
+	testutil.Equal(t, atomic.LoadInt32(&calls), int32(1))
+}
+
+func TestClientContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	client := NewClient(ClientConfig{Retry: RetryPolicy{MaxRetries: 5, MinBackoff: time.Hour, MaxBackoff: time.Hour}})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.Get(ctx, srv.URL)
+	testutil.Equal(t, errors.Is(err, context.DeadlineExceeded), true)
+}
+
+func TestClientHeaderAndMiddleware(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Path + "|" + r.Header.Get("X-App") + "|" + r.Header.Get("X-Trace") + "|" + r.Header.Get("Authorization")))
+	}))
+	defer srv.Close()
+
+	var order []string
+	client := NewClient(ClientConfig{
+		BaseURL: srv.URL + "/api/",
+		Header:  map[string]string{"X-App": "demo", "X-Trace": "default"},
+	})
+	client.Use(func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			order = append(order, "outer")
+			req.Header.Set("Authorization", "Bearer token")
+			resp, err := next(req)
+			order = append(order, "outer-done")
+			return resp, err
+		}
+	}, func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			order = append(order, "inner")
+			return next(req)
+		}
+	})
+
+	data, err := client.Get(context.Background(), "/users", map[string]string{"X-Trace": "abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Equal(t, string(data), "/api/users|demo|abc|Bearer token")
+	testutil.Equal(t, strings.Join(order, ","), "outer,inner,outer-done")
+}
+
+func TestClientURL(t *testing.T) {
+	client := NewClient(ClientConfig{BaseURL: "https://example.com/v1/"})
+	testutil.Equal(t, client.URL("/users"), "https://example.com/v1/users")
+	testutil.Equal(t, client.URL("users"), "https://example.com/v1/users")
+	testutil.Equal(t, client.URL("http://other.com/x"), "http://other.com/x")
+	testutil.Equal(t, NewClient().URL("/users"), "/users")
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	resp := &http.Response{Header: http.Header{}}
+	_, ok := retryAfter(resp, now)
+	testutil.Equal(t, ok, false)
+
+	resp.Header.Set("Retry-After", "120")
+	d, ok := retryAfter(resp, now)
+	testutil.Equal(t, ok, true)
+	testutil.Equal(t, d, 2*time.Minute)
+
+	resp.Header.Set("Retry-After", now.Add(30*time.Second).Format(http.TimeFormat))
+	d, _ = retryAfter(resp, now)
+	testutil.Equal(t, d, 30*time.Second)
+
+	resp.Header.Set("Retry-After", now.Add(-time.Minute).Format(http.TimeFormat))
+	d, _ = retryAfter(resp, now)
+	testutil.Equal(t, d, time.Duration(0))
+}
+
+func TestRetryBackoff(t *testing.T) {
+	p := RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	p.setDefaults()
+	for attempt, want := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		want *= time.Millisecond
+		for i := 0; i < 20; i++ {
+			d := p.backoff(attempt)
+			testutil.Equal(t, d >= want/2 && d <= want, true)
+		}
+	}
+	testutil.Equal(t, p.backoff(100) <= time.Second, true)
+}
+
+func TestHttpRequestWrapper(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		w.Write([]byte(r.Method + "|" + r.Header.Get("Content-Type") + "|" + string(b)))
+	}))
+	defer srv.Close()
+
+	data, err := HttpPost(srv.URL, map[string]string{"k": "v"})
+	testutil.Equal(t, err, nil)
+	testutil.Equal(t, string(data), `POST|application/json;charset=UTF-8|{"k":"v"}`)
+	data, _ = HttpGetWithTimeout(srv.URL, time.Second)
+	testutil.Equal(t, string(data), "GET|application/json;charset=UTF-8|")
+}
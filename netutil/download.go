@@ -0,0 +1,573 @@
+package netutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/up-zero/gotool"
+	"github.com/up-zero/gotool/cryptoutil"
+)
+
+const (
+	downloadMinSegmentSize = 64 << 10
+	downloadBufferSize     = 32 << 10
+	downloadSaveInterval   = time.Second
+)
+
+// errRemoteChanged 下载过程中远端文件发生变化
+var errRemoteChanged = errors.New("remote file changed")
+
+// DownloadConfig 分段下载配置，零值字段使用默认值
+type DownloadConfig struct {
+	Concurrency int                      // 并行分段数, 默认 4, 服务端不支持 Range 时使用单连接
+	RateLimit   int64                    // 所有分段合计的限速, 字节/秒, 0 表示不限速
+	SHA256      string                   // 期望的 SHA-256 (十六进制), 可选, 下载完成后校验
+	Header      map[string]string        // 请求头
+	Progress    DownloadProgressCallback // 进度回调, 可选, 总大小未知时 totalBytes 为 -1
+	Client      *Client                  // 使用的客户端, 默认使用 NewClient() 创建的客户端
+}
+
+// setDefaults 设置默认值
+func (c *DownloadConfig) setDefaults() {
+	if c.Concurrency <= 0 {
+		c.Concurrency = 4
+	}
+	if c.Client == nil {
+		c.Client = defaultClient
+	}
+}
+
+// downloadSegment 下载分段, 范围为 [Start, End]
+type downloadSegment struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+	Done  int64 `json:"done"` // 已写入的字节数
+}
+
+// downloadState 断点续传状态, 保存在 filePath + ".tmp.meta"
+type downloadState struct {
+	URL          string             `json:"url"`
+	Size         int64              `json:"size"`
+	ETag         string             `json:"etag"`
+	LastModified string             `json:"last_modified"`
+	Segments     []*downloadSegment `json:"segments"`
+}
+
+// validator 用于 If-Range 的校验值, 优先使用强 ETag
+func (s *downloadState) validator() string {
+	if s.ETag != "" && !strings.HasPrefix(s.ETag, "W/") {
+		return s.ETag
+	}
+	return s.LastModified
+}
+
+// FileDownloadResumable 支持断点续传和并行分段的文件下载
+//
+// 服务端支持 Range 时按 Concurrency 将文件分段并行下载, 直接写入 filePath + ".tmp" 的对应位置,
+// 下载进度保存在 filePath + ".tmp.meta", 中断后再次调用会从上次的位置继续;
+// 续传前使用 ETag/Last-Modified 校验远端文件, 文件变化时重新下载。
+// 没有 .tmp.meta 但存在 FileDownload 等函数中断后留下的 filePath + ".tmp" 时, 如果远端的
+// Last-Modified 不晚于临时文件的修改时间, 则从临时文件的长度处继续下载, 否则 (包括只有 ETag 时) 重新下载。
+// 全部完成后校验 SHA-256 (如果设置), 再重命名为 filePath
+//
+// # Params:
+//
+//	ctx: 上下文，取消后保留已下载的部分
+//	url: 文件地址
+//	filePath: 文件路径
+//	conf: 下载配置，可选
+func FileDownloadResumable(ctx context.Context, url, filePath string, conf ...DownloadConfig) error {
+	var c DownloadConfig
+	if len(conf) > 0 {
+		c = conf[0]
+	}
+	c.setDefaults()
+
+	if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		return err
+	}
+	d := &downloader{conf: c, url: url, tmpPath: filePath + ".tmp", metaPath: filePath + ".tmp.meta"}
+	if c.RateLimit > 0 {
+		d.limiter = &rateLimiter{rate: c.RateLimit}
+	}
+
+	err := d.run(ctx)
+	if errors.Is(err, errRemoteChanged) {
+		// 续传时远端文件发生变化, 从头重新下载
+		d.reset()
+		err = d.run(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	if c.SHA256 != "" {
+		sum, err := cryptoutil.Sha256File(d.tmpPath)
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(sum, c.SHA256) {
+			d.reset()
+			return fmt.Errorf("%w, sha256 want %s, got %s", gotool.ErrChecksumMismatch, c.SHA256, sum)
+		}
+	}
+	if err := os.Rename(d.tmpPath, filePath); err != nil {
+		return err
+	}
+	os.Remove(d.metaPath)
+	return nil
+}
+
+// downloader 一次下载任务
+type downloader struct {
+	conf     DownloadConfig
+	url      string
+	tmpPath  string
+	metaPath string
+	limiter  *rateLimiter
+
+	mu     sync.Mutex
+	state  *downloadState
+	finish int64
+}
+
+// reset 删除临时文件和状态
+func (d *downloader) reset() {
+	os.Remove(d.tmpPath)
+	os.Remove(d.metaPath)
+}
+
+// newRequest 创建 GET 请求
+func (d *downloader) newRequest(ctx context.Context) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.conf.Client.URL(d.url), nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range d.conf.Header {
+		if k != "" && v != "" {
+			req.Header.Set(k, v)
+		}
+	}
+	return req, nil
+}
+
+// probe 请求第一个字节, 获取文件大小、校验值以及是否支持 Range
+func (d *downloader) probe(ctx context.Context) (*downloadState, bool, error) {
+	req, err := d.newRequest(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Range", "bytes=0-0")
+	resp, err := d.conf.Client.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	resp.Body.Close()
+
+	state := &downloadState{
+		URL:          d.url,
+		Size:         resp.ContentLength,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		// Content-Range: bytes 0-0/total
+		start, end, size, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != 0 || end != 0 || size < 0 {
+			return state, false, nil
+		}
+		state.Size = size
+		return state, true, nil
+	case http.StatusOK:
+		return state, false, nil
+	case http.StatusRequestedRangeNotSatisfiable:
+		// 空文件
+		state.Size = 0
+		return state, false, nil
+	default:
+		return nil, false, &HttpError{StatusCode: resp.StatusCode, Header: resp.Header}
+	}
+}
+
+// loadState 读取并校验上次的下载状态
+func (d *downloader) loadState(remote *downloadState) *downloadState {
+	data, err := os.ReadFile(d.metaPath)
+	if err != nil {
+		return nil
+	}
+	var state downloadState
+	if json.Unmarshal(data, &state) != nil {
+		return nil
+	}
+	if state.URL != remote.URL || state.Size != remote.Size || state.ETag != remote.ETag ||
+		state.LastModified != remote.LastModified || state.validator() == "" {
+		return nil
+	}
+	info, err := os.Stat(d.tmpPath)
+	if err != nil || info.Size() != state.Size {
+		return nil
+	}
+	return &state
+}
+
+// saveState 保存下载状态
+func (d *downloader) saveState() error {
+	d.mu.Lock()
+	data, err := json.Marshal(d.state)
+	d.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(d.metaPath, data, 0644)
+}
+
+// progress 累加进度并回调
+func (d *downloader) progress(n int64) {
+	d.mu.Lock()
+	d.finish += n
+	finish := d.finish
+	d.mu.Unlock()
+	if d.conf.Progress != nil {
+		total := int64(-1)
+		if d.state != nil {
+			total = d.state.Size
+		}
+		d.conf.Progress(finish, total)
+	}
+}
+
+// run 执行下载
+func (d *downloader) run(ctx context.Context) error {
+	remote, ranged, err := d.probe(ctx)
+	if err != nil {
+		return err
+	}
+	// 不支持 Range 或没有校验值时无法安全地续传, 使用单连接完整下载
+	if !ranged || remote.validator() == "" {
+		d.reset()
+		return d.single(ctx, remote)
+	}
+
+	d.state = d.loadState(remote)
+	if d.state == nil {
+		d.state = d.adoptPartial(remote)
+	}
+	if d.state == nil {
+		d.state = remote
+		if err := d.prepare(); err != nil {
+			return err
+		}
+	}
+	d.finish = 0
+	for _, seg := range d.state.Segments {
+		d.finish += seg.Done
+	}
+
+	f, err := os.OpenFile(d.tmpPath, os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// 定期保存进度
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	saved := make(chan struct{})
+	go func() {
+		defer close(saved)
+		ticker := time.NewTicker(downloadSaveInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.saveState()
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(d.state.Segments))
+	for _, seg := range d.state.Segments {
+		if seg.Start+seg.Done > seg.End {
+			continue
+		}
+		wg.Add(1)
+		go func(seg *downloadSegment) {
+			defer wg.Done()
+			if err := d.segment(ctx, f, seg); err != nil {
+				errs <- err
+				cancel()
+			}
+		}(seg)
+	}
+	wg.Wait()
+	cancel()
+	<-saved
+	close(errs)
+
+	if err := <-errs; err != nil {
+		if errors.Is(err, errRemoteChanged) {
+			return err
+		}
+		d.saveState()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	return d.saveState()
+}
+
+// prepare 创建临时文件并划分分段
+func (d *downloader) prepare() error {
+	f, err := os.Create(d.tmpPath)
+	if err != nil {
+		return err
+	}
+	if err := f.Truncate(d.state.Size); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	d.state.Segments = d.split(0, d.state.Size)
+	return d.saveState()
+}
+
+// split 将 [start, end) 按 Concurrency 划分为分段, 每段不小于 downloadMinSegmentSize
+func (d *downloader) split(start, end int64) []*downloadSegment {
+	size := end - start
+	n := int64(d.conf.Concurrency)
+	if maxN := (size + downloadMinSegmentSize - 1) / downloadMinSegmentSize; n > maxN {
+		n = max(maxN, 1)
+	}
+	segSize := (size + n - 1) / n
+	var segments []*downloadSegment
+	for ; start < end; start += segSize {
+		segments = append(segments, &downloadSegment{Start: start, End: min(start+segSize, end) - 1})
+	}
+	return segments
+}
+
+// adoptPartial 没有下载状态时, 接管其他下载函数中断后留下的临时文件, 从其长度处继续下载;
+// 只有远端 Last-Modified 不晚于临时文件的修改时间时才能确认临时文件的内容仍然有效
+func (d *downloader) adoptPartial(remote *downloadState) *downloadState {
+	if _, err := os.Stat(d.metaPath); err == nil {
+		return nil
+	}
+	info, err := os.Stat(d.tmpPath)
+	if err != nil || !info.Mode().IsRegular() || info.Size() <= 0 || info.Size() >= remote.Size {
+		return nil
+	}
+	modified, err := http.ParseTime(remote.LastModified)
+	if err != nil || info.ModTime().Before(modified) {
+		return nil
+	}
+	if err := os.Truncate(d.tmpPath, remote.Size); err != nil {
+		return nil
+	}
+
+	state := *remote
+	partial := info.Size()
+	state.Segments = append([]*downloadSegment{{Start: 0, End: partial - 1, Done: partial}}, d.split(partial, remote.Size)...)
+	d.state = &state
+	if d.saveState() != nil {
+		return nil
+	}
+	return &state
+}
+
+// segment 下载一个分段剩余的部分
+func (d *downloader) segment(ctx context.Context, f *os.File, seg *downloadSegment) error {
+	req, err := d.newRequest(ctx)
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	offset := seg.Start + seg.Done
+	d.mu.Unlock()
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, seg.End))
+	req.Header.Set("If-Range", d.state.validator())
+
+	resp, err := d.conf.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		// If-Range 不匹配时服务端返回完整文件
+		return errRemoteChanged
+	default:
+		return &HttpError{StatusCode: resp.StatusCode, Header: resp.Header}
+	}
+	// 返回的范围必须与请求一致, 否则会写到错误的位置
+	cr := resp.Header.Get("Content-Range")
+	start, end, size, ok := parseContentRange(cr)
+	if ok && size >= 0 && size != d.state.Size {
+		return errRemoteChanged
+	}
+	if !ok || start != offset || end != seg.End {
+		return fmt.Errorf("unexpected Content-Range %q, want bytes %d-%d", cr, offset, seg.End)
+	}
+
+	buf := make([]byte, d.bufferSize())
+	for offset <= seg.End {
+		n, err := resp.Body.Read(buf[:min(int64(len(buf)), seg.End-offset+1)])
+		if n > 0 {
+			if err := d.limiter.wait(ctx, n); err != nil {
+				return err
+			}
+			if _, err := f.WriteAt(buf[:n], offset); err != nil {
+				return err
+			}
+			offset += int64(n)
+			d.mu.Lock()
+			seg.Done += int64(n)
+			d.mu.Unlock()
+			d.progress(int64(n))
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if offset <= seg.End {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
+// parseContentRange 解析 Content-Range: bytes start-end/size, size 为 * 时返回 -1
+func parseContentRange(cr string) (start, end, size int64, ok bool) {
+	rng, found := strings.CutPrefix(cr, "bytes ")
+	if !found {
+		return 0, 0, 0, false
+	}
+	rng, total, found := strings.Cut(rng, "/")
+	if !found {
+		return 0, 0, 0, false
+	}
+	first, last, found := strings.Cut(rng, "-")
+	if !found {
+		return 0, 0, 0, false
+	}
+	var err1, err2, err3 error
+	start, err1 = strconv.ParseInt(first, 10, 64)
+	end, err2 = strconv.ParseInt(last, 10, 64)
+	size = -1
+	if total != "*" {
+		size, err3 = strconv.ParseInt(total, 10, 64)
+	}
+	if err1 != nil || err2 != nil || err3 != nil || start < 0 || end < start || size >= 0 && end >= size {
+		return 0, 0, 0, false
+	}
+	return start, end, size, true
+}
+
+// single 单连接完整下载
+func (d *downloader) single(ctx context.Context, remote *downloadState) error {
+	d.state = remote
+	d.finish = 0
+	req, err := d.newRequest(ctx)
+	if err != nil {
+		return err
+	}
+	resp, err := d.conf.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return &HttpError{StatusCode: resp.StatusCode, Header: resp.Header}
+	}
+	if resp.ContentLength >= 0 {
+		d.state.Size = resp.ContentLength
+	}
+
+	f, err := os.Create(d.tmpPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	buf := make([]byte, d.bufferSize())
+	for {
+		n, err := resp.Body.Read(buf)
+		if n > 0 {
+			if err := d.limiter.wait(ctx, n); err != nil {
+				return err
+			}
+			if _, err := f.Write(buf[:n]); err != nil {
+				return err
+			}
+			d.progress(int64(n))
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// bufferSize 读取缓冲区大小, 限速较低时减小缓冲区使速度更平滑
+func (d *downloader) bufferSize() int {
+	if d.limiter != nil {
+		return int(min(max(d.limiter.rate/10, 1024), downloadBufferSize))
+	}
+	return downloadBufferSize
+}
+
+// rateLimiter 按字节限速, 多个 goroutine 共享
+type rateLimiter struct {
+	mu   sync.Mutex
+	rate int64 // 字节/秒
+	next time.Time
+}
+
+// wait 预约 n 个字节, 等待到可以发送为止, 为 nil 时不限速
+func (l *rateLimiter) wait(ctx context.Context, n int) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(time.Duration(int64(n) * int64(time.Second) / l.rate))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
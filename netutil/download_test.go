@@ -0,0 +1,318 @@
+package netutil
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/up-zero/gotool"
+	"github.com/up-zero/gotool/testutil"
+)
+
+// downloadServer 支持 Range 的测试服务器, 统计请求数和发送的字节数
+type downloadServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	content  []byte
+	etag     string
+	ranges   int32
+	sent     int64
+	noRanges bool
+}
+
+func newDownloadServer(content []byte) *downloadServer {
+	s := &downloadServer{content: content, etag: `"v1"`}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		content, etag := s.content, s.etag
+		s.mu.Unlock()
+		if s.noRanges {
+			w.Write(content)
+			atomic.AddInt64(&s.sent, int64(len(content)))
+			return
+		}
+		if r.Header.Get("Range") != "" && r.Header.Get("Range") != "bytes=0-0" {
+			atomic.AddInt32(&s.ranges, 1)
+		}
+		w.Header().Set("ETag", etag)
+		http.ServeContent(&countingWriter{ResponseWriter: w, n: &s.sent}, r, "file.bin", time.Time{}, bytes.NewReader(content))
+	}))
+	return s
+}
+
+func (s *downloadServer) setContent(content []byte, etag string) {
+	s.mu.Lock()
+	s.content, s.etag = content, etag
+	s.mu.Unlock()
+}
+
+type countingWriter struct {
+	http.ResponseWriter
+	n *int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	atomic.AddInt64(w.n, int64(len(p)))
+	return w.ResponseWriter.Write(p)
+}
+
+func downloadContent(size int, seed byte) []byte {
+	b := make([]byte, size)
+	for i := range b {
+		b[i] = byte(i*31) ^ seed
+	}
+	return b
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestFileDownloadResumableParallel(t *testing.T) {
+	content := downloadContent(1<<20, 1)
+	srv := newDownloadServer(content)
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "sub", "file.bin")
+	var finish, total int64
+	err := FileDownloadResumable(context.Background(), srv.URL, path, DownloadConfig{
+		Concurrency: 4,
+		SHA256:      strings.ToUpper(sha256Hex(content)),
+		Progress: func(finishBytes, totalBytes int64) {
+			atomic.StoreInt64(&finish, finishBytes)
+			atomic.StoreInt64(&total, totalBytes)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(path)
+	testutil.Equal(t, bytes.Equal(got, content), true)
+	testutil.Equal(t, atomic.LoadInt32(&srv.ranges), int32(4))
+	testutil.Equal(t, atomic.LoadInt64(&finish), int64(len(content)))
+	testutil.Equal(t, atomic.LoadInt64(&total), int64(len(content)))
+	_, err = os.Stat(path + ".tmp")
+	testutil.Equal(t, os.IsNotExist(err), true)
+	_, err = os.Stat(path + ".tmp.meta")
+	testutil.Equal(t, os.IsNotExist(err), true)
+}
+
+func TestFileDownloadResumableResume(t *testing.T) {
+	content := downloadContent(1<<20, 2)
+	srv := newDownloadServer(content)
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "file.bin")
+
+	// 限速下载一部分后取消
+	ctx, cancel := context.WithCancel(context.Background())
+	err := FileDownloadResumable(ctx, srv.URL, path, DownloadConfig{
+		Concurrency: 2,
+		RateLimit:   512 << 10,
+		Progress: func(finishBytes, totalBytes int64) {
+			if finishBytes > 256<<10 {
+				cancel()
+			}
+		},
+	})
+	testutil.Equal(t, errors.Is(err, context.Canceled), true)
+	_, err = os.Stat(path + ".tmp.meta")
+	testutil.Equal(t, err, nil)
+
+	// 续传只下载剩余的部分
+	atomic.StoreInt64(&srv.sent, 0)
+	if err := FileDownloadResumable(context.Background(), srv.URL, path, DownloadConfig{Concurrency: 2, SHA256: sha256Hex(content)}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(path)
+	testutil.Equal(t, bytes.Equal(got, content), true)
+	sent := atomic.LoadInt64(&srv.sent)
+	testutil.Equal(t, sent > 0 && sent < int64(len(content))*3/4, true)
+}
+
+func TestFileDownloadResumableChanged(t *testing.T) {
+	content := downloadContent(512<<10, 3)
+	srv := newDownloadServer(content)
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "file.bin")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	FileDownloadResumable(ctx, srv.URL, path, DownloadConfig{
+		RateLimit: 512 << 10,
+		Progress: func(finishBytes, totalBytes int64) {
+			if finishBytes > 128<<10 {
+				cancel()
+			}
+		},
+	})
+
+	// 远端文件变化后重新下载
+	changed := downloadContent(512<<10, 4)
+	srv.setContent(changed, `"v2"`)
+	if err := FileDownloadResumable(context.Background(), srv.URL, path, DownloadConfig{SHA256: sha256Hex(changed)}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(path)
+	testutil.Equal(t, bytes.Equal(got, changed), true)
+}
+
+func TestFileDownloadResumableNoRange(t *testing.T) {
+	content := downloadContent(300<<10, 5)
+	srv := newDownloadServer(content)
+	srv.noRanges = true
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "file.bin")
+
+	if err := FileDownloadResumable(context.Background(), srv.URL, path); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(path)
+	testutil.Equal(t, bytes.Equal(got, content), true)
+}
+
+func TestFileDownloadResumableChecksum(t *testing.T) {
+	srv := newDownloadServer(downloadContent(100<<10, 6))
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "file.bin")
+
+	err := FileDownloadResumable(context.Background(), srv.URL, path, DownloadConfig{SHA256: sha256Hex([]byte("other"))})
+	testutil.Equal(t, errors.Is(err, gotool.ErrChecksumMismatch), true)
+	for _, p := range []string{path, path + ".tmp", path + ".tmp.meta"} {
+		_, err := os.Stat(p)
+		testutil.Equal(t, os.IsNotExist(err), true)
+	}
+}
+
+func TestFileDownloadResumableRateLimit(t *testing.T) {
+	content := downloadContent(100<<10, 7)
+	srv := newDownloadServer(content)
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "file.bin")
+
+	start := time.Now()
+	if err := FileDownloadResumable(context.Background(), srv.URL, path, DownloadConfig{RateLimit: 200 << 10}); err != nil {
+		t.Fatal(err)
+	}
+	// 100KB / 200KB/s, 第一块不等待
+	testutil.Equal(t, time.Since(start) >= 400*time.Millisecond, true)
+}
+
+func TestFileDownloadResumableError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	err := FileDownloadResumable(context.Background(), srv.URL, filepath.Join(t.TempDir(), "file.bin"))
+	var httpErr *HttpError
+	testutil.Equal(t, errors.As(err, &httpErr), true)
+	testutil.Equal(t, httpErr.StatusCode, http.StatusNotFound)
+}
+
+func TestFileDownloadResumableWrongRange(t *testing.T) {
+	content := downloadContent(100<<10, 1)
+	// 返回的范围比请求的晚一个字节
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var start, end int64
+		if _, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-%d", &start, &end); err == nil && end > 0 {
+			r.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start+1, end))
+		}
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, r, "file.bin", time.Time{}, bytes.NewReader(content))
+	}))
+	defer srv.Close()
+
+	filePath := filepath.Join(t.TempDir(), "file.bin")
+	err := FileDownloadResumable(context.Background(), srv.URL, filePath)
+	testutil.NotEqual(t, err, nil)
+	testutil.Equal(t, strings.Contains(err.Error(), "Content-Range"), true)
+	_, err = os.Stat(filePath)
+	testutil.Equal(t, os.IsNotExist(err), true)
+}
+
+func TestParseContentRange(t *testing.T) {
+	cases := []struct {
+		cr               string
+		start, end, size int64
+		ok               bool
+	}{
+		{"bytes 0-0/100", 0, 0, 100, true},
+		{"bytes 10-99/100", 10, 99, 100, true},
+		{"bytes 10-99/*", 10, 99, -1, true},
+		{"bytes 10-100/100", 0, 0, 0, false},
+		{"bytes 10-9/100", 0, 0, 0, false},
+		{"bytes */100", 0, 0, 0, false},
+		{"items 0-1/2", 0, 0, 0, false},
+		{"", 0, 0, 0, false},
+	}
+	for _, c := range cases {
+		start, end, size, ok := parseContentRange(c.cr)
+		testutil.Equal(t, []any{start, end, size, ok}, []any{c.start, c.end, c.size, c.ok})
+	}
+}
+
+// newLastModifiedServer 只返回 Last-Modified 的下载服务, 统计发送的字节数
+func newLastModifiedServer(content []byte, modTime time.Time, sent *int64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(&countingWriter{ResponseWriter: w, n: sent}, r, "file.bin", modTime, bytes.NewReader(content))
+	}))
+}
+
+func TestFileDownloadResumablePartialTmp(t *testing.T) {
+	content := downloadContent(200<<10, 5)
+	var sent int64
+	srv := newLastModifiedServer(content, time.Now().Add(-time.Hour), &sent)
+	defer srv.Close()
+
+	// FileDownload 中断后留下的临时文件, 没有 .tmp.meta
+	filePath := filepath.Join(t.TempDir(), "file.bin")
+	if err := os.WriteFile(filePath+".tmp", content[:80<<10], 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := FileDownloadResumable(context.Background(), srv.URL, filePath); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(filePath)
+	testutil.Equal(t, bytes.Equal(data, content), true)
+	// 只下载了剩余部分 (加上探测的 1 字节)
+	testutil.Equal(t, atomic.LoadInt64(&sent), int64(120<<10+1))
+}
+
+func TestFileDownloadResumableStalePartialTmp(t *testing.T) {
+	content := downloadContent(200<<10, 6)
+
+	// 远端文件比临时文件新, 临时文件作废
+	var sent int64
+	srv := newLastModifiedServer(content, time.Now(), &sent)
+	defer srv.Close()
+	filePath := filepath.Join(t.TempDir(), "file.bin")
+	os.WriteFile(filePath+".tmp", make([]byte, 80<<10), 0644)
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(filePath+".tmp", old, old)
+	if err := FileDownloadResumable(context.Background(), srv.URL, filePath); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(filePath)
+	testutil.Equal(t, bytes.Equal(data, content), true)
+	testutil.Equal(t, atomic.LoadInt64(&sent), int64(200<<10+1))
+
+	// 只有 ETag 时无法判断临时文件是否有效, 重新下载
+	etagSrv := newDownloadServer(content)
+	defer etagSrv.Close()
+	filePath = filepath.Join(t.TempDir(), "file.bin")
+	os.WriteFile(filePath+".tmp", make([]byte, 80<<10), 0644)
+	if err := FileDownloadResumable(context.Background(), etagSrv.URL, filePath); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(filePath)
+	testutil.Equal(t, bytes.Equal(data, content), true)
+}
@@ -1,16 +1,15 @@
 package netutil
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"path/filepath"
-	"reflect"
 	"time"
 )
 
@@ -104,6 +103,28 @@ func HttpDeleteWithTimeout(url string, timeout time.Duration, header ...map[stri
 	return httpRequest(url, "DELETE", []byte{}, getHeader(header), timeout)
 }
 
+// HttpPostForm http post 表单请求 (application/x-www-form-urlencoded)
+//
+// # Params:
+//
+//	url: 请求地址
+//	form: 表单
+//	header: 请求头
+func HttpPostForm(url string, form neturl.Values, header ...map[string]string) ([]byte, error) {
+	return httpRequest(url, "POST", form, getHeader(header), defaultTimeout)
+}
+
+// HttpUpload 使用 multipart/form-data 上传文件，文件从磁盘流式读取，不设置超时
+//
+// # Params:
+//
+//	url: 请求地址
+//	form: 表单，可通过 Progress 获取上传进度
+//	header: 请求头
+func HttpUpload(url string, form *MultipartForm, header ...map[string]string) ([]byte, error) {
+	return defaultClient.Upload(context.Background(), url, form, header...)
+}
+
 // ParseResponse 解析响应
 //
 // # Params:
@@ -144,51 +165,14 @@ func getHeader(headers []map[string]string) map[string]string {
 	return nil
 }
 
+// defaultClient HttpGet 等函数使用的客户端, 不重试
+var defaultClient = NewClient()
+
 // httpRequest .
 func httpRequest(url, method string, data any, header map[string]string, timeout time.Duration) ([]byte, error) {
-	var dataBytes []byte
-	var err error
-
-	// 判断 data 类型
-	if reflect.TypeOf(data).Kind() == reflect.Slice && reflect.TypeOf(data).Elem().Kind() == reflect.Uint8 {
-		dataBytes = data.([]byte)
-	} else {
-		dataBytes, err = json.Marshal(data)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	reader := bytes.NewBuffer(dataBytes)
-
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
-
-	request, err := http.NewRequestWithContext(ctx, method, url, reader)
-	if err != nil {
-		return nil, err
-	}
-
-	request.Header.Set("Content-Type", "application/json;charset=UTF-8")
-	// 处理 header
-	for k, v := range header {
-		if k != "" && v != "" {
-			request.Header.Set(k, v)
-		}
-	}
-
-	resp, err := httpClient.Do(request)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	respBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	return respBytes, nil
+	return defaultClient.Request(ctx, method, url, data, header)
 }
 
 // FileDownload 文件下载
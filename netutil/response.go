@@ -0,0 +1,140 @@
+package netutil
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// Response HTTP 响应，响应体已读入内存
+type Response struct {
+	StatusCode int         // 状态码
+	Header     http.Header // 响应头
+	Body       []byte      // 响应体
+}
+
+// OK 状态码是否为 2xx
+func (r *Response) OK() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// Err 状态码不是 2xx 时返回 *HttpError
+func (r *Response) Err() error {
+	if r.OK() {
+		return nil
+	}
+	return &HttpError{StatusCode: r.StatusCode, Header: r.Header, Body: r.Body}
+}
+
+// Decode 根据 Content-Type 将响应体解码为 JSON 或 XML，不检查状态码
+//
+// # Params:
+//
+//	v: 目标指针，*[]byte 和 *string 直接获取原始响应体
+func (r *Response) Decode(v any) error {
+	return decodeBody(r.Header, r.Body, v)
+}
+
+// HttpError 非 2xx 响应
+type HttpError struct {
+	StatusCode int         // 状态码
+	Header     http.Header // 响应头
+	Body       []byte      // 响应体
+}
+
+// Error 实现 error 接口
+func (e *HttpError) Error() string {
+	body := strings.TrimSpace(string(e.Body))
+	if len(body) > 256 {
+		// 在字符边界处截断, 避免截断多字节的 UTF-8 字符
+		n := 256
+		for n > 0 && !utf8.RuneStart(body[n]) {
+			n--
+		}
+		body = body[:n] + "..."
+	}
+	if body == "" {
+		return fmt.Sprintf("http status %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("http status %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), body)
+}
+
+// Decode 根据 Content-Type 将错误响应体解码为 JSON 或 XML
+//
+// # Params:
+//
+//	v: 目标指针
+func (e *HttpError) Decode(v any) error {
+	return decodeBody(e.Header, e.Body, v)
+}
+
+// decodeBody 根据 Content-Type 解码, 包含 xml 时使用 XML, 否则使用 JSON
+func decodeBody(header http.Header, body []byte, v any) error {
+	switch p := v.(type) {
+	case *[]byte:
+		*p = body
+		return nil
+	case *string:
+		*p = string(body)
+		return nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	if strings.HasSuffix(mediaType, "xml") {
+		return xml.Unmarshal(body, v)
+	}
+	return json.Unmarshal(body, v)
+}
+
+// DecodeResponse 检查状态码并将响应体解码为 T，非 2xx 响应返回 *HttpError
+//
+// # Params:
+//
+//	T: 为返回值类型
+//	resp: Client.Send 的响应
+//	err: Client.Send 的错误
+//
+// # Examples:
+//
+//	user, err := DecodeResponse[User](client.Send(ctx, "GET", "/users/1", nil))
+func DecodeResponse[T any](resp *Response, err error) (T, error) {
+	var reply T
+	if err != nil {
+		return reply, err
+	}
+	if err := resp.Err(); err != nil {
+		return reply, err
+	}
+	if err := resp.Decode(&reply); err != nil {
+		return reply, err
+	}
+	return reply, nil
+}
+
+// DecodeError 从 DecodeResponse 返回的错误中取出 *HttpError，并将错误响应体解码为 E
+//
+// # Params:
+//
+//	E: 错误响应体类型
+//	err: 错误
+//
+// # Examples:
+//
+//	if apiErr, ok := DecodeError[ApiError](err); ok {
+//		fmt.Println(apiErr.Code, apiErr.Message)
+//	}
+func DecodeError[E any](err error) (E, bool) {
+	var reply E
+	var httpErr *HttpError
+	if !errors.As(err, &httpErr) {
+		return reply, false
+	}
+	if err := httpErr.Decode(&reply); err != nil {
+		return reply, false
+	}
+	return reply, true
+}
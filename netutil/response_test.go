@@ -0,0 +1,99 @@
+package netutil
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/up-zero/gotool/testutil"
+)
+
+type responseUser struct {
+	ID   int    `json:"id" xml:"id"`
+	Name string `json:"name" xml:"name"`
+}
+
+type responseApiError struct {
+	Code    string `json:"code" xml:"code"`
+	Message string `json:"message" xml:"message"`
+}
+
+func TestDecodeResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id":1,"name":"alice"}`))
+		case "/xml":
+			w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+			w.Write([]byte(`<user><id>2</id><name>bob</name></user>`))
+		case "/json-error":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":"not_found","message":"user not found"}`))
+		case "/xml-error":
+			w.Header().Set("Content-Type", "text/xml")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`<error><code>forbidden</code><message>denied</message></error>`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient(ClientConfig{BaseURL: srv.URL})
+	ctx := context.Background()
+
+	user, err := DecodeResponse[responseUser](client.Send(ctx, http.MethodGet, "/json", nil))
+	testutil.Equal(t, err, nil)
+	testutil.Equal(t, user, responseUser{ID: 1, Name: "alice"})
+
+	user, err = DecodeResponse[responseUser](client.Send(ctx, http.MethodGet, "/xml", nil))
+	testutil.Equal(t, err, nil)
+	testutil.Equal(t, user, responseUser{ID: 2, Name: "bob"})
+
+	raw, err := DecodeResponse[string](client.Send(ctx, http.MethodGet, "/json", nil))
+	testutil.Equal(t, err, nil)
+	testutil.Equal(t, raw, `{"id":1,"name":"alice"}`)
+
+	_, err = DecodeResponse[responseUser](client.Send(ctx, http.MethodGet, "/json-error", nil))
+	var httpErr *HttpError
+	testutil.Equal(t, errors.As(err, &httpErr), true)
+	testutil.Equal(t, httpErr.StatusCode, http.StatusNotFound)
+	testutil.Equal(t, err.Error(), `http status 404 Not Found: {"code":"not_found","message":"user not found"}`)
+	apiErr, ok := DecodeError[responseApiError](err)
+	testutil.Equal(t, ok, true)
+	testutil.Equal(t, apiErr, responseApiError{Code: "not_found", Message: "user not found"})
+
+	_, err = DecodeResponse[responseUser](client.Send(ctx, http.MethodGet, "/xml-error", nil))
+	apiErr, ok = DecodeError[responseApiError](err)
+	testutil.Equal(t, ok, true)
+	testutil.Equal(t, apiErr.Code, "forbidden")
+
+	_, err = DecodeResponse[responseUser](client.Send(ctx, http.MethodGet, "/other", nil))
+	testutil.Equal(t, err.Error(), "http status 400 Bad Request")
+	_, ok = DecodeError[responseApiError](errors.New("other"))
+	testutil.Equal(t, ok, false)
+}
+
+func TestResponse(t *testing.T) {
+	resp := &Response{StatusCode: http.StatusNoContent}
+	testutil.Equal(t, resp.OK(), true)
+	testutil.Equal(t, resp.Err(), nil)
+	resp.StatusCode = http.StatusInternalServerError
+	testutil.Equal(t, resp.OK(), false)
+	testutil.NotEqual(t, resp.Err(), nil)
+}
+
+func TestHttpErrorTruncate(t *testing.T) {
+	// 中文每个字符 3 字节, 第 256 字节落在字符中间
+	err := &HttpError{StatusCode: http.StatusInternalServerError, Body: []byte("xx" + strings.Repeat("错误", 100))}
+	msg := err.Error()
+	testutil.Equal(t, utf8.ValidString(msg), true)
+	testutil.Equal(t, strings.HasSuffix(msg, "..."), true)
+	testutil.Equal(t, msg, "http status 500 Internal Server Error: xx"+strings.Repeat("错误", 42)+"...")
+}